@@ -0,0 +1,52 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v3"
+
+	appconfig "user-management/internal/config"
+)
+
+// writeConfig writes the configuration in the format of the config file, with its secrets masked
+func writeConfig(w io.Writer, cfg *appconfig.Config) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(cfg.Redacted()); err != nil {
+		return fmt.Errorf("error encoding the configuration: %w", err)
+	}
+	return enc.Close()
+}
+
+// ShowCommand prints the configuration the server resolves from the same flags, environment and config file
+func ShowCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "show",
+		Usage:     "Print the effective configuration of the API server, with the secrets masked",
+		ArgsUsage: "[server flags, e.g. --config config.yaml --port 9090]",
+		// the arguments are the server's flags, parsed by its own parser
+		SkipFlagParsing: true,
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			cfg, err := appconfig.Load(cmd.Args().Slice())
+			if err != nil {
+				return err
+			}
+			return writeConfig(os.Stdout, cfg)
+		},
+	}
+}
+
+// RegisterCommands registers the configuration commands
+func RegisterCommands() *cli.Command {
+	return &cli.Command{
+		Name:  "config",
+		Usage: "Configuration commands",
+		Commands: []*cli.Command{
+			ShowCommand(),
+		},
+	}
+}
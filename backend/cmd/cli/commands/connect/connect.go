@@ -0,0 +1,68 @@
+// Package connect opens the database of the CLI commands, with the driver, connection string and pool settings
+// of the global flags.
+package connect
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+	"github.com/urfave/cli/v3"
+
+	"user-management/internal/database"
+)
+
+// PoolFlags are the global flags of the connection pool, named and defaulting like the ones of the server
+func PoolFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:      "max-open-conns",
+			Usage:     "Maximum number of open connections to the database",
+			Value:     int64(database.DefaultPoolConfig.MaxOpenConns),
+			Sources:   cli.EnvVars("DB_MAX_OPEN_CONNS"),
+			Validator: notNegative,
+		},
+		&cli.IntFlag{
+			Name:      "max-idle-conns",
+			Usage:     "Maximum number of idle connections to the database",
+			Value:     int64(database.DefaultPoolConfig.MaxIdleConns),
+			Sources:   cli.EnvVars("DB_MAX_IDLE_CONNS"),
+			Validator: notNegative,
+		},
+		&cli.DurationFlag{
+			Name:    "max-conn-lifetime",
+			Usage:   "Close the connections once this old, 0 keeps them forever",
+			Value:   database.DefaultPoolConfig.ConnMaxLifetime,
+			Sources: cli.EnvVars("DB_MAX_CONN_LIFETIME"),
+		},
+		&cli.DurationFlag{
+			Name:    "max-conn-idle-time",
+			Usage:   "Close the connections idle for this long, 0 keeps them forever",
+			Value:   database.DefaultPoolConfig.ConnMaxIdleTime,
+			Sources: cli.EnvVars("DB_MAX_CONN_IDLE_TIME"),
+		},
+	}
+}
+
+// notNegative validates a connection count
+func notNegative(count int64) error {
+	if count < 0 {
+		return fmt.Errorf("invalid connection count %d: must not be negative", count)
+	}
+	return nil
+}
+
+// Pool returns the pool settings of the PoolFlags
+func Pool(cmd *cli.Command) database.PoolConfig {
+	return database.PoolConfig{
+		MaxOpenConns:    int(cmd.Int("max-open-conns")),
+		MaxIdleConns:    int(cmd.Int("max-idle-conns")),
+		ConnMaxLifetime: cmd.Duration("max-conn-lifetime"),
+		ConnMaxIdleTime: cmd.Duration("max-conn-idle-time"),
+	}
+}
+
+// Database connects to the database of the --driver and --dsn flags, with the pool of the PoolFlags
+func Database(ctx context.Context, cmd *cli.Command) (*bun.DB, error) {
+	return database.Connect(ctx, cmd.String("driver"), cmd.String("dsn"), Pool(cmd))
+}
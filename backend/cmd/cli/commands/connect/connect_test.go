@@ -0,0 +1,50 @@
+package connect
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+
+	"user-management/internal/database"
+)
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		args     []string
+		expected database.PoolConfig
+	}{
+		{"Defaults", nil, database.DefaultPoolConfig},
+		{
+			"Flags",
+			[]string{"--max-open-conns", "3", "--max-idle-conns", "2", "--max-conn-lifetime", "1m", "--max-conn-idle-time", "0"},
+			database.PoolConfig{MaxOpenConns: 3, MaxIdleConns: 2, ConnMaxLifetime: time.Minute},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var pool database.PoolConfig
+			cmd := &cli.Command{
+				Flags: PoolFlags(),
+				Action: func(_ context.Context, cmd *cli.Command) error {
+					pool = Pool(cmd)
+					return nil
+				},
+			}
+			require.NoError(t, cmd.Run(context.Background(), append([]string{"usercli"}, tc.args...)))
+			assert.Equal(t, tc.expected, pool)
+		})
+	}
+
+	cmd := &cli.Command{Flags: PoolFlags(), Action: func(context.Context, *cli.Command) error { return nil }}
+	assert.Error(t, cmd.Run(context.Background(), []string{"usercli", "--max-open-conns", "-1"}))
+}
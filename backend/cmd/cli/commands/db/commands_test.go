@@ -0,0 +1,18 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRegisterCommands checks the db commands of the build, run it with and without -tags migrate_tools
+func TestRegisterCommands(t *testing.T) {
+	t.Parallel()
+
+	var names []string
+	for _, cmd := range RegisterCommands().Commands {
+		names = append(names, cmd.Name)
+	}
+	assert.Equal(t, expectedCommands, names)
+}
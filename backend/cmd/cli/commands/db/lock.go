@@ -0,0 +1,76 @@
+//go:build migrate_tools
+
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/urfave/cli/v3"
+)
+
+// the default tables of the bun migrator, a lock is a row of the locks table naming the migrations table
+const (
+	migrationsTable     = "bun_migrations"
+	migrationLocksTable = "bun_migration_locks"
+)
+
+// lockPollInterval is how often migrate and rollback retry a held lock until their --lock-timeout
+const lockPollInterval = time.Second
+
+// errLockTimeout is returned when the migrations lock is still held once the --lock-timeout is over
+var errLockTimeout = errors.New("timed out waiting for the migrations lock")
+
+// lockTimeoutFlag is the flag bounding how long the migrate and rollback commands wait for the migrations lock
+func lockTimeoutFlag() cli.Flag {
+	return &cli.DurationFlag{
+		Name:    "lock-timeout",
+		Usage:   "How long to wait for the migrations lock held by another run, 0 gives up at once",
+		Value:   time.Minute,
+		Sources: cli.EnvVars("MIGRATIONS_LOCK_TIMEOUT"),
+		Validator: func(timeout time.Duration) error {
+			if timeout < 0 {
+				return fmt.Errorf("invalid lock timeout %s: must not be negative", timeout)
+			}
+			return nil
+		},
+	}
+}
+
+// lockWithTimeout takes the migrations lock with lock, retrying every interval while it's held, until the timeout.
+// bun's lock doesn't expire: the one of a crashed run is held until cleared, the error says how.
+func lockWithTimeout(ctx context.Context, lock func(context.Context) error, timeout, interval time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := lock(ctx)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("%w after %s (%w): if no migration is running, the lock was left by a crashed one "+
+				"and `db unlock --force` clears it", errLockTimeout, timeout, err)
+		}
+
+		timer := time.NewTimer(min(interval, remaining))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+// lockHeld reports whether the migrations are locked, by a running migration or one that crashed, which
+// the lock can't tell apart: it has no owner nor timestamp.
+func lockHeld(ctx context.Context, db *bun.DB) (bool, error) {
+	return db.NewSelect().
+		TableExpr(migrationLocksTable).
+		Where("? = ?", bun.Ident("table_name"), db.Formatter().FormatQuery(migrationsTable)).
+		Exists(ctx)
+}
@@ -0,0 +1,97 @@
+//go:build migrate_tools
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+	"github.com/uptrace/bun/migrate"
+)
+
+func TestLockWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	errLocked := errors.New("migrate: migrations table is already locked")
+
+	// locked returns a lock held for the first n attempts
+	locked := func(n int) (func(context.Context) error, *int) {
+		calls := 0
+		return func(context.Context) error {
+			calls++
+			if calls <= n {
+				return errLocked
+			}
+			return nil
+		}, &calls
+	}
+
+	t.Run("WaitsForTheLock", func(t *testing.T) {
+		t.Parallel()
+		lock, calls := locked(2)
+		require.NoError(t, lockWithTimeout(context.Background(), lock, time.Second, time.Millisecond))
+		assert.Equal(t, 3, *calls)
+	})
+
+	t.Run("TimesOut", func(t *testing.T) {
+		t.Parallel()
+		lock, calls := locked(1000)
+		err := lockWithTimeout(context.Background(), lock, 20*time.Millisecond, time.Millisecond)
+		require.ErrorIs(t, err, errLockTimeout)
+		require.ErrorIs(t, err, errLocked)
+		assert.Contains(t, err.Error(), "db unlock --force")
+		assert.Greater(t, *calls, 1)
+	})
+
+	t.Run("ZeroTimeoutTriesOnce", func(t *testing.T) {
+		t.Parallel()
+		lock, calls := locked(1)
+		require.ErrorIs(t, lockWithTimeout(context.Background(), lock, 0, time.Millisecond), errLockTimeout)
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("StopsOnContextDone", func(t *testing.T) {
+		t.Parallel()
+		lock, calls := locked(1)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.ErrorIs(t, lockWithTimeout(ctx, lock, time.Hour, time.Hour), errLocked)
+		assert.Equal(t, 1, *calls)
+	})
+}
+
+func TestLockHeld(t *testing.T) {
+	t.Parallel()
+
+	sqldb, err := sql.Open(sqliteshim.ShimName, "file::memory:")
+	require.NoError(t, err)
+	sqldb.SetMaxOpenConns(1)
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	migrator := migrate.NewMigrator(db, migrate.NewMigrations())
+	require.NoError(t, migrator.Init(ctx))
+
+	held, err := lockHeld(ctx, db)
+	require.NoError(t, err)
+	assert.False(t, held)
+
+	require.NoError(t, migrator.Lock(ctx))
+	held, err = lockHeld(ctx, db)
+	require.NoError(t, err)
+	assert.True(t, held)
+
+	require.NoError(t, migrator.Unlock(ctx))
+	held, err = lockHeld(ctx, db)
+	require.NoError(t, err)
+	assert.False(t, held)
+}
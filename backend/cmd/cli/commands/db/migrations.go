@@ -4,20 +4,28 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
 	"strings"
 
 	"github.com/uptrace/bun/migrate"
 	"github.com/urfave/cli/v3"
 
+	"user-management/cmd/cli/commands/connect"
 	"user-management/internal/migrations"
 	"user-management/internal/models"
 )
 
 // commonCommandAction is a helper function to reduce code duplication
 func commonCommandAction(ctx context.Context, cmd *cli.Command, operation func(*migrate.Migrator, context.Context) error) error {
-	db, err := initDB(cmd.String("dsn"))
+	ms, err := loadMigrations(cmd)
+	if err != nil {
+		return err
+	}
+
+	db, err := connect.Database(ctx, cmd)
 	if err != nil {
 		return err
 	}
@@ -27,11 +35,65 @@ func commonCommandAction(ctx context.Context, cmd *cli.Command, operation func(*
 		}
 	}()
 
-	migrator := migrate.NewMigrator(db, migrations.Migrations)
+	migrator := migrate.NewMigrator(db, ms)
 
 	return operation(migrator, ctx)
 }
 
+// loadMigrations combines the built-in migrations with the ones of the --migrations-dir directories,
+// up to the --target migration of the migrate command. An unknown target fails before connecting to the database.
+func loadMigrations(cmd *cli.Command) (*migrate.Migrations, error) {
+	ms := migrations.Migrations
+	if dirs := cmd.StringSlice("migrations-dir"); len(dirs) > 0 {
+		sources := make([]migrations.Source, 0, len(dirs))
+		for _, dir := range dirs {
+			sources = append(sources, migrations.DirSource(dir))
+		}
+
+		var err error
+		if ms, err = migrations.Combine(sources...); err != nil {
+			return nil, err
+		}
+	}
+
+	if target := cmd.String("target"); target != "" {
+		return migrations.UpTo(ms, target)
+	}
+	return ms, nil
+}
+
+// dryRunFlag is the flag printing the plan of the migrate and rollback commands instead of running it
+func dryRunFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Print the migrations that would run, in order, without running them",
+	}
+}
+
+// printPlan prints the migrations a dry run would run in the direction, up or down, in order
+func printPlan(direction string, ms migrate.MigrationSlice) error {
+	if len(ms) == 0 {
+		_, err := fmt.Fprintln(os.Stdout, "nothing to run")
+		return err
+	}
+	for _, m := range ms {
+		if _, err := fmt.Fprintf(os.Stdout, "%s %s\n", direction, m.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printGroup prints the migrations of the group, the verb telling what was done with them
+func printGroup(verb string, group *migrate.MigrationGroup) error {
+	for _, m := range group.Migrations {
+		if _, err := fmt.Fprintf(os.Stdout, "%s %s (group #%d)\n", verb, m.String(), group.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // InitCommand creates migration tables.
 func InitCommand() *cli.Command {
 	return &cli.Command{
@@ -50,9 +112,25 @@ func MigrateCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "migrate",
 		Usage: "migrate database",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "target",
+				Usage: "Stop after this migration, given by version (20250424000000) or full name, instead of migrating to the latest",
+			},
+			dryRunFlag(),
+			lockTimeoutFlag(),
+		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			return commonCommandAction(ctx, cmd, func(migrator *migrate.Migrator, ctx context.Context) error {
-				if err := migrator.Lock(ctx); err != nil {
+				if cmd.Bool("dry-run") {
+					ms, err := migrator.MigrationsWithStatus(ctx)
+					if err != nil {
+						return err
+					}
+					return printPlan("up", ms.Unapplied())
+				}
+
+				if err := lockWithTimeout(ctx, migrator.Lock, cmd.Duration("lock-timeout"), lockPollInterval); err != nil {
 					return err
 				}
 				defer migrator.Unlock(ctx) //nolint:errcheck
@@ -68,19 +146,39 @@ func MigrateCommand() *cli.Command {
 
 				slog.With("group", group.String()).
 					Info("migrated to")
-				return nil
+				return printGroup("applied", group)
 			})
 		},
 	}
 }
 
-// RollbackCommand rolls back the last migration group.
+// RollbackCommand rolls back the last migration groups.
 func RollbackCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "rollback",
-		Usage: "rollback the last migration group",
+		Usage: "rollback the last migration group, or the last --steps groups",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "steps",
+				Usage: "Number of migration groups to roll back, the latest first",
+				Value: 1,
+				Validator: func(steps int64) error {
+					if steps < 1 {
+						return fmt.Errorf("invalid steps %d: must be positive", steps)
+					}
+					return nil
+				},
+			},
+			dryRunFlag(),
+			lockTimeoutFlag(),
+		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
-			db, err := initDB(cmd.String("dsn"))
+			ms, err := loadMigrations(cmd)
+			if err != nil {
+				return err
+			}
+
+			db, err := connect.Database(ctx, cmd)
 			if err != nil {
 				return err
 			}
@@ -90,23 +188,40 @@ func RollbackCommand() *cli.Command {
 				}
 			}()
 
-			migrator := migrate.NewMigrator(db, migrations.Migrations)
+			migrator := migrate.NewMigrator(db, ms)
 
-			if err := migrator.Lock(ctx); err != nil {
-				return err
+			if cmd.Bool("dry-run") {
+				ms, err := migrator.MigrationsWithStatus(ctx)
+				if err != nil {
+					return err
+				}
+				var reverted migrate.MigrationSlice
+				for _, group := range migrations.RollbackPlan(ms, int(cmd.Int("steps"))) {
+					reverted = append(reverted, group.Migrations...)
+				}
+				return printPlan("down", reverted)
 			}
-			defer migrator.Unlock(ctx) //nolint:errcheck
 
-			group, err := migrator.Rollback(ctx)
-			if err != nil {
+			if err := lockWithTimeout(ctx, migrator.Lock, cmd.Duration("lock-timeout"), lockPollInterval); err != nil {
 				return err
 			}
-			if group.IsZero() {
-				slog.Info("there are no groups to roll back\n")
-				return nil
+			defer migrator.Unlock(ctx) //nolint:errcheck
+
+			for range cmd.Int("steps") {
+				group, err := migrator.Rollback(ctx)
+				if err != nil {
+					return err
+				}
+				if group.IsZero() {
+					slog.Info("there are no groups to roll back")
+					return nil
+				}
+				slog.With("group", group.String()).
+					Info("rolled back")
+				if err := printGroup("reverted", group); err != nil {
+					return err
+				}
 			}
-			slog.With("group", group.String()).
-				Info("rolled back")
 			return nil
 		},
 	}
@@ -126,13 +241,41 @@ func LockCommand() *cli.Command {
 }
 
 // UnlockCommand unlocks migrations.
+// A held lock is only cleared with --force, nothing tells the lock of a crashed migration from the one of a running one.
 func UnlockCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "unlock",
-		Usage: "unlock migrations",
+		Usage: "unlock migrations left locked by a crashed migrate or rollback",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Clear the lock, only once sure that no migrate or rollback is running",
+			},
+		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			return commonCommandAction(ctx, cmd, func(migrator *migrate.Migrator, ctx context.Context) error {
-				return migrator.Unlock(ctx)
+				held, err := lockHeld(ctx, migrator.DB())
+				if err != nil {
+					return fmt.Errorf("error checking the migrations lock: %w", err)
+				}
+				if !held {
+					_, err := fmt.Fprintln(os.Stdout, "migrations are not locked")
+					return err
+				}
+				if !cmd.Bool("force") {
+					return errors.New("migrations are locked, by a running migrate or rollback or a crashed one: " +
+						"if none is running, clear the lock with --force")
+				}
+
+				if _, err := fmt.Fprintln(os.Stderr,
+					"warning: clearing the migrations lock, a migrate or rollback still running would no longer be exclusive"); err != nil {
+					return err
+				}
+				if err := migrator.Unlock(ctx); err != nil {
+					return err
+				}
+				_, err = fmt.Fprintln(os.Stdout, "cleared the migrations lock")
+				return err
 			})
 		},
 	}
@@ -217,7 +360,7 @@ func TruncateUserTableCommand() *cli.Command {
 		Name:  "truncate_user_table",
 		Usage: "truncate the user table",
 		Action: func(ctx context.Context, cmd *cli.Command) error {
-			db, err := initDB(cmd.String("dsn"))
+			db, err := connect.Database(ctx, cmd)
 			if err != nil {
 				return err
 			}
@@ -239,18 +382,29 @@ func TruncateUserTableCommand() *cli.Command {
 	}
 }
 
+// RegisterCommands registers the database commands, including the migration ones of the migrate_tools build tag.
 func RegisterCommands() *cli.Command {
 	return &cli.Command{
 		Name:  "db",
 		Usage: "Database management commands",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:    "migrations-dir",
+				Usage:   "Additional directory of SQL migrations, applied along with the built-in ones (can be repeated)",
+				Sources: cli.EnvVars("MIGRATIONS_DIRS"),
+			},
+		},
 		Commands: []*cli.Command{
+			PingCommand(),
 			InitCommand(),
 			MigrateCommand(),
 			RollbackCommand(),
+			LockCommand(),
 			UnlockCommand(),
 			CreateGoCommand(),
 			CreateSQLCommand(),
 			StatusCommand(),
+			SeedCommand(),
 			TruncateUserTableCommand(),
 		},
 	}
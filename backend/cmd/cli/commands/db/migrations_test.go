@@ -0,0 +1,7 @@
+//go:build migrate_tools
+
+package db
+
+var expectedCommands = []string{
+	"ping", "init", "migrate", "rollback", "lock", "unlock", "create_go", "create_sql", "status", "seed", "truncate_user_table",
+}
@@ -0,0 +1,5 @@
+//go:build !migrate_tools
+
+package db
+
+var expectedCommands = []string{"ping"}
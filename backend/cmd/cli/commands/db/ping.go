@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/urfave/cli/v3"
+
+	"user-management/cmd/cli/commands/connect"
+)
+
+// PingCommand pings the database.
+func PingCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "ping",
+		Usage: "ping the database",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			db, err := connect.Database(ctx, cmd)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := db.Close(); err != nil {
+					slog.With("error", err).Error("failed to close database connection")
+				}
+			}()
+
+			err = db.PingContext(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to ping database: %w", err)
+			}
+			slog.Info("database pinged... pong!")
+
+			return nil
+		},
+	}
+}
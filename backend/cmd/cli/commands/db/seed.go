@@ -0,0 +1,118 @@
+//go:build migrate_tools
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"user-management/cmd/cli/commands/connect"
+	"user-management/internal/models"
+	"user-management/internal/repository"
+	"user-management/internal/services"
+	vld "user-management/internal/validator"
+)
+
+// the sample users draw their names and departments from these, the names cover several scripts on purpose
+var (
+	seedFirstNames = []string{
+		"John", "Maria", "José", "Zoë", "Łukasz", "Søren", "Ngozi", "Анна", "Дмитрий", "Yūki", "Aiko", "Nguyễn", "Ahmed", "Chloé",
+	}
+	seedLastNames = []string{
+		"Smith", "García", "Müller", "Kowalski", "Øberg", "Okafor", "Иванова", "Nakamura", "Trần", "Haddad", "Lefèvre", "Wang",
+	}
+	seedDepartments = []string{"Engineering", "Sales", "Marketing", "Human Resources", "Research and Development", "Support"}
+)
+
+// maxSeedCount bounds the users created by a seed, they're all inserted in a single transaction
+const maxSeedCount = 10_000
+
+// seedStatuses weighs the statuses of the sample users, most of them are active
+var seedStatuses = []models.UserStatus{
+	models.UserStatusActive, models.UserStatusActive, models.UserStatusActive, models.UserStatusActive,
+	models.UserStatusInactive, models.UserStatusTerminated,
+}
+
+// seedUsers generates count valid users, the same ones for the same seed.
+// A user name (and its email) may come up twice, the duplicates are skipped when creating them.
+func seedUsers(seed uint64, count int) []models.UserCreateRequest {
+	rng := rand.New(rand.NewPCG(seed, seed)) //nolint:gosec // sample data, not a secret
+
+	users := make([]models.UserCreateRequest, count)
+	for i := range users {
+		userName := fmt.Sprintf("seed%06d", rng.IntN(1_000_000))
+		users[i] = models.UserCreateRequest{UserCommon: models.UserCommon{
+			UserName:   userName,
+			FirstName:  seedFirstNames[rng.IntN(len(seedFirstNames))],
+			LastName:   seedLastNames[rng.IntN(len(seedLastNames))],
+			Email:      userName + "@example.com",
+			UserStatus: seedStatuses[rng.IntN(len(seedStatuses))],
+			Department: seedDepartments[rng.IntN(len(seedDepartments))],
+		}}
+	}
+	return users
+}
+
+// SeedCommand creates sample users for development.
+func SeedCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "seed",
+		Usage: "create sample users, skipping the ones that already exist",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "count",
+				Usage: "Number of users to generate",
+				Value: 50,
+				Validator: func(count int64) error {
+					if count < 1 || count > maxSeedCount {
+						return fmt.Errorf("invalid count %d: must be between 1 and %d", count, maxSeedCount)
+					}
+					return nil
+				},
+			},
+			&cli.UintFlag{
+				Name:  "seed",
+				Usage: "Seed of the generator, the same seed generates the same users",
+				Value: 1,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			validate, err := vld.NewValidator()
+			if err != nil {
+				return err
+			}
+
+			users := seedUsers(cmd.Uint("seed"), int(cmd.Int("count")))
+			for i := range users {
+				if err := validate.Struct(users[i]); err != nil {
+					return fmt.Errorf("invalid sample user %s: %w", users[i].UserName, err)
+				}
+			}
+
+			db, err := connect.Database(ctx, cmd)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := db.Close(); err != nil {
+					slog.With("error", err).Error("failed to close database connection")
+				}
+			}()
+
+			// the sample departments are created along with their first user
+			userService := services.NewUserService(repository.NewUserRepository(db), services.WithAutoCreateDepartments(true))
+			result, err := userService.CreateUsers(ctx, users, models.ConflictModeIgnore)
+			if err != nil {
+				return fmt.Errorf("error seeding users: %w", err)
+			}
+
+			_, err = fmt.Fprintf(os.Stdout, "created %d users, skipped %d duplicates\n", len(result.Created), len(result.Skipped))
+			return err
+		},
+	}
+}
@@ -0,0 +1,35 @@
+//go:build migrate_tools
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	vld "user-management/internal/validator"
+)
+
+func TestSeedUsers(t *testing.T) {
+	t.Parallel()
+
+	validate, err := vld.NewValidator()
+	require.NoError(t, err)
+
+	users := seedUsers(42, 200)
+	require.Len(t, users, 200)
+	for _, user := range users {
+		require.NoError(t, validate.Struct(user), user.UserName)
+	}
+
+	// reproducible for the same seed, another seed generates other users
+	assert.Equal(t, users, seedUsers(42, 200))
+	assert.NotEqual(t, users, seedUsers(43, 200))
+
+	statuses := map[string]bool{}
+	for _, user := range users {
+		statuses[string(user.UserStatus)] = true
+	}
+	assert.Len(t, statuses, 3, "every status is represented")
+}
@@ -0,0 +1,41 @@
+package user
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"user-management/internal/models"
+)
+
+// isTerminal reports whether f is an interactive terminal rather than a pipe or a file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// confirmDelete shows the user about to be deleted and reads the answer,
+// anything but y or yes (case-insensitive) declines
+func confirmDelete(r io.Reader, w io.Writer, user *models.User) (bool, error) {
+	if _, err := fmt.Fprintf(w, "Delete user %d (%s, %s)? [y/N]: ", user.UserID, user.UserName, user.Email); err != nil {
+		return false, err
+	}
+
+	answer, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
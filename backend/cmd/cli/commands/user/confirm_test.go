@@ -0,0 +1,69 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-management/internal/models"
+)
+
+func TestConfirmDelete(t *testing.T) {
+	t.Parallel()
+
+	user := &models.User{UserID: 5, UserCommon: models.UserCommon{UserName: "johndoe", Email: "john@example.com"}}
+
+	testCases := []struct {
+		input    string
+		expected bool
+	}{
+		{input: "y\n", expected: true},
+		{input: " YES \n", expected: true},
+		{input: "yes", expected: true},
+		{input: "\n", expected: false},
+		{input: "n\n", expected: false},
+		{input: "yep\n", expected: false},
+		{input: "", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			var prompt bytes.Buffer
+			confirmed, err := confirmDelete(strings.NewReader(tc.input), &prompt, user)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, confirmed)
+			assert.Equal(t, "Delete user 5 (johndoe, john@example.com)? [y/N]: ", prompt.String())
+		})
+	}
+}
+
+func TestDeleteCommandRequiresConfirmation(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "stdin"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+	assert.False(t, isTerminal(f), "a regular file isn't a terminal")
+
+	// without a terminal on stdin and without --yes the command gives up before connecting
+	run := func(args ...string) error {
+		cmd := DeleteCommand()
+		cmd.Writer = io.Discard
+		cmd.ErrWriter = io.Discard
+		return cmd.Run(context.Background(), append([]string{"delete"}, args...))
+	}
+
+	if !isTerminal(os.Stdin) {
+		assert.ErrorContains(t, run("--id", "5"), "pass --yes")
+	}
+	assert.ErrorContains(t, run("--id", "5", "--yes"), "database connection string is required")
+}
@@ -0,0 +1,129 @@
+package user
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"user-management/internal/models"
+	"user-management/internal/services"
+)
+
+// exportPageSize is the number of users fetched per query while exporting
+const exportPageSize = models.MaxListLimit
+
+// Export formats
+const (
+	exportCSV  = "csv"
+	exportJSON = "json"
+)
+
+// usersEncoder streams users in an export format, close completes the document
+type usersEncoder interface {
+	encode(user models.User) error
+	close() error
+}
+
+func newUsersEncoder(format string, w io.Writer) (usersEncoder, error) {
+	switch format {
+	case exportCSV:
+		enc := &csvUsersEncoder{w: csv.NewWriter(w)}
+		// the import format, framed by the ID and the timestamps, which the import ignores
+		header := append(append([]string{"id"}, csvHeader...), "createdAt", "updatedAt")
+		if err := enc.w.Write(header); err != nil {
+			return nil, err
+		}
+		return enc, nil
+	case exportJSON:
+		return &jsonUsersEncoder{w: w}, nil
+	default:
+		return nil, fmt.Errorf("invalid format %q: must be one of %s, %s", format, exportCSV, exportJSON)
+	}
+}
+
+type csvUsersEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvUsersEncoder) encode(user models.User) error {
+	return e.w.Write([]string{
+		strconv.FormatInt(user.UserID, 10),
+		user.UserName,
+		user.FirstName,
+		user.LastName,
+		user.Email,
+		string(user.UserStatus),
+		user.Department,
+		user.CreatedAt.UTC().Format(time.RFC3339),
+		user.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (e *csvUsersEncoder) close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonUsersEncoder writes a JSON array one user per line, without holding the whole array in memory
+type jsonUsersEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonUsersEncoder) encode(user models.User) error {
+	data, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+
+	separator := ",\n  "
+	if e.count == 0 {
+		separator = "[\n  "
+	}
+	e.count++
+
+	if _, err := io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonUsersEncoder) close() error {
+	if e.count == 0 {
+		_, err := io.WriteString(e.w, "[]\n")
+		return err
+	}
+	_, err := io.WriteString(e.w, "\n]\n")
+	return err
+}
+
+// exportUsers streams the users matching the filters of params to the encoder page by page in user ID order,
+// returning the number of exported users
+func exportUsers(ctx context.Context, userService services.UserService, params models.ListParams, enc usersEncoder) (int, error) {
+	params.Sort = "user_id"
+	params.Limit = exportPageSize
+
+	count := 0
+	for params.Offset = 0; ; params.Offset += exportPageSize {
+		users, _, err := userService.ListUsers(ctx, params)
+		if err != nil {
+			return count, err
+		}
+
+		for _, user := range users {
+			if err := enc.encode(user); err != nil {
+				return count, err
+			}
+		}
+		count += len(users)
+
+		if len(users) < exportPageSize {
+			return count, enc.close()
+		}
+	}
+}
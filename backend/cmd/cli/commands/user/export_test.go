@@ -0,0 +1,114 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-management/internal/models"
+	"user-management/internal/services"
+)
+
+// pagedUserService serves the list pages from memory and records the requested params
+type pagedUserService struct {
+	services.UserService
+
+	users  []models.User
+	params []models.ListParams
+}
+
+func (s *pagedUserService) ListUsers(_ context.Context, params models.ListParams) ([]models.User, int, error) {
+	s.params = append(s.params, params)
+
+	end := min(params.Offset+params.Limit, len(s.users))
+	if params.Offset >= end {
+		return nil, len(s.users), nil
+	}
+	return s.users[params.Offset:end], len(s.users), nil
+}
+
+func exportTestUsers(n int) []models.User {
+	created := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	users := make([]models.User, n)
+	for i := range users {
+		users[i] = models.User{
+			UserID: int64(i + 1),
+			UserCommon: models.UserCommon{
+				UserName:   fmt.Sprintf("user%d", i+1),
+				FirstName:  "Export",
+				LastName:   "User",
+				Email:      fmt.Sprintf("user%d@example.com", i+1),
+				UserStatus: models.UserStatusActive,
+				Department: "Sales, EMEA",
+			},
+			CreatedAt: created,
+			UpdatedAt: created.Add(time.Hour),
+		}
+	}
+	return users
+}
+
+func TestExportUsers(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []string{exportCSV, exportJSON} {
+		t.Run(format+" pages through the users and round-trips with import", func(t *testing.T) {
+			t.Parallel()
+
+			svc := &pagedUserService{users: exportTestUsers(exportPageSize + 1)}
+			var buf bytes.Buffer
+			enc, err := newUsersEncoder(format, &buf)
+			require.NoError(t, err)
+
+			count, err := exportUsers(context.Background(), svc, models.ListParams{Status: models.UserStatusActive}, enc)
+			require.NoError(t, err)
+			assert.Equal(t, exportPageSize+1, count)
+
+			require.Len(t, svc.params, 2)
+			for i, params := range svc.params {
+				assert.Equal(t, exportPageSize, params.Limit)
+				assert.Equal(t, i*exportPageSize, params.Offset)
+				assert.Equal(t, models.UserStatusActive, params.Status, "the filters are kept")
+			}
+
+			assert.Contains(t, buf.String(), "2025-04-01T13:00:00Z", "timestamps are exported")
+
+			var reqs []models.UserCreateRequest
+			if format == exportCSV {
+				reqs, err = readUsersCSV(&buf)
+			} else {
+				reqs, err = readUsersJSON(&buf)
+			}
+			require.NoError(t, err)
+			require.Len(t, reqs, count)
+			assert.Equal(t, svc.users[0].UserCommon, reqs[0].UserCommon)
+			assert.Equal(t, svc.users[count-1].UserCommon, reqs[count-1].UserCommon)
+		})
+	}
+
+	t.Run("empty json export is an empty array", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		enc, err := newUsersEncoder(exportJSON, &buf)
+		require.NoError(t, err)
+
+		count, err := exportUsers(context.Background(), &pagedUserService{}, models.ListParams{}, enc)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		assert.JSONEq(t, "[]", buf.String())
+	})
+
+	t.Run("rejects unknown formats", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newUsersEncoder("xml", &bytes.Buffer{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,95 @@
+package user
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"user-management/internal/models"
+)
+
+// csvHeader lists the supported CSV columns, named after the JSON keys of the user model
+var csvHeader = []string{"userName", "firstName", "lastName", "email", "userStatus", "department"}
+
+// readUsersFile reads user records from a JSON (array of objects) or CSV (with header) file,
+// the format is picked by the file extension.
+func readUsersFile(path string) ([]models.UserCreateRequest, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return readUsersJSON(f)
+	case ".csv":
+		return readUsersCSV(f)
+	default:
+		return nil, fmt.Errorf("unsupported file extension %q: must be .json or .csv", filepath.Ext(path))
+	}
+}
+
+func readUsersJSON(r io.Reader) ([]models.UserCreateRequest, error) {
+	var reqs []models.UserCreateRequest
+	if err := json.NewDecoder(r).Decode(&reqs); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+	return reqs, nil
+}
+
+func readUsersCSV(r io.Reader) ([]models.UserCreateRequest, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range csvHeader[:5] {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing required CSV column %q", name)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	var reqs []models.UserCreateRequest
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		reqs = append(reqs, models.UserCreateRequest{
+			UserCommon: models.UserCommon{
+				UserName:   field(record, "userName"),
+				FirstName:  field(record, "firstName"),
+				LastName:   field(record, "lastName"),
+				Email:      field(record, "email"),
+				UserStatus: models.UserStatus(field(record, "userStatus")),
+				Department: field(record, "department"),
+			},
+		})
+	}
+
+	return reqs, nil
+}
@@ -0,0 +1,61 @@
+package user
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCommandSelectors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "no selector",
+			args:     []string{},
+			expected: "one of these flags needs to be provided",
+		},
+		{
+			name:     "several selectors",
+			args:     []string{"--id", "1", "--email", "john@example.com"},
+			expected: "cannot be set along with option",
+		},
+		{
+			name:     "invalid id",
+			args:     []string{"--id", "0"},
+			expected: "invalid user ID",
+		},
+		{
+			name:     "empty username",
+			args:     []string{"--username", " "},
+			expected: "invalid username",
+		},
+		{
+			// a valid selector gets as far as connecting to the database
+			name:     "email",
+			args:     []string{"--email", "john@example.com"},
+			expected: "database connection string is required",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd := GetCommand()
+			cmd.Writer = io.Discard
+			cmd.ErrWriter = io.Discard
+
+			err := cmd.Run(context.Background(), append([]string{"get"}, tc.args...))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
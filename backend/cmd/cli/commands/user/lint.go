@@ -0,0 +1,104 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/urfave/cli/v3"
+
+	"user-management/internal/models"
+)
+
+// recordProblem is an issue found in a single record of a users file
+type recordProblem struct {
+	Index   int
+	Problem string
+}
+
+func (p recordProblem) String() string {
+	return fmt.Sprintf("record %d: %s", p.Index, p.Problem)
+}
+
+// validateRecord runs the model validation rules on a single record,
+// reporting every failing field rather than only the first one
+func validateRecord(req models.UserCreateRequest) []string {
+	err := getValidator().Struct(req)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return []string{err.Error()}
+	}
+
+	problems := make([]string, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		problems = append(problems, fmt.Sprintf("%s failed on the '%s' rule", fe.Field(), fe.Tag()))
+	}
+	return problems
+}
+
+// lintUsers validates every record and detects user names or emails repeated within the file.
+// It never touches the database, so conflicts with stored users aren't detected.
+func lintUsers(reqs []models.UserCreateRequest) []recordProblem {
+	var problems []recordProblem
+	userNames := make(map[string]int, len(reqs))
+	emails := make(map[string]int, len(reqs))
+
+	for i, req := range reqs {
+		for _, problem := range validateRecord(req) {
+			problems = append(problems, recordProblem{Index: i, Problem: problem})
+		}
+
+		if first, ok := userNames[req.UserName]; ok && req.UserName != "" {
+			problems = append(problems, recordProblem{Index: i, Problem: fmt.Sprintf("duplicate userName %q, first used by record %d", req.UserName, first)})
+		} else {
+			userNames[req.UserName] = i
+		}
+
+		if first, ok := emails[req.Email]; ok && req.Email != "" {
+			problems = append(problems, recordProblem{Index: i, Problem: fmt.Sprintf("duplicate email %q, first used by record %d", req.Email, first)})
+		} else {
+			emails[req.Email] = i
+		}
+	}
+
+	return problems
+}
+
+// LintCommand returns a CLI command for validating a JSON or CSV users file without a database
+func LintCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "lint",
+		Usage: "Validate a JSON or CSV users file offline, without a database connection",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "file",
+				Aliases:  []string{"f"},
+				Usage:    "Path to a .json or .csv file",
+				Required: true,
+			},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			reqs, err := readUsersFile(cmd.String("file"))
+			if err != nil {
+				return err
+			}
+
+			problems := lintUsers(reqs)
+			for _, problem := range problems {
+				fmt.Println(problem)
+			}
+
+			if len(problems) > 0 {
+				return fmt.Errorf("found %d problems in %d records", len(problems), len(reqs))
+			}
+
+			fmt.Printf("%d records are valid\n", len(reqs))
+			return nil
+		},
+	}
+}
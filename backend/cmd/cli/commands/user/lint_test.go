@@ -0,0 +1,59 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"user-management/internal/models"
+)
+
+func lintRecord(userName, email string) models.UserCreateRequest {
+	return models.UserCreateRequest{UserCommon: models.UserCommon{
+		UserName:   userName,
+		FirstName:  "Lint",
+		LastName:   "User",
+		Email:      email,
+		UserStatus: models.UserStatusActive,
+	}}
+}
+
+func TestLintUsers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("accepts a valid file", func(t *testing.T) {
+		t.Parallel()
+
+		problems := lintUsers([]models.UserCreateRequest{
+			lintRecord("johndoe", "john@example.com"),
+			lintRecord("janedoe", "jane@example.com"),
+		})
+		assert.Empty(t, problems)
+	})
+
+	t.Run("reports every invalid field and intra-file duplicates", func(t *testing.T) {
+		t.Parallel()
+
+		invalid := lintRecord("ab", "not-an-email")
+		invalid.UserStatus = "X"
+
+		problems := lintUsers([]models.UserCreateRequest{
+			lintRecord("johndoe", "john@example.com"),
+			invalid,
+			lintRecord("johndoe", "other@example.com"),
+			lintRecord("janedoe", "john@example.com"),
+		})
+
+		lines := make([]string, 0, len(problems))
+		for _, problem := range problems {
+			lines = append(lines, problem.String())
+		}
+		assert.Equal(t, []string{
+			"record 1: UserName failed on the 'min' rule",
+			"record 1: Email failed on the 'email' rule",
+			"record 1: UserStatus failed on the 'oneof' rule",
+			`record 2: duplicate userName "johndoe", first used by record 0`,
+			`record 3: duplicate email "john@example.com", first used by record 0`,
+		}, lines)
+	})
+}
@@ -0,0 +1,137 @@
+package user
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v3"
+
+	"user-management/internal/models"
+)
+
+// Output formats supported by the commands printing users
+const (
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	outputTable = "table"
+	outputCSV   = "csv"
+)
+
+var outputFormats = []string{outputJSON, outputYAML, outputTable, outputCSV}
+
+// outputFlag returns the --output flag shared by the commands printing users
+func outputFlag() *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:    "output",
+		Aliases: []string{"o"},
+		Usage:   "Output format: " + strings.Join(outputFormats, ", "),
+		Value:   outputJSON,
+		Validator: func(format string) error {
+			if !slices.Contains(outputFormats, format) {
+				return fmt.Errorf("invalid output format %q: must be one of %s", format, strings.Join(outputFormats, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+// printUsers writes the users in the given format. value is what json and yaml print,
+// e.g. a single user for get, so the structured output keeps its shape,
+// while table and csv always print one row per user.
+func printUsers(w io.Writer, format string, value any, users []models.User) error {
+	switch format {
+	case outputYAML:
+		return writeYAML(w, value)
+	case outputTable:
+		return writeTable(w, users)
+	case outputCSV:
+		return writeCSV(w, users)
+	default:
+		output, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error formatting output: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(output))
+		return err
+	}
+}
+
+// writeYAML prints the value with the same keys as the JSON output,
+// going through JSON so the models don't need yaml tags
+func writeYAML(w io.Writer, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("error formatting output: %w", err)
+	}
+
+	// JSON is valid YAML, decoding it into a node keeps the field order
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("error formatting output: %w", err)
+	}
+	blockStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return fmt.Errorf("error formatting output: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("error formatting output: %w", err)
+	}
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// blockStyle drops the flow style and quoting the nodes inherit from the JSON source
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// writeTable prints the users as aligned columns
+func writeTable(w io.Writer, users []models.User) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tUSERNAME\tNAME\tEMAIL\tSTATUS\tDEPARTMENT") //nolint:errcheck
+	for _, user := range users {
+		fmt.Fprintf(tw, "%d\t%s\t%s %s\t%s\t%s\t%s\n", //nolint:errcheck
+			user.UserID, user.UserName, user.FirstName, user.LastName, user.Email, user.UserStatus, user.Department)
+	}
+	return tw.Flush()
+}
+
+// writeCSV prints the users with a header row, the columns after the ID match the import format
+func writeCSV(w io.Writer, users []models.User) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append([]string{"id"}, csvHeader...)); err != nil {
+		return err
+	}
+	for _, user := range users {
+		record := []string{
+			strconv.FormatInt(user.UserID, 10),
+			user.UserName,
+			user.FirstName,
+			user.LastName,
+			user.Email,
+			string(user.UserStatus),
+			user.Department,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
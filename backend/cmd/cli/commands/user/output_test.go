@@ -0,0 +1,80 @@
+package user
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-management/internal/models"
+)
+
+func TestPrintUsers(t *testing.T) {
+	t.Parallel()
+
+	users := []models.User{
+		{UserID: 1, UserCommon: models.UserCommon{
+			UserName: "johndoe", FirstName: "John", LastName: "Doe", Email: "john@example.com",
+			UserStatus: models.UserStatusActive, Department: "Sales, EMEA",
+		}},
+		{UserID: 12, UserCommon: models.UserCommon{
+			UserName: "jane", FirstName: "Jane", LastName: "Roe", Email: "jane@example.com",
+			UserStatus: models.UserStatusInactive,
+		}},
+	}
+
+	testCases := []struct {
+		format   string
+		value    any
+		expected []string
+	}{
+		{
+			format:   outputJSON,
+			value:    users[0],
+			expected: []string{"{\n  \"id\": 1,\n  \"userName\": \"johndoe\","},
+		},
+		{
+			format:   outputYAML,
+			value:    users,
+			expected: []string{"- id: 1\n  userName: johndoe\n", "  department: Sales, EMEA\n", "- id: 12\n"},
+		},
+		{
+			format: outputTable,
+			value:  users,
+			expected: []string{
+				"ID  USERNAME  NAME      EMAIL             STATUS  DEPARTMENT\n",
+				"1   johndoe   John Doe  john@example.com  A       Sales, EMEA\n",
+				"12  jane      Jane Roe  jane@example.com  I       \n",
+			},
+		},
+		{
+			format: outputCSV,
+			value:  users,
+			expected: []string{
+				"id,userName,firstName,lastName,email,userStatus,department\n",
+				"1,johndoe,John,Doe,john@example.com,A,\"Sales, EMEA\"\n",
+				"12,jane,Jane,Roe,jane@example.com,I,\n",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			require.NoError(t, printUsers(&buf, tc.format, tc.value, users))
+			for _, expected := range tc.expected {
+				assert.Contains(t, buf.String(), expected)
+			}
+		})
+	}
+
+	t.Run("rejects unknown formats", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Error(t, outputFlag().Validator("xml"))
+		assert.NoError(t, outputFlag().Validator(outputTable))
+	})
+}
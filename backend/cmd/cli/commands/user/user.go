@@ -2,19 +2,21 @@ package user
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-playground/validator/v10"
-	"github.com/uptrace/bun"
-	"github.com/uptrace/bun/dialect/pgdialect"
-	"github.com/uptrace/bun/driver/pgdriver"
 	"github.com/urfave/cli/v3"
 
+	"user-management/cmd/cli/commands/connect"
 	"user-management/internal/models"
 	"user-management/internal/repository"
 	"user-management/internal/services"
@@ -38,29 +40,9 @@ func getValidator() *validator.Validate {
 	return validate
 }
 
-// initDB creates a database connection with the given DSN
-func initDB(dsn string) (*bun.DB, error) {
-	sqldb := sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(dsn)))
-
-	// Set connection pool parameters
-	sqldb.SetMaxOpenConns(8)
-	sqldb.SetMaxIdleConns(4)
-	sqldb.SetConnMaxLifetime(time.Hour)
-	sqldb.SetConnMaxIdleTime(30 * time.Minute)
-
-	// Check if the connection is valid with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := sqldb.PingContext(ctx); err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
-	}
-
-	return bun.NewDB(sqldb, pgdialect.New()), nil
-}
-
 // commonCommandAction is a helper function to reduce code duplication
 func commonCommandAction(ctx context.Context, cmd *cli.Command, operation func(services.UserService, context.Context) error) error {
-	db, err := initDB(cmd.String("dsn"))
+	db, err := connect.Database(ctx, cmd)
 	if err != nil {
 		return err
 	}
@@ -71,7 +53,7 @@ func commonCommandAction(ctx context.Context, cmd *cli.Command, operation func(s
 	}()
 
 	userRepo := repository.NewUserRepository(db)
-	userService := services.NewUserService(userRepo)
+	userService := services.NewUserService(userRepo, services.WithAutoCreateDepartments(cmd.Bool("auto-create-departments")))
 
 	return operation(userService, ctx)
 }
@@ -81,65 +63,135 @@ func ListCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "list",
 		Usage: "List all users",
+		Flags: []cli.Flag{
+			outputFlag(),
+		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			return commonCommandAction(ctx, cmd, func(userService services.UserService, ctx context.Context) error {
-				users, err := userService.ListUsers(ctx)
+				users, _, err := userService.ListUsers(ctx, models.ListParams{})
 				if err != nil {
 					return fmt.Errorf("error listing users: %w", err)
 				}
 
 				slog.Info("Listing users", "count", len(users))
 
-				// Output as JSON for cleaner display
-				output, err := json.MarshalIndent(users, "", "  ")
-				if err != nil {
-					return fmt.Errorf("error formatting output: %w", err)
-				}
-
-				fmt.Println(string(output))
-				return nil
+				return printUsers(os.Stdout, cmd.String("output"), users, users)
 			})
 		},
 	}
 }
 
-// GetCommand returns a CLI command for getting a user by ID
+// GetCommand returns a CLI command for getting a user by ID, username or email
 func GetCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "get",
-		Usage: "Get a user by ID",
+		Usage: "Get a user by ID, username or email",
 		Flags: []cli.Flag{
-			&cli.IntFlag{
-				Name:     "id",
-				Aliases:  []string{"i"},
-				Usage:    "User ID",
+			outputFlag(),
+		},
+		MutuallyExclusiveFlags: []cli.MutuallyExclusiveFlags{
+			{
 				Required: true,
+				Flags: [][]cli.Flag{
+					{
+						&cli.IntFlag{
+							Name:    "id",
+							Aliases: []string{"i"},
+							Usage:   "User ID",
+						},
+					},
+					{
+						&cli.StringFlag{
+							Name:    "username",
+							Aliases: []string{"u"},
+							Usage:   "Username",
+						},
+					},
+					{
+						&cli.StringFlag{
+							Name:    "email",
+							Aliases: []string{"e"},
+							Usage:   "Email",
+						},
+					},
+				},
 			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
-			id := cmd.Int("id")
-			if id <= 0 {
-				return fmt.Errorf("invalid user ID: must be greater than 0")
+			lookup, err := userLookup(cmd)
+			if err != nil {
+				return err
 			}
 
 			return commonCommandAction(ctx, cmd, func(userService services.UserService, ctx context.Context) error {
-				user, err := userService.GetUser(ctx, id)
+				user, err := lookup.get(ctx, userService)
 				if err != nil {
+					if errors.Is(err, services.ErrUserNotFound) {
+						return fmt.Errorf("user %s not found", lookup)
+					}
 					return fmt.Errorf("error getting user: %w", err)
 				}
 
-				output, err := json.MarshalIndent(user, "", "  ")
-				if err != nil {
-					return fmt.Errorf("error formatting output: %w", err)
-				}
-
-				fmt.Println(string(output))
-				return nil
+				return printUsers(os.Stdout, cmd.String("output"), user, []models.User{*user})
 			})
 		},
 	}
 }
 
+// lookup selects a single user by exactly one of its ID, username or email
+type lookup struct {
+	id       int64
+	userName string
+	email    string
+}
+
+// userLookup builds the lookup from the get command's flags,
+// the mutually exclusive group already ensures exactly one of them is set
+func userLookup(cmd *cli.Command) (lookup, error) {
+	var l lookup
+	if cmd.IsSet("id") {
+		l.id = cmd.Int("id")
+		if l.id <= 0 {
+			return l, fmt.Errorf("invalid user ID: must be greater than 0")
+		}
+	}
+	if cmd.IsSet("username") {
+		l.userName = strings.TrimSpace(cmd.String("username"))
+		if l.userName == "" {
+			return l, errors.New("invalid username: must not be empty")
+		}
+	}
+	if cmd.IsSet("email") {
+		l.email = strings.TrimSpace(cmd.String("email"))
+		if l.email == "" {
+			return l, errors.New("invalid email: must not be empty")
+		}
+	}
+	return l, nil
+}
+
+func (l lookup) get(ctx context.Context, userService services.UserService) (*models.User, error) {
+	switch {
+	case l.userName != "":
+		return userService.GetUserByUserName(ctx, l.userName)
+	case l.email != "":
+		return userService.GetUserByEmail(ctx, l.email)
+	default:
+		return userService.GetUser(ctx, l.id)
+	}
+}
+
+func (l lookup) String() string {
+	switch {
+	case l.userName != "":
+		return fmt.Sprintf("with username %q", l.userName)
+	case l.email != "":
+		return fmt.Sprintf("with email %q", l.email)
+	default:
+		return fmt.Sprint(l.id)
+	}
+}
+
 // CreateCommand returns a CLI command for creating a new user
 func CreateCommand() *cli.Command {
 	return &cli.Command{
@@ -182,6 +234,18 @@ func CreateCommand() *cli.Command {
 				Usage:    "Department",
 				Required: false,
 			},
+			&cli.StringFlag{
+				Name:     "phone",
+				Aliases:  []string{"p"},
+				Usage:    "Phone number in E.164 format, e.g. +14155552671",
+				Required: false,
+			},
+			&cli.IntFlag{
+				Name:     "manager-id",
+				Aliases:  []string{"m"},
+				Usage:    "ID of the user's manager",
+				Required: false,
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			req := models.UserCreateRequest{
@@ -192,6 +256,8 @@ func CreateCommand() *cli.Command {
 					Email:      cmd.String("email"),
 					UserStatus: models.UserStatus(cmd.String("status")),
 					Department: cmd.String("department"),
+					Phone:      cmd.String("phone"),
+					ManagerID:  managerID(cmd),
 				},
 			}
 
@@ -261,6 +327,18 @@ func UpdateCommand() *cli.Command {
 				Usage:    "Department",
 				Required: false,
 			},
+			&cli.StringFlag{
+				Name:     "phone",
+				Aliases:  []string{"p"},
+				Usage:    "Phone number in E.164 format, e.g. +14155552671",
+				Required: false,
+			},
+			&cli.IntFlag{
+				Name:     "manager-id",
+				Aliases:  []string{"m"},
+				Usage:    "ID of the user's manager",
+				Required: false,
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			id := cmd.Int("id")
@@ -276,6 +354,8 @@ func UpdateCommand() *cli.Command {
 					Email:      cmd.String("email"),
 					UserStatus: models.UserStatus(cmd.String("status")),
 					Department: cmd.String("department"),
+					Phone:      cmd.String("phone"),
+					ManagerID:  managerID(cmd),
 				},
 			}
 
@@ -286,6 +366,9 @@ func UpdateCommand() *cli.Command {
 			return commonCommandAction(ctx, cmd, func(userService services.UserService, ctx context.Context) error {
 				user, err := userService.UpdateUser(ctx, id, req)
 				if err != nil {
+					if errors.Is(err, services.ErrUserNotFound) {
+						return fmt.Errorf("user %d not found", id)
+					}
 					return fmt.Errorf("error updating user: %w", err)
 				}
 
@@ -300,7 +383,7 @@ func UpdateCommand() *cli.Command {
 func DeleteCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "delete",
-		Usage: "Delete a user by ID",
+		Usage: "Delete a user by ID, after confirming it interactively",
 		Flags: []cli.Flag{
 			&cli.IntFlag{
 				Name:     "id",
@@ -308,6 +391,17 @@ func DeleteCommand() *cli.Command {
 				Usage:    "User ID",
 				Required: true,
 			},
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   "Skip the confirmation prompt, required when stdin isn't a terminal",
+			},
+			&cli.IntFlag{
+				Name:     "reassign-to",
+				Aliases:  []string{"r"},
+				Usage:    "ID of the user taking over the direct reports of the deleted user",
+				Required: false,
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			id := cmd.Int("id")
@@ -315,13 +409,219 @@ func DeleteCommand() *cli.Command {
 				return fmt.Errorf("invalid user ID: must be greater than 0")
 			}
 
+			confirmed := cmd.Bool("yes")
+			if !confirmed && !isTerminal(os.Stdin) {
+				return errors.New("refusing to delete without confirmation: stdin is not a terminal, pass --yes to skip the prompt")
+			}
+
 			return commonCommandAction(ctx, cmd, func(userService services.UserService, ctx context.Context) error {
-				err := userService.DeleteUser(ctx, id)
+				if !confirmed {
+					user, err := userService.GetUser(ctx, id)
+					if err != nil {
+						if errors.Is(err, services.ErrUserNotFound) {
+							return fmt.Errorf("user %d not found", id)
+						}
+						return fmt.Errorf("error getting user: %w", err)
+					}
+
+					confirmed, err = confirmDelete(os.Stdin, os.Stderr, user)
+					if err != nil {
+						return err
+					}
+					if !confirmed {
+						slog.With("user_id", id).Info("User deletion cancelled")
+						return nil
+					}
+				}
+
+				err := userService.DeleteUser(ctx, id, cmd.Int("reassign-to"))
 				if err != nil {
+					if errors.Is(err, services.ErrUserNotFound) {
+						return fmt.Errorf("user %d not found", id)
+					}
+					if errors.Is(err, services.ErrHasReports) {
+						return fmt.Errorf("user %d has direct reports, pass --reassign-to to move them to another manager", id)
+					}
 					return fmt.Errorf("error deleting user: %w", err)
 				}
 
-				slog.With("user_id", id).Info("User updated successfully")
+				slog.With("user_id", id).Info("User deleted successfully")
+				return nil
+			})
+		},
+	}
+}
+
+// ExportCommand returns a CLI command for exporting users to a CSV or JSON file
+func ExportCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "export",
+		Usage: "Export users as CSV or JSON, in a format the import command accepts",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Export format: csv or json",
+				Value: exportCSV,
+				Validator: func(format string) error {
+					if format != exportCSV && format != exportJSON {
+						return fmt.Errorf("invalid format %q: must be one of %s, %s", format, exportCSV, exportJSON)
+					}
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Path of the file to write, stdout when empty",
+			},
+			&cli.StringFlag{
+				Name:  "status",
+				Usage: "Only export the users with this status (A, I or T)",
+			},
+			&cli.StringFlag{
+				Name:  "department",
+				Usage: "Only export the users of this department",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			params := models.ListParams{
+				Status:     models.UserStatus(cmd.String("status")),
+				Department: cmd.String("department"),
+			}
+			if params.Status != "" && !params.Status.IsValid() {
+				return fmt.Errorf("invalid status %q: must be one of A, I, T", params.Status)
+			}
+
+			return commonCommandAction(ctx, cmd, func(userService services.UserService, ctx context.Context) error {
+				out := io.Writer(os.Stdout)
+				var file *os.File
+				if path := cmd.String("file"); path != "" {
+					f, err := os.Create(filepath.Clean(path))
+					if err != nil {
+						return fmt.Errorf("failed to create file: %w", err)
+					}
+					defer f.Close() //nolint:errcheck
+					out, file = f, f
+				}
+
+				enc, err := newUsersEncoder(cmd.String("format"), out)
+				if err != nil {
+					return err
+				}
+
+				count, err := exportUsers(ctx, userService, params, enc)
+				if err != nil {
+					return fmt.Errorf("error exporting users: %w", err)
+				}
+				if file != nil {
+					if err := file.Close(); err != nil {
+						return fmt.Errorf("failed to write file: %w", err)
+					}
+				}
+
+				slog.With("count", count).Info("Users exported successfully")
+				return nil
+			})
+		},
+	}
+}
+
+// DeactivateStaleCommand returns a CLI command for deactivating the users who haven't logged in for a while
+func DeactivateStaleCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "deactivate-stale",
+		Usage: "Mark the active users who haven't logged in for the given number of days as inactive",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "days",
+				Usage: "Inactivity threshold in days",
+				Value: 90,
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Only list the users who would be deactivated",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			days := cmd.Int("days")
+			if days <= 0 {
+				return fmt.Errorf("invalid days: must be greater than 0")
+			}
+
+			return commonCommandAction(ctx, cmd, func(userService services.UserService, ctx context.Context) error {
+				result, err := userService.DeactivateStaleUsers(ctx, time.Duration(days)*24*time.Hour, cmd.Bool("dry-run"))
+				if err != nil {
+					return fmt.Errorf("error deactivating stale users: %w", err)
+				}
+
+				output, err := json.MarshalIndent(result, "", "  ")
+				if err != nil {
+					return fmt.Errorf("error formatting output: %w", err)
+				}
+
+				fmt.Println(string(output))
+				return nil
+			})
+		},
+	}
+}
+
+// ImportCommand returns a CLI command for creating users from a JSON or CSV file
+func ImportCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "import",
+		Usage: "Create users from a JSON or CSV file in a single transaction",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "file",
+				Aliases:  []string{"f"},
+				Usage:    "Path to a .json or .csv file",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "mode",
+				Aliases: []string{"m"},
+				Usage:   "Conflict mode: atomic (a duplicate aborts the import) or ignore (duplicates are skipped)",
+				Value:   string(models.ConflictModeAtomic),
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			mode := models.ConflictMode(cmd.String("mode"))
+			if mode != models.ConflictModeAtomic && mode != models.ConflictModeIgnore {
+				return fmt.Errorf("invalid mode %q: must be one of atomic, ignore", mode)
+			}
+
+			reqs, err := readUsersFile(cmd.String("file"))
+			if err != nil {
+				return err
+			}
+
+			for i, req := range reqs {
+				if problems := validateRecord(req); len(problems) > 0 {
+					return fmt.Errorf("invalid record %d: %s", i, strings.Join(problems, ", "))
+				}
+			}
+
+			return commonCommandAction(ctx, cmd, func(userService services.UserService, ctx context.Context) error {
+				result, err := userService.CreateUsers(ctx, reqs, mode)
+				if err != nil {
+					var dupErr *services.DuplicateUserError
+					if errors.As(err, &dupErr) {
+						return fmt.Errorf("import rolled back, duplicate record: %w", err)
+					}
+					return fmt.Errorf("error importing users: %w", err)
+				}
+
+				for _, skipped := range result.Skipped {
+					slog.With("index", skipped.Index).
+						With("field", skipped.Field).
+						With("value", skipped.Value).
+						Warn("Skipped duplicate user")
+				}
+
+				slog.With("created", len(result.Created)).
+					With("skipped", len(result.Skipped)).
+					Info("Users imported successfully")
 				return nil
 			})
 		},
@@ -339,6 +639,19 @@ func RegisterCommands() *cli.Command {
 			GetCommand(),
 			UpdateCommand(),
 			DeleteCommand(),
+			DeactivateStaleCommand(),
+			ImportCommand(),
+			ExportCommand(),
+			LintCommand(),
 		},
 	}
 }
+
+// managerID returns the --manager-id flag, nil when it isn't set
+func managerID(cmd *cli.Command) *int64 {
+	if !cmd.IsSet("manager-id") {
+		return nil
+	}
+	id := cmd.Int("manager-id")
+	return &id
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"runtime/debug"
 
@@ -9,8 +10,11 @@ import (
 
 	"github.com/urfave/cli/v3"
 
+	configcmd "user-management/cmd/cli/commands/config"
+	"user-management/cmd/cli/commands/connect"
 	"user-management/cmd/cli/commands/db"
 	"user-management/cmd/cli/commands/user"
+	"user-management/internal/config"
 )
 
 const (
@@ -37,17 +41,39 @@ func main() {
 		subCommands = append(subCommands, uc)
 	}
 
+	if cc := configcmd.RegisterCommands(); cc != nil {
+		subCommands = append(subCommands, cc)
+	}
+
 	app := &cli.Command{
 		Name:                   appName,
 		Usage:                  "User management CLI tool",
 		UseShortOptionHandling: true,
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				// not required here, so offline commands (e.g. user lint) run without it,
+				// the commands connecting to the database check it instead
+				Name:    "dsn",
+				Usage:   "Database connection string",
+				Sources: cli.EnvVars("DSN"),
+				Config:  cli.StringConfig{TrimSpace: true},
+			},
 			&cli.StringFlag{
-				Name:     "dsn",
-				Usage:    "Database connection string",
-				Required: true,
-				Sources:  cli.EnvVars("DSN"),
-				Config:   cli.StringConfig{TrimSpace: true},
+				Name:    "driver",
+				Usage:   "Database driver: postgres or mysql",
+				Value:   config.DriverPostgres,
+				Sources: cli.EnvVars("DB_DRIVER"),
+				Validator: func(driver string) error {
+					if driver != config.DriverPostgres && driver != config.DriverMySQL {
+						return fmt.Errorf("invalid driver %q: must be one of %s, %s", driver, config.DriverPostgres, config.DriverMySQL)
+					}
+					return nil
+				},
+			},
+			&cli.BoolFlag{
+				Name:    "auto-create-departments",
+				Usage:   "Create the unknown departments of the created, updated and imported users instead of rejecting them",
+				Sources: cli.EnvVars("USERS_AUTO_CREATE_DEPARTMENTS"),
 			},
 			&cli.BoolFlag{
 				Name:    "verbosity",
@@ -64,7 +90,7 @@ func main() {
 					return nil
 				},
 			},
-		},
+		}, connect.PoolFlags()...),
 		Commands: subCommands,
 	}
 
@@ -11,24 +11,51 @@ import (
 	"user-management/internal/config"
 	"user-management/internal/database"
 	"user-management/internal/handlers"
+	"user-management/internal/metrics"
+	"user-management/internal/outbox"
 	"user-management/internal/repository"
 	"user-management/internal/server"
 	"user-management/internal/services"
+	"user-management/internal/tracing"
 	"user-management/internal/validator"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 )
 
+// stopMargin is the time left to the components stopping after the server, e.g. the database connections
+const stopMargin = 5 * time.Second
+
 //	@title			User Management API
 //	@version		1.0
 //	@description	A simple user management API
 //	@host			localhost:8080
 //	@BasePath		/api/v1
+//
+//	@securityDefinitions.apikey	AdminToken
+//	@in							header
+//	@name						Authorization
+//	@description				"Bearer <admin token>", see --admin-token
+//
+//	@securityDefinitions.apikey	BearerAuth
+//	@in							header
+//	@name						Authorization
+//	@description				"Bearer <JWT>", signed with HS256 (--jwt-secret) or RS256 (--jwt-public-key)
+//
+//	@securityDefinitions.apikey	APIKey
+//	@in							header
+//	@name						X-API-Key
+//	@description				API key whose SHA-256 digest is configured with --api-key-hash, instead of a JWT
 func main() {
+	var cfg *config.Config
 	app := fx.New(
+		fx.Populate(&cfg),
+
 		fx.Provide(
 			config.NewConfig,
 			database.NewConnection,
+			metrics.New,
+			tracing.NewTracerProvider,
 		),
 
 		fx.Provide(
@@ -37,18 +64,58 @@ func main() {
 
 		fx.Provide(
 			services.NewHealthcheck,
-			services.NewUserService,
+			func(cfg *config.Config, repo repository.UserRepository) services.UserService {
+				return services.NewUserService(repo,
+					services.WithAutoCreateDepartments(cfg.Users.AutoCreateDepartments),
+					services.WithOutbox(len(cfg.Webhooks.URLs) > 0),
+				)
+			},
+			services.NewDepartmentService,
+			services.NewTokenService,
 
 			handlers.NewHealthcheckHandler,
 			handlers.NewUserHandler,
+			handlers.NewDepartmentHandler,
+			handlers.NewAuthHandler,
+			handlers.NewGraphQLHandler,
 
 			validator.NewEchoValidator,
 
 			server.NewServer,
 		),
 
+		fx.Decorate(
+			func(cfg *config.Config, tp trace.TracerProvider, repo repository.UserRepository) repository.UserRepository {
+				repo = repository.NewTracedUserRepository(repo, tp.Tracer(tracing.TracerName))
+				// outside the tracing, so every attempt gets its own span
+				return repository.NewRetryingUserRepository(repo, repository.RetryPolicy{
+					MaxRetries: cfg.DB.ReadRetries,
+					Backoff:    cfg.DB.ReadRetryBackoff,
+					MaxBackoff: cfg.DB.ReadRetryMaxBackoff,
+				})
+			},
+			func(cfg *config.Config, m *metrics.Metrics, tp trace.TracerProvider, svc services.UserService) services.UserService {
+				// innermost, so the deadline only bounds the database work, not the cache
+				svc = services.NewTimeoutUserService(svc, cfg.DB.QueryTimeout)
+				svc = services.NewInstrumentedUserService(svc, m)
+				if cfg.Cache.ListTTL > 0 {
+					svc = services.NewCachedUserService(svc, cfg.Cache.ListTTL, cfg.Cache.ListMaxStale)
+				}
+				// outermost, so the spans also cover the lists served from the cache
+				return services.NewTracedUserService(svc, tp.Tracer(tracing.TracerName))
+			},
+			func(cfg *config.Config, svc services.DepartmentService) services.DepartmentService {
+				return services.NewTimeoutDepartmentService(svc, cfg.DB.QueryTimeout)
+			},
+			func(cfg *config.Config, svc services.TokenService) services.TokenService {
+				return services.NewTimeoutTokenService(svc, cfg.DB.QueryTimeout)
+			},
+		),
+
 		fx.Invoke(
 			server.NewRegister,
+			server.RegisterGRPC,
+			outbox.Register,
 		),
 	)
 
@@ -72,8 +139,9 @@ func main() {
 	// Wait for interrupt signal
 	<-app.Done()
 
-	// Create another timeout context for shutdown
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 15*time.Second)
+	// Create another timeout context for shutdown, the server drains its requests for up to the shutdown timeout
+	// and the other components stop once it's done
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout+stopMargin)
 	defer shutdownCancel()
 
 	if err := app.Stop(shutdownCtx); err != nil {
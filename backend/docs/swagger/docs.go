@@ -16,45 +16,141 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/users": {
+        "/../../healthz": {
             "get": {
-                "description": "get all users",
-                "consumes": [
+                "description": "answer 200 while the process serves requests, whatever the state of the database. Served at /healthz, outside of the API base path.",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ProbeStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/../../ping": {
+            "get": {
+                "description": "answer pong. Served at /ping, outside of the API base path.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Ping the server",
+                "responses": {
+                    "200": {
+                        "description": "pong",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/../../readyz": {
+            "get": {
+                "description": "answer 503 while the database can't be pinged within 2 seconds or has migrations to apply. Served at /readyz, outside of the API base path.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "List all users",
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/User"
-                            }
+                            "$ref": "#/definitions/ProbeStatus"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ProbeStatus"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/../../status": {
+            "get": {
+                "description": "get the memory usage, goroutine count, GC and heap stats, uptime, database status, database clock skew and whether the migrations are up to date. Served at /status, outside of the API base path.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Report the API status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/HealthStatus"
+                        }
+                    },
+                    "503": {
+                        "description": "the database is down, with the same report",
+                        "schema": {
+                            "$ref": "#/definitions/HealthStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/../../version": {
+            "get": {
+                "description": "get the version, revision, build time and Go version of the running binary. Served at /version, outside of the API base path.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Report the build info",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/VersionInfo"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tokens": {
             "post": {
-                "description": "create a new user",
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "create a refresh token for the subject and roles of the access tokens it's exchanged for. Only its digest is stored,\nthe token is returned once. Exposed with an admin token and a JWT secret, until there's a login.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Create a user",
+                "summary": "Issue a refresh token",
                 "parameters": [
                     {
-                        "description": "User Data",
-                        "name": "user",
+                        "description": "Subject and roles",
+                        "name": "token",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/UserCreateRequest"
+                            "$ref": "#/definitions/RefreshTokenIssueRequest"
                         }
                     }
                 ],
@@ -62,91 +158,170 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/User"
+                            "$ref": "#/definitions/RefreshTokenIssueResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "422": {
                         "description": "Unprocessable Entity",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/users/{id}": {
-            "get": {
-                "description": "get user by ID",
+        "/admin/users/deactivate-stale": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "mark the active users who haven't logged in for the given number of days as inactive (I), in a single transaction.\nUsers who never logged in are judged by their creation time. On a dry run the users are only reported.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get a user",
+                "summary": "Deactivate stale users",
                 "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 90,
+                        "description": "Inactivity threshold in days",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only report the affected users",
+                        "name": "X-Dry-Run",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Same as the X-Dry-Run header",
+                        "name": "dryRun",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "User ID (int64)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Who makes the change, recorded in the audit log of the users (default system), ignored on an authenticated request",
+                        "name": "X-Actor",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/User"
+                            "$ref": "#/definitions/UserDeactivateStaleResult"
+                        },
+                        "headers": {
+                            "X-Dry-Run": {
+                                "type": "string",
+                                "description": "true on a dry run"
+                            },
+                            "X-Resource-Action": {
+                                "type": "string",
+                                "description": "updated"
+                            },
+                            "X-Server-Time": {
+                                "type": "string",
+                                "description": "Server time (RFC 3339)"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "update a user by ID",
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "description": "revoke the refresh token, which can't be exchanged for access tokens anymore. Revoking it again is a no-op.",
+                "consumes": [
+                    "application/json"
+                ],
+                "summary": "Log out",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "exchange a valid refresh token for a new access token. The refresh token stays valid until it expires or is revoked.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Update a user",
+                "summary": "Refresh an access token",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID (int64)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "User Data",
-                        "name": "user",
+                        "description": "Refresh token",
+                        "name": "token",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/UserUpdateRequest"
+                            "$ref": "#/definitions/RefreshTokenRequest"
                         }
                     }
                 ],
@@ -154,74 +329,1756 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/User"
+                            "$ref": "#/definitions/AccessTokenResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "422": {
                         "description": "Unprocessable Entity",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/departments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "get every department, ordered by name. The department of a user has to be one of them.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "List the departments",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Department"
                             }
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     }
                 }
             },
-            "delete": {
-                "description": "delete a user by ID",
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "create a department the users can then belong to",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Delete a user",
+                "summary": "Create a department",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID (int64)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Department Data",
+                        "name": "department",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/DepartmentCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Department"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "get a page of users, optionally searching by a case-insensitive substring of the user name, first name, last name or email.\nWith sort=relevance search results are ranked: exact user name or email match first,\nthen user names starting with the term, then first/last names or emails starting with it, then other matches.\nEvery invalid parameter is reported at once in the 400 response.\nPages in the default user_id order can also be followed with the nextCursor of the response, which doesn't skip\nor repeat users when users are added or deleted between pages, unlike offset.\nWith Accept: application/x-ndjson the users are streamed one JSON object per line instead, for exports:\nevery matching user unless a limit is given, without the total nor ETag.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.api+json",
+                    "application/x-ndjson"
+                ],
+                "summary": "List all users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search term",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "user_id",
+                            "created_at",
+                            "last_name",
+                            "user_name",
+                            "relevance"
+                        ],
+                        "type": "string",
+                        "default": "user_id",
+                        "description": "Sort field",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Sort direction, ignored by relevance",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "A",
+                            "I",
+                            "T"
+                        ],
+                        "type": "string",
+                        "description": "User status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Department (exact match)",
+                        "name": "department",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Department substring (case-insensitive), not combinable with department",
+                        "name": "department_like",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Only the users created at or after this RFC 3339 time",
+                        "name": "createdAfter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Only the users created before this RFC 3339 time",
+                        "name": "createdBefore",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Only the users last updated at or after this RFC 3339 time",
+                        "name": "updatedAfter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Only the users last updated before this RFC 3339 time",
+                        "name": "updatedBefore",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 500,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Users to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page, for keyset pagination in the default order, not combinable with offset",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the page the client has, answered with 304 while it's unchanged",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UserListResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Weak validator of the page"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified",
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Weak validator of the page"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/InvalidParamsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "create a new user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.api+json"
+                ],
+                "summary": "Create a user",
+                "parameters": [
+                    {
+                        "description": "User Data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UserCreateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who makes the change, recorded as the createdBy/updatedBy of the users (default system), ignored on an authenticated request",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Run every check and return the would-be result without persisting anything",
+                        "name": "X-Dry-Run",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Same as the X-Dry-Run header",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run, nothing was persisted",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        },
+                        "headers": {
+                            "X-Dry-Run": {
+                                "type": "string",
+                                "description": "true on a dry run"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        },
+                        "headers": {
+                            "X-Resource-Action": {
+                                "type": "string",
+                                "description": "created"
+                            },
+                            "X-Server-Time": {
+                                "type": "string",
+                                "description": "Server time (RFC 3339)"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/availability": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "report whether the email and the user name are still free, e.g. for a form to warn before submitting.\nAt least one of them is required, only the given ones are checked. Rate limited more strictly than the other endpoints.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Check the availability of an email and a user name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User name",
+                        "name": "username",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UserAvailabilityResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/batch": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "update several users in a single transaction, any failing item rolls back the whole batch.\nItems are applied in order, so an item may take over a user name or email released by an earlier item,\nbut two items can't claim the same user, user name or email (409).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update users in batch",
+                "parameters": [
+                    {
+                        "description": "Users Data",
+                        "name": "users",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/UserBatchUpdateItem"
+                            }
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who makes the change, recorded as the createdBy/updatedBy of the users (default system), ignored on an authenticated request",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Run every check and return the would-be result without persisting anything",
+                        "name": "X-Dry-Run",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Same as the X-Dry-Run header",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UserBatchUpdateResult"
+                        },
+                        "headers": {
+                            "X-Dry-Run": {
+                                "type": "string",
+                                "description": "true on a dry run"
+                            },
+                            "X-Resource-Action": {
+                                "type": "string",
+                                "description": "updated"
+                            },
+                            "X-Server-Time": {
+                                "type": "string",
+                                "description": "Server time (RFC 3339)"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "create several users in a single transaction.\nIn \"atomic\" mode (default) a duplicate rolls back the whole batch and is reported with 409,\nin \"ignore\" mode duplicates are skipped and listed in the response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Create users in batch",
+                "parameters": [
+                    {
+                        "enum": [
+                            "atomic",
+                            "ignore"
+                        ],
+                        "type": "string",
+                        "default": "atomic",
+                        "description": "Conflict mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Users Data",
+                        "name": "users",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/UserCreateRequest"
+                            }
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who makes the change, recorded as the createdBy/updatedBy of the users (default system), ignored on an authenticated request",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Run every check and return the would-be result without persisting anything",
+                        "name": "X-Dry-Run",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Same as the X-Dry-Run header",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run, nothing was persisted",
+                        "schema": {
+                            "$ref": "#/definitions/UserBatchCreateResult"
+                        },
+                        "headers": {
+                            "X-Dry-Run": {
+                                "type": "string",
+                                "description": "true on a dry run"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/UserBatchCreateResult"
+                        },
+                        "headers": {
+                            "X-Resource-Action": {
+                                "type": "string",
+                                "description": "created"
+                            },
+                            "X-Server-Time": {
+                                "type": "string",
+                                "description": "Server time (RFC 3339)"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/count": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "count the users matching the same search and filters as the list, in total and per status.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Count users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search term",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "A",
+                            "I",
+                            "T"
+                        ],
+                        "type": "string",
+                        "description": "User status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Department (exact match)",
+                        "name": "department",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Department substring (case-insensitive), not combinable with department",
+                        "name": "department_like",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Only the users created at or after this RFC 3339 time",
+                        "name": "createdAfter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Only the users created before this RFC 3339 time",
+                        "name": "createdBefore",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Only the users last updated at or after this RFC 3339 time",
+                        "name": "updatedAfter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Only the users last updated before this RFC 3339 time",
+                        "name": "updatedBefore",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UserCountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/InvalidParamsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/status": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "set the status of up to 500 users in a single transaction, e.g. T to offboard a team.\nThe users already in the status are left untouched, the IDs no user has are listed rather than rejected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Set the status of several users",
+                "parameters": [
+                    {
+                        "description": "IDs and status",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UserStatusUpdateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who makes the change, recorded as the updatedBy of the users (default system), ignored on an authenticated request",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Run every check and return the would-be result without persisting anything",
+                        "name": "X-Dry-Run",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Same as the X-Dry-Run header",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UserStatusUpdateResult"
+                        },
+                        "headers": {
+                            "X-Dry-Run": {
+                                "type": "string",
+                                "description": "true on a dry run"
+                            },
+                            "X-Resource-Action": {
+                                "type": "string",
+                                "description": "updated"
+                            },
+                            "X-Server-Time": {
+                                "type": "string",
+                                "description": "Server time (RFC 3339)"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "get user by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.api+json"
+                ],
+                "summary": "Get a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (int64)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the user the client has, answered with 304 while it's unchanged",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified time of the user the client has, answered with 304 while it's unchanged, ignored along with If-None-Match",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Weak validator of the user, changing with its version and update time"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Update time of the user, for If-Modified-Since and If-Unmodified-Since"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified",
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Weak validator of the user, changing with its version and update time"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Update time of the user, for If-Modified-Since and If-Unmodified-Since"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "update a user by ID. Send the version read with the user (If-Match header or version field)\nto get a 409 instead of overwriting a concurrent change, or its Last-Modified time (If-Unmodified-Since header)\nto get a 412.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.api+json"
+                ],
+                "summary": "Update a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (int64)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User Data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UserUpdateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Version the update is based on, takes precedence over the body version",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified time the update is based on, ignored along with If-Match",
+                        "name": "If-Unmodified-Since",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who makes the change, recorded as the createdBy/updatedBy of the users (default system), ignored on an authenticated request",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Run every check and return the would-be result without persisting anything",
+                        "name": "X-Dry-Run",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Same as the X-Dry-Run header",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        },
+                        "headers": {
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Update time of the user"
+                            },
+                            "X-Dry-Run": {
+                                "type": "string",
+                                "description": "true on a dry run"
+                            },
+                            "X-Resource-Action": {
+                                "type": "string",
+                                "description": "updated"
+                            },
+                            "X-Server-Time": {
+                                "type": "string",
+                                "description": "Server time (RFC 3339)"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "The user was updated since If-Unmodified-Since",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "delete a user by ID. The response body confirms the deletion,\nsend \"Prefer: return=minimal\" to get an empty body instead.\nA user who still manages other users can only be deleted along with reassignTo, their new manager.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (int64)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "return=minimal to omit the response body",
+                        "name": "Prefer",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who makes the change, recorded in the audit log (default system), ignored on an authenticated request",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Run every check and return the would-be result without persisting anything",
+                        "name": "X-Dry-Run",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Same as the X-Dry-Run header",
+                        "name": "dryRun",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the user taking over the direct reports",
+                        "name": "reassignTo",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run, nothing was persisted",
+                        "schema": {
+                            "$ref": "#/definitions/UserDeleteResponse"
+                        },
+                        "headers": {
+                            "X-Dry-Run": {
+                                "type": "string",
+                                "description": "true on a dry run"
+                            }
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/UserDeleteResponse"
+                        },
+                        "headers": {
+                            "X-Resource-Action": {
+                                "type": "string",
+                                "description": "deleted"
+                            },
+                            "X-Server-Time": {
+                                "type": "string",
+                                "description": "Server time (RFC 3339)"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The user has direct reports and no reassignTo",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "reassignTo doesn't exist or is below the user",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "check whether a user ID exists without transferring the user, the responses have no body",
+                "summary": "Check that a user exists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (int64)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The user exists"
+                    },
+                    "400": {
+                        "description": "Invalid user ID"
+                    },
+                    "401": {
+                        "description": "Invalid or missing bearer token or API key"
+                    },
+                    "404": {
+                        "description": "The user doesn't exist"
+                    },
+                    "500": {
+                        "description": "Internal error"
+                    }
+                }
+            }
+        },
+        "/users/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "get the audit log entries of a user, newest first. The history of a deleted user is kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the history of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (int64)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/AuditEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/reports": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKey": []
+                    }
+                ],
+                "description": "get the users the user directly manages, ordered by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the direct reports of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (int64)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/User"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "AccessTokenResponse": {
+            "type": "object",
+            "properties": {
+                "accessToken": {
+                    "description": "JWT signed with HS256, sent as \"Authorization: Bearer \u003caccessToken\u003e\"",
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "expiresIn": {
+                    "description": "Lifetime of the access token in seconds",
+                    "type": "integer",
+                    "example": 900
+                },
+                "tokenType": {
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "AuditChange": {
+            "type": "object",
+            "properties": {
+                "new": {
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "old": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                }
+            }
+        },
+        "AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "enum": [
+                        "create",
+                        "update",
+                        "delete"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/user-management_internal_models.AuditAction"
+                        }
+                    ],
+                    "example": "update"
+                },
+                "actor": {
+                    "description": "Who made the change",
+                    "type": "string",
+                    "example": "jane.admin"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2025-03-27T10:23:51.495798-05:00"
+                },
+                "diff": {
+                    "description": "Changed fields by their JSON key",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/AuditChange"
+                    }
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "userId": {
+                    "description": "ID of the changed user, kept once the user is deleted",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "Department": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2025-03-27T10:23:51.495798-05:00"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "Name, unique among the departments",
+                    "type": "string",
+                    "example": "Engineering"
+                }
+            }
+        },
+        "DepartmentCreateRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "Name of the department, the value of the users' department field\n\t@maxLength\t255\n\t@example\tEngineering",
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Engineering"
+                }
+            }
+        },
+        "ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Machine readable code of the error, the snake_case text of the HTTP status",
+                    "type": "string",
+                    "example": "not_found"
+                },
+                "errors": {
+                    "description": "Every rejected field at once, set on the validation errors and the errors of a batch item",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/FieldError"
+                    }
+                },
+                "message": {
+                    "description": "English sentence describing the error",
+                    "type": "string",
+                    "example": "user not found"
+                },
+                "requestId": {
+                    "description": "ID of the request, as in the X-Request-ID header, added to every error body by the server",
+                    "type": "string",
+                    "example": "rrpSsGO3dQxyEtTXzbpNwzHbnwkfyRtn"
+                }
+            }
+        },
+        "FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "JSON key of the field, prefixed by the item position for the batch endpoints",
+                    "type": "string",
+                    "example": "email"
+                },
+                "message": {
+                    "description": "English sentence describing the failure",
+                    "type": "string",
+                    "example": "email must be a valid email address"
+                },
+                "tag": {
+                    "description": "Validation rule the field failed",
+                    "type": "string",
+                    "example": "email"
+                }
+            }
+        },
+        "HealthStatus": {
+            "type": "object",
+            "properties": {
+                "clock_skew": {
+                    "description": "Drift of the database clock from the app clock",
+                    "type": "string",
+                    "example": "120ms"
+                },
+                "clock_status": {
+                    "description": "DEGRADED once the clock skew exceeds the configured threshold",
+                    "type": "string",
+                    "enum": [
+                        "OK",
+                        "DEGRADED",
+                        "FAIL"
+                    ],
+                    "example": "OK"
+                },
+                "db_status": {
+                    "description": "Whether the database can be reached",
+                    "type": "string",
+                    "enum": [
+                        "OK",
+                        "FAIL"
+                    ],
+                    "example": "OK"
+                },
+                "heap_inuse": {
+                    "description": "Memory of the heap spans in use",
+                    "type": "string",
+                    "example": "14 MiB"
+                },
+                "mem_usage": {
+                    "description": "Memory allocated by the process",
+                    "type": "string",
+                    "example": "12 MiB"
+                },
+                "migrations": {
+                    "description": "Whether every migration is applied to the database, unknown when it can't be told, e.g. on a database never migrated",
+                    "type": "string",
+                    "enum": [
+                        "up_to_date",
+                        "pending",
+                        "unknown"
+                    ],
+                    "example": "up_to_date"
+                },
+                "num_gc": {
+                    "description": "Number of completed GC cycles",
+                    "type": "integer",
+                    "example": 57
+                },
+                "num_goroutine": {
+                    "description": "Number of live goroutines",
+                    "type": "integer",
+                    "example": 24
+                },
+                "online_t": {
+                    "description": "Uptime of the process",
+                    "type": "string",
+                    "example": "1h2m3s"
+                },
+                "pending_migrations": {
+                    "description": "Number of migrations not applied yet",
+                    "type": "integer",
+                    "example": 0
+                },
+                "sys": {
+                    "description": "Memory obtained from the OS",
+                    "type": "string",
+                    "example": "31 MiB"
+                }
+            }
+        },
+        "InvalidParam": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "limit"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "must be a positive integer"
+                }
+            }
+        },
+        "InvalidParamsResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Machine readable code of the error, the snake_case text of the HTTP status",
+                    "type": "string",
+                    "example": "not_found"
+                },
+                "errors": {
+                    "description": "Every rejected field at once, set on the validation errors and the errors of a batch item",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/FieldError"
+                    }
+                },
+                "invalidParams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/InvalidParam"
+                    }
+                },
+                "message": {
+                    "description": "English sentence describing the error",
+                    "type": "string",
+                    "example": "user not found"
+                },
+                "requestId": {
+                    "description": "ID of the request, as in the X-Request-ID header, added to every error body by the server",
+                    "type": "string",
+                    "example": "rrpSsGO3dQxyEtTXzbpNwzHbnwkfyRtn"
+                }
+            }
+        },
+        "ProbeStatus": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "OK",
+                        "FAIL"
+                    ],
+                    "example": "OK"
+                }
+            }
+        },
+        "RefreshTokenIssueRequest": {
+            "type": "object",
+            "required": [
+                "roles",
+                "subject"
+            ],
+            "properties": {
+                "roles": {
+                    "description": "Roles of the access tokens",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    }
+                    "example": [
+                        "admin"
+                    ]
+                },
+                "subject": {
+                    "description": "Subject of the access tokens, e.g. the user name",
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "jane"
                 }
             }
-        }
-    },
-    "definitions": {
+        },
+        "RefreshTokenIssueResponse": {
+            "type": "object",
+            "properties": {
+                "refreshToken": {
+                    "type": "string",
+                    "example": "3q2-7wX9yF0c8kQ1n5vLzT4mH6pA2sDgE0rUbJiKoNw"
+                }
+            }
+        },
+        "RefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refreshToken"
+            ],
+            "properties": {
+                "refreshToken": {
+                    "type": "string",
+                    "example": "3q2-7wX9yF0c8kQ1n5vLzT4mH6pA2sDgE0rUbJiKoNw"
+                }
+            }
+        },
         "User": {
             "type": "object",
             "required": [
@@ -237,6 +2094,12 @@ const docTemplate = `{
                     "format": "date-time",
                     "example": "2025-03-27T10:23:51.495798-05:00"
                 },
+                "createdBy": {
+                    "description": "Who created the user, \"system\" for the changes made without an actor (e.g. by the CLI)",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "jane.admin"
+                },
                 "department": {
                     "description": "Department\n\t@maxLength\t255\n\t@example\tEngineering",
                     "type": "string",
@@ -250,6 +2113,13 @@ const docTemplate = `{
                     "maxLength": 255,
                     "example": "john.doe@example.com"
                 },
+                "emailUpdatedAt": {
+                    "description": "When the email last changed, omitted if it never changed since the creation",
+                    "type": "string",
+                    "format": "date-time",
+                    "readOnly": true,
+                    "example": "2025-03-28T08:12:03.120412-05:00"
+                },
                 "firstName": {
                     "description": "First name\n\t@minLength\t1\n\t@maxLength\t255\n\t@pattern\t^[\\p{L}\\p{N}]+$\n\t@example\tJohn",
                     "type": "string",
@@ -261,6 +2131,13 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 1
                 },
+                "lastLoginAt": {
+                    "description": "When the user last logged in, omitted if they never did",
+                    "type": "string",
+                    "format": "date-time",
+                    "readOnly": true,
+                    "example": "2025-03-28T08:12:03.120412-05:00"
+                },
                 "lastName": {
                     "description": "Last name\n\t@minLength\t1\n\t@maxLength\t255\n\t@pattern\t^[\\p{L}\\p{N}]+$\n\t@example\tDoe",
                     "type": "string",
@@ -268,11 +2145,175 @@ const docTemplate = `{
                     "minLength": 1,
                     "example": "Doe"
                 },
+                "manager": {
+                    "description": "The user's manager, only loaded by GET /users/{id}, without their own manager",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/User"
+                        }
+                    ],
+                    "readOnly": true
+                },
+                "managerId": {
+                    "description": "ID of the user's manager, omitted when they have none\n\t@minimum\t1\n\t@example\t2",
+                    "type": "integer",
+                    "example": 2
+                },
+                "phone": {
+                    "description": "Contact phone number in E.164 format\n\t@maxLength\t16\n\t@pattern\t^\\+[1-9]?[0-9]{7,14}$\n\t@example\t+14155552671",
+                    "type": "string",
+                    "example": "+14155552671"
+                },
+                "statusUpdatedAt": {
+                    "description": "When the status last changed, omitted if it never changed since the creation",
+                    "type": "string",
+                    "format": "date-time",
+                    "readOnly": true,
+                    "example": "2025-03-28T08:12:03.120412-05:00"
+                },
                 "updatedAt": {
                     "type": "string",
                     "format": "date-time",
                     "example": "2025-03-27T10:23:51.495798-05:00"
                 },
+                "updatedBy": {
+                    "description": "Who last changed the user",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "jane.admin"
+                },
+                "userName": {
+                    "description": "The username\n\t@minLength\t4\n\t@maxLength\t255\n\t@pattern\t^[a-zA-Z0-9]+$\n\t@example\tjohndoe",
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 4,
+                    "example": "johndoe"
+                },
+                "userStatus": {
+                    "description": "User Status\n\t@enum\t\tA,I,T\n\t@example\tA",
+                    "enum": [
+                        "A",
+                        "I",
+                        "T"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/UserStatus"
+                        }
+                    ],
+                    "example": "A"
+                },
+                "version": {
+                    "description": "Incremented by every update, send it back (as If-Match or in the body) to detect concurrent changes",
+                    "type": "integer",
+                    "readOnly": true,
+                    "example": 1
+                }
+            }
+        },
+        "UserAvailabilityResponse": {
+            "type": "object",
+            "properties": {
+                "emailAvailable": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "usernameAvailable": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "UserBatchCreateResult": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/User"
+                    }
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/UserBatchSkipped"
+                    }
+                }
+            }
+        },
+        "UserBatchSkipped": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Field that collided with an existing user",
+                    "type": "string",
+                    "example": "email"
+                },
+                "index": {
+                    "description": "Position of the item in the request",
+                    "type": "integer",
+                    "example": 2
+                },
+                "value": {
+                    "description": "Value that collided with an existing user",
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                }
+            }
+        },
+        "UserBatchUpdateItem": {
+            "type": "object",
+            "required": [
+                "email",
+                "firstName",
+                "id",
+                "lastName",
+                "userName",
+                "userStatus"
+            ],
+            "properties": {
+                "department": {
+                    "description": "Department\n\t@maxLength\t255\n\t@example\tEngineering",
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Engineering"
+                },
+                "email": {
+                    "description": "Email address\n\t@maxLength\t255\n\t@format\t\temail\n\t@example\tjohn.doe@example.com",
+                    "type": "string",
+                    "format": "email",
+                    "maxLength": 255,
+                    "example": "john.doe@example.com"
+                },
+                "firstName": {
+                    "description": "First name\n\t@minLength\t1\n\t@maxLength\t255\n\t@pattern\t^[\\p{L}\\p{N}]+$\n\t@example\tJohn",
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1,
+                    "example": "John"
+                },
+                "id": {
+                    "description": "ID of the user to update",
+                    "type": "integer",
+                    "example": 1
+                },
+                "lastName": {
+                    "description": "Last name\n\t@minLength\t1\n\t@maxLength\t255\n\t@pattern\t^[\\p{L}\\p{N}]+$\n\t@example\tDoe",
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1,
+                    "example": "Doe"
+                },
+                "managerId": {
+                    "description": "ID of the user's manager, omitted when they have none\n\t@minimum\t1\n\t@example\t2",
+                    "type": "integer",
+                    "example": 2
+                },
+                "phone": {
+                    "description": "Contact phone number in E.164 format\n\t@maxLength\t16\n\t@pattern\t^\\+[1-9]?[0-9]{7,14}$\n\t@example\t+14155552671",
+                    "type": "string",
+                    "example": "+14155552671"
+                },
                 "userName": {
                     "description": "The username\n\t@minLength\t4\n\t@maxLength\t255\n\t@pattern\t^[a-zA-Z0-9]+$\n\t@example\tjohndoe",
                     "type": "string",
@@ -296,6 +2337,35 @@ const docTemplate = `{
                 }
             }
         },
+        "UserBatchUpdateResult": {
+            "type": "object",
+            "properties": {
+                "updated": {
+                    "description": "Updated users, in the order of the request items",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/User"
+                    }
+                }
+            }
+        },
+        "UserCountResponse": {
+            "type": "object",
+            "properties": {
+                "byStatus": {
+                    "description": "Number of matching users per status, every status is listed",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "total": {
+                    "description": "Number of users matching the criteria",
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
         "UserCreateRequest": {
             "type": "object",
             "required": [
@@ -333,6 +2403,24 @@ const docTemplate = `{
                     "minLength": 1,
                     "example": "Doe"
                 },
+                "managerId": {
+                    "description": "ID of the user's manager, omitted when they have none\n\t@minimum\t1\n\t@example\t2",
+                    "type": "integer",
+                    "example": 2
+                },
+                "password": {
+                    "description": "Password, write-only: only its bcrypt hash is stored and it's never returned\n\t@minLength\t8\n\t@maxLength\t72\n\t@format\t\tpassword",
+                    "type": "string",
+                    "format": "password",
+                    "maxLength": 72,
+                    "minLength": 8,
+                    "example": "correct-horse-battery"
+                },
+                "phone": {
+                    "description": "Contact phone number in E.164 format\n\t@maxLength\t16\n\t@pattern\t^\\+[1-9]?[0-9]{7,14}$\n\t@example\t+14155552671",
+                    "type": "string",
+                    "example": "+14155552671"
+                },
                 "userName": {
                     "description": "The username\n\t@minLength\t4\n\t@maxLength\t255\n\t@pattern\t^[a-zA-Z0-9]+$\n\t@example\tjohndoe",
                     "type": "string",
@@ -356,6 +2444,71 @@ const docTemplate = `{
                 }
             }
         },
+        "UserDeactivateStaleResult": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Number of affected users",
+                    "type": "integer",
+                    "example": 1
+                },
+                "dryRun": {
+                    "description": "Whether the users were only reported, not deactivated",
+                    "type": "boolean",
+                    "example": false
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/User"
+                    }
+                }
+            }
+        },
+        "UserDeleteResponse": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "UserListResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "description": "Applied page size",
+                    "type": "integer",
+                    "example": 50
+                },
+                "nextCursor": {
+                    "description": "Cursor of the next page (the cursor query parameter), set when the page is full and ordered by user_id ascending",
+                    "type": "string",
+                    "example": "NTA"
+                },
+                "offset": {
+                    "description": "Applied number of skipped users",
+                    "type": "integer",
+                    "example": 0
+                },
+                "total": {
+                    "description": "Total number of users matching the criteria, regardless of the page",
+                    "type": "integer",
+                    "example": 120
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/User"
+                    }
+                }
+            }
+        },
         "UserStatus": {
             "type": "string",
             "enum": [
@@ -369,6 +2522,57 @@ const docTemplate = `{
                 "UserStatusTerminated"
             ]
         },
+        "UserStatusUpdateRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "ids": {
+                    "description": "IDs of the users to update",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
+                },
+                "status": {
+                    "description": "Status set on every user\n\t@enum\t\tA,I,T\n\t@example\tT",
+                    "enum": [
+                        "A",
+                        "I",
+                        "T"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/UserStatus"
+                        }
+                    ],
+                    "example": "T"
+                }
+            }
+        },
+        "UserStatusUpdateResult": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Number of users whose status changed, the users already in the status are left untouched",
+                    "type": "integer",
+                    "example": 2
+                },
+                "notFound": {
+                    "description": "Requested IDs no user has",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
         "UserUpdateRequest": {
             "type": "object",
             "required": [
@@ -406,6 +2610,16 @@ const docTemplate = `{
                     "minLength": 1,
                     "example": "Doe"
                 },
+                "managerId": {
+                    "description": "ID of the user's manager, omitted when they have none\n\t@minimum\t1\n\t@example\t2",
+                    "type": "integer",
+                    "example": 2
+                },
+                "phone": {
+                    "description": "Contact phone number in E.164 format\n\t@maxLength\t16\n\t@pattern\t^\\+[1-9]?[0-9]{7,14}$\n\t@example\t+14155552671",
+                    "type": "string",
+                    "example": "+14155552671"
+                },
                 "userName": {
                     "description": "The username\n\t@minLength\t4\n\t@maxLength\t255\n\t@pattern\t^[a-zA-Z0-9]+$\n\t@example\tjohndoe",
                     "type": "string",
@@ -426,8 +2640,67 @@ const docTemplate = `{
                         }
                     ],
                     "example": "A"
+                },
+                "version": {
+                    "description": "Version the update is based on, a stale version is rejected with 409. Omit it to overwrite unconditionally",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "VersionInfo": {
+            "type": "object",
+            "properties": {
+                "buildTime": {
+                    "type": "string",
+                    "example": "2025-04-15T12:00:00Z"
+                },
+                "goVersion": {
+                    "type": "string",
+                    "example": "go1.24.1"
+                },
+                "revision": {
+                    "type": "string",
+                    "example": "8f3c2a1"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "v1.2.0"
                 }
             }
+        },
+        "user-management_internal_models.AuditAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "delete"
+            ],
+            "x-enum-varnames": [
+                "AuditActionCreate",
+                "AuditActionUpdate",
+                "AuditActionDelete"
+            ]
+        }
+    },
+    "securityDefinitions": {
+        "APIKey": {
+            "description": "API key whose SHA-256 digest is configured with --api-key-hash, instead of a JWT",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "AdminToken": {
+            "description": "\"Bearer \u003cadmin token\u003e\", see --admin-token",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "\"Bearer \u003cJWT\u003e\", signed with HS256 (--jwt-secret) or RS256 (--jwt-public-key)",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -188,16 +188,19 @@ func TestUserCreationE2E(t *testing.T) {
 		body, err := io.ReadAll(resp.Body)
 		require.NoError(t, err)
 
-		var users []models.User
-		err = json.Unmarshal(body, &users)
+		var list models.UserListResponse
+		err = json.Unmarshal(body, &list)
 		require.NoError(t, err)
 
 		// Verify we got at least the user we created
-		assert.NotEmpty(t, users, "Expected at least one user")
+		assert.NotEmpty(t, list.Users, "Expected at least one user")
+		assert.Equal(t, models.DefaultListLimit, list.Limit)
+		assert.Equal(t, 0, list.Offset)
+		assert.GreaterOrEqual(t, list.Total, len(list.Users))
 
 		// Check if our created user is in the list
 		var foundUser bool
-		for _, user := range users {
+		for _, user := range list.Users {
 			if user.UserName == "johndoe" {
 				foundUser = true
 				break
@@ -341,6 +344,13 @@ func TestUserCreationE2E(t *testing.T) {
 
 		// Expected status code based on your handler
 		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+		assert.Equal(t, "deleted", resp.Header.Get("X-Resource-Action"))
+
+		var deleted models.UserDeleteResponse
+		err = json.NewDecoder(resp.Body).Decode(&deleted)
+		require.NoError(t, err)
+		assert.True(t, deleted.Deleted)
+		assert.Equal(t, user.UserID, deleted.UserID)
 
 		// Verify user was deleted in the database using bun count
 		count, err := db.NewSelect().
@@ -0,0 +1,125 @@
+package e2e_test
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+
+	"user-management/internal/models"
+	"user-management/internal/repository"
+	"user-management/internal/services"
+)
+
+// TestRowLockingE2E checks that GetByIDForUpdate serializes concurrent updates of the same user,
+// it requires to run test with docker-compose up
+func TestRowLockingE2E(t *testing.T) {
+	if isFeatureDisabled("E2E_ENABLE") {
+		t.Skip("skipping integration test")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultWaitTimeout)
+	t.Cleanup(cancel)
+
+	db := bun.NewDB(sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(dsn))), pgdialect.New())
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	repo := repository.NewUserRepository(db)
+
+	user := &models.User{
+		UserCommon: models.UserCommon{
+			UserName:   "lockeduser",
+			FirstName:  "Locked",
+			LastName:   "User",
+			Email:      "locked.user@example.com",
+			UserStatus: models.UserStatusActive,
+		},
+	}
+	require.NoError(t, repo.Create(ctx, user))
+	t.Cleanup(func() {
+		_ = repo.Delete(context.Background(), user.UserID)
+	})
+
+	t.Run("SecondLockWaitsForFirstTransaction", func(t *testing.T) {
+		locked := make(chan struct{})
+		release := make(chan struct{})
+		firstDone := make(chan error, 1)
+		go func() {
+			firstDone <- repo.RunInTx(ctx, func(ctx context.Context, tx repository.UserRepository) error {
+				if _, err := tx.GetByIDForUpdate(ctx, user.UserID); err != nil {
+					close(locked)
+					return err
+				}
+				close(locked)
+				<-release
+				return nil
+			})
+		}()
+		<-locked
+
+		secondDone := make(chan error, 1)
+		go func() {
+			secondDone <- repo.RunInTx(ctx, func(ctx context.Context, tx repository.UserRepository) error {
+				_, err := tx.GetByIDForUpdate(ctx, user.UserID)
+				return err
+			})
+		}()
+
+		select {
+		case err := <-secondDone:
+			t.Fatalf("second lock acquired while the first transaction holds it: %v", err)
+		case <-time.After(300 * time.Millisecond):
+		}
+
+		close(release)
+		require.NoError(t, <-firstDone)
+		require.NoError(t, <-secondDone)
+	})
+
+	t.Run("ConcurrentUpdatesAreApplied", func(t *testing.T) {
+		svc := services.NewUserService(repo)
+
+		departments := []string{"Sales", "Support", "Marketing", "Finance"}
+
+		var wg sync.WaitGroup
+		errs := make([]error, len(departments))
+		for i, department := range departments {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[i] = svc.UpdateUser(ctx, user.UserID, models.UserUpdateRequest{
+					UserCommon: models.UserCommon{
+						UserName:   user.UserName,
+						FirstName:  user.FirstName,
+						LastName:   user.LastName,
+						Email:      user.Email,
+						UserStatus: models.UserStatusInactive,
+						Department: department,
+					},
+				})
+			}()
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			assert.NoError(t, err)
+		}
+
+		stored, err := repo.GetByID(ctx, user.UserID)
+		require.NoError(t, err)
+		assert.Contains(t, departments, stored.Department)
+		assert.Equal(t, models.UserStatusInactive, stored.UserStatus)
+		// only the first update changed the status, the others must have seen it under the lock
+		require.NotNil(t, stored.StatusUpdatedAt)
+		assert.True(t, stored.StatusUpdatedAt.Before(stored.UpdatedAt) || stored.StatusUpdatedAt.Equal(stored.UpdatedAt))
+	})
+}
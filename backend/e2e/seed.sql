@@ -2,15 +2,71 @@
 CREATE TABLE IF NOT EXISTS users (
     -- consider to use UUID v7, UUIDs are a better choice to prevent:
     -- ID enumeration attacks, data scraping, IDOR vulnerabilities, and competitor intelligence gathering.
-    user_id bigint GENERATED BY DEFAULT AS IDENTITY,
+    user_id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
     user_name VARCHAR(50) NOT NULL,
     first_name VARCHAR(255) NOT NULL,
     last_name VARCHAR(255) NOT NULL,
     email VARCHAR(255) UNIQUE NOT NULL,
     user_status VARCHAR(1) NOT NULL CHECK (user_status IN ('A', 'I', 'T')),
     department VARCHAR(255),
+    phone VARCHAR(16),
+    -- deleting a manager leaves their reports without one
+    manager_id bigint REFERENCES users (user_id) ON DELETE SET NULL,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    email_updated_at TIMESTAMP WITH TIME ZONE,
+    status_updated_at TIMESTAMP WITH TIME ZONE,
+    last_login_at TIMESTAMP WITH TIME ZONE,
+    version BIGINT NOT NULL DEFAULT 1,
+    created_by VARCHAR(255) NOT NULL DEFAULT 'system',
+    updated_by VARCHAR(255) NOT NULL DEFAULT 'system',
+    -- bcrypt hash, NULL for the users without a password
+    password_hash VARCHAR(255)
+);
+
+-- Indexes of the lookups by user name, the filters by department and status and the listing of the reports
+CREATE UNIQUE INDEX IF NOT EXISTS users_user_name_key ON users (user_name);
+CREATE INDEX IF NOT EXISTS users_department_idx ON users (department, user_id);
+CREATE INDEX IF NOT EXISTS users_user_status_idx ON users (user_status, user_id);
+CREATE INDEX IF NOT EXISTS users_manager_id_idx ON users (manager_id);
+
+-- Create audit log table, the history of a user outlives the user
+CREATE TABLE IF NOT EXISTS audit_log (
+    id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id bigint NOT NULL,
+    action VARCHAR(16) NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    actor VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    diff JSONB
+);
+CREATE INDEX IF NOT EXISTS audit_log_user_id_idx ON audit_log (user_id, created_at DESC);
+
+-- Create departments table, the users' department has to name one
+CREATE TABLE IF NOT EXISTS departments (
+    id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create outbox table of the user events posted to the webhooks, the unsent ones are read in id order
+CREATE TABLE IF NOT EXISTS outbox (
+    id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    event JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    attempts INT NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS outbox_unsent_idx ON outbox (id) WHERE sent_at IS NULL;
+
+-- Create refresh tokens table, looked up by the unique digest of the token
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    subject VARCHAR(255) NOT NULL,
+    roles VARCHAR(1024) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE
 );
 
 -- Create trigger function to update updated_at timestamp
@@ -49,4 +105,41 @@ INSERT INTO users (user_name, first_name, last_name, email, user_status, departm
 ('tthomas', 'Thomas', 'Thomas', 'thomas.thomas@example.com', 'T', 'Engineering'),
 ('kharris', 'Karen', 'Harris', 'karen.harris@example.com', 'A', 'Customer Support'),
 ('canderson', 'Charles', 'Anderson', 'charles.anderson@example.com', 'A', 'Sales');
+
+-- Backfill the departments of the users, and the ones the e2e tests move users to
+INSERT INTO departments (name)
+SELECT DISTINCT department FROM users WHERE department IS NOT NULL AND department <> ''
+ON CONFLICT (name) DO NOTHING;
+INSERT INTO departments (name) VALUES ('Research Development'), ('Support')
+ON CONFLICT (name) DO NOTHING;
 COMMIT;
+
+-- Record the migrations the schema above matches as applied, like "db migrate" would, so the readiness probe
+-- passes. A new migration has to be added to the schema and recorded here.
+CREATE TABLE IF NOT EXISTS bun_migrations (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    name VARCHAR,
+    group_id BIGINT,
+    migrated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE IF NOT EXISTS bun_migration_locks (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    table_name VARCHAR UNIQUE
+);
+INSERT INTO bun_migrations (name, group_id)
+SELECT name, group_id FROM (VALUES
+('20250414000000', 1),
+('20250415000000', 1),
+('20250416000000', 1),
+('20250417000000', 1),
+('20250418000000', 1),
+('20250419000000', 1),
+('20250420000000', 1),
+('20250421000000', 1),
+('20250422000000', 1),
+('20250423000000', 1),
+('20250424000000', 1),
+('20250425000000', 1),
+('20250426000000', 1)
+) AS applied (name, group_id)
+WHERE NOT EXISTS (SELECT 1 FROM bun_migrations);
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+	"gopkg.in/yaml.v3"
+)
+
+// applyFile turns the options set in the YAML config file into the defaults of the parser,
+// so they go through the same conversion and validation as the flags, and the environment
+// and the flags still override them. A missing file is an error only when it was set explicitly.
+func applyFile(p *flags.Parser, path string, explicit bool) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) && !explicit {
+			return nil
+		}
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	if err := applyFileSection(p, reflect.TypeOf(Config{}), "", doc); err != nil {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// applyFileSection sets the defaults of the options of the struct t found in the section, keyed by their yaml tag,
+// the nested structs being sections of their own. Unknown keys are rejected, they're most likely typos.
+func applyFileSection(p *flags.Parser, t reflect.Type, prefix string, section map[string]any) error {
+	known := make([]string, 0, t.NumField())
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+		known = append(known, key)
+
+		value, ok := section[key]
+		if !ok {
+			continue
+		}
+
+		if long := field.Tag.Get("long"); long != "" {
+			values, err := optionValues(value)
+			if err != nil {
+				return fmt.Errorf("%s%s: %w", prefix, key, err)
+			}
+			p.FindOptionByLongName(long).Default = values
+			continue
+		}
+
+		// an empty section, e.g. with only comments, is null
+		nested, ok := value.(map[string]any)
+		if field.Type.Kind() != reflect.Struct || (!ok && value != nil) {
+			return fmt.Errorf("%s%s: must be a mapping", prefix, key)
+		}
+		if err := applyFileSection(p, field.Type, prefix+key+".", nested); err != nil {
+			return err
+		}
+	}
+
+	for key := range section {
+		if !slices.Contains(known, key) {
+			return fmt.Errorf("unknown key %s%s", prefix, key)
+		}
+	}
+	return nil
+}
+
+// optionValues formats the YAML value as the option arguments, one per list item
+func optionValues(value any) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if _, ok := item.(map[string]any); ok {
+				return nil, errors.New("must be a scalar or a list of scalars")
+			}
+			values = append(values, fmt.Sprint(item))
+		}
+		return values, nil
+	case map[string]any:
+		return nil, errors.New("must be a scalar or a list of scalars")
+	default:
+		return []string{fmt.Sprint(v)}, nil
+	}
+}
+
+// isExplicit reports whether the option was set by a flag or an environment variable rather than its default
+func isExplicit(option *flags.Option) bool {
+	if option.IsSet() && !option.IsSetDefault() {
+		return true
+	}
+	_, ok := os.LookupEnv(option.EnvKeyWithNamespace())
+	return ok
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// the tests set environment variables, so they can't run in parallel
+func TestLoadConfigFile(t *testing.T) {
+	path := writeConfigFile(t, `
+http:
+  port: 9090
+  rate_limit: 20
+db:
+  driver: mysql
+  dsn: app:secret@tcp(db:3306)/users
+  max_clock_skew: 5s
+  max_open_conns: 16
+cache:
+  list_ttl: 1m
+`)
+
+	t.Run("File Over Defaults", func(t *testing.T) {
+		cfg, err := load([]string{"--config", path})
+		require.NoError(t, err)
+
+		assert.Equal(t, 9090, cfg.HTTP.Port)
+		assert.Equal(t, 20, cfg.HTTP.RateLimit)
+		assert.Equal(t, DriverMySQL, cfg.DB.Driver)
+		assert.Equal(t, "app:secret@tcp(db:3306)/users", cfg.DB.DSN)
+		assert.Equal(t, 5*time.Second, cfg.DB.MaxClockSkew)
+		assert.Equal(t, time.Minute, cfg.Cache.ListTTL)
+		// not in the file
+		assert.Equal(t, 4, cfg.DB.MaxIdleConns)
+		assert.Equal(t, 8192, cfg.HTTP.MaxURLLength)
+	})
+
+	t.Run("Env Over File", func(t *testing.T) {
+		t.Setenv("HTTP_PORT", "7070")
+		t.Setenv("DB_MAX_OPEN_CONNS", "2")
+
+		cfg, err := load([]string{"--config", path})
+		require.NoError(t, err)
+
+		assert.Equal(t, 7070, cfg.HTTP.Port)
+		assert.Equal(t, 2, cfg.DB.MaxOpenConns)
+		assert.Equal(t, 20, cfg.HTTP.RateLimit)
+	})
+
+	t.Run("Flags Over Env And File", func(t *testing.T) {
+		t.Setenv("HTTP_PORT", "7070")
+
+		cfg, err := load([]string{"--config", path, "--port", "6060", "--db-driver", "postgres"})
+		require.NoError(t, err)
+
+		assert.Equal(t, 6060, cfg.HTTP.Port)
+		assert.Equal(t, DriverPostgres, cfg.DB.Driver)
+	})
+
+	t.Run("File From Env", func(t *testing.T) {
+		t.Setenv("CONFIG_FILE", path)
+
+		cfg, err := load(nil)
+		require.NoError(t, err)
+		assert.Equal(t, 9090, cfg.HTTP.Port)
+	})
+}
+
+func TestLoadExampleConfigFile(t *testing.T) {
+	cfg, err := load([]string{"--config", "../../config.example.yaml"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "user-management", cfg.DB.Name)
+	assert.Equal(t, 30*time.Second, cfg.Cache.ListMaxStale)
+	assert.Empty(t, cfg.Tracing.OTLPEndpoint)
+	assert.Empty(t, cfg.Webhooks.URLs)
+	assert.Equal(t, 3, cfg.Webhooks.MaxRetries)
+}
+
+func TestLoadWebhookURLs(t *testing.T) {
+	path := writeConfigFile(t, `
+webhooks:
+  urls:
+    - https://a.example.com/hook
+    - https://b.example.com/hook
+`)
+
+	t.Run("File List", func(t *testing.T) {
+		cfg, err := load([]string{"--config", path})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://a.example.com/hook", "https://b.example.com/hook"}, cfg.Webhooks.URLs)
+	})
+
+	t.Run("Comma Separated Env", func(t *testing.T) {
+		t.Setenv("WEBHOOKS_URLS", "https://c.example.com/hook,https://d.example.com/hook")
+
+		cfg, err := load([]string{"--config", path})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://c.example.com/hook", "https://d.example.com/hook"}, cfg.Webhooks.URLs)
+	})
+
+	t.Run("Repeated Flag", func(t *testing.T) {
+		cfg, err := load([]string{"--webhook-url", "https://e.example.com/hook", "--webhook-url", "https://f.example.com/hook"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://e.example.com/hook", "https://f.example.com/hook"}, cfg.Webhooks.URLs)
+	})
+}
+
+func TestLoadMissingConfigFile(t *testing.T) {
+	t.Run("Default Path Ignored", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+
+		cfg, err := load(nil)
+		require.NoError(t, err)
+		assert.Equal(t, 8080, cfg.HTTP.Port)
+	})
+
+	t.Run("Explicit Path Required", func(t *testing.T) {
+		_, err := load([]string{"--config", filepath.Join(t.TempDir(), "missing.yaml")})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read config file")
+	})
+
+	t.Run("Explicit Path From Env Required", func(t *testing.T) {
+		t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+
+		_, err := load(nil)
+		require.Error(t, err)
+	})
+}
+
+func TestLoadInvalidConfigFile(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{"Unknown Key", "http:\n  prot: 9090\n", "unknown key http.prot"},
+		{"Unknown Section", "server:\n  port: 9090\n", "unknown key server"},
+		{"Section Not A Mapping", "http: 9090\n", "http: must be a mapping"},
+		{"Option Is A Mapping", "http:\n  port:\n    value: 1\n", "http.port: must be a scalar"},
+		{"Invalid Choice", "db:\n  driver: oracle\n", "Invalid value `oracle'"},
+		{"Invalid Gzip Level", "http:\n  gzip_level: 12\n", "invalid gzip level 12"},
+		{"CORS Origin With Path", "http:\n  cors_allowed_origins: [https://app.example.com/]\n", "invalid CORS origin"},
+		{"CORS Any Origin With Credentials", "http:\n  cors_allowed_origins: ['*']\n  cors_allow_credentials: true\n", "can't be allowed credentials"},
+		{"TLS Certificate Without Key", "http:\n  tls_cert: tls.crt\n", "must be set together"},
+		{"Redirect Without TLS", "http:\n  redirect_port: 8081\n", "requires a certificate"},
+		{"Invalid Number", "http:\n  port: eighty\n", "eighty"},
+		{"Malformed YAML", "http: [", "invalid config file"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := load([]string{"--config", writeConfigFile(t, tc.content)})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
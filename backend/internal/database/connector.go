@@ -0,0 +1,61 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/uptrace/bun/dialect/mysqldialect"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"github.com/uptrace/bun/schema"
+
+	"user-management/internal/config"
+)
+
+// NewConnector returns the connector and the bun dialect of the given driver (postgres or mysql)
+func NewConnector(driverName, dsn string) (driver.Connector, schema.Dialect, error) {
+	switch driverName {
+	case config.DriverPostgres, "":
+		return pgdriver.NewConnector(
+			pgdriver.WithDSN(dsn),
+			pgdriver.WithApplicationName(config.AppName),
+			// if we have a custom schema, we can specify it here
+			pgdriver.WithConnParams(map[string]any{
+				"search_path": "public",
+			}),
+		), pgdialect.New(), nil
+	case config.DriverMySQL:
+		cfg, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid mysql dsn: %w", err)
+		}
+		// bun scans DATETIME columns into time.Time and the timestamps are stored in UTC
+		cfg.ParseTime = true
+		cfg.Loc = time.UTC
+		if cfg.Params == nil {
+			cfg.Params = map[string]string{}
+		}
+		cfg.Params["time_zone"] = "'+00:00'"
+
+		connector, err := mysql.NewConnector(cfg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid mysql dsn: %w", err)
+		}
+		return connector, mysqldialect.New(), nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported database driver %q: must be one of %s, %s",
+			driverName, config.DriverPostgres, config.DriverMySQL)
+	}
+}
+
+// Open opens a connection pool for the given driver (postgres or mysql) and returns it with its dialect
+func Open(driverName, dsn string) (*sql.DB, schema.Dialect, error) {
+	connector, dialect, err := NewConnector(driverName, dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sql.OpenDB(connector), dialect, nil
+}
@@ -2,12 +2,12 @@ package database
 
 import (
 	"context"
-	"database/sql"
+	"errors"
+	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/uptrace/bun"
-	"github.com/uptrace/bun/dialect/pgdialect"
-	"github.com/uptrace/bun/driver/pgdriver"
 	"github.com/uptrace/bun/extra/bunslog"
 	"go.uber.org/fx"
 
@@ -16,24 +16,22 @@ import (
 
 // NewConnection will manage when the database connects
 // and will stop connection when application shutdown
-func NewConnection(lc fx.Lifecycle, cfg *config.Config) *bun.DB {
-
-	// Initialize Bun with PostgreSQL driver
-	pgconn := pgdriver.NewConnector(
-		pgdriver.WithDSN(cfg.DB.DSN),
-		pgdriver.WithApplicationName(config.AppName),
-		// if we have a custom schema, we can specify it here
-		pgdriver.WithConnParams(map[string]any{
-			"search_path": "public",
-		}),
-	)
-	sqldb := sql.OpenDB(pgconn)
+func NewConnection(lc fx.Lifecycle, cfg *config.Config) (*bun.DB, error) {
+
+	// Initialize Bun with the configured driver (PostgreSQL or MySQL)
+	sqldb, dialect, err := Open(cfg.DB.Driver, cfg.DB.DSN)
+	if err != nil {
+		return nil, err
+	}
 
 	// Set connection pool parameters
-	sqldb.SetMaxOpenConns(cfg.DB.MaxOpenConns)
-	sqldb.SetMaxIdleConns(cfg.DB.MaxIdleConns)
+	NewPoolConfig(cfg).Apply(sqldb)
 
-	db := bun.NewDB(sqldb, pgdialect.New())
+	db := bun.NewDB(sqldb, dialect)
+
+	if !cfg.DB.NoQueryErrorLog {
+		db.AddQueryHook(NewErrorQueryHook(slog.Default()))
+	}
 
 	if slog.Default().Enabled(context.TODO(), slog.LevelDebug) {
 		db.AddQueryHook(bunslog.NewQueryHook(
@@ -43,7 +41,9 @@ func NewConnection(lc fx.Lifecycle, cfg *config.Config) *bun.DB {
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			slog.Info("Connecting with database")
+			slog.With("driver", cfg.DB.Driver).
+				With("dsn", config.DSN(cfg.DB.DSN)).
+				Info("Connecting with database")
 			return db.PingContext(ctx)
 		},
 		OnStop: func(_ context.Context) error {
@@ -52,5 +52,41 @@ func NewConnection(lc fx.Lifecycle, cfg *config.Config) *bun.DB {
 		},
 	})
 
-	return db
+	return db, nil
+}
+
+// ErrMissingDSN is returned by Connect without a connection string
+var ErrMissingDSN = errors.New("database connection string is required: set --dsn or DSN")
+
+// connectTimeout bounds the ping checking the connection in Connect
+const connectTimeout = 5 * time.Second
+
+// Connect opens the pool of the given driver (postgres or mysql) and checks it reaches the database.
+// It's the counterpart of NewConnection for the CLI, which closes the returned database itself.
+func Connect(ctx context.Context, driver, dsn string, pool PoolConfig) (*bun.DB, error) {
+	if dsn == "" {
+		return nil, ErrMissingDSN
+	}
+
+	slog.With("driver", driver).
+		With("dsn", config.DSN(dsn)).
+		Debug("connecting to the database")
+
+	sqldb, dialect, err := Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	pool.Apply(sqldb)
+
+	ctx, cancel := context.WithTimeout(ctx, connectTimeout)
+	defer cancel()
+	if err := sqldb.PingContext(ctx); err != nil {
+		_ = sqldb.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	db := bun.NewDB(sqldb, dialect)
+	db.AddQueryHook(NewErrorQueryHook(slog.Default()))
+
+	return db, nil
 }
@@ -0,0 +1,42 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"user-management/internal/config"
+)
+
+// PoolConfig sizes the connection pool and sets when its connections are recycled
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// DefaultPoolConfig holds the defaults of the CLI pool flags, it matches the defaults of the REST server configuration
+var DefaultPoolConfig = PoolConfig{
+	MaxOpenConns:    8,
+	MaxIdleConns:    4,
+	ConnMaxLifetime: time.Hour,
+	ConnMaxIdleTime: 30 * time.Minute,
+}
+
+// NewPoolConfig takes the pool settings from the configuration
+func NewPoolConfig(cfg *config.Config) PoolConfig {
+	return PoolConfig{
+		MaxOpenConns:    cfg.DB.MaxOpenConns,
+		MaxIdleConns:    cfg.DB.MaxIdleConns,
+		ConnMaxLifetime: cfg.DB.MaxConnLifetime,
+		ConnMaxIdleTime: cfg.DB.MaxConnIdleTime,
+	}
+}
+
+// Apply sets the pool settings on db, a zero lifetime or idle time keeps the connections forever
+func (p PoolConfig) Apply(db *sql.DB) {
+	db.SetMaxOpenConns(p.MaxOpenConns)
+	db.SetMaxIdleConns(p.MaxIdleConns)
+	db.SetConnMaxLifetime(p.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(p.ConnMaxIdleTime)
+}
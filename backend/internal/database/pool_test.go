@@ -0,0 +1,32 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-management/internal/config"
+)
+
+func TestPoolConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{}
+	cfg.DB.MaxOpenConns = 3
+	cfg.DB.MaxIdleConns = 2
+	cfg.DB.MaxConnLifetime = time.Minute
+	cfg.DB.MaxConnIdleTime = time.Second
+
+	pool := NewPoolConfig(cfg)
+	assert.Equal(t, PoolConfig{MaxOpenConns: 3, MaxIdleConns: 2, ConnMaxLifetime: time.Minute, ConnMaxIdleTime: time.Second}, pool)
+
+	// opening the pool doesn't connect, so no server is needed
+	sqldb, _, err := Open(config.DriverPostgres, "postgres://localhost:5432/users")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqldb.Close() })
+
+	pool.Apply(sqldb)
+	assert.Equal(t, 3, sqldb.Stats().MaxOpenConnections)
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	// stringLiteralRe matches single-quoted SQL string literals, including escaped quotes
+	stringLiteralRe = regexp.MustCompile(`'(?:[^']|'')*'`)
+	// emailRe matches email addresses, e.g. echoed back in driver error messages
+	emailRe = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+)
+
+// ErrorQueryHook logs the SQL of failed queries at error level, whatever the configured log level is,
+// so production failures can be debugged without enabling the noisy debug query hook.
+// Values that may hold PII (emails) are redacted.
+type ErrorQueryHook struct {
+	logger *slog.Logger
+}
+
+var _ bun.QueryHook = (*ErrorQueryHook)(nil)
+
+// NewErrorQueryHook creates a new ErrorQueryHook.
+func NewErrorQueryHook(logger *slog.Logger) *ErrorQueryHook {
+	return &ErrorQueryHook{logger: logger}
+}
+
+// BeforeQuery implements bun.QueryHook.
+func (h *ErrorQueryHook) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+// AfterQuery implements bun.QueryHook.
+func (h *ErrorQueryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	// no rows is an expected outcome (e.g. user not found), not a failure
+	if event.Err == nil || errors.Is(event.Err, sql.ErrNoRows) {
+		return
+	}
+
+	h.logger.ErrorContext(ctx, "Query failed",
+		"operation", event.Operation(),
+		"query", redactQuery(event.Query),
+		"duration", time.Since(event.StartTime),
+		"error", emailRe.ReplaceAllString(event.Err.Error(), redacted),
+	)
+}
+
+// redactQuery replaces the string literals that contain an email (or an email pattern) in the formatted query
+func redactQuery(query string) string {
+	return stringLiteralRe.ReplaceAllStringFunc(query, func(literal string) string {
+		if strings.Contains(literal, "@") {
+			return "'" + redacted + "'"
+		}
+		return literal
+	})
+}
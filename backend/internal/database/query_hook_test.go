@@ -0,0 +1,104 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+
+	"user-management/internal/models"
+)
+
+func newHookedDB(t *testing.T) (*bun.DB, *bytes.Buffer) {
+	t.Helper()
+
+	sqldb, err := sql.Open(sqliteshim.ShimName, "file::memory:")
+	require.NoError(t, err)
+	// a single connection keeps the in-memory database alive between queries
+	sqldb.SetMaxOpenConns(1)
+
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	t.Cleanup(func() { _ = db.Close() })
+
+	var logs bytes.Buffer
+	// the hook has to log even when the logger only accepts errors
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelError}))
+	db.AddQueryHook(NewErrorQueryHook(logger))
+
+	return db, &logs
+}
+
+func TestErrorQueryHook(t *testing.T) {
+	t.Parallel()
+
+	t.Run("logs failed queries with redacted emails", func(t *testing.T) {
+		t.Parallel()
+		db, logs := newHookedDB(t)
+
+		var user models.User
+		err := db.NewSelect().Model(&user).Where("email = ?", "john.doe@example.com").Scan(context.Background())
+		require.Error(t, err)
+
+		output := logs.String()
+		assert.Contains(t, output, "level=ERROR")
+		assert.Contains(t, output, "Query failed")
+		assert.Contains(t, output, "no such table")
+		assert.Contains(t, output, `WHERE (email = '[REDACTED]')`)
+		assert.NotContains(t, output, "john.doe@example.com")
+	})
+
+	t.Run("ignores successful queries and missing rows", func(t *testing.T) {
+		t.Parallel()
+		db, logs := newHookedDB(t)
+
+		ctx := context.Background()
+		_, err := db.NewCreateTable().Model((*models.User)(nil)).Exec(ctx)
+		require.NoError(t, err)
+
+		var user models.User
+		err = db.NewSelect().Model(&user).Where("user_id = ?", 1).Scan(ctx)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		assert.Empty(t, logs.String())
+	})
+}
+
+func TestRedactQuery(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{
+			name:     "keeps literals without emails",
+			query:    `SELECT * FROM "users" WHERE (user_name = 'john')`,
+			expected: `SELECT * FROM "users" WHERE (user_name = 'john')`,
+		},
+		{
+			name:     "redacts emails",
+			query:    `UPDATE "users" SET "email" = 'a@b.com', "first_name" = 'John' WHERE "user_id" = 1`,
+			expected: `UPDATE "users" SET "email" = '[REDACTED]', "first_name" = 'John' WHERE "user_id" = 1`,
+		},
+		{
+			name:     "redacts literals with escaped quotes",
+			query:    `SELECT 1 WHERE email = 'o''brien@example.com'`,
+			expected: `SELECT 1 WHERE email = '[REDACTED]'`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, redactQuery(tc.query))
+		})
+	}
+}
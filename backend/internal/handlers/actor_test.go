@@ -0,0 +1,69 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	//revive:enable:dot-imports
+
+	"user-management/internal/handlers"
+	"user-management/internal/models"
+)
+
+func sendAs(actor, method, target string, body any) *httptest.ResponseRecorder {
+	jsonBody, err := json.Marshal(body)
+	Expect(err).NotTo(HaveOccurred())
+	req := httptest.NewRequest(method, target, bytes.NewReader(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	if actor != "" {
+		req.Header.Set(handlers.HeaderActor, actor)
+	}
+	resp := httptest.NewRecorder()
+	srv.ServeHTTP(resp, req)
+	return resp
+}
+
+func decodeUser(resp *httptest.ResponseRecorder) models.User {
+	var user models.User
+	Expect(json.Unmarshal(resp.Body.Bytes(), &user)).To(Succeed())
+	return user
+}
+
+var _ = Describe("Actor", func() {
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+	})
+
+	It("should record the X-Actor header as the creator and the last editor", func() {
+		resp := sendAs("alice", http.MethodPost, "/users", batchUser("tracked", "tracked@example.com"))
+		Expect(resp.Code).To(Equal(http.StatusCreated))
+		created := decodeUser(resp)
+		Expect(created.CreatedBy).To(Equal("alice"))
+		Expect(created.UpdatedBy).To(Equal("alice"))
+
+		resp = sendAs("bob", http.MethodPut, "/users/1", batchUser("tracked", "moved@example.com"))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		updated := decodeUser(resp)
+		Expect(updated.CreatedBy).To(Equal("alice"))
+		Expect(updated.UpdatedBy).To(Equal("bob"))
+	})
+
+	It("should default to the system actor", func() {
+		resp := sendAs("", http.MethodPost, "/users", batchUser("anonymous", "anonymous@example.com"))
+		Expect(resp.Code).To(Equal(http.StatusCreated))
+		Expect(decodeUser(resp).CreatedBy).To(Equal("system"))
+	})
+
+	It("should reject an overlong actor", func() {
+		resp := sendAs(strings.Repeat("a", 256), http.MethodPost, "/users", batchUser("overlong", "overlong@example.com"))
+		Expect(resp.Code).To(Equal(http.StatusBadRequest))
+	})
+})
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"user-management/internal/models"
+	"user-management/internal/services"
+)
+
+// AuthHandler represents a handler exchanging the refresh tokens for access tokens.
+type AuthHandler struct {
+	tokenService services.TokenService
+}
+
+// NewAuthHandler creates a new AuthHandler.
+func NewAuthHandler(tokenService services.TokenService) *AuthHandler {
+	return &AuthHandler{tokenService: tokenService}
+}
+
+// Refresh godoc
+//
+//	@Summary		Refresh an access token
+//	@Description	exchange a valid refresh token for a new access token. The refresh token stays valid until it expires or is revoked.
+//	@Accept			json
+//	@Produce		json
+//	@Param			token	body		models.RefreshTokenRequest	true	"Refresh token"
+//	@Success		200		{object}	models.AccessTokenResponse
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		401		{object}	models.ErrorResponse
+//	@Failure		422		{object}	models.ErrorResponse
+//	@Router			/auth/refresh [post]
+func (h *AuthHandler) Refresh(c echo.Context) error {
+	var req models.RefreshTokenRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	if err := c.Validate(req); err != nil {
+		return respondValidationError(c, err, "")
+	}
+
+	token, err := h.tokenService.Refresh(c.Request().Context(), req.RefreshToken)
+	if err != nil {
+		return respondTokenError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.JSON(http.StatusOK, token)
+}
+
+// Logout godoc
+//
+//	@Summary		Log out
+//	@Description	revoke the refresh token, which can't be exchanged for access tokens anymore. Revoking it again is a no-op.
+//	@Accept			json
+//	@Param			token	body	models.RefreshTokenRequest	true	"Refresh token"
+//	@Success		204
+//	@Failure		400	{object}	models.ErrorResponse
+//	@Failure		401	{object}	models.ErrorResponse
+//	@Failure		422	{object}	models.ErrorResponse
+//	@Router			/auth/logout [post]
+func (h *AuthHandler) Logout(c echo.Context) error {
+	var req models.RefreshTokenRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	if err := c.Validate(req); err != nil {
+		return respondValidationError(c, err, "")
+	}
+
+	if err := h.tokenService.Revoke(c.Request().Context(), req.RefreshToken); err != nil {
+		return respondTokenError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// IssueRefreshToken godoc
+//
+//	@Summary		Issue a refresh token
+//	@Description	create a refresh token for the subject and roles of the access tokens it's exchanged for. Only its digest is stored,
+//	@Description	the token is returned once. Exposed with an admin token and a JWT secret, until there's a login.
+//	@Accept			json
+//	@Produce		json
+//	@Security		AdminToken
+//	@Param			token	body		models.RefreshTokenIssueRequest	true	"Subject and roles"
+//	@Success		201		{object}	models.RefreshTokenIssueResponse
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		401		{object}	models.ErrorResponse
+//	@Failure		422		{object}	models.ErrorResponse
+//	@Router			/admin/tokens [post]
+func (h *AuthHandler) IssueRefreshToken(c echo.Context) error {
+	var req models.RefreshTokenIssueRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	if err := c.Validate(req); err != nil {
+		return respondValidationError(c, err, "")
+	}
+
+	token, err := h.tokenService.IssueRefreshToken(c.Request().Context(), req.Subject, req.Roles)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.JSON(http.StatusCreated, models.RefreshTokenIssueResponse{RefreshToken: token})
+}
+
+// respondTokenError maps an unusable refresh token to 401, anything else is a 500
+func respondTokenError(c echo.Context, err error) error {
+	if errors.Is(err, services.ErrInvalidRefreshToken) {
+		return c.JSON(http.StatusUnauthorized, models.NewErrorResponse(http.StatusUnauthorized, err.Error()))
+	}
+	return c.JSON(http.StatusInternalServerError, models.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"user-management/internal/models"
+	"user-management/internal/services"
+)
+
+// DepartmentHandler represents a handler for the departments the users can belong to.
+type DepartmentHandler struct {
+	departmentService services.DepartmentService
+}
+
+// NewDepartmentHandler creates a new DepartmentHandler.
+func NewDepartmentHandler(departmentService services.DepartmentService) *DepartmentHandler {
+	return &DepartmentHandler{departmentService: departmentService}
+}
+
+// ListDepartments godoc
+//
+//	@Summary		List the departments
+//	@Description	get every department, ordered by name. The department of a user has to be one of them.
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		APIKey
+//	@Success		200	{array}		models.Department
+//	@Failure		401	{object}	models.ErrorResponse
+//	@Failure		500	{object}	models.ErrorResponse
+//	@Router			/departments [get]
+func (h *DepartmentHandler) ListDepartments(c echo.Context) error {
+	departments, err := h.departmentService.ListDepartments(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, departments)
+}
+
+// CreateDepartment godoc
+//
+//	@Summary		Create a department
+//	@Description	create a department the users can then belong to
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		APIKey
+//	@Param			department	body		models.DepartmentCreateRequest	true	"Department Data"
+//	@Success		201			{object}	models.Department
+//	@Failure		400			{object}	models.ErrorResponse
+//	@Failure		401			{object}	models.ErrorResponse
+//	@Failure		403			{object}	models.ErrorResponse
+//	@Failure		409			{object}	models.ErrorResponse
+//	@Failure		422			{object}	models.ErrorResponse
+//	@Router			/departments [post]
+func (h *DepartmentHandler) CreateDepartment(c echo.Context) error {
+	var req models.DepartmentCreateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	if err := c.Validate(req); err != nil {
+		return respondValidationError(c, err, "")
+	}
+
+	department, err := h.departmentService.CreateDepartment(c.Request().Context(), req)
+	if err != nil {
+		if errors.Is(err, services.ErrDepartmentExists) {
+			return c.JSON(http.StatusConflict, models.NewErrorResponse(http.StatusConflict, err.Error()))
+		}
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, department)
+}
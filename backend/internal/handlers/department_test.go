@@ -0,0 +1,113 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/labstack/echo/v4"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	//revive:enable:dot-imports
+
+	"user-management/internal/handlers"
+	"user-management/internal/models"
+	"user-management/internal/repository"
+	"user-management/internal/services"
+	"user-management/internal/validator"
+)
+
+var _ = Describe("Departments", func() {
+	// strict is served by the default service, which rejects the unknown departments
+	var strict *echo.Echo
+
+	send := func(method, target string, body any) *httptest.ResponseRecorder {
+		jsonBody, err := json.Marshal(body)
+		Expect(err).NotTo(HaveOccurred())
+		req := httptest.NewRequest(method, target, bytes.NewReader(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		strict.ServeHTTP(resp, req)
+		return resp
+	}
+
+	member := func(userName, department string) models.UserCreateRequest {
+		user := batchUser(userName, userName+"@example.com")
+		user.Department = department
+		return user
+	}
+
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		repo := repository.NewUserRepository(db)
+		userHandler := handlers.NewUserHandler(services.NewUserService(repo))
+		departmentHandler := handlers.NewDepartmentHandler(services.NewDepartmentService(repo))
+
+		strict = echo.New()
+		strict.Validator = validator.NewEchoValidator()
+		strict.POST("/users", userHandler.CreateUser)
+		strict.POST("/users/batch", userHandler.CreateUsers)
+		strict.PUT("/users/:id", userHandler.UpdateUser)
+		strict.GET("/departments", departmentHandler.ListDepartments)
+		strict.POST("/departments", departmentHandler.CreateDepartment)
+
+		Expect(send(http.MethodPost, "/departments", models.DepartmentCreateRequest{Name: "Engineering"}).Code).
+			To(Equal(http.StatusCreated))
+	})
+
+	It("should list the departments by name", func() {
+		Expect(send(http.MethodPost, "/departments", models.DepartmentCreateRequest{Name: "Accounting"}).Code).
+			To(Equal(http.StatusCreated))
+
+		resp := send(http.MethodGet, "/departments", nil)
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		var departments []models.Department
+		Expect(json.Unmarshal(resp.Body.Bytes(), &departments)).To(Succeed())
+		Expect(departments).To(HaveLen(2))
+		Expect(departments[0].Name).To(Equal("Accounting"))
+		Expect(departments[1].Name).To(Equal("Engineering"))
+	})
+
+	It("should reject a taken or invalid department name", func() {
+		Expect(send(http.MethodPost, "/departments", models.DepartmentCreateRequest{Name: "Engineering"}).Code).
+			To(Equal(http.StatusConflict))
+		Expect(send(http.MethodPost, "/departments", models.DepartmentCreateRequest{}).Code).
+			To(Equal(http.StatusUnprocessableEntity))
+	})
+
+	It("should only accept users of a known department", func() {
+		Expect(send(http.MethodPost, "/users", member("engineer", "Engineering")).Code).To(Equal(http.StatusCreated))
+		Expect(send(http.MethodPost, "/users", member("unassigned", "")).Code).To(Equal(http.StatusCreated))
+
+		resp := send(http.MethodPost, "/users", member("typo", "Enginering"))
+		Expect(validationErrors(resp.Code, resp.Body.Bytes())).To(ConsistOf(
+			models.FieldError{Field: "department", Tag: "exists", Message: `unknown department "Enginering"`},
+		))
+
+		resp = send(http.MethodPut, "/users/1", member("engineer", "Enginering").UserCommon)
+		Expect(validationErrors(resp.Code, resp.Body.Bytes())).To(HaveLen(1))
+	})
+
+	It("should point at the batch item of an unknown department", func() {
+		resp := send(http.MethodPost, "/users/batch", []models.UserCreateRequest{
+			member("engineer", "Engineering"),
+			member("typo", "Enginering"),
+		})
+		Expect(validationErrors(resp.Code, resp.Body.Bytes())).To(ConsistOf(
+			models.FieldError{Field: "[1].department", Tag: "exists", Message: `unknown department "Enginering"`},
+		))
+		Expect(countUsers()).To(BeZero(), "the batch was rolled back")
+	})
+
+	It("should create the unknown departments when enabled", func() {
+		Expect(sendAs("", http.MethodPost, "/users", member("newcomer", "Research")).Code).To(Equal(http.StatusCreated))
+
+		resp := send(http.MethodGet, "/departments", nil)
+		Expect(resp.Body.String()).To(ContainSubstring(`"name":"Research"`))
+	})
+})
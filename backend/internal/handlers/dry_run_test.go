@@ -0,0 +1,100 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	//revive:enable:dot-imports
+
+	"user-management/internal/handlers"
+	"user-management/internal/models"
+)
+
+func dryRun(method, target string, body any) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, http.NoBody)
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		Expect(err).NotTo(HaveOccurred())
+		req = httptest.NewRequest(method, target, bytes.NewReader(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set(handlers.HeaderDryRun, "true")
+	resp := httptest.NewRecorder()
+	srv.ServeHTTP(resp, req)
+	return resp
+}
+
+var _ = Describe("Dry run", func() {
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		resp := postBatch("atomic", []models.UserCreateRequest{batchUser("existing", "existing@example.com")})
+		Expect(resp.Code).To(Equal(http.StatusCreated))
+	})
+
+	expectDryRun := func(resp *httptest.ResponseRecorder) {
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get(handlers.HeaderDryRun)).To(Equal("true"))
+		Expect(resp.Header().Get(handlers.HeaderResourceAction)).To(BeEmpty())
+		Expect(storedEmails()).To(Equal([]string{"existing@example.com"}))
+	}
+
+	It("should simulate a create", func() {
+		resp := dryRun(http.MethodPost, "/users", batchUser("newcomer", "newcomer@example.com"))
+		expectDryRun(resp)
+
+		var user models.User
+		Expect(json.Unmarshal(resp.Body.Bytes(), &user)).To(Succeed())
+		Expect(user.UserName).To(Equal("newcomer"))
+	})
+
+	It("should simulate an update", func() {
+		resp := dryRun(http.MethodPut, "/users/1", batchUser("existing", "changed@example.com"))
+		expectDryRun(resp)
+
+		var user models.User
+		Expect(json.Unmarshal(resp.Body.Bytes(), &user)).To(Succeed())
+		Expect(user.Email).To(Equal("changed@example.com"))
+		Expect(user.EmailUpdatedAt).NotTo(BeNil())
+	})
+
+	It("should simulate a delete", func() {
+		resp := dryRun(http.MethodDelete, "/users/1", nil)
+		expectDryRun(resp)
+		Expect(countUsers()).To(Equal(1))
+	})
+
+	It("should simulate the batch operations", func() {
+		expectDryRun(dryRun(http.MethodPost, "/users/batch", []models.UserCreateRequest{batchUser("first", "first@example.com")}))
+		expectDryRun(dryRun(http.MethodPut, "/users/batch", []models.UserBatchUpdateItem{updateItem(1, "existing", "batch@example.com")}))
+	})
+
+	It("should accept the query parameter", func() {
+		req := httptest.NewRequest(http.MethodDelete, "/users/1?dryRun=true", http.NoBody)
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		expectDryRun(resp)
+	})
+
+	It("should still run the checks", func() {
+		Expect(dryRun(http.MethodPost, "/users", batchUser("existing", "other@example.com")).Code).To(Equal(http.StatusConflict))
+		Expect(dryRun(http.MethodPut, "/users/99", batchUser("ghost", "ghost@example.com")).Code).To(Equal(http.StatusNotFound))
+		Expect(dryRun(http.MethodDelete, "/users/99", nil).Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should reject a malformed flag", func() {
+		req := httptest.NewRequest(http.MethodDelete, "/users/1", http.NoBody)
+		req.Header.Set(handlers.HeaderDryRun, "perhaps")
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		Expect(resp.Code).To(Equal(http.StatusBadRequest))
+		Expect(countUsers()).To(Equal(1))
+	})
+})
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"user-management/internal/models"
+)
+
+const (
+	// HeaderETag carries the weak validator of a user or a page of users.
+	HeaderETag = "ETag"
+	// HeaderIfNoneMatch carries the validators the client already has, answered with 304 when one matches.
+	HeaderIfNoneMatch = "If-None-Match"
+	// HeaderLastModified carries the update time of a user, to the second, for If-Modified-Since and If-Unmodified-Since.
+	HeaderLastModified = "Last-Modified"
+	// HeaderIfModifiedSince carries the Last-Modified time of the user the client has, answered with 304 while it's unchanged.
+	HeaderIfModifiedSince = "If-Modified-Since"
+)
+
+// userETag returns the weak ETag of the user, which changes with its version and update time
+func userETag(c echo.Context, user *models.User) string {
+	h := fnv.New64a()
+	writeUserValidator(h, user)
+	return weakETag(c, h)
+}
+
+// setLastModified sets the Last-Modified header to the update time of the user
+func setLastModified(c echo.Context, user *models.User) {
+	c.Response().Header().Set(HeaderLastModified, user.UpdatedAt.UTC().Format(http.TimeFormat))
+}
+
+// listETag returns the weak ETag of a page of users. Besides the version and update time of every user in order,
+// it covers the paging, so a user inserted or deleted elsewhere (shifting the total) changes it too.
+func listETag(c echo.Context, list *models.UserListResponse) string {
+	h := fnv.New64a()
+	for _, n := range []int{list.Total, list.Limit, list.Offset, len(list.Users)} {
+		_ = binary.Write(h, binary.LittleEndian, int64(n))
+	}
+	for i := range list.Users {
+		writeUserValidator(h, &list.Users[i])
+	}
+	return weakETag(c, h)
+}
+
+func writeUserValidator(h hash.Hash64, user *models.User) {
+	_ = binary.Write(h, binary.LittleEndian, []int64{user.UserID, user.Version, user.UpdatedAt.UnixNano()})
+}
+
+// weakETag formats the hash as a weak ETag. The JSON:API document is another representation of the same data,
+// so it gets an ETag of its own.
+func weakETag(c echo.Context, h hash.Hash64) string {
+	if wantsJSONAPI(c) {
+		_, _ = h.Write([]byte(MIMEApplicationJSONAPI))
+	}
+	return fmt.Sprintf(`W/"%016x"`, h.Sum64())
+}
+
+// respondNotModified sets the ETag header and, when If-None-Match matches it, answers 304 and returns true.
+// The validators are compared weakly, as RFC 9110 mandates for If-None-Match.
+func respondNotModified(c echo.Context, etag string) (bool, error) {
+	c.Response().Header().Set(HeaderETag, etag)
+
+	for _, value := range c.Request().Header.Values(HeaderIfNoneMatch) {
+		for _, candidate := range strings.Split(value, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true, c.NoContent(http.StatusNotModified)
+			}
+		}
+	}
+	return false, nil
+}
+
+// respondUserNotModified is respondNotModified for a user, also setting its Last-Modified header.
+// Without If-None-Match, which takes precedence as RFC 9110 mandates, it answers 304 when the user wasn't updated
+// since If-Modified-Since, compared to the second as HTTP dates are. An If-Modified-Since that isn't a date is ignored.
+func respondUserNotModified(c echo.Context, user *models.User) (bool, error) {
+	setLastModified(c, user)
+	if done, err := respondNotModified(c, userETag(c, user)); done || c.Request().Header.Get(HeaderIfNoneMatch) != "" {
+		return done, err
+	}
+
+	since, err := http.ParseTime(c.Request().Header.Get(HeaderIfModifiedSince))
+	if err == nil && !user.UpdatedAt.Truncate(time.Second).After(since) {
+		return true, c.NoContent(http.StatusNotModified)
+	}
+	return false, nil
+}
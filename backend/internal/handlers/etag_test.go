@@ -0,0 +1,127 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	//revive:enable:dot-imports
+
+	"user-management/internal/handlers"
+)
+
+func getWithETag(target, ifNoneMatch, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, http.NoBody)
+	if ifNoneMatch != "" {
+		req.Header.Set(handlers.HeaderIfNoneMatch, ifNoneMatch)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp := httptest.NewRecorder()
+	srv.ServeHTTP(resp, req)
+	return resp
+}
+
+var _ = Describe("ETag", func() {
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		Expect(sendAs("", http.MethodPost, "/users", batchUser("tagged", "tagged@example.com")).Code).To(Equal(http.StatusCreated))
+	})
+
+	for _, target := range []string{"/users/1", "/users?limit=10"} {
+		It("should answer 304 for the current ETag of "+target, func() {
+			first := getWithETag(target, "", "")
+			Expect(first.Code).To(Equal(http.StatusOK))
+			etag := first.Header().Get(handlers.HeaderETag)
+			Expect(etag).To(MatchRegexp(`^W/"[0-9a-f]{16}"$`))
+
+			resp := getWithETag(target, etag, "")
+			Expect(resp.Code).To(Equal(http.StatusNotModified))
+			Expect(resp.Body.Len()).To(BeZero())
+			Expect(resp.Header().Get(handlers.HeaderETag)).To(Equal(etag))
+
+			// weak comparison, among other validators
+			Expect(getWithETag(target, `"other", `+etag[2:], "").Code).To(Equal(http.StatusNotModified))
+			Expect(getWithETag(target, "*", "").Code).To(Equal(http.StatusNotModified))
+		})
+
+		It("should change the ETag of "+target+" with the user", func() {
+			etag := getWithETag(target, "", "").Header().Get(handlers.HeaderETag)
+
+			Expect(sendAs("", http.MethodPut, "/users/1", batchUser("tagged", "moved@example.com")).Code).To(Equal(http.StatusOK))
+
+			resp := getWithETag(target, etag, "")
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(resp.Header().Get(handlers.HeaderETag)).NotTo(Equal(etag))
+		})
+
+		It("should give the JSON:API representation of "+target+" its own ETag", func() {
+			etag := getWithETag(target, "", "").Header().Get(handlers.HeaderETag)
+
+			resp := getWithETag(target, etag, handlers.MIMEApplicationJSONAPI)
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(resp.Header().Get(handlers.HeaderETag)).NotTo(Equal(etag))
+		})
+	}
+
+	It("should change the ETag of the list when a user is added", func() {
+		etag := getWithETag("/users", "", "").Header().Get(handlers.HeaderETag)
+
+		Expect(sendAs("", http.MethodPost, "/users", batchUser("another", "another@example.com")).Code).To(Equal(http.StatusCreated))
+
+		Expect(getWithETag("/users", etag, "").Code).To(Equal(http.StatusOK))
+	})
+})
+
+var _ = Describe("Last-Modified", func() {
+	updatedAt := time.Date(2025, 1, 2, 3, 4, 5, 678e6, time.UTC)
+
+	getSince := func(ifModifiedSince, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/users/1", http.NoBody)
+		req.Header.Set(handlers.HeaderIfModifiedSince, ifModifiedSince)
+		if ifNoneMatch != "" {
+			req.Header.Set(handlers.HeaderIfNoneMatch, ifNoneMatch)
+		}
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		return resp
+	}
+
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		Expect(sendAs("", http.MethodPost, "/users", batchUser("dated", "dated@example.com")).Code).To(Equal(http.StatusCreated))
+		_, err := db.NewUpdate().Table("users").Set("updated_at = ?", updatedAt).Where("user_id = 1").Exec(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should set Last-Modified to the update time as an HTTP date", func() {
+		resp := getSince("", "")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get(handlers.HeaderLastModified)).To(Equal("Thu, 02 Jan 2025 03:04:05 GMT"))
+	})
+
+	It("should answer 304 while the user wasn't modified since, to the second", func() {
+		resp := getSince("Thu, 02 Jan 2025 03:04:05 GMT", "")
+		Expect(resp.Code).To(Equal(http.StatusNotModified))
+		Expect(resp.Body.Len()).To(BeZero())
+		Expect(resp.Header().Get(handlers.HeaderLastModified)).To(Equal("Thu, 02 Jan 2025 03:04:05 GMT"))
+
+		Expect(getSince("Fri, 03 Jan 2025 00:00:00 GMT", "").Code).To(Equal(http.StatusNotModified))
+		Expect(getSince("Thu, 02 Jan 2025 03:04:04 GMT", "").Code).To(Equal(http.StatusOK))
+	})
+
+	It("should ignore If-Modified-Since along with If-None-Match, or when it isn't a date", func() {
+		Expect(getSince("Fri, 03 Jan 2025 00:00:00 GMT", `W/"0000000000000000"`).Code).To(Equal(http.StatusOK))
+		Expect(getSince("yesterday", "").Code).To(Equal(http.StatusOK))
+	})
+})
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/log"
+	"github.com/labstack/echo/v4"
+
+	"user-management/internal/models"
+	"user-management/internal/services"
+)
+
+// graphQLSchema mirrors the user CRUD of the REST routes
+//
+//go:embed schema.graphql
+var graphQLSchema string
+
+// GraphQLHandler serves the GraphQL API, backed by the same service, validation and error mapping as the REST routes
+type GraphQLHandler struct {
+	schema *graphql.Schema
+}
+
+// NewGraphQLHandler creates a new GraphQLHandler.
+func NewGraphQLHandler(userService services.UserService, v echo.Validator) *GraphQLHandler {
+	resolver := &graphQLResolver{userService: userService, validator: v}
+	return &GraphQLHandler{
+		schema: graphql.MustParseSchema(graphQLSchema, resolver, graphql.Logger(log.LoggerFunc(logGraphQLPanic))),
+	}
+}
+
+// graphQLRequest is the body of a GraphQL request
+type graphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// writeAuthorizationKey holds the error rejecting the mutations of the request, nil when they're allowed
+type writeAuthorizationKey struct{}
+
+// Handler returns the handler of the GraphQL requests, POSTed as JSON. The response is a 200 with the data and the errors
+// of the resolvers, whose extensions carry the code and the rejected fields of the matching REST error body.
+// authorizeWrite returns the error rejecting the mutations of the request, e.g. for a token without the write role.
+// The X-Actor and X-Dry-Run headers apply to the mutations as to the REST writes.
+func (h *GraphQLHandler) Handler(authorizeWrite func(c echo.Context) error) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx, dryRun, err := writeContext(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, err.Error()))
+		}
+
+		var req graphQLRequest
+		if err := c.Bind(&req); err != nil || req.Query == "" {
+			return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid request: must be a JSON object with a query"))
+		}
+
+		if dryRun {
+			c.Response().Header().Set(HeaderDryRun, "true")
+		}
+		ctx = context.WithValue(ctx, writeAuthorizationKey{}, authorizeWrite(c))
+		return c.JSON(http.StatusOK, h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+	}
+}
+
+// authorizeWrite returns the error of a mutation the request isn't allowed to run
+func authorizeWrite(ctx context.Context) error {
+	if err, _ := ctx.Value(writeAuthorizationKey{}).(error); err != nil {
+		return newGraphQLError(models.NewErrorResponse(http.StatusForbidden, err.Error()))
+	}
+	return nil
+}
+
+// graphQLError is the error of a resolver, the message of the REST error body
+// with its code and rejected fields as extensions
+type graphQLError struct {
+	body          models.ErrorResponse
+	invalidParams []models.InvalidParam
+}
+
+func newGraphQLError(body models.ErrorResponse) *graphQLError {
+	return &graphQLError{body: body}
+}
+
+func (e *graphQLError) Error() string {
+	return e.body.Message
+}
+
+// Extensions implements the extensions of the GraphQL errors
+func (e *graphQLError) Extensions() map[string]any {
+	extensions := map[string]any{"code": e.body.Code}
+	if len(e.body.Errors) > 0 {
+		extensions["errors"] = e.body.Errors
+	}
+	if len(e.invalidParams) > 0 {
+		extensions["invalidParams"] = e.invalidParams
+	}
+	return extensions
+}
+
+// userError maps the service error to the GraphQL error, as respondUserError does to the REST one
+func userError(err error) *graphQLError {
+	_, body := userErrorResponse(err)
+	return newGraphQLError(body)
+}
+
+// logGraphQLPanic logs the panic of a resolver like the Recover middleware, the client only gets a generic error
+func logGraphQLPanic(ctx context.Context, value any) {
+	stack := make([]byte, 4<<10)
+	stack = stack[:runtime.Stack(stack, false)]
+	slog.With("request_id", services.RequestIDFrom(ctx)).
+		With("panic", fmt.Sprint(value)).
+		With("stack", string(stack)).
+		Error("recovered from a panic")
+}
@@ -0,0 +1,404 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/labstack/echo/v4"
+
+	"user-management/internal/models"
+	"user-management/internal/services"
+)
+
+// graphQLStatuses are the values of the UserStatus enum of the GraphQL schema
+var graphQLStatuses = map[models.UserStatus]string{
+	models.UserStatusActive:     "ACTIVE",
+	models.UserStatusInactive:   "INACTIVE",
+	models.UserStatusTerminated: "TERMINATED",
+}
+
+// userStatusOf returns the user status of a UserStatus enum value, the schema only lets the known ones through
+func userStatusOf(value string) models.UserStatus {
+	for status, name := range graphQLStatuses {
+		if name == value {
+			return status
+		}
+	}
+	return models.UserStatus(value)
+}
+
+// parseGraphQLID returns the user ID of a GraphQL ID
+func parseGraphQLID(id graphql.ID) (int64, error) {
+	userID, err := strconv.ParseInt(string(id), 10, 64)
+	if err != nil {
+		return 0, newGraphQLError(models.NewErrorResponse(http.StatusBadRequest, "invalid user id format"))
+	}
+	return userID, nil
+}
+
+// graphQLResolver resolves the queries and the mutations of the schema
+type graphQLResolver struct {
+	userService services.UserService
+	validator   echo.Validator
+}
+
+// usersArgs are the arguments of the users query, the query parameters of GET /users
+type usersArgs struct {
+	Q              *string
+	Status         *string
+	Department     *string
+	DepartmentLike *string
+	Sort           *string
+	Order          *string
+	Limit          *int32
+	Offset         *int32
+	Cursor         *string
+}
+
+// listParams reads the arguments as parseListParams reads the query parameters,
+// every rejected argument is reported at once in a *services.InvalidParamsError
+func (args usersArgs) listParams() (models.ListParams, error) {
+	value := func(arg *string) string {
+		if arg == nil {
+			return ""
+		}
+		return *arg
+	}
+
+	params := models.ListParams{
+		Query:          strings.TrimSpace(value(args.Q)),
+		Sort:           value(args.Sort),
+		Order:          strings.ToLower(value(args.Order)),
+		Department:     value(args.Department),
+		DepartmentLike: strings.TrimSpace(value(args.DepartmentLike)),
+		Limit:          models.DefaultListLimit,
+	}
+	if args.Status != nil {
+		params.Status = userStatusOf(*args.Status)
+	}
+
+	var invalid []models.InvalidParam
+
+	if args.Limit != nil {
+		if *args.Limit < 1 {
+			invalid = append(invalid, models.InvalidParam{Name: "limit", Reason: "must be a positive integer"})
+		} else {
+			params.Limit = min(int(*args.Limit), models.MaxListLimit)
+		}
+	}
+
+	if args.Offset != nil {
+		if *args.Offset < 0 {
+			invalid = append(invalid, models.InvalidParam{Name: "offset", Reason: "must be a non-negative integer"})
+		} else {
+			params.Offset = int(*args.Offset)
+		}
+	}
+
+	if args.Cursor != nil {
+		afterID, err := decodeCursor(*args.Cursor)
+		if err != nil {
+			invalid = append(invalid, models.InvalidParam{Name: "cursor", Reason: "must be the nextCursor of a previous page"})
+		} else {
+			params.AfterID = afterID
+		}
+	}
+
+	var invalidErr *services.InvalidParamsError
+	if err := services.ValidateListParams(params); errors.As(err, &invalidErr) {
+		invalid = append(invalid, invalidErr.Params...)
+	}
+
+	if len(invalid) > 0 {
+		return params, &services.InvalidParamsError{Params: invalid}
+	}
+	return params, nil
+}
+
+// invalidParamsError maps the rejected list parameters to the GraphQL error, as respondInvalidParams does
+func invalidParamsError(err *services.InvalidParamsError) *graphQLError {
+	return &graphQLError{
+		body:          models.NewErrorResponse(http.StatusBadRequest, err.Error()),
+		invalidParams: err.Params,
+	}
+}
+
+// Users resolves the users query
+func (r *graphQLResolver) Users(ctx context.Context, args usersArgs) (*userListResolver, error) {
+	var invalidErr *services.InvalidParamsError
+
+	params, err := args.listParams()
+	if errors.As(err, &invalidErr) {
+		return nil, invalidParamsError(invalidErr)
+	}
+
+	users, total, err := r.userService.ListUsers(ctx, params)
+	if err != nil {
+		if errors.As(err, &invalidErr) {
+			return nil, invalidParamsError(invalidErr)
+		}
+		return nil, userError(err)
+	}
+
+	return &userListResolver{list: models.UserListResponse{
+		Users:      users,
+		Total:      total,
+		Limit:      params.Limit,
+		Offset:     params.Offset,
+		NextCursor: nextCursor(params, users),
+	}}, nil
+}
+
+// User resolves the user query, a missing user is null
+func (r *graphQLResolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	id, err := parseGraphQLID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := r.userService.GetUser(ctx, id)
+	if errors.Is(err, services.ErrUserNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, userError(err)
+	}
+	return &userResolver{user: user}, nil
+}
+
+// userInput is the UserCreateInput and the UserUpdateInput of the schema, the password only being set
+// on the former and the version on the latter
+type userInput struct {
+	UserName   string
+	FirstName  string
+	LastName   string
+	Email      string
+	UserStatus string
+	Department *string
+	Phone      *string
+	ManagerID  *graphql.ID
+	Password   *string
+	Version    *int32
+}
+
+// common returns the fields common to the create and the update requests
+func (in userInput) common() (models.UserCommon, error) {
+	common := models.UserCommon{
+		UserName:   in.UserName,
+		FirstName:  in.FirstName,
+		LastName:   in.LastName,
+		Email:      in.Email,
+		UserStatus: userStatusOf(in.UserStatus),
+	}
+	if in.Department != nil {
+		common.Department = *in.Department
+	}
+	if in.Phone != nil {
+		common.Phone = *in.Phone
+	}
+	if in.ManagerID != nil {
+		managerID, err := strconv.ParseInt(string(*in.ManagerID), 10, 64)
+		if err != nil {
+			return common, newGraphQLError(fieldErrorsResponse(http.StatusUnprocessableEntity, validationFailed, models.FieldError{
+				Field: "managerId", Tag: "gt", Message: "managerId must be a user ID",
+			}))
+		}
+		common.ManagerID = &managerID
+	}
+	return common, nil
+}
+
+// validate runs the validation rules of the REST requests
+func (r *graphQLResolver) validate(req any) error {
+	if err := r.validator.Validate(req); err != nil {
+		return newGraphQLError(fieldErrorsResponse(http.StatusUnprocessableEntity, validationFailed, fieldErrors(err, "")...))
+	}
+	return nil
+}
+
+// CreateUser resolves the createUser mutation
+func (r *graphQLResolver) CreateUser(ctx context.Context, args struct{ Input userInput }) (*userResolver, error) {
+	if err := authorizeWrite(ctx); err != nil {
+		return nil, err
+	}
+
+	common, err := args.Input.common()
+	if err != nil {
+		return nil, err
+	}
+	req := models.UserCreateRequest{UserCommon: common}
+	if args.Input.Password != nil {
+		req.Password = *args.Input.Password
+	}
+	if err := r.validate(req); err != nil {
+		return nil, err
+	}
+
+	user, err := r.userService.CreateUser(ctx, req)
+	if err != nil {
+		return nil, userError(err)
+	}
+	return &userResolver{user: user}, nil
+}
+
+// UpdateUser resolves the updateUser mutation
+func (r *graphQLResolver) UpdateUser(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input userInput
+}) (*userResolver, error) {
+	if err := authorizeWrite(ctx); err != nil {
+		return nil, err
+	}
+
+	id, err := parseGraphQLID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	common, err := args.Input.common()
+	if err != nil {
+		return nil, err
+	}
+	req := models.UserUpdateRequest{UserCommon: common}
+	if args.Input.Version != nil {
+		req.Version = int64(*args.Input.Version)
+	}
+	if err := r.validate(req); err != nil {
+		return nil, err
+	}
+
+	user, err := r.userService.UpdateUser(ctx, id, req)
+	if err != nil {
+		return nil, userError(err)
+	}
+	return &userResolver{user: user}, nil
+}
+
+// userDeleteResult is the UserDeleteResult of the schema
+type userDeleteResult struct {
+	id graphql.ID
+}
+
+// Deleted is always true, a failed delete is an error
+func (r userDeleteResult) Deleted() bool { return true }
+
+// ID is the ID of the deleted user
+func (r userDeleteResult) ID() graphql.ID { return r.id }
+
+// DeleteUser resolves the deleteUser mutation
+func (r *graphQLResolver) DeleteUser(ctx context.Context, args struct {
+	ID         graphql.ID
+	ReassignTo *graphql.ID
+}) (*userDeleteResult, error) {
+	if err := authorizeWrite(ctx); err != nil {
+		return nil, err
+	}
+
+	id, err := parseGraphQLID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	var reassignTo int64
+	if args.ReassignTo != nil {
+		reassignTo, err = strconv.ParseInt(string(*args.ReassignTo), 10, 64)
+		if err != nil || reassignTo < 1 {
+			return nil, newGraphQLError(models.NewErrorResponse(http.StatusBadRequest, "invalid reassignTo: must be a user id"))
+		}
+	}
+
+	if err := r.userService.DeleteUser(ctx, id, reassignTo); err != nil {
+		// the manager error isn't about a field of the input
+		var managerErr *services.InvalidManagerError
+		if errors.As(err, &managerErr) {
+			return nil, newGraphQLError(models.NewErrorResponse(http.StatusUnprocessableEntity, "invalid reassignTo: "+err.Error()))
+		}
+		return nil, userError(err)
+	}
+	return &userDeleteResult{id: args.ID}, nil
+}
+
+// userListResolver resolves the UserList of the schema
+type userListResolver struct {
+	list models.UserListResponse
+}
+
+// Users resolves the users of the page
+func (r *userListResolver) Users() []*userResolver {
+	users := make([]*userResolver, len(r.list.Users))
+	for i := range r.list.Users {
+		users[i] = &userResolver{user: &r.list.Users[i]}
+	}
+	return users
+}
+
+// Total resolves the number of matching users
+func (r *userListResolver) Total() int32 { return int32(r.list.Total) } //nolint:gosec
+
+// Limit resolves the applied page size
+func (r *userListResolver) Limit() int32 { return int32(r.list.Limit) } //nolint:gosec
+
+// Offset resolves the applied number of skipped users
+func (r *userListResolver) Offset() int32 { return int32(r.list.Offset) } //nolint:gosec
+
+// NextCursor resolves the cursor of the next page, null when there's none
+func (r *userListResolver) NextCursor() *string { return optionalString(r.list.NextCursor) }
+
+// userResolver resolves the User of the schema
+type userResolver struct {
+	user *models.User
+}
+
+// optionalString returns nil for an empty string, the null of a GraphQL field
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// ID and the methods below resolve the fields of the user
+func (r *userResolver) ID() graphql.ID {
+	return graphql.ID(strconv.FormatInt(r.user.UserID, 10))
+}
+
+func (r *userResolver) UserName() string { return r.user.UserName }
+
+func (r *userResolver) FirstName() string { return r.user.FirstName }
+
+func (r *userResolver) LastName() string { return r.user.LastName }
+
+func (r *userResolver) Email() string { return r.user.Email }
+
+func (r *userResolver) UserStatus() string { return graphQLStatuses[r.user.UserStatus] }
+
+func (r *userResolver) Department() *string { return optionalString(r.user.Department) }
+
+func (r *userResolver) Phone() *string { return optionalString(r.user.Phone) }
+
+func (r *userResolver) ManagerID() *graphql.ID {
+	if r.user.ManagerID == nil {
+		return nil
+	}
+	id := graphql.ID(strconv.FormatInt(*r.user.ManagerID, 10))
+	return &id
+}
+
+func (r *userResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.user.CreatedAt} }
+
+func (r *userResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: r.user.UpdatedAt} }
+
+func (r *userResolver) CreatedBy() string { return r.user.CreatedBy }
+
+func (r *userResolver) UpdatedBy() string { return r.user.UpdatedBy }
+
+func (r *userResolver) Version() int32 { return int32(r.user.Version) } //nolint:gosec
+
+func (r *userResolver) LastLoginAt() *graphql.Time {
+	if r.user.LastLoginAt == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *r.user.LastLoginAt}
+}
@@ -0,0 +1,213 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	//revive:enable:dot-imports
+
+	"user-management/internal/handlers"
+)
+
+// graphQLResponse is the body of a GraphQL response
+type graphQLResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Path       []any          `json:"path"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func postGraphQL(target, query string, variables map[string]any) (*httptest.ResponseRecorder, graphQLResponse) {
+	jsonBody, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	Expect(err).NotTo(HaveOccurred())
+	req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.ServeHTTP(resp, req)
+
+	var body graphQLResponse
+	if resp.Code == http.StatusOK {
+		Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+	}
+	return resp, body
+}
+
+const (
+	graphQLUserFields = `id userName firstName lastName email userStatus department phone managerId version createdBy`
+
+	createUserMutation = `mutation($input: UserCreateInput!) { createUser(input: $input) { ` + graphQLUserFields + ` } }`
+	updateUserMutation = `mutation($id: ID!, $input: UserUpdateInput!) { updateUser(id: $id, input: $input) { ` + graphQLUserFields + ` } }`
+	deleteUserMutation = `mutation($id: ID!) { deleteUser(id: $id) { deleted id } }`
+	userQuery          = `query($id: ID!) { user(id: $id) { ` + graphQLUserFields + ` } }`
+)
+
+func graphQLUserInput(userName string) map[string]any {
+	return map[string]any{
+		"userName":   userName,
+		"firstName":  "John",
+		"lastName":   "Doe",
+		"email":      userName + "@example.com",
+		"userStatus": "ACTIVE",
+		"department": "Engineering",
+	}
+}
+
+var _ = Describe("GraphQL API", func() {
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+	})
+
+	createUser := func(userName string) map[string]any {
+		resp, body := postGraphQL("/graphql", createUserMutation, map[string]any{"input": graphQLUserInput(userName)})
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(body.Errors).To(BeEmpty())
+
+		var user map[string]any
+		Expect(json.Unmarshal(body.Data["createUser"], &user)).To(Succeed())
+		return user
+	}
+
+	It("creates, reads, updates and deletes a user", func() {
+		created := createUser("johndoe")
+		Expect(created).To(HaveKeyWithValue("userName", "johndoe"))
+		Expect(created).To(HaveKeyWithValue("userStatus", "ACTIVE"))
+		Expect(created).To(HaveKeyWithValue("phone", BeNil()))
+		Expect(created).To(HaveKeyWithValue("version", BeEquivalentTo(1)))
+		id := created["id"]
+
+		_, body := postGraphQL("/graphql", userQuery, map[string]any{"id": id})
+		Expect(body.Errors).To(BeEmpty())
+		var user map[string]any
+		Expect(json.Unmarshal(body.Data["user"], &user)).To(Succeed())
+		Expect(user).To(HaveKeyWithValue("email", "johndoe@example.com"))
+
+		input := graphQLUserInput("johndoe")
+		input["userStatus"] = "TERMINATED"
+		input["version"] = 1
+		_, body = postGraphQL("/graphql", updateUserMutation, map[string]any{"id": id, "input": input})
+		Expect(body.Errors).To(BeEmpty())
+		Expect(json.Unmarshal(body.Data["updateUser"], &user)).To(Succeed())
+		Expect(user).To(HaveKeyWithValue("userStatus", "TERMINATED"))
+		Expect(user).To(HaveKeyWithValue("version", BeEquivalentTo(2)))
+
+		_, body = postGraphQL("/graphql", deleteUserMutation, map[string]any{"id": id})
+		Expect(body.Errors).To(BeEmpty())
+		Expect(body.Data["deleteUser"]).To(MatchJSON(`{"deleted": true, "id": "` + id.(string) + `"}`))
+
+		_, body = postGraphQL("/graphql", userQuery, map[string]any{"id": id})
+		Expect(body.Errors).To(BeEmpty())
+		Expect(body.Data["user"]).To(MatchJSON(`null`))
+	})
+
+	It("lists the users like GET /users", func() {
+		for _, userName := range []string{"alice1", "bobby1", "carol1"} {
+			createUser(userName)
+		}
+
+		_, body := postGraphQL("/graphql", `{ users(limit: 2, sort: "user_name", order: "desc") { total limit offset nextCursor users { userName } } }`, nil)
+		Expect(body.Errors).To(BeEmpty())
+		Expect(body.Data["users"]).To(MatchJSON(`{
+			"total": 3, "limit": 2, "offset": 0, "nextCursor": null,
+			"users": [{"userName": "carol1"}, {"userName": "bobby1"}]
+		}`))
+
+		_, body = postGraphQL("/graphql", `{ users(limit: 2) { nextCursor users { userName } } }`, nil)
+		var page struct {
+			NextCursor string `json:"nextCursor"`
+		}
+		Expect(json.Unmarshal(body.Data["users"], &page)).To(Succeed())
+		Expect(page.NextCursor).NotTo(BeEmpty())
+
+		_, body = postGraphQL("/graphql", `query($cursor: String) { users(cursor: $cursor) { users { userName } } }`,
+			map[string]any{"cursor": page.NextCursor})
+		Expect(body.Data["users"]).To(MatchJSON(`{"users": [{"userName": "carol1"}]}`))
+
+		_, body = postGraphQL("/graphql", `{ users(status: INACTIVE) { total } }`, nil)
+		Expect(body.Data["users"]).To(MatchJSON(`{"total": 0}`))
+	})
+
+	It("rejects the invalid list arguments at once", func() {
+		_, body := postGraphQL("/graphql", `{ users(limit: 0, offset: -1) { total } }`, nil)
+		Expect(body.Errors).To(HaveLen(1))
+		Expect(body.Errors[0].Extensions).To(HaveKeyWithValue("code", "bad_request"))
+		Expect(body.Errors[0].Extensions["invalidParams"]).To(HaveLen(2))
+	})
+
+	It("maps the validation and the domain errors like the REST routes", func() {
+		createUser("johndoe")
+
+		input := graphQLUserInput("jd")
+		input["email"] = "not-an-email"
+		_, body := postGraphQL("/graphql", createUserMutation, map[string]any{"input": input})
+		Expect(body.Errors).To(HaveLen(1))
+		Expect(body.Errors[0].Message).To(Equal("the request failed validation"))
+		Expect(body.Errors[0].Extensions).To(HaveKeyWithValue("code", "unprocessable_entity"))
+		Expect(body.Errors[0].Extensions["errors"]).To(ConsistOf(
+			HaveKeyWithValue("field", "userName"),
+			HaveKeyWithValue("field", "email"),
+		))
+
+		_, body = postGraphQL("/graphql", createUserMutation, map[string]any{"input": graphQLUserInput("johndoe")})
+		Expect(body.Errors).To(HaveLen(1))
+		Expect(body.Errors[0].Message).To(Equal("username already exists"))
+		Expect(body.Errors[0].Extensions).To(HaveKeyWithValue("code", "conflict"))
+
+		_, body = postGraphQL("/graphql", deleteUserMutation, map[string]any{"id": "999"})
+		Expect(body.Errors).To(HaveLen(1))
+		Expect(body.Errors[0].Extensions).To(HaveKeyWithValue("code", "not_found"))
+
+		_, body = postGraphQL("/graphql", userQuery, map[string]any{"id": "abc"})
+		Expect(body.Errors).To(HaveLen(1))
+		Expect(body.Errors[0].Extensions).To(HaveKeyWithValue("code", "bad_request"))
+	})
+
+	It("rejects an unknown status with the schema", func() {
+		input := graphQLUserInput("johndoe")
+		input["userStatus"] = "A"
+		_, body := postGraphQL("/graphql", createUserMutation, map[string]any{"input": input})
+		Expect(body.Errors).To(HaveLen(1))
+		Expect(body.Data).To(BeNil())
+	})
+
+	It("rejects the mutations of a request without the write role", func() {
+		createUser("johndoe")
+
+		_, body := postGraphQL("/graphql/read-only", `{ users { total } }`, nil)
+		Expect(body.Errors).To(BeEmpty())
+		Expect(body.Data["users"]).To(MatchJSON(`{"total": 1}`))
+
+		_, body = postGraphQL("/graphql/read-only", createUserMutation, map[string]any{"input": graphQLUserInput("janedoe")})
+		Expect(body.Errors).To(HaveLen(1))
+		Expect(body.Errors[0].Message).To(Equal("the users:write role is required"))
+		Expect(body.Errors[0].Extensions).To(HaveKeyWithValue("code", "forbidden"))
+	})
+
+	It("applies the dry run header to the mutations", func() {
+		jsonBody, err := json.Marshal(map[string]any{"query": createUserMutation, "variables": map[string]any{"input": graphQLUserInput("johndoe")}})
+		Expect(err).NotTo(HaveOccurred())
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(handlers.HeaderDryRun, "true")
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get(handlers.HeaderDryRun)).To(Equal("true"))
+
+		_, body := postGraphQL("/graphql", `{ users { total } }`, nil)
+		Expect(body.Data["users"]).To(MatchJSON(`{"total": 0}`))
+	})
+
+	It("rejects a body without a query", func() {
+		resp, _ := postGraphQL("/graphql", "", nil)
+		Expect(resp.Code).To(Equal(http.StatusBadRequest))
+	})
+})
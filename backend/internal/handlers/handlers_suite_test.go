@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -32,30 +33,58 @@ func TestHandlers(t *testing.T) {
 	RunSpecs(t, "Api Suite")
 }
 
-var srv *echo.Echo
+var (
+	srv *echo.Echo
+	db  *bun.DB
+)
+
+// resetUsers recreates the users, audit log and departments tables, so specs that don't belong
+// to the ordered "User API" container start and leave with a clean state.
+func resetUsers() {
+	err := db.ResetModel(context.TODO(), (*models.User)(nil), (*models.AuditEntry)(nil), (*models.Department)(nil))
+	Expect(err).NotTo(HaveOccurred())
+}
 
 var _ = BeforeSuite(func() {
 	// use in-memory database
 	sqldb, err := sql.Open(sqliteshim.ShimName, "file::memory:?cache=shared")
 	Expect(err).NotTo(HaveOccurred())
 
-	db := bun.NewDB(sqldb, sqlitedialect.New())
+	db = bun.NewDB(sqldb, sqlitedialect.New())
 	// for debugging
 	// db.AddQueryHook(bundebug.NewQueryHook(bundebug.WithVerbose(true)))
 
-	err = db.ResetModel(context.TODO(), (*models.User)(nil))
+	err = db.ResetModel(context.TODO(), (*models.User)(nil), (*models.AuditEntry)(nil), (*models.Department)(nil))
 	Expect(err).NotTo(HaveOccurred())
 
 	userRepo := repository.NewUserRepository(db)
-	userService := services.NewUserService(userRepo)
+	// most specs make up their departments, the unknown departments are covered by their own specs
+	userService := services.NewUserService(userRepo, services.WithAutoCreateDepartments(true))
 	userHandler := handlers.NewUserHandler(userService)
+	departmentHandler := handlers.NewDepartmentHandler(services.NewDepartmentService(userRepo))
+	graphQLHandler := handlers.NewGraphQLHandler(userService, validator.NewEchoValidator())
 
 	srv = echo.New()
 	srv.GET("/users", userHandler.ListUsers)
+	srv.GET("/users/count", userHandler.CountUsers)
+	srv.GET("/users/availability", userHandler.CheckAvailability)
 	srv.POST("/users", userHandler.CreateUser)
+	srv.POST("/users/batch", userHandler.CreateUsers)
+	srv.PUT("/users/batch", userHandler.UpdateUsers)
+	srv.POST("/users/status", userHandler.UpdateUsersStatus)
 	srv.GET("/users/:id", userHandler.GetUser)
+	srv.HEAD("/users/:id", userHandler.HeadUser)
+	srv.GET("/users/:id/history", userHandler.GetUserHistory)
+	srv.GET("/users/:id/reports", userHandler.GetUserReports)
 	srv.PUT("/users/:id", userHandler.UpdateUser)
 	srv.DELETE("/users/:id", userHandler.DeleteUser)
+	srv.POST("/admin/users/deactivate-stale", userHandler.DeactivateStaleUsers)
+	srv.GET("/departments", departmentHandler.ListDepartments)
+	srv.POST("/departments", departmentHandler.CreateDepartment)
+	srv.POST("/graphql", graphQLHandler.Handler(func(echo.Context) error { return nil }))
+	srv.POST("/graphql/read-only", graphQLHandler.Handler(func(echo.Context) error {
+		return errors.New("the users:write role is required")
+	}))
 
 	srv.Validator = validator.NewEchoValidator()
 })
@@ -156,6 +185,19 @@ var _ = Describe("User API", func() {
 		resp := httptest.NewRecorder()
 		srv.ServeHTTP(resp, req)
 		Expect(resp.Code).To(Equal(http.StatusAccepted))
+		Expect(resp.Header().Get(handlers.HeaderResourceAction)).To(Equal("deleted"))
+
+		var deleted models.UserDeleteResponse
+		Expect(json.Unmarshal(resp.Body.Bytes(), &deleted)).To(Succeed())
+		Expect(deleted).To(Equal(models.UserDeleteResponse{Deleted: true, UserID: 1}))
+	})
+
+	It("should return not found when deleting a non-existent user", func() {
+		req := httptest.NewRequest(http.MethodDelete, "/users/999", http.NoBody)
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		Expect(resp.Code).To(Equal(http.StatusNotFound))
+		Expect(resp.Header().Get(handlers.HeaderResourceAction)).To(BeEmpty())
 	})
 
 	It("should return error for non-existent user", func() {
@@ -0,0 +1,42 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	//revive:enable:dot-imports
+)
+
+var _ = Describe("HEAD /users/{id}", func() {
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		Expect(sendAs("", http.MethodPost, "/users", batchUser("present", "present@example.com")).Code).To(Equal(http.StatusCreated))
+	})
+
+	head := func(target string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, httptest.NewRequest(http.MethodHead, target, http.NoBody))
+		return resp
+	}
+
+	It("should answer 200 without a body for an existing user", func() {
+		resp := head("/users/1")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.Len()).To(BeZero())
+	})
+
+	It("should answer 404 without a body for a missing user", func() {
+		resp := head("/users/999")
+		Expect(resp.Code).To(Equal(http.StatusNotFound))
+		Expect(resp.Body.Len()).To(BeZero())
+	})
+
+	It("should answer 400 for an invalid ID", func() {
+		Expect(head("/users/abc").Code).To(Equal(http.StatusBadRequest))
+	})
+})
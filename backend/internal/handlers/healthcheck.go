@@ -3,14 +3,23 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
+	"user-management/internal/models"
 	"user-management/internal/services"
+	"user-management/internal/version"
 )
 
+// readinessTimeout bounds the database check of the readiness probe, so it fails fast
+const readinessTimeout = 2 * time.Second
+
 // Healthcheck handlers define the endpoint controllers
-// to access the API status
+// to access the API status.
+//
+// Their endpoints are served on the root, outside of the /api/v1 base path of the spec, which Swagger 2.0 can't
+// override per operation: their routes step out of it with /../.. so the Swagger UI still calls the right URL.
 type Healthcheck struct {
 	hcService services.Healthcheck
 }
@@ -21,18 +30,128 @@ func NewHealthcheckHandler(hcService services.Healthcheck) *Healthcheck {
 	return &Healthcheck{hcService}
 }
 
+// Ping answers pong, without checking anything
+//
+//	@Summary		Ping the server
+//	@Description	answer pong. Served at /ping, outside of the API base path.
+//	@Tags			health
+//	@Produce		plain
+//	@Success		200	{string}	string	"pong"
+//	@Router			/../../ping [get]
+func (h *Healthcheck) Ping(e echo.Context) error {
+	return e.String(http.StatusOK, "pong")
+}
+
 // GetAPIStatus returns the status of mongodb connection
-// when the last sync occours and the system info
+// when the last sync occours and the system info,
+// with 503 instead of 200 while the database is down so monitoring can alert on the status code
+//
+//	@Summary		Report the API status
+//	@Description	get the memory usage, goroutine count, GC and heap stats, uptime, database status, database clock skew and whether the migrations are up to date. Served at /status, outside of the API base path.
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	models.HealthStatus
+//	@Failure		503	{object}	models.HealthStatus	"the database is down, with the same report"
+//	@Router			/../../status [get]
 func (h *Healthcheck) GetAPIStatus(e echo.Context) error {
 	dbReady, err := h.hcService.DatabaseReady()
 	dbStatus := "OK"
+	code := http.StatusOK
 	if err != nil || !dbReady {
 		dbStatus = "FAIL"
+		code = http.StatusServiceUnavailable
 	}
 
-	return e.JSON(http.StatusOK, map[string]interface{}{
-		"mem_usage": fmt.Sprintf("%v MiB", h.hcService.GetMemUsage()/1024/1024),
-		"online_t":  h.hcService.OnlineSince().String(),
-		"db_status": dbStatus,
+	// time-based comparisons (updated_at, sync cursors) break silently when the clocks drift apart
+	skew, withinThreshold, err := h.hcService.ClockSkew()
+	clockStatus := "OK"
+	switch {
+	case err != nil:
+		clockStatus = "FAIL"
+	case !withinThreshold:
+		clockStatus = "DEGRADED"
+	}
+
+	pending, err := h.hcService.PendingMigrations(readinessTimeout)
+	stats := h.hcService.GetRuntimeStats()
+
+	return e.JSON(code, models.HealthStatus{
+		MemUsage:          mebibytes(h.hcService.GetMemUsage()),
+		NumGoroutine:      stats.NumGoroutine,
+		NumGC:             stats.NumGC,
+		HeapInuse:         mebibytes(stats.HeapInuse),
+		Sys:               mebibytes(stats.Sys),
+		OnlineTime:        h.hcService.OnlineSince().String(),
+		DBStatus:          dbStatus,
+		ClockSkew:         skew.String(),
+		ClockStatus:       clockStatus,
+		Migrations:        migrationsStatus(pending, err),
+		PendingMigrations: pending,
 	})
 }
+
+// mebibytes formats a number of bytes in whole MiB
+func mebibytes(bytes uint64) string {
+	return fmt.Sprintf("%v MiB", bytes/1024/1024)
+}
+
+// migrationsStatus returns the migration status of the HealthStatus for a PendingMigrations result
+func migrationsStatus(pending int, err error) string {
+	switch {
+	case err != nil:
+		return models.MigrationsUnknown
+	case pending > 0:
+		return models.MigrationsPending
+	default:
+		return models.MigrationsUpToDate
+	}
+}
+
+// Liveness answers 200 as long as the process is able to serve requests,
+// it doesn't check any dependency so a database outage doesn't get the pod restarted
+//
+//	@Summary		Liveness probe
+//	@Description	answer 200 while the process serves requests, whatever the state of the database. Served at /healthz, outside of the API base path.
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	models.ProbeStatus
+//	@Router			/../../healthz [get]
+func (h *Healthcheck) Liveness(e echo.Context) error {
+	return e.JSON(http.StatusOK, models.ProbeStatus{Status: "OK"})
+}
+
+// Readiness answers 503 while the database can't be reached or lacks migrations, so no traffic is routed to the instance
+// until the schema is the one the code expects
+//
+//	@Summary		Readiness probe
+//	@Description	answer 503 while the database can't be pinged within 2 seconds or has migrations to apply. Served at /readyz, outside of the API base path.
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	models.ProbeStatus
+//	@Failure		503	{object}	models.ProbeStatus
+//	@Router			/../../readyz [get]
+func (h *Healthcheck) Readiness(e echo.Context) error {
+	dbReady, err := h.hcService.DatabaseReadyWithin(readinessTimeout)
+	if err != nil || !dbReady {
+		return e.JSON(http.StatusServiceUnavailable, models.ProbeStatus{Status: "FAIL"})
+	}
+
+	if pending, err := h.hcService.PendingMigrations(readinessTimeout); err != nil || pending > 0 {
+		return e.JSON(http.StatusServiceUnavailable, models.ProbeStatus{Status: "FAIL"})
+	}
+
+	return e.JSON(http.StatusOK, models.ProbeStatus{Status: "OK"})
+}
+
+// GetVersion reports the version, revision and Go version of the running binary,
+// so deploy pipelines can check it matches what was shipped
+//
+//	@Summary		Report the build info
+//	@Description	get the version, revision, build time and Go version of the running binary. Served at /version, outside of the API base path.
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	version.Info
+//	@Router			/../../version [get]
+func (h *Healthcheck) GetVersion(e echo.Context) error {
+	return e.JSON(http.StatusOK, version.Get())
+}
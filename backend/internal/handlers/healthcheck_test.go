@@ -0,0 +1,171 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	//revive:enable:dot-imports
+
+	"user-management/internal/handlers"
+	"user-management/internal/models"
+	"user-management/internal/services"
+	"user-management/internal/version"
+)
+
+// stubHealthcheck reports a fixed database state
+type stubHealthcheck struct {
+	services.Healthcheck
+
+	dbErr   error
+	pending int
+}
+
+func (s *stubHealthcheck) GetMemUsage() uint64 {
+	return 8 << 20
+}
+
+func (s *stubHealthcheck) GetRuntimeStats() services.RuntimeStats {
+	return services.RuntimeStats{NumGoroutine: 12, NumGC: 3, HeapInuse: 6 << 20, Sys: 20 << 20}
+}
+
+func (s *stubHealthcheck) OnlineSince() time.Duration {
+	return time.Minute
+}
+
+func (s *stubHealthcheck) ClockSkew() (time.Duration, bool, error) {
+	if s.dbErr != nil {
+		return 0, false, s.dbErr
+	}
+	return 0, true, nil
+}
+
+func (s *stubHealthcheck) DatabaseReady() (bool, error) {
+	return s.dbErr == nil, s.dbErr
+}
+
+func (s *stubHealthcheck) DatabaseReadyWithin(time.Duration) (bool, error) {
+	return s.DatabaseReady()
+}
+
+func (s *stubHealthcheck) PendingMigrations(time.Duration) (int, error) {
+	return s.pending, s.dbErr
+}
+
+func probe(hcService services.Healthcheck, path string) *httptest.ResponseRecorder {
+	hc := handlers.NewHealthcheckHandler(hcService)
+
+	e := echo.New()
+	e.GET("/ping", hc.Ping)
+	e.GET("/status", hc.GetAPIStatus)
+	e.GET("/version", hc.GetVersion)
+	e.GET("/healthz", hc.Liveness)
+	e.GET("/readyz", hc.Readiness)
+
+	resp := httptest.NewRecorder()
+	e.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+	return resp
+}
+
+var _ = Describe("Health probes", func() {
+	It("is live and ready while the database is reachable", func() {
+		hcService := &stubHealthcheck{}
+
+		Expect(probe(hcService, "/healthz").Code).To(Equal(http.StatusOK))
+
+		resp := probe(hcService, "/readyz")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(MatchJSON(`{"status":"OK"}`))
+	})
+
+	It("stays live but isn't ready while the database is down", func() {
+		hcService := &stubHealthcheck{dbErr: errors.New("connection refused")}
+
+		Expect(probe(hcService, "/healthz").Code).To(Equal(http.StatusOK))
+
+		resp := probe(hcService, "/readyz")
+		Expect(resp.Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(resp.Body.String()).To(MatchJSON(`{"status":"FAIL"}`))
+	})
+
+	It("stays live but isn't ready while migrations are pending", func() {
+		hcService := &stubHealthcheck{pending: 2}
+
+		Expect(probe(hcService, "/healthz").Code).To(Equal(http.StatusOK))
+
+		resp := probe(hcService, "/readyz")
+		Expect(resp.Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(resp.Body.String()).To(MatchJSON(`{"status":"FAIL"}`))
+	})
+})
+
+var _ = Describe("Ping", func() {
+	It("answers pong", func() {
+		resp := probe(&stubHealthcheck{}, "/ping")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(Equal("pong"))
+	})
+})
+
+var _ = Describe("API status", func() {
+	It("responds with 200 while everything is healthy", func() {
+		resp := probe(&stubHealthcheck{}, "/status")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(ContainSubstring(`"db_status":"OK"`))
+		Expect(resp.Body.String()).To(ContainSubstring(`"migrations":"up_to_date"`))
+		Expect(resp.Body.String()).NotTo(ContainSubstring(`"pending_migrations"`))
+	})
+
+	It("reports the runtime stats along with the memory usage", func() {
+		resp := probe(&stubHealthcheck{}, "/status")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+
+		var status models.HealthStatus
+		Expect(json.Unmarshal(resp.Body.Bytes(), &status)).To(Succeed())
+		Expect(status.MemUsage).To(Equal("8 MiB"))
+		Expect(status.NumGoroutine).To(Equal(12))
+		Expect(status.NumGC).To(Equal(uint32(3)))
+		Expect(status.HeapInuse).To(Equal("6 MiB"))
+		Expect(status.Sys).To(Equal("20 MiB"))
+	})
+
+	It("reports the pending migrations without failing", func() {
+		resp := probe(&stubHealthcheck{pending: 2}, "/status")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(ContainSubstring(`"migrations":"pending"`))
+		Expect(resp.Body.String()).To(ContainSubstring(`"pending_migrations":2`))
+	})
+
+	It("responds with 503 and the same report while the database is down", func() {
+		resp := probe(&stubHealthcheck{dbErr: errors.New("connection refused")}, "/status")
+		Expect(resp.Code).To(Equal(http.StatusServiceUnavailable))
+
+		var status map[string]any
+		Expect(json.Unmarshal(resp.Body.Bytes(), &status)).To(Succeed())
+		Expect(status).To(HaveKeyWithValue("db_status", "FAIL"))
+		Expect(status).To(HaveKeyWithValue("clock_status", "FAIL"))
+		Expect(status).To(HaveKeyWithValue("migrations", "unknown"))
+		Expect(status).To(HaveKey("mem_usage"))
+	})
+})
+
+var _ = Describe("Version", func() {
+	It("reports the build info", func() {
+		resp := probe(&stubHealthcheck{}, "/version")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+
+		var info version.Info
+		Expect(json.Unmarshal(resp.Body.Bytes(), &info)).To(Succeed())
+		Expect(info).To(Equal(version.Get()))
+		Expect(info.GoVersion).To(Equal(runtime.Version()))
+	})
+})
@@ -0,0 +1,80 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	//revive:enable:dot-imports
+
+	"user-management/internal/models"
+)
+
+func getHistory(target string) (*httptest.ResponseRecorder, []models.AuditEntry) {
+	resp := httptest.NewRecorder()
+	srv.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, target, http.NoBody))
+
+	var entries []models.AuditEntry
+	if resp.Code == http.StatusOK {
+		Expect(json.Unmarshal(resp.Body.Bytes(), &entries)).To(Succeed())
+	}
+	return resp, entries
+}
+
+var _ = Describe("User history", func() {
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		Expect(sendAs("alice", http.MethodPost, "/users", batchUser("tracked", "tracked@example.com")).Code).To(Equal(http.StatusCreated))
+	})
+
+	It("should record every change newest first, and keep it after the deletion", func() {
+		Expect(sendAs("bob", http.MethodPut, "/users/1", batchUser("tracked", "moved@example.com")).Code).To(Equal(http.StatusOK))
+		Expect(sendAs("carol", http.MethodDelete, "/users/1", nil).Code).To(Equal(http.StatusAccepted))
+
+		resp, entries := getHistory("/users/1/history")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(entries).To(HaveLen(3))
+
+		Expect(entries[0].Action).To(Equal(models.AuditActionDelete))
+		Expect(entries[0].Actor).To(Equal("carol"))
+		Expect(entries[0].Diff).To(HaveKeyWithValue("email", models.AuditChange{Old: "moved@example.com"}))
+
+		Expect(entries[1].Action).To(Equal(models.AuditActionUpdate))
+		Expect(entries[1].Actor).To(Equal("bob"))
+		Expect(entries[1].Diff).To(Equal(map[string]models.AuditChange{
+			"email": {Old: "tracked@example.com", New: "moved@example.com"},
+		}))
+
+		Expect(entries[2].Action).To(Equal(models.AuditActionCreate))
+		Expect(entries[2].Actor).To(Equal("alice"))
+		Expect(entries[2].UserID).To(Equal(int64(1)))
+		Expect(entries[2].Diff).To(HaveKeyWithValue("userName", models.AuditChange{New: "tracked"}))
+	})
+
+	It("should record the batch changes", func() {
+		Expect(putBatch([]models.UserBatchUpdateItem{updateItem(1, "renamed", "tracked@example.com")}).Code).To(Equal(http.StatusOK))
+
+		_, entries := getHistory("/users/1/history")
+		Expect(entries).To(HaveLen(2))
+		Expect(entries[0].Actor).To(Equal("system"))
+		Expect(entries[0].Diff).To(Equal(map[string]models.AuditChange{"userName": {Old: "tracked", New: "renamed"}}))
+	})
+
+	It("should not record a dry run", func() {
+		Expect(dryRun(http.MethodPut, "/users/1", batchUser("tracked", "moved@example.com")).Code).To(Equal(http.StatusOK))
+
+		_, entries := getHistory("/users/1/history")
+		Expect(entries).To(HaveLen(1))
+	})
+
+	It("should answer 404 for an unknown user", func() {
+		resp, _ := getHistory("/users/99/history")
+		Expect(resp.Code).To(Equal(http.StatusNotFound))
+	})
+})
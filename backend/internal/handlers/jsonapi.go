@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"user-management/internal/models"
+)
+
+const (
+	// MIMEApplicationJSONAPI is the media type defined by the JSON:API specification.
+	MIMEApplicationJSONAPI = "application/vnd.api+json"
+
+	jsonAPIUserType = "users"
+)
+
+// jsonAPIResource is a single JSON:API resource object.
+type jsonAPIResource struct {
+	Type       string                     `json:"type"`
+	ID         string                     `json:"id"`
+	Attributes map[string]json.RawMessage `json:"attributes"`
+}
+
+// jsonAPIDocument is the top-level JSON:API document.
+type jsonAPIDocument struct {
+	Data  any               `json:"data"`
+	Links map[string]string `json:"links,omitempty"`
+	Meta  map[string]any    `json:"meta,omitempty"`
+}
+
+// wantsJSONAPI reports whether the client asked for a JSON:API response.
+func wantsJSONAPI(c echo.Context) bool {
+	return strings.Contains(c.Request().Header.Get(echo.HeaderAccept), MIMEApplicationJSONAPI)
+}
+
+// newUserResource converts a user into a JSON:API resource object,
+// moving every field except the ID into the attributes.
+func newUserResource(user *models.User) (jsonAPIResource, error) {
+	raw, err := json.Marshal(user)
+	if err != nil {
+		return jsonAPIResource{}, err
+	}
+
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &attributes); err != nil {
+		return jsonAPIResource{}, err
+	}
+	delete(attributes, "id")
+
+	return jsonAPIResource{
+		Type:       jsonAPIUserType,
+		ID:         strconv.FormatInt(user.UserID, 10),
+		Attributes: attributes,
+	}, nil
+}
+
+// renderJSONAPI writes the document with the JSON:API media type.
+func renderJSONAPI(c echo.Context, status int, doc jsonAPIDocument) error {
+	if doc.Links == nil {
+		doc.Links = map[string]string{}
+	}
+	if _, ok := doc.Links["self"]; !ok {
+		doc.Links["self"] = c.Request().URL.String()
+	}
+
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	return c.Blob(status, MIMEApplicationJSONAPI, body)
+}
+
+// respondUser writes a single user either as plain JSON (default)
+// or as a JSON:API document when requested via the Accept header.
+func respondUser(c echo.Context, status int, user *models.User) error {
+	if !wantsJSONAPI(c) {
+		return c.JSON(status, user)
+	}
+
+	resource, err := newUserResource(user)
+	if err != nil {
+		return err
+	}
+
+	return renderJSONAPI(c, status, jsonAPIDocument{Data: resource})
+}
+
+// respondUsers writes a page of users either as plain JSON (default)
+// or as a JSON:API document when requested via the Accept header.
+// The JSON:API document carries the paging in its meta and links.
+func respondUsers(c echo.Context, status int, list *models.UserListResponse) error {
+	if !wantsJSONAPI(c) {
+		return c.JSON(status, list)
+	}
+
+	resources := make([]jsonAPIResource, 0, len(list.Users))
+	for i := range list.Users {
+		resource, err := newUserResource(&list.Users[i])
+		if err != nil {
+			return err
+		}
+		resources = append(resources, resource)
+	}
+
+	return renderJSONAPI(c, status, jsonAPIDocument{
+		Data:  resources,
+		Links: pageLinks(c, list),
+		Meta: map[string]any{
+			"total":  list.Total,
+			"limit":  list.Limit,
+			"offset": list.Offset,
+		},
+	})
+}
+
+// pageLinks builds the JSON:API first/prev/next links for a page,
+// keeping every other query parameter of the request.
+func pageLinks(c echo.Context, list *models.UserListResponse) map[string]string {
+	link := func(offset int) string {
+		u := *c.Request().URL
+		query := u.Query()
+		query.Set("limit", strconv.Itoa(list.Limit))
+		query.Set("offset", strconv.Itoa(offset))
+		u.RawQuery = query.Encode()
+		return u.String()
+	}
+
+	// a cursor page links to the next one by its cursor, it doesn't know its offset
+	if c.QueryParam("cursor") != "" {
+		u := *c.Request().URL
+		query := u.Query()
+		query.Del("cursor")
+		u.RawQuery = query.Encode()
+		links := map[string]string{"first": u.String()}
+		if list.NextCursor != "" {
+			query.Set("cursor", list.NextCursor)
+			u.RawQuery = query.Encode()
+			links["next"] = u.String()
+		}
+		return links
+	}
+
+	links := map[string]string{"first": link(0)}
+	if list.Offset > 0 {
+		links["prev"] = link(max(list.Offset-list.Limit, 0))
+	}
+	if list.Offset+list.Limit < list.Total {
+		links["next"] = link(list.Offset + list.Limit)
+	}
+	return links
+}
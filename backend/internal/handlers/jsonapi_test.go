@@ -0,0 +1,101 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	//revive:enable:dot-imports
+
+	"user-management/internal/handlers"
+	"user-management/internal/models"
+)
+
+type jsonAPIResource struct {
+	Type       string         `json:"type"`
+	ID         string         `json:"id"`
+	Attributes map[string]any `json:"attributes"`
+}
+
+var _ = Describe("JSON:API responses", func() {
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		user := models.UserCreateRequest{
+			UserCommon: models.UserCommon{
+				UserName:   "jsonapi",
+				FirstName:  "Jason",
+				LastName:   "Api",
+				Email:      "jason@api.com",
+				UserStatus: models.UserStatusActive,
+			},
+		}
+		jsonBody, err := json.Marshal(user)
+		Expect(err).NotTo(HaveOccurred())
+		req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		Expect(resp.Code).To(Equal(http.StatusCreated))
+	})
+
+	It("should keep plain JSON as the default", func() {
+		req := httptest.NewRequest(http.MethodGet, "/users/1", http.NoBody)
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Content-Type")).To(HavePrefix("application/json"))
+
+		var user models.User
+		Expect(json.Unmarshal(resp.Body.Bytes(), &user)).To(Succeed())
+		Expect(user.UserName).To(Equal("jsonapi"))
+	})
+
+	It("should wrap a single user in a JSON:API document", func() {
+		req := httptest.NewRequest(http.MethodGet, "/users/1", http.NoBody)
+		req.Header.Set("Accept", handlers.MIMEApplicationJSONAPI)
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Content-Type")).To(Equal(handlers.MIMEApplicationJSONAPI))
+
+		var doc struct {
+			Data  jsonAPIResource   `json:"data"`
+			Links map[string]string `json:"links"`
+		}
+		Expect(json.Unmarshal(resp.Body.Bytes(), &doc)).To(Succeed())
+		Expect(doc.Data.Type).To(Equal("users"))
+		Expect(doc.Data.ID).To(Equal("1"))
+		Expect(doc.Data.Attributes).To(HaveKeyWithValue("userName", "jsonapi"))
+		Expect(doc.Data.Attributes).NotTo(HaveKey("id"))
+		Expect(doc.Links).To(HaveKeyWithValue("self", "/users/1"))
+	})
+
+	It("should wrap a user list in a JSON:API document", func() {
+		req := httptest.NewRequest(http.MethodGet, "/users", http.NoBody)
+		req.Header.Set("Accept", handlers.MIMEApplicationJSONAPI)
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Content-Type")).To(Equal(handlers.MIMEApplicationJSONAPI))
+
+		var doc struct {
+			Data  []jsonAPIResource `json:"data"`
+			Links map[string]string `json:"links"`
+		}
+		Expect(json.Unmarshal(resp.Body.Bytes(), &doc)).To(Succeed())
+		Expect(doc.Data).To(HaveLen(1))
+		Expect(doc.Data[0].Type).To(Equal("users"))
+		Expect(doc.Data[0].Attributes).To(HaveKeyWithValue("email", "jason@api.com"))
+		Expect(doc.Links).To(HaveKey("self"))
+		Expect(doc.Links).To(HaveKeyWithValue("first", "/users?limit=50&offset=0"))
+		Expect(doc.Links).NotTo(HaveKey("prev"))
+		Expect(doc.Links).NotTo(HaveKey("next"))
+	})
+})
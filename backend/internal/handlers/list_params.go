@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"user-management/internal/models"
+	"user-management/internal/services"
+)
+
+// parseListParams reads the list query parameters, rejecting malformed values rather than silently defaulting.
+// Every rejected parameter is reported at once in a *services.InvalidParamsError.
+func parseListParams(c echo.Context) (models.ListParams, error) {
+	params := models.ListParams{
+		Query:          strings.TrimSpace(c.QueryParam("q")),
+		Sort:           c.QueryParam("sort"),
+		Order:          strings.ToLower(c.QueryParam("order")),
+		Status:         models.UserStatus(c.QueryParam("status")),
+		Department:     c.QueryParam("department"),
+		DepartmentLike: strings.TrimSpace(c.QueryParam("department_like")),
+		Limit:          models.DefaultListLimit,
+	}
+
+	var invalid []models.InvalidParam
+
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			invalid = append(invalid, models.InvalidParam{Name: "limit", Reason: "must be a positive integer"})
+		} else {
+			params.Limit = min(limit, models.MaxListLimit)
+		}
+	}
+
+	if raw := c.QueryParam("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			invalid = append(invalid, models.InvalidParam{Name: "offset", Reason: "must be a non-negative integer"})
+		} else {
+			params.Offset = offset
+		}
+	}
+
+	for _, bound := range []struct {
+		name string
+		dest *time.Time
+	}{
+		{"createdAfter", &params.CreatedAfter},
+		{"createdBefore", &params.CreatedBefore},
+		{"updatedAfter", &params.UpdatedAfter},
+		{"updatedBefore", &params.UpdatedBefore},
+	} {
+		if raw := c.QueryParam(bound.name); raw != "" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				invalid = append(invalid, models.InvalidParam{Name: bound.name, Reason: "must be an RFC 3339 time, e.g. 2025-01-31T00:00:00Z"})
+			} else {
+				// in UTC the same instant is always the same params, e.g. for the list cache
+				*bound.dest = t.UTC()
+			}
+		}
+	}
+
+	if raw := c.QueryParam("cursor"); raw != "" {
+		afterID, err := decodeCursor(raw)
+		if err != nil {
+			invalid = append(invalid, models.InvalidParam{Name: "cursor", Reason: "must be the nextCursor of a previous page"})
+		} else {
+			params.AfterID = afterID
+		}
+	}
+
+	// the sort, order, status, filter and cursor combinations are checked by the service
+	var invalidErr *services.InvalidParamsError
+	if err := services.ValidateListParams(params); errors.As(err, &invalidErr) {
+		invalid = append(invalid, invalidErr.Params...)
+	}
+
+	if len(invalid) > 0 {
+		return params, &services.InvalidParamsError{Params: invalid}
+	}
+	return params, nil
+}
+
+// encodeCursor returns the opaque cursor of the page after the given user ID, the base64-encoded ID
+func encodeCursor(lastID int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(lastID, 10)))
+}
+
+// decodeCursor returns the user ID the cursor continues after
+func decodeCursor(cursor string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || id < 1 {
+		return 0, errors.New("invalid cursor")
+	}
+	return id, nil
+}
+
+// nextCursor returns the cursor of the page after the users, empty when there's none to follow:
+// the page isn't full or it isn't in the user_id order keyset pagination follows
+func nextCursor(params models.ListParams, users []models.User) string {
+	if params.Limit <= 0 || len(users) < params.Limit || !services.IsKeysetOrder(params) {
+		return ""
+	}
+	return encodeCursor(users[len(users)-1].UserID)
+}
+
+// respondInvalidParams writes the 400 response listing every rejected parameter
+func respondInvalidParams(c echo.Context, err *services.InvalidParamsError) error {
+	return c.JSON(http.StatusBadRequest, models.InvalidParamsResponse{
+		ErrorResponse: models.NewErrorResponse(http.StatusBadRequest, err.Error()),
+		InvalidParams: err.Params,
+	})
+}
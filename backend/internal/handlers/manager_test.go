@@ -0,0 +1,116 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	//revive:enable:dot-imports
+
+	"user-management/internal/models"
+)
+
+func getReports(target string) (*httptest.ResponseRecorder, []models.User) {
+	resp := httptest.NewRecorder()
+	srv.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, target, http.NoBody))
+
+	var reports []models.User
+	if resp.Code == http.StatusOK {
+		Expect(json.Unmarshal(resp.Body.Bytes(), &reports)).To(Succeed())
+	}
+	return resp, reports
+}
+
+// managedUser is batchUser managed by the given user
+func managedUser(userName string, managerID int64) models.UserCreateRequest {
+	user := batchUser(userName, userName+"@example.com")
+	user.ManagerID = &managerID
+	return user
+}
+
+var _ = Describe("User managers", func() {
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		// alice manages bobby, who manages carol
+		Expect(sendAs("", http.MethodPost, "/users", batchUser("alice", "alice@example.com")).Code).To(Equal(http.StatusCreated))
+		Expect(sendAs("", http.MethodPost, "/users", managedUser("bobby", 1)).Code).To(Equal(http.StatusCreated))
+		Expect(sendAs("", http.MethodPost, "/users", managedUser("carol", 2)).Code).To(Equal(http.StatusCreated))
+	})
+
+	It("should list the direct reports", func() {
+		resp, reports := getReports("/users/1/reports")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(userNames(reports)).To(Equal([]string{"bobby"}))
+
+		resp, reports = getReports("/users/3/reports")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(reports).To(BeEmpty())
+
+		resp, _ = getReports("/users/99/reports")
+		Expect(resp.Code).To(Equal(http.StatusNotFound))
+		resp, _ = getReports("/users/abc/reports")
+		Expect(resp.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should return the user with their manager", func() {
+		user := decodeUser(sendAs("", http.MethodGet, "/users/3", nil))
+		Expect(*user.ManagerID).To(Equal(int64(2)))
+		Expect(user.Manager).NotTo(BeNil())
+		Expect(user.Manager.UserName).To(Equal("bobby"))
+		Expect(user.Manager.Manager).To(BeNil(), "only one level is loaded")
+	})
+
+	It("should reject a management cycle", func() {
+		resp := sendAs("", http.MethodPut, "/users/1", managedUser("alice", 1).UserCommon)
+		Expect(validationErrors(resp.Code, resp.Body.Bytes())).To(ConsistOf(
+			models.FieldError{Field: "managerId", Tag: "cycle", Message: "manager 1 would create a management cycle"},
+		))
+
+		resp = sendAs("", http.MethodPut, "/users/1", managedUser("alice", 3).UserCommon)
+		Expect(validationErrors(resp.Code, resp.Body.Bytes())).To(ConsistOf(
+			models.FieldError{Field: "managerId", Tag: "cycle", Message: "manager 3 would create a management cycle"},
+		))
+
+		resp = sendAs("", http.MethodPost, "/users", managedUser("dave", 99))
+		Expect(validationErrors(resp.Code, resp.Body.Bytes())).To(ConsistOf(
+			models.FieldError{Field: "managerId", Tag: "exists", Message: "unknown manager 99"},
+		))
+	})
+
+	It("should check the batch items against the earlier ones", func() {
+		// alice can't be managed by carol while carol is below her
+		bobby := models.UserBatchUpdateItem{UserID: 2, UserCommon: managedUser("bobby", 1).UserCommon}
+		alice := models.UserBatchUpdateItem{UserID: 1, UserCommon: managedUser("alice", 3).UserCommon}
+		resp := putBatch([]models.UserBatchUpdateItem{bobby, alice})
+		Expect(validationErrors(resp.Code, resp.Body.Bytes())).To(ConsistOf(
+			models.FieldError{Field: "[1].managerId", Tag: "cycle", Message: "manager 3 would create a management cycle"},
+		))
+
+		// she can once bobby, and carol with him, left her team
+		bobby.ManagerID = nil
+		Expect(putBatch([]models.UserBatchUpdateItem{bobby, alice}).Code).To(Equal(http.StatusOK))
+		_, reports := getReports("/users/3/reports")
+		Expect(userNames(reports)).To(Equal([]string{"alice"}))
+	})
+
+	It("should only delete a manager along with the reassignment of their reports", func() {
+		resp := sendAs("", http.MethodDelete, "/users/2", nil)
+		Expect(resp.Code).To(Equal(http.StatusConflict))
+		Expect(resp.Body.String()).To(ContainSubstring("user has direct reports"))
+
+		Expect(sendAs("", http.MethodDelete, "/users/2?reassignTo=2", nil).Code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(sendAs("", http.MethodDelete, "/users/1?reassignTo=3", nil).Code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(sendAs("", http.MethodDelete, "/users/2?reassignTo=abc", nil).Code).To(Equal(http.StatusBadRequest))
+		Expect(countUsers()).To(Equal(3))
+
+		Expect(sendAs("", http.MethodDelete, "/users/2?reassignTo=1", nil).Code).To(Equal(http.StatusAccepted))
+		_, reports := getReports("/users/1/reports")
+		Expect(userNames(reports)).To(Equal([]string{"carol"}))
+	})
+})
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"user-management/internal/models"
+	"user-management/internal/services"
+)
+
+const (
+	// MIMEApplicationNDJSON is the media type of the newline-delimited JSON streams, one JSON value per line.
+	MIMEApplicationNDJSON = "application/x-ndjson"
+
+	// ndjsonFlushEvery is the number of users written between two flushes of a stream
+	ndjsonFlushEvery = 100
+	// ndjsonWriteWindow is the time allowed to write the users between two flushes. It replaces the server's
+	// write timeout, which bounds the whole response, so a long export isn't cut off while a stalled client still is.
+	ndjsonWriteWindow = 30 * time.Second
+)
+
+// ResponseControllerKey is the echo context key of the *http.ResponseController of the connection's response writer.
+// The server sets it before any middleware wraps the writer, some wrappers (the request logs') can't be unwrapped
+// to extend the write deadline of a stream.
+const ResponseControllerKey = "http.response_controller"
+
+// responseController returns the controller set under ResponseControllerKey, or the one of the echo response
+func responseController(c echo.Context) *http.ResponseController {
+	if controller, ok := c.Get(ResponseControllerKey).(*http.ResponseController); ok {
+		return controller
+	}
+	return http.NewResponseController(c.Response())
+}
+
+// wantsNDJSON reports whether the client asked for a newline-delimited JSON stream.
+func wantsNDJSON(c echo.Context) bool {
+	return strings.Contains(c.Request().Header.Get(echo.HeaderAccept), MIMEApplicationNDJSON)
+}
+
+// streamUsers writes the users of the stream one per line as they come, flushing every ndjsonFlushEvery users.
+// An error before the first user is returned for the handler to answer as usual. Past it the 200 is sent already,
+// so the connection is cut instead, for the client not to mistake the truncated stream for the whole list.
+func streamUsers(c echo.Context, stream func(fn func(user *models.User) error) error) error {
+	res := c.Response()
+	controller := responseController(c)
+	encoder := json.NewEncoder(res)
+
+	start := func() {
+		res.Header().Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+		res.WriteHeader(http.StatusOK)
+		// not supported by every writer, e.g. the test recorders
+		_ = controller.SetWriteDeadline(time.Now().Add(ndjsonWriteWindow))
+	}
+
+	written := 0
+	err := stream(func(user *models.User) error {
+		if written == 0 {
+			start()
+		}
+		if err := encoder.Encode(user); err != nil {
+			return err
+		}
+		written++
+		if written%ndjsonFlushEvery == 0 {
+			res.Flush()
+			_ = controller.SetWriteDeadline(time.Now().Add(ndjsonWriteWindow))
+		}
+		return nil
+	})
+
+	switch {
+	case err == nil && written == 0:
+		start()
+		return nil
+	case err == nil:
+		res.Flush()
+		return nil
+	case !res.Committed:
+		return err
+	}
+
+	// a client hanging up midway isn't worth an error
+	ctx := c.Request().Context()
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		slog.With("request_id", services.RequestIDFrom(ctx)).
+			With("users_written", written).
+			With("error", err).
+			Error("failed to stream the users, cutting the response off")
+	}
+	panic(http.ErrAbortHandler)
+}
@@ -0,0 +1,109 @@
+package handlers_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	//revive:enable:dot-imports
+
+	"user-management/internal/handlers"
+	"user-management/internal/models"
+)
+
+var _ = Describe("NDJSON user streams", func() {
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		for i, status := range []models.UserStatus{models.UserStatusActive, models.UserStatusInactive, models.UserStatusActive} {
+			user := models.UserCreateRequest{
+				UserCommon: models.UserCommon{
+					UserName:   fmt.Sprintf("stream%d", i),
+					FirstName:  "Stream",
+					LastName:   "User",
+					Email:      fmt.Sprintf("stream%d@example.com", i),
+					UserStatus: status,
+				},
+			}
+			jsonBody, err := json.Marshal(user)
+			Expect(err).NotTo(HaveOccurred())
+			req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			srv.ServeHTTP(resp, req)
+			Expect(resp.Code).To(Equal(http.StatusCreated))
+		}
+	})
+
+	stream := func(target string) (*httptest.ResponseRecorder, []models.User) {
+		req := httptest.NewRequest(http.MethodGet, target, http.NoBody)
+		req.Header.Set("Accept", handlers.MIMEApplicationNDJSON)
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+
+		var users []models.User
+		if resp.Code == http.StatusOK {
+			scanner := bufio.NewScanner(resp.Body)
+			for scanner.Scan() {
+				var user models.User
+				Expect(json.Unmarshal(scanner.Bytes(), &user)).To(Succeed())
+				users = append(users, user)
+			}
+		}
+		return resp, users
+	}
+
+	It("should stream every matching user, one per line", func() {
+		resp, users := stream("/users")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Content-Type")).To(Equal(handlers.MIMEApplicationNDJSON))
+		Expect(resp.Header().Get("ETag")).To(BeEmpty())
+		Expect(users).To(HaveLen(3))
+		Expect(users[0].UserName).To(Equal("stream0"))
+		Expect(users[2].UserName).To(Equal("stream2"))
+	})
+
+	It("should apply the filters, the order and a given limit", func() {
+		_, users := stream("/users?status=A&sort=user_name&order=desc")
+		Expect(users).To(HaveLen(2))
+		Expect(users[0].UserName).To(Equal("stream2"))
+		Expect(users[1].UserName).To(Equal("stream0"))
+
+		_, users = stream("/users?limit=1&offset=1")
+		Expect(users).To(HaveLen(1))
+		Expect(users[0].UserName).To(Equal("stream1"))
+	})
+
+	It("should answer an empty stream when no user matches", func() {
+		resp, users := stream("/users?q=nobody")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Content-Type")).To(Equal(handlers.MIMEApplicationNDJSON))
+		Expect(users).To(BeEmpty())
+	})
+
+	It("should reject invalid parameters with the usual JSON error", func() {
+		resp, _ := stream("/users?sort=password")
+		Expect(resp.Code).To(Equal(http.StatusBadRequest))
+		Expect(resp.Header().Get("Content-Type")).To(HavePrefix("application/json"))
+	})
+
+	It("should keep the JSON list for the other clients", func() {
+		req := httptest.NewRequest(http.MethodGet, "/users", http.NoBody)
+		req.Header.Set("Accept", "application/json")
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		Expect(resp.Code).To(Equal(http.StatusOK))
+
+		var list models.UserListResponse
+		Expect(json.Unmarshal(resp.Body.Bytes(), &list)).To(Succeed())
+		Expect(list.Total).To(Equal(3))
+	})
+})
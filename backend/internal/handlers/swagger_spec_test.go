@@ -0,0 +1,144 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	//revive:enable:dot-imports
+
+	"user-management/docs/swagger"
+	"user-management/internal/models"
+)
+
+// apiBasePath is the prefix of the router group serving the user API
+const apiBasePath = "/api/v1"
+
+// loadSpec converts the served swagger 2.0 document into OpenAPI 3,
+// forbidding undocumented properties on every documented object.
+func loadSpec() *openapi3.T {
+	var v2 openapi2.T
+	Expect(json.Unmarshal([]byte(swagger.SwaggerInfo.ReadDoc()), &v2)).To(Succeed())
+
+	doc, err := openapi2conv.ToV3(&v2)
+	Expect(err).NotTo(HaveOccurred())
+
+	for _, schema := range doc.Components.Schemas {
+		if schema.Value != nil && len(schema.Value.Properties) > 0 {
+			schema.Value.AdditionalProperties = openapi3.AdditionalProperties{Has: openapi3.BoolPtr(false)}
+		}
+	}
+
+	Expect(doc.Validate(context.TODO())).To(Succeed())
+	return doc
+}
+
+// specChecker executes requests against the test server
+// and validates the responses against the documented schema.
+type specChecker struct {
+	router routers.Router
+	host   string
+}
+
+func (s *specChecker) do(method, path string, body any) *httptest.ResponseRecorder {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.ServeHTTP(resp, req)
+
+	// the test server mounts the handlers without the group prefix,
+	// the spec has to describe them under the real one (the scheme defaults to https when none is documented)
+	specReq, err := http.NewRequest(method, "https://"+s.host+apiBasePath+path, bytes.NewReader(payload))
+	Expect(err).NotTo(HaveOccurred())
+	specReq.Header.Set("Content-Type", "application/json")
+
+	route, pathParams, err := s.router.FindRoute(specReq)
+	Expect(err).NotTo(HaveOccurred(), "%s %s is not documented", method, apiBasePath+path)
+
+	err = openapi3filter.ValidateResponse(context.TODO(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    specReq,
+			PathParams: pathParams,
+			Route:      route,
+		},
+		Status:  resp.Code,
+		Header:  resp.Header(),
+		Body:    io.NopCloser(bytes.NewReader(resp.Body.Bytes())),
+		Options: &openapi3filter.Options{IncludeResponseStatus: true},
+	})
+	Expect(err).NotTo(HaveOccurred(), "%s %s responded with %d: %s", method, path, resp.Code, resp.Body.String())
+
+	return resp
+}
+
+var _ = Describe("Swagger spec", func() {
+	var checker *specChecker
+
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		doc := loadSpec()
+		router, err := gorillamux.NewRouter(doc)
+		Expect(err).NotTo(HaveOccurred())
+
+		checker = &specChecker{router: router, host: swagger.SwaggerInfo.Host}
+	})
+
+	It("should document the user responses", func() {
+		user := models.UserCreateRequest{
+			UserCommon: models.UserCommon{
+				UserName:   "swagger",
+				FirstName:  "Swag",
+				LastName:   "Ger",
+				Email:      "swag@ger.com",
+				UserStatus: models.UserStatusActive,
+				Department: "Docs",
+			},
+		}
+
+		Expect(checker.do(http.MethodPost, "/users", user).Code).To(Equal(http.StatusCreated))
+		Expect(checker.do(http.MethodGet, "/users", nil).Code).To(Equal(http.StatusOK))
+		Expect(checker.do(http.MethodGet, "/users?sort=random&limit=0", nil).Code).To(Equal(http.StatusBadRequest))
+		Expect(checker.do(http.MethodGet, "/users/1", nil).Code).To(Equal(http.StatusOK))
+		Expect(checker.do(http.MethodPut, "/users/1", models.UserUpdateRequest{UserCommon: user.UserCommon}).Code).To(Equal(http.StatusOK))
+		Expect(checker.do(http.MethodDelete, "/users/1", nil).Code).To(Equal(http.StatusAccepted))
+		Expect(checker.do(http.MethodDelete, "/users/1", nil).Code).To(Equal(http.StatusNotFound))
+		Expect(checker.do(http.MethodGet, "/users/1/history", nil).Code).To(Equal(http.StatusOK))
+		Expect(checker.do(http.MethodGet, "/users/99/history", nil).Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should document the batch responses", func() {
+		users := []models.UserCreateRequest{batchUser("swagger", "swag@ger.com"), batchUser("swagger", "other@ger.com")}
+
+		Expect(checker.do(http.MethodPost, "/users/batch?mode=ignore", users).Code).To(Equal(http.StatusCreated))
+		Expect(checker.do(http.MethodPut, "/users/batch", []models.UserBatchUpdateItem{updateItem(1, "swagger", "new@ger.com")}).Code).To(Equal(http.StatusOK))
+		Expect(checker.do(http.MethodPut, "/users/batch", []models.UserBatchUpdateItem{updateItem(99, "ghost", "ghost@ger.com")}).Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should document the error responses", func() {
+		Expect(checker.do(http.MethodGet, "/users/999", nil).Code).To(Equal(http.StatusNotFound))
+		Expect(checker.do(http.MethodPost, "/users", models.UserCreateRequest{}).Code).To(Equal(http.StatusUnprocessableEntity))
+	})
+})
@@ -0,0 +1,52 @@
+package handlers_test
+
+import (
+	"net/http"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	//revive:enable:dot-imports
+)
+
+var _ = Describe("GET /users/availability", func() {
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		Expect(sendAs("", http.MethodPost, "/users", batchUser("taken", "taken@example.com")).Code).To(Equal(http.StatusCreated))
+	})
+
+	check := func(query string) (int, string) {
+		resp := sendAs("", http.MethodGet, "/users/availability"+query, nil)
+		return resp.Code, resp.Body.String()
+	}
+
+	It("should report the taken email and user name", func() {
+		code, body := check("?email=taken@example.com&username=taken")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(`{"emailAvailable":false,"usernameAvailable":false}`))
+	})
+
+	It("should report the free email and user name", func() {
+		code, body := check("?email=free@example.com&username=free")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(`{"emailAvailable":true,"usernameAvailable":true}`))
+	})
+
+	It("should only check the given parameter", func() {
+		code, body := check("?username=taken")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(`{"usernameAvailable":false}`))
+
+		code, body = check("?email=free@example.com")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(`{"emailAvailable":true}`))
+	})
+
+	It("should answer 400 without any parameter", func() {
+		code, body := check("")
+		Expect(code).To(Equal(http.StatusBadRequest))
+		Expect(body).To(MatchJSON(`{"code":"bad_request","message":"at least one of email and username is required"}`))
+	})
+})
@@ -0,0 +1,223 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	//revive:enable:dot-imports
+
+	"user-management/internal/models"
+)
+
+func batchUser(userName, email string) models.UserCreateRequest {
+	return models.UserCreateRequest{
+		UserCommon: models.UserCommon{
+			UserName:   userName,
+			FirstName:  "Batch",
+			LastName:   "User",
+			Email:      email,
+			UserStatus: models.UserStatusActive,
+		},
+	}
+}
+
+func postBatch(mode string, users []models.UserCreateRequest) *httptest.ResponseRecorder {
+	jsonBody, err := json.Marshal(users)
+	Expect(err).NotTo(HaveOccurred())
+	req := httptest.NewRequest(http.MethodPost, "/users/batch?mode="+mode, bytes.NewReader(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.ServeHTTP(resp, req)
+	return resp
+}
+
+func countUsers() int {
+	count, err := db.NewSelect().Model((*models.User)(nil)).Count(context.TODO())
+	Expect(err).NotTo(HaveOccurred())
+	return count
+}
+
+var _ = Describe("Batch create", func() {
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		resp := postBatch("atomic", []models.UserCreateRequest{batchUser("existing", "existing@example.com")})
+		Expect(resp.Code).To(Equal(http.StatusCreated))
+	})
+
+	It("should reject an unknown mode", func() {
+		resp := postBatch("merge", []models.UserCreateRequest{batchUser("another", "another@example.com")})
+		Expect(resp.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should reject an empty batch", func() {
+		resp := postBatch("atomic", []models.UserCreateRequest{})
+		Expect(resp.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	Context("in atomic mode", func() {
+		It("should roll back the whole batch on a duplicate email", func() {
+			resp := postBatch("atomic", []models.UserCreateRequest{
+				batchUser("fresh", "fresh@example.com"),
+				batchUser("clash", "existing@example.com"),
+			})
+			Expect(resp.Code).To(Equal(http.StatusConflict))
+
+			var body models.ErrorResponse
+			Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.Code).To(Equal("conflict"))
+			Expect(body.Errors).To(ConsistOf(HaveField("Field", "[1].email")))
+
+			Expect(countUsers()).To(Equal(1))
+		})
+
+		It("should detect duplicates within the batch itself", func() {
+			resp := postBatch("atomic", []models.UserCreateRequest{
+				batchUser("twin", "twin1@example.com"),
+				batchUser("twin", "twin2@example.com"),
+			})
+			Expect(resp.Code).To(Equal(http.StatusConflict))
+			Expect(countUsers()).To(Equal(1))
+		})
+	})
+
+	Context("in ignore mode", func() {
+		It("should skip duplicates and insert the rest", func() {
+			resp := postBatch("ignore", []models.UserCreateRequest{
+				batchUser("fresh", "fresh@example.com"),
+				batchUser("clash", "existing@example.com"),
+				batchUser("existing", "other@example.com"),
+			})
+			Expect(resp.Code).To(Equal(http.StatusCreated))
+
+			var result models.UserBatchCreateResult
+			Expect(json.Unmarshal(resp.Body.Bytes(), &result)).To(Succeed())
+			Expect(result.Created).To(HaveLen(1))
+			Expect(result.Created[0].UserName).To(Equal("fresh"))
+			Expect(result.Skipped).To(ConsistOf(
+				models.UserBatchSkipped{Index: 1, Field: "email", Value: "existing@example.com"},
+				models.UserBatchSkipped{Index: 2, Field: "userName", Value: "existing"},
+			))
+
+			Expect(countUsers()).To(Equal(2))
+		})
+	})
+})
+
+func updateItem(id int64, userName, email string) models.UserBatchUpdateItem {
+	return models.UserBatchUpdateItem{UserID: id, UserCommon: batchUser(userName, email).UserCommon}
+}
+
+func putBatch(items []models.UserBatchUpdateItem) *httptest.ResponseRecorder {
+	jsonBody, err := json.Marshal(items)
+	Expect(err).NotTo(HaveOccurred())
+	req := httptest.NewRequest(http.MethodPut, "/users/batch", bytes.NewReader(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.ServeHTTP(resp, req)
+	return resp
+}
+
+func storedEmails() []string {
+	var emails []string
+	Expect(db.NewSelect().Model((*models.User)(nil)).Column("email").Order("user_id ASC").Scan(context.TODO(), &emails)).To(Succeed())
+	return emails
+}
+
+var _ = Describe("Batch update", func() {
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		resp := postBatch("atomic", []models.UserCreateRequest{
+			batchUser("first", "first@example.com"),
+			batchUser("second", "second@example.com"),
+			batchUser("third", "third@example.com"),
+		})
+		Expect(resp.Code).To(Equal(http.StatusCreated))
+	})
+
+	It("should update every item and report them in order", func() {
+		resp := putBatch([]models.UserBatchUpdateItem{
+			updateItem(2, "second", "second@new.com"),
+			updateItem(1, "firstrenamed", "first@example.com"),
+		})
+		Expect(resp.Code).To(Equal(http.StatusOK))
+
+		var result models.UserBatchUpdateResult
+		Expect(json.Unmarshal(resp.Body.Bytes(), &result)).To(Succeed())
+		Expect(result.Updated).To(HaveLen(2))
+		Expect(result.Updated[0].UserID).To(Equal(int64(2)))
+		Expect(result.Updated[1].UserName).To(Equal("firstrenamed"))
+
+		Expect(storedEmails()).To(Equal([]string{"first@example.com", "second@new.com", "third@example.com"}))
+	})
+
+	It("should reject two items claiming the same email", func() {
+		resp := putBatch([]models.UserBatchUpdateItem{
+			updateItem(1, "first", "shared@example.com"),
+			updateItem(2, "second", "shared@example.com"),
+		})
+		Expect(resp.Code).To(Equal(http.StatusConflict))
+
+		var body models.ErrorResponse
+		Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+		Expect(body.Errors).To(ConsistOf(HaveField("Field", "[1].email")))
+
+		Expect(storedEmails()).To(Equal([]string{"first@example.com", "second@example.com", "third@example.com"}))
+	})
+
+	It("should reject two items updating the same user", func() {
+		resp := putBatch([]models.UserBatchUpdateItem{
+			updateItem(1, "first", "one@example.com"),
+			updateItem(1, "first", "two@example.com"),
+		})
+		Expect(resp.Code).To(Equal(http.StatusConflict))
+	})
+
+	It("should roll back when an item collides with a stored user", func() {
+		resp := putBatch([]models.UserBatchUpdateItem{
+			updateItem(1, "first", "first@new.com"),
+			updateItem(2, "second", "third@example.com"),
+		})
+		Expect(resp.Code).To(Equal(http.StatusConflict))
+		Expect(storedEmails()).To(Equal([]string{"first@example.com", "second@example.com", "third@example.com"}))
+	})
+
+	It("should let an item take over an email released earlier in the batch", func() {
+		resp := putBatch([]models.UserBatchUpdateItem{
+			updateItem(1, "first", "first@new.com"),
+			updateItem(2, "second", "first@example.com"),
+		})
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(storedEmails()).To(Equal([]string{"first@new.com", "first@example.com", "third@example.com"}))
+	})
+
+	It("should roll back and report a missing user", func() {
+		resp := putBatch([]models.UserBatchUpdateItem{
+			updateItem(1, "first", "first@new.com"),
+			updateItem(99, "ghost", "ghost@example.com"),
+		})
+		Expect(resp.Code).To(Equal(http.StatusNotFound))
+
+		var body models.ErrorResponse
+		Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+		Expect(body.Code).To(Equal("not_found"))
+		Expect(body.Errors).To(ConsistOf(HaveField("Field", "[1].id")))
+
+		Expect(storedEmails()).To(Equal([]string{"first@example.com", "second@example.com", "third@example.com"}))
+	})
+
+	It("should reject an item without an ID", func() {
+		resp := putBatch([]models.UserBatchUpdateItem{updateItem(0, "first", "first@example.com")})
+		Expect(resp.Code).To(Equal(http.StatusUnprocessableEntity))
+	})
+})
@@ -0,0 +1,132 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	//revive:enable:dot-imports
+
+	"user-management/internal/handlers"
+	"user-management/internal/models"
+)
+
+func deactivateStale(query string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/admin/users/deactivate-stale"+query, http.NoBody)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	resp := httptest.NewRecorder()
+	srv.ServeHTTP(resp, req)
+	return resp
+}
+
+func deactivatedNames(resp *httptest.ResponseRecorder) []string {
+	var result models.UserDeactivateStaleResult
+	Expect(json.Unmarshal(resp.Body.Bytes(), &result)).To(Succeed())
+	Expect(result.Count).To(Equal(len(result.Users)))
+	return userNames(result.Users)
+}
+
+func storedStatus(userName string) models.UserStatus {
+	var user models.User
+	Expect(db.NewSelect().Model(&user).Where("user_name = ?", userName).Scan(context.TODO())).To(Succeed())
+	return user.UserStatus
+}
+
+var _ = Describe("Deactivate stale users", func() {
+	daysAgo := func(days int) time.Time {
+		return time.Now().UTC().AddDate(0, 0, -days)
+	}
+
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		terminated := batchUser("terminated", "terminated@example.com")
+		terminated.UserStatus = models.UserStatusTerminated
+		resp := postBatch("atomic", []models.UserCreateRequest{
+			batchUser("recent", "recent@example.com"),
+			batchUser("stale", "stale@example.com"),
+			batchUser("newcomer", "newcomer@example.com"),
+			batchUser("oldtimer", "oldtimer@example.com"),
+			terminated,
+		})
+		Expect(resp.Code).To(Equal(http.StatusCreated))
+
+		ctx := context.TODO()
+		for userName, lastLogin := range map[string]time.Time{
+			"recent":     daysAgo(10),
+			"stale":      daysAgo(100),
+			"terminated": daysAgo(100),
+		} {
+			_, err := db.NewUpdate().Model((*models.User)(nil)).
+				Set("last_login_at = ?", lastLogin).
+				Where("user_name = ?", userName).
+				Exec(ctx)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		// never logged in since it was created long ago
+		_, err := db.NewUpdate().Model((*models.User)(nil)).
+			Set("created_at = ?", daysAgo(200)).
+			Where("user_name = ?", "oldtimer").
+			Exec(ctx)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should only report the stale users on a dry run", func() {
+		resp := deactivateStale("", handlers.HeaderDryRun, "true")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get(handlers.HeaderDryRun)).To(Equal("true"))
+		Expect(resp.Header().Get(handlers.HeaderResourceAction)).To(BeEmpty())
+		Expect(deactivatedNames(resp)).To(Equal([]string{"stale", "oldtimer"}))
+
+		Expect(storedStatus("stale")).To(Equal(models.UserStatusActive))
+		Expect(storedStatus("oldtimer")).To(Equal(models.UserStatusActive))
+	})
+
+	It("should accept the dryRun query parameter", func() {
+		resp := deactivateStale("?dryRun=true")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get(handlers.HeaderDryRun)).To(Equal("true"))
+		Expect(deactivatedNames(resp)).To(Equal([]string{"stale", "oldtimer"}))
+
+		Expect(storedStatus("stale")).To(Equal(models.UserStatusActive))
+	})
+
+	It("should deactivate the active users past the threshold", func() {
+		resp := deactivateStale("")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get(handlers.HeaderResourceAction)).To(Equal("updated"))
+		Expect(deactivatedNames(resp)).To(Equal([]string{"stale", "oldtimer"}))
+
+		Expect(storedStatus("stale")).To(Equal(models.UserStatusInactive))
+		Expect(storedStatus("oldtimer")).To(Equal(models.UserStatusInactive))
+		Expect(storedStatus("recent")).To(Equal(models.UserStatusActive))
+		Expect(storedStatus("newcomer")).To(Equal(models.UserStatusActive))
+		Expect(storedStatus("terminated")).To(Equal(models.UserStatusTerminated))
+
+		// already inactive users aren't counted again
+		resp = deactivateStale("")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(deactivatedNames(resp)).To(BeEmpty())
+	})
+
+	It("should honor the days threshold", func() {
+		resp := deactivateStale("?days=5")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(deactivatedNames(resp)).To(Equal([]string{"recent", "stale", "oldtimer"}))
+	})
+
+	It("should reject malformed parameters", func() {
+		for _, query := range []string{"?days=0", "?days=-3", "?days=abc", "?dryRun=maybe"} {
+			Expect(deactivateStale(query).Code).To(Equal(http.StatusBadRequest), query)
+		}
+	})
+})
@@ -0,0 +1,92 @@
+package handlers_test
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	//revive:enable:dot-imports
+
+	"user-management/internal/handlers"
+	"user-management/internal/models"
+	"user-management/internal/repository"
+	"user-management/internal/services"
+)
+
+func sendUser(method, target string, common models.UserCommon) *httptest.ResponseRecorder {
+	jsonBody, err := json.Marshal(common)
+	Expect(err).NotTo(HaveOccurred())
+	req := httptest.NewRequest(method, target, bytes.NewReader(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.ServeHTTP(resp, req)
+	return resp
+}
+
+var _ = Describe("Domain error responses", func() {
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		resp := postBatch("atomic", []models.UserCreateRequest{
+			batchUser("taken", "taken@example.com"),
+			batchUser("other", "other@example.com"),
+		})
+		Expect(resp.Code).To(Equal(http.StatusCreated))
+	})
+
+	DescribeTable("creating a user",
+		func(userName, email string, expected int, message string) {
+			resp := sendUser(http.MethodPost, "/users", batchUser(userName, email).UserCommon)
+			Expect(resp.Code).To(Equal(expected))
+			Expect(resp.Body.String()).To(ContainSubstring(message))
+		},
+		Entry("conflicts on a taken user name", "taken", "fresh@example.com", http.StatusConflict, "username already exists"),
+		Entry("conflicts on a taken email", "fresh", "taken@example.com", http.StatusConflict, "email already exists"),
+	)
+
+	DescribeTable("updating a user",
+		func(target, userName, email string, expected int, message string) {
+			resp := sendUser(http.MethodPut, target, batchUser(userName, email).UserCommon)
+			Expect(resp.Code).To(Equal(expected))
+			Expect(resp.Body.String()).To(ContainSubstring(message))
+		},
+		Entry("conflicts on another user's name", "/users/1", "other", "taken@example.com", http.StatusConflict, "username already exists"),
+		Entry("conflicts on another user's email", "/users/1", "taken", "other@example.com", http.StatusConflict, "email already exists"),
+		Entry("reports a missing user", "/users/99", "ghost", "ghost@example.com", http.StatusNotFound, "user not found"),
+	)
+})
+
+var _ = Describe("Database failures", func() {
+	var broken *echo.Echo
+
+	BeforeEach(func() {
+		sqldb, err := sql.Open(sqliteshim.ShimName, "file::memory:")
+		Expect(err).NotTo(HaveOccurred())
+		closedDB := bun.NewDB(sqldb, sqlitedialect.New())
+		Expect(closedDB.Close()).To(Succeed())
+
+		userHandler := handlers.NewUserHandler(services.NewUserService(repository.NewUserRepository(closedDB)))
+		broken = echo.New()
+		broken.GET("/users/:id", userHandler.GetUser)
+	})
+
+	It("should not be reported as a missing user", func() {
+		req := httptest.NewRequest(http.MethodGet, "/users/1", http.NoBody)
+		resp := httptest.NewRecorder()
+		broken.ServeHTTP(resp, req)
+		Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+		Expect(resp.Body.String()).NotTo(ContainSubstring("user not found"))
+	})
+})
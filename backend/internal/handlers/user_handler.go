@@ -2,8 +2,11 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
@@ -22,135 +25,745 @@ func NewUserHandler(userService services.UserService) *UserHandler {
 }
 
 // ListUsers godoc
+//
 //	@Summary		List all users
-//	@Description	get all users
+//	@Description	get a page of users, optionally searching by a case-insensitive substring of the user name, first name, last name or email.
+//	@Description	With sort=relevance search results are ranked: exact user name or email match first,
+//	@Description	then user names starting with the term, then first/last names or emails starting with it, then other matches.
+//	@Description	Every invalid parameter is reported at once in the 400 response.
+//	@Description	Pages in the default user_id order can also be followed with the nextCursor of the response, which doesn't skip
+//	@Description	or repeat users when users are added or deleted between pages, unlike offset.
+//	@Description	With Accept: application/x-ndjson the users are streamed one JSON object per line instead, for exports:
+//	@Description	every matching user unless a limit is given, without the total nor ETag.
 //	@Accept			json
-//	@Produce		json
-//	@Success		200	{array}	models.User
+//	@Produce		json,application/vnd.api+json,application/x-ndjson
+//	@Security		BearerAuth
+//	@Security		APIKey
+//	@Param			q				query		string	false	"Search term"
+//	@Param			sort			query		string	false	"Sort field"							Enums(user_id, created_at, last_name, user_name, relevance)	default(user_id)
+//	@Param			order			query		string	false	"Sort direction, ignored by relevance"	Enums(asc, desc)											default(asc)
+//	@Param			status			query		string	false	"User status"							Enums(A, I, T)
+//	@Param			department		query		string	false	"Department (exact match)"
+//	@Param			department_like	query		string	false	"Department substring (case-insensitive), not combinable with department"
+//	@Param			createdAfter	query		string	false	"Only the users created at or after this RFC 3339 time"		format(date-time)
+//	@Param			createdBefore	query		string	false	"Only the users created before this RFC 3339 time"			format(date-time)
+//	@Param			updatedAfter	query		string	false	"Only the users last updated at or after this RFC 3339 time"	format(date-time)
+//	@Param			updatedBefore	query		string	false	"Only the users last updated before this RFC 3339 time"		format(date-time)
+//	@Param			limit			query		int		false	"Page size"								default(50)	minimum(1)	maximum(500)
+//	@Param			offset			query		int		false	"Users to skip"							default(0)	minimum(0)
+//	@Param			cursor			query		string	false	"nextCursor of the previous page, for keyset pagination in the default order, not combinable with offset"
+//	@Param			If-None-Match	header		string	false	"ETag of the page the client has, answered with 304 while it's unchanged"
+//	@Success		200				{object}	models.UserListResponse
+//	@Success		304				"Not modified"
+//	@Failure		400				{object}	models.InvalidParamsResponse
+//	@Failure		401				{object}	models.ErrorResponse
+//	@Header			200,304			{string}	ETag	"Weak validator of the page"
 //	@Router			/users [get]
 func (h *UserHandler) ListUsers(c echo.Context) error {
 	ctx := c.Request().Context()
-	users, err := h.userService.ListUsers(ctx)
+
+	var invalidErr *services.InvalidParamsError
+
+	params, err := parseListParams(c)
+	if errors.As(err, &invalidErr) {
+		return respondInvalidParams(c, invalidErr)
+	}
+
+	if wantsNDJSON(c) {
+		return h.streamUsers(c, params)
+	}
+
+	users, total, err := h.userService.ListUsers(ctx, params)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		if errors.As(err, &invalidErr) {
+			return respondInvalidParams(c, invalidErr)
+		}
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	list := &models.UserListResponse{
+		Users:      users,
+		Total:      total,
+		Limit:      params.Limit,
+		Offset:     params.Offset,
+		NextCursor: nextCursor(params, users),
+	}
+	if done, err := respondNotModified(c, listETag(c, list)); done {
+		return err
 	}
-	return c.JSON(http.StatusOK, users)
+	return respondUsers(c, http.StatusOK, list)
+}
+
+// streamUsers streams the users of the list as NDJSON, all of them unless the limit parameter is given
+func (h *UserHandler) streamUsers(c echo.Context, params models.ListParams) error {
+	if c.QueryParam("limit") == "" {
+		params.Limit = 0
+	}
+
+	err := streamUsers(c, func(fn func(user *models.User) error) error {
+		return h.userService.StreamUsers(c.Request().Context(), params, fn)
+	})
+	if err != nil {
+		var invalidErr *services.InvalidParamsError
+		if errors.As(err, &invalidErr) {
+			return respondInvalidParams(c, invalidErr)
+		}
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+	return nil
+}
+
+// CountUsers godoc
+//
+//	@Summary		Count users
+//	@Description	count the users matching the same search and filters as the list, in total and per status.
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		APIKey
+//	@Param			q				query		string	false	"Search term"
+//	@Param			status			query		string	false	"User status"	Enums(A, I, T)
+//	@Param			department		query		string	false	"Department (exact match)"
+//	@Param			department_like	query		string	false	"Department substring (case-insensitive), not combinable with department"
+//	@Param			createdAfter	query		string	false	"Only the users created at or after this RFC 3339 time"		format(date-time)
+//	@Param			createdBefore	query		string	false	"Only the users created before this RFC 3339 time"			format(date-time)
+//	@Param			updatedAfter	query		string	false	"Only the users last updated at or after this RFC 3339 time"	format(date-time)
+//	@Param			updatedBefore	query		string	false	"Only the users last updated before this RFC 3339 time"		format(date-time)
+//	@Success		200				{object}	models.UserCountResponse
+//	@Failure		400				{object}	models.InvalidParamsResponse
+//	@Failure		401				{object}	models.ErrorResponse
+//	@Router			/users/count [get]
+func (h *UserHandler) CountUsers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var invalidErr *services.InvalidParamsError
+
+	params, err := parseListParams(c)
+	if errors.As(err, &invalidErr) {
+		return respondInvalidParams(c, invalidErr)
+	}
+
+	counts, err := h.userService.CountUsers(ctx, params)
+	if err != nil {
+		if errors.As(err, &invalidErr) {
+			return respondInvalidParams(c, invalidErr)
+		}
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, counts)
+}
+
+// CheckAvailability godoc
+//
+//	@Summary		Check the availability of an email and a user name
+//	@Description	report whether the email and the user name are still free, e.g. for a form to warn before submitting.
+//	@Description	At least one of them is required, only the given ones are checked. Rate limited more strictly than the other endpoints.
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		APIKey
+//	@Param			email		query		string	false	"Email"
+//	@Param			username	query		string	false	"User name"
+//	@Success		200			{object}	models.UserAvailabilityResponse
+//	@Failure		400			{object}	models.ErrorResponse
+//	@Failure		401			{object}	models.ErrorResponse
+//	@Failure		429			{object}	models.ErrorResponse
+//	@Router			/users/availability [get]
+func (h *UserHandler) CheckAvailability(c echo.Context) error {
+	email, userName := c.QueryParam("email"), c.QueryParam("username")
+	if email == "" && userName == "" {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "at least one of email and username is required"))
+	}
+
+	result, err := h.userService.CheckAvailability(c.Request().Context(), email, userName)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, result)
 }
 
 // GetUser godoc
+//
 //	@Summary		Get a user
 //	@Description	get user by ID
 //	@Accept			json
-//	@Produce		json
-//	@Param			id	path		string	true	"User ID (int64)"
-//	@Success		200	{object}	models.User
-//	@Failure		404	{object}	map[string]string
+//	@Produce		json,application/vnd.api+json
+//	@Security		BearerAuth
+//	@Security		APIKey
+//	@Param			id				path		string	true	"User ID (int64)"
+//	@Param			If-None-Match	header		string	false	"ETag of the user the client has, answered with 304 while it's unchanged"
+//	@Param			If-Modified-Since	header	string	false	"Last-Modified time of the user the client has, answered with 304 while it's unchanged, ignored along with If-None-Match"
+//	@Success		200				{object}	models.User
+//	@Success		304				"Not modified"
+//	@Failure		400				{object}	models.ErrorResponse
+//	@Failure		401				{object}	models.ErrorResponse
+//	@Failure		404				{object}	models.ErrorResponse
+//	@Failure		500				{object}	models.ErrorResponse
+//	@Header			200,304			{string}	ETag	"Weak validator of the user, changing with its version and update time"
+//	@Header			200,304			{string}	Last-Modified	"Update time of the user, for If-Modified-Since and If-Unmodified-Since"
 //	@Router			/users/{id} [get]
 func (h *UserHandler) GetUser(c echo.Context) error {
 	ctx := c.Request().Context()
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid user id format"})
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid user id format"))
 	}
 
 	user, err := h.userService.GetUser(ctx, id)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, map[string]string{"error": "user not found"})
+		return respondUserError(c, err)
+	}
+
+	if done, err := respondUserNotModified(c, user); done {
+		return err
+	}
+	return respondUser(c, http.StatusOK, user)
+}
+
+// HeadUser godoc
+//
+//	@Summary		Check that a user exists
+//	@Description	check whether a user ID exists without transferring the user, the responses have no body
+//	@Security		BearerAuth
+//	@Security		APIKey
+//	@Param			id	path	string	true	"User ID (int64)"
+//	@Success		200	"The user exists"
+//	@Failure		400	"Invalid user ID"
+//	@Failure		401	"Invalid or missing bearer token or API key"
+//	@Failure		404	"The user doesn't exist"
+//	@Failure		500	"Internal error"
+//	@Router			/users/{id} [head]
+func (h *UserHandler) HeadUser(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.NoContent(http.StatusBadRequest)
 	}
 
-	return c.JSON(http.StatusOK, user)
+	exists, err := h.userService.UserExists(c.Request().Context(), id)
+	switch {
+	case err != nil:
+		return c.NoContent(http.StatusInternalServerError)
+	case !exists:
+		return c.NoContent(http.StatusNotFound)
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// GetUserHistory godoc
+//
+//	@Summary		Get the history of a user
+//	@Description	get the audit log entries of a user, newest first. The history of a deleted user is kept.
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		APIKey
+//	@Param			id	path		string	true	"User ID (int64)"
+//	@Success		200	{array}		models.AuditEntry
+//	@Failure		400	{object}	models.ErrorResponse
+//	@Failure		401	{object}	models.ErrorResponse
+//	@Failure		404	{object}	models.ErrorResponse
+//	@Failure		500	{object}	models.ErrorResponse
+//	@Router			/users/{id}/history [get]
+func (h *UserHandler) GetUserHistory(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid user id format"))
+	}
+
+	entries, err := h.userService.GetUserHistory(c.Request().Context(), id)
+	if err != nil {
+		return respondUserError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, entries)
+}
+
+// GetUserReports godoc
+//
+//	@Summary		Get the direct reports of a user
+//	@Description	get the users the user directly manages, ordered by ID
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		APIKey
+//	@Param			id	path		string	true	"User ID (int64)"
+//	@Success		200	{array}		models.User
+//	@Failure		400	{object}	models.ErrorResponse
+//	@Failure		401	{object}	models.ErrorResponse
+//	@Failure		404	{object}	models.ErrorResponse
+//	@Failure		500	{object}	models.ErrorResponse
+//	@Router			/users/{id}/reports [get]
+func (h *UserHandler) GetUserReports(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid user id format"))
+	}
+
+	reports, err := h.userService.GetUserReports(c.Request().Context(), id)
+	if err != nil {
+		return respondUserError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, reports)
 }
 
 // CreateUser godoc
+//
 //	@Summary		Create a user
 //	@Description	create a new user
 //	@Accept			json
-//	@Produce		json
-//	@Param			user	body		models.UserCreateRequest	true	"User Data"
-//	@Success		201		{object}	models.User
-//	@Failure		400		{object}	map[string]string
-//	@Failure		422		{object}	map[string]string
+//	@Produce		json,application/vnd.api+json
+//	@Security		BearerAuth
+//	@Security		APIKey
+//	@Param			user		body		models.UserCreateRequest	true	"User Data"
+//	@Param			X-Actor		header		string						false	"Who makes the change, recorded as the createdBy/updatedBy of the users (default system), ignored on an authenticated request"
+//	@Param			X-Dry-Run	header		bool						false	"Run every check and return the would-be result without persisting anything"
+//	@Param			dryRun		query		bool						false	"Same as the X-Dry-Run header"
+//	@Success		201			{object}	models.User
+//	@Success		200			{object}	models.User	"Dry run, nothing was persisted"
+//	@Failure		400			{object}	models.ErrorResponse
+//	@Failure		401			{object}	models.ErrorResponse
+//	@Failure		403			{object}	models.ErrorResponse
+//	@Failure		409			{object}	models.ErrorResponse
+//	@Failure		422			{object}	models.ErrorResponse
+//	@Header			201			{string}	X-Resource-Action	"created"
+//	@Header			201			{string}	X-Server-Time		"Server time (RFC 3339)"
+//	@Header			200			{string}	X-Dry-Run			"true on a dry run"
 //	@Router			/users [post]
 func (h *UserHandler) CreateUser(c echo.Context) error {
-	ctx := c.Request().Context()
+	ctx, dryRun, err := writeContext(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
 	var req models.UserCreateRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid request"))
 	}
 
 	if err := c.Validate(req); err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+		return respondValidationError(c, err, "")
 	}
 
 	user, err := h.userService.CreateUser(ctx, req)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondUserError(c, err)
 	}
 
-	return c.JSON(http.StatusCreated, user)
+	return respondUser(c, finishWrite(c, dryRun, http.StatusCreated, actionCreated), user)
+}
+
+// maxBatchSize caps the number of users accepted by a single batch request
+const maxBatchSize = 500
+
+// CreateUsers godoc
+//
+//	@Summary		Create users in batch
+//	@Description	create several users in a single transaction.
+//	@Description	In "atomic" mode (default) a duplicate rolls back the whole batch and is reported with 409,
+//	@Description	in "ignore" mode duplicates are skipped and listed in the response.
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		APIKey
+//	@Param			mode		query		string						false	"Conflict mode"	Enums(atomic, ignore)	default(atomic)
+//	@Param			users		body		[]models.UserCreateRequest	true	"Users Data"
+//	@Param			X-Actor		header		string						false	"Who makes the change, recorded as the createdBy/updatedBy of the users (default system), ignored on an authenticated request"
+//	@Param			X-Dry-Run	header		bool						false	"Run every check and return the would-be result without persisting anything"
+//	@Param			dryRun		query		bool						false	"Same as the X-Dry-Run header"
+//	@Success		201			{object}	models.UserBatchCreateResult
+//	@Success		200			{object}	models.UserBatchCreateResult	"Dry run, nothing was persisted"
+//	@Failure		400			{object}	models.ErrorResponse
+//	@Failure		401			{object}	models.ErrorResponse
+//	@Failure		403			{object}	models.ErrorResponse
+//	@Failure		409			{object}	models.ErrorResponse
+//	@Failure		422			{object}	models.ErrorResponse
+//	@Header			201			{string}	X-Resource-Action	"created"
+//	@Header			201			{string}	X-Server-Time		"Server time (RFC 3339)"
+//	@Header			200			{string}	X-Dry-Run			"true on a dry run"
+//	@Router			/users/batch [post]
+func (h *UserHandler) CreateUsers(c echo.Context) error {
+	ctx, dryRun, err := writeContext(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	mode := models.ConflictMode(c.QueryParam("mode"))
+	switch mode {
+	case "":
+		mode = models.ConflictModeAtomic
+	case models.ConflictModeAtomic, models.ConflictModeIgnore:
+	default:
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid mode: must be one of atomic, ignore"))
+	}
+
+	var reqs []models.UserCreateRequest
+	if err := c.Bind(&reqs); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	if len(reqs) == 0 || len(reqs) > maxBatchSize {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, fmt.Sprintf("batch must contain between 1 and %d users", maxBatchSize)))
+	}
+
+	for i := range reqs {
+		if err := c.Validate(reqs[i]); err != nil {
+			return respondValidationError(c, err, fmt.Sprintf("[%d].", i))
+		}
+	}
+
+	result, err := h.userService.CreateUsers(ctx, reqs, mode)
+	if err != nil {
+		var dupErr *services.DuplicateUserError
+		if errors.As(err, &dupErr) {
+			return respondBatchItemError(c, http.StatusConflict, dupErr, dupErr.Index, dupErr.Field, "unique")
+		}
+		var departmentErr *services.UnknownDepartmentError
+		if errors.As(err, &departmentErr) {
+			return respondUnknownDepartment(c, departmentErr, true)
+		}
+		var managerErr *services.InvalidManagerError
+		if errors.As(err, &managerErr) {
+			return respondInvalidManager(c, managerErr, true)
+		}
+		if errors.Is(err, services.ErrInvalidPassword) {
+			return c.JSON(http.StatusUnprocessableEntity, models.NewErrorResponse(http.StatusUnprocessableEntity, err.Error()))
+		}
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(finishWrite(c, dryRun, http.StatusCreated, actionCreated), result)
+}
+
+// UpdateUsers godoc
+//
+//	@Summary		Update users in batch
+//	@Description	update several users in a single transaction, any failing item rolls back the whole batch.
+//	@Description	Items are applied in order, so an item may take over a user name or email released by an earlier item,
+//	@Description	but two items can't claim the same user, user name or email (409).
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		APIKey
+//	@Param			users		body		[]models.UserBatchUpdateItem	true	"Users Data"
+//	@Param			X-Actor		header		string							false	"Who makes the change, recorded as the createdBy/updatedBy of the users (default system), ignored on an authenticated request"
+//	@Param			X-Dry-Run	header		bool							false	"Run every check and return the would-be result without persisting anything"
+//	@Param			dryRun		query		bool							false	"Same as the X-Dry-Run header"
+//	@Success		200			{object}	models.UserBatchUpdateResult
+//	@Failure		400			{object}	models.ErrorResponse
+//	@Failure		401			{object}	models.ErrorResponse
+//	@Failure		403			{object}	models.ErrorResponse
+//	@Failure		404			{object}	models.ErrorResponse
+//	@Failure		409			{object}	models.ErrorResponse
+//	@Failure		422			{object}	models.ErrorResponse
+//	@Header			200			{string}	X-Resource-Action	"updated"
+//	@Header			200			{string}	X-Server-Time		"Server time (RFC 3339)"
+//	@Header			200			{string}	X-Dry-Run			"true on a dry run"
+//	@Router			/users/batch [put]
+func (h *UserHandler) UpdateUsers(c echo.Context) error {
+	ctx, dryRun, err := writeContext(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	var items []models.UserBatchUpdateItem
+	if err := c.Bind(&items); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	if len(items) == 0 || len(items) > maxBatchSize {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, fmt.Sprintf("batch must contain between 1 and %d users", maxBatchSize)))
+	}
+
+	for i := range items {
+		if err := c.Validate(items[i]); err != nil {
+			return respondValidationError(c, err, fmt.Sprintf("[%d].", i))
+		}
+	}
+
+	result, err := h.userService.UpdateUsers(ctx, items)
+	if err != nil {
+		var dupErr *services.DuplicateUserError
+		if errors.As(err, &dupErr) {
+			return respondBatchItemError(c, http.StatusConflict, dupErr, dupErr.Index, dupErr.Field, "unique")
+		}
+		var missingErr *services.MissingUserError
+		if errors.As(err, &missingErr) {
+			return respondBatchItemError(c, http.StatusNotFound, missingErr, missingErr.Index, "id", "exists")
+		}
+		var departmentErr *services.UnknownDepartmentError
+		if errors.As(err, &departmentErr) {
+			return respondUnknownDepartment(c, departmentErr, true)
+		}
+		var managerErr *services.InvalidManagerError
+		if errors.As(err, &managerErr) {
+			return respondInvalidManager(c, managerErr, true)
+		}
+		if errors.Is(err, services.ErrInvalidPassword) {
+			return c.JSON(http.StatusUnprocessableEntity, models.NewErrorResponse(http.StatusUnprocessableEntity, err.Error()))
+		}
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(finishWrite(c, dryRun, http.StatusOK, actionUpdated), result)
+}
+
+// UpdateUsersStatus godoc
+//
+//	@Summary		Set the status of several users
+//	@Description	set the status of up to 500 users in a single transaction, e.g. T to offboard a team.
+//	@Description	The users already in the status are left untouched, the IDs no user has are listed rather than rejected.
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		APIKey
+//	@Param			request		body		models.UserStatusUpdateRequest	true	"IDs and status"
+//	@Param			X-Actor		header		string							false	"Who makes the change, recorded as the updatedBy of the users (default system), ignored on an authenticated request"
+//	@Param			X-Dry-Run	header		bool							false	"Run every check and return the would-be result without persisting anything"
+//	@Param			dryRun		query		bool							false	"Same as the X-Dry-Run header"
+//	@Success		200			{object}	models.UserStatusUpdateResult
+//	@Failure		400			{object}	models.ErrorResponse
+//	@Failure		401			{object}	models.ErrorResponse
+//	@Failure		403			{object}	models.ErrorResponse
+//	@Failure		422			{object}	models.ErrorResponse
+//	@Header			200			{string}	X-Resource-Action	"updated"
+//	@Header			200			{string}	X-Server-Time		"Server time (RFC 3339)"
+//	@Header			200			{string}	X-Dry-Run			"true on a dry run"
+//	@Router			/users/status [post]
+func (h *UserHandler) UpdateUsersStatus(c echo.Context) error {
+	ctx, dryRun, err := writeContext(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	var req models.UserStatusUpdateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	if len(req.IDs) == 0 || len(req.IDs) > maxBatchSize {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, fmt.Sprintf("ids must contain between 1 and %d user ids", maxBatchSize)))
+	}
+
+	if err := c.Validate(req); err != nil {
+		return respondValidationError(c, err, "")
+	}
+
+	result, err := h.userService.UpdateStatus(ctx, req.IDs, req.Status)
+	if err != nil {
+		return respondUserError(c, err)
+	}
+
+	return c.JSON(finishWrite(c, dryRun, http.StatusOK, actionUpdated), result)
 }
 
 // UpdateUser godoc
+//
 //	@Summary		Update a user
-//	@Description	update a user by ID
+//	@Description	update a user by ID. Send the version read with the user (If-Match header or version field)
+//	@Description	to get a 409 instead of overwriting a concurrent change, or its Last-Modified time (If-Unmodified-Since header)
+//	@Description	to get a 412.
 //	@Accept			json
-//	@Produce		json
-//	@Param			id		path		string						true	"User ID (int64)"
-//	@Param			user	body		models.UserUpdateRequest	true	"User Data"
-//	@Success		200		{object}	models.User
-//	@Failure		400		{object}	map[string]string
-//	@Failure		404		{object}	map[string]string
-//	@Failure		422		{object}	map[string]string
+//	@Produce		json,application/vnd.api+json
+//	@Security		BearerAuth
+//	@Security		APIKey
+//	@Param			id			path		string						true	"User ID (int64)"
+//	@Param			user		body		models.UserUpdateRequest	true	"User Data"
+//	@Param			If-Match	header		string						false	"Version the update is based on, takes precedence over the body version"
+//	@Param			If-Unmodified-Since	header	string					false	"Last-Modified time the update is based on, ignored along with If-Match"
+//	@Param			X-Actor		header		string						false	"Who makes the change, recorded as the createdBy/updatedBy of the users (default system), ignored on an authenticated request"
+//	@Param			X-Dry-Run	header		bool						false	"Run every check and return the would-be result without persisting anything"
+//	@Param			dryRun		query		bool						false	"Same as the X-Dry-Run header"
+//	@Success		200			{object}	models.User
+//	@Failure		400			{object}	models.ErrorResponse
+//	@Failure		401			{object}	models.ErrorResponse
+//	@Failure		403			{object}	models.ErrorResponse
+//	@Failure		404			{object}	models.ErrorResponse
+//	@Failure		409			{object}	models.ErrorResponse
+//	@Failure		412			{object}	models.ErrorResponse	"The user was updated since If-Unmodified-Since"
+//	@Failure		422			{object}	models.ErrorResponse
+//	@Header			200			{string}	X-Resource-Action	"updated"
+//	@Header			200			{string}	X-Server-Time		"Server time (RFC 3339)"
+//	@Header			200			{string}	X-Dry-Run			"true on a dry run"
+//	@Header			200			{string}	Last-Modified		"Update time of the user"
 //	@Router			/users/{id} [put]
 func (h *UserHandler) UpdateUser(c echo.Context) error {
-	ctx := c.Request().Context()
+	ctx, dryRun, err := writeContext(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid user id format"})
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid user id format"))
 	}
 
 	var req models.UserUpdateRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid request"))
 	}
 
 	if err := c.Validate(req); err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+		return respondValidationError(c, err, "")
+	}
+
+	// the header takes precedence over the version in the body
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+	if version != 0 {
+		req.Version = version
 	}
+	req.UnmodifiedSince = ifUnmodifiedSince(c)
 
 	user, err := h.userService.UpdateUser(ctx, id, req)
 	if err != nil {
-		if err.Error() == "username already exists" || err.Error() == "email already exists" || err.Error() == "invalid user status" {
-			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
-		}
-		return c.JSON(http.StatusNotFound, map[string]string{"error": "user not found"})
+		return respondUserError(c, err)
 	}
 
-	return c.JSON(http.StatusOK, user)
+	setLastModified(c, user)
+	return respondUser(c, finishWrite(c, dryRun, http.StatusOK, actionUpdated), user)
 }
 
 // DeleteUser godoc
+//
 //	@Summary		Delete a user
-//	@Description	delete a user by ID
+//	@Description	delete a user by ID. The response body confirms the deletion,
+//	@Description	send "Prefer: return=minimal" to get an empty body instead.
+//	@Description	A user who still manages other users can only be deleted along with reassignTo, their new manager.
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		string	true	"User ID (int64)"
-//	@Success		204	{object}	nil
-//	@Failure		400	{object}	map[string]string
+//	@Security		BearerAuth
+//	@Security		APIKey
+//	@Param			id			path		string	true	"User ID (int64)"
+//	@Param			Prefer		header		string	false	"return=minimal to omit the response body"
+//	@Param			X-Actor		header		string	false	"Who makes the change, recorded in the audit log (default system), ignored on an authenticated request"
+//	@Param			X-Dry-Run	header		bool	false	"Run every check and return the would-be result without persisting anything"
+//	@Param			dryRun		query		bool	false	"Same as the X-Dry-Run header"
+//	@Param			reassignTo	query		int		false	"ID of the user taking over the direct reports"
+//	@Success		202			{object}	models.UserDeleteResponse
+//	@Success		200			{object}	models.UserDeleteResponse	"Dry run, nothing was persisted"
+//	@Failure		400			{object}	models.ErrorResponse
+//	@Failure		401			{object}	models.ErrorResponse
+//	@Failure		403			{object}	models.ErrorResponse
+//	@Failure		404			{object}	models.ErrorResponse
+//	@Failure		409			{object}	models.ErrorResponse	"The user has direct reports and no reassignTo"
+//	@Failure		422			{object}	models.ErrorResponse	"reassignTo doesn't exist or is below the user"
+//	@Header			202			{string}	X-Resource-Action	"deleted"
+//	@Header			202			{string}	X-Server-Time		"Server time (RFC 3339)"
+//	@Header			200			{string}	X-Dry-Run			"true on a dry run"
 //	@Router			/users/{id} [delete]
 func (h *UserHandler) DeleteUser(c echo.Context) error {
-	ctx := c.Request().Context()
+	ctx, dryRun, err := writeContext(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid user id format"})
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid user id format"))
+	}
+
+	var reassignTo int64
+	if raw := c.QueryParam("reassignTo"); raw != "" {
+		reassignTo, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || reassignTo < 1 {
+			return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid reassignTo: must be a user id"))
+		}
+	}
+
+	if err := h.userService.DeleteUser(ctx, id, reassignTo); err != nil {
+		// the manager error isn't about a field of the request body
+		var managerErr *services.InvalidManagerError
+		if errors.As(err, &managerErr) {
+			return c.JSON(http.StatusUnprocessableEntity, models.NewErrorResponse(http.StatusUnprocessableEntity, "invalid reassignTo: "+err.Error()))
+		}
+		return respondUserError(c, err)
+	}
+
+	status := finishWrite(c, dryRun, http.StatusAccepted, actionDeleted)
+	if prefersMinimal(c) {
+		c.Response().Header().Set(HeaderPreferenceApplied, preferReturnMinimal)
+		return c.NoContent(status)
+	}
+
+	return c.JSON(status, models.UserDeleteResponse{Deleted: true, UserID: id})
+}
+
+// userErrorResponse maps the service domain errors to their HTTP status and error body, anything else is a 500
+func userErrorResponse(err error) (int, models.ErrorResponse) {
+	var departmentErr *services.UnknownDepartmentError
+	var managerErr *services.InvalidManagerError
+	status := http.StatusInternalServerError
+	switch {
+	case errors.As(err, &departmentErr):
+		return http.StatusUnprocessableEntity, unknownDepartmentResponse(departmentErr, false)
+	case errors.As(err, &managerErr):
+		return http.StatusUnprocessableEntity, invalidManagerResponse(managerErr, false)
+	case errors.Is(err, services.ErrUserNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrUsernameExists), errors.Is(err, services.ErrEmailExists),
+		errors.Is(err, services.ErrVersionConflict), errors.Is(err, services.ErrHasReports):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrModifiedSince):
+		status = http.StatusPreconditionFailed
+	case errors.Is(err, services.ErrInvalidStatus), errors.Is(err, services.ErrInvalidPassword):
+		status = http.StatusUnprocessableEntity
+	}
+	return status, models.NewErrorResponse(status, err.Error())
+}
+
+// respondUserError writes the error response of userErrorResponse
+func respondUserError(c echo.Context, err error) error {
+	return c.JSON(userErrorResponse(err))
+}
+
+// defaultStaleDays is the inactivity threshold used when the days parameter is omitted
+const defaultStaleDays = 90
+
+// DeactivateStaleUsers godoc
+//
+//	@Summary		Deactivate stale users
+//	@Description	mark the active users who haven't logged in for the given number of days as inactive (I), in a single transaction.
+//	@Description	Users who never logged in are judged by their creation time. On a dry run the users are only reported.
+//	@Accept			json
+//	@Produce		json
+//	@Security		AdminToken
+//	@Param			days		query		int		false	"Inactivity threshold in days"	default(90)	minimum(1)
+//	@Param			X-Dry-Run	header		bool	false	"Only report the affected users"
+//	@Param			dryRun		query		bool	false	"Same as the X-Dry-Run header"
+//	@Param			X-Actor		header		string	false	"Who makes the change, recorded in the audit log of the users (default system), ignored on an authenticated request"
+//	@Success		200			{object}	models.UserDeactivateStaleResult
+//	@Failure		400			{object}	models.ErrorResponse
+//	@Failure		401			{object}	models.ErrorResponse
+//	@Header			200			{string}	X-Resource-Action	"updated"
+//	@Header			200			{string}	X-Server-Time		"Server time (RFC 3339)"
+//	@Header			200			{string}	X-Dry-Run			"true on a dry run"
+//	@Router			/admin/users/deactivate-stale [post]
+func (h *UserHandler) DeactivateStaleUsers(c echo.Context) error {
+	ctx, dryRun, err := writeContext(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, err.Error()))
 	}
 
-	if err := h.userService.DeleteUser(ctx, id); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	days := defaultStaleDays
+	if raw := c.QueryParam("days"); raw != "" {
+		var err error
+		days, err = strconv.Atoi(raw)
+		if err != nil || days < 1 {
+			return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid days: must be a positive integer"))
+		}
+	}
+
+	result, err := h.userService.DeactivateStaleUsers(ctx, time.Duration(days)*24*time.Hour, dryRun)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse(http.StatusInternalServerError, err.Error()))
 	}
 
-	return c.NoContent(http.StatusAccepted)
+	return c.JSON(finishWrite(c, dryRun, http.StatusOK, actionUpdated), result)
 }
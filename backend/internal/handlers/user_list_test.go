@@ -0,0 +1,428 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	//revive:enable:dot-imports
+
+	"user-management/internal/handlers"
+	"user-management/internal/models"
+)
+
+func listUsers(query string) (*httptest.ResponseRecorder, []models.User) {
+	resp, list := listUsersPage(query)
+	return resp, list.Users
+}
+
+func listUsersPage(query string) (*httptest.ResponseRecorder, models.UserListResponse) {
+	req := httptest.NewRequest(http.MethodGet, "/users"+query, nil)
+	resp := httptest.NewRecorder()
+	srv.ServeHTTP(resp, req)
+
+	var list models.UserListResponse
+	if resp.Code == http.StatusOK {
+		Expect(json.Unmarshal(resp.Body.Bytes(), &list)).To(Succeed())
+	}
+	return resp, list
+}
+
+func countUsersBy(query string) (*httptest.ResponseRecorder, models.UserCountResponse) {
+	req := httptest.NewRequest(http.MethodGet, "/users/count"+query, nil)
+	resp := httptest.NewRecorder()
+	srv.ServeHTTP(resp, req)
+
+	var counts models.UserCountResponse
+	if resp.Code == http.StatusOK {
+		Expect(json.Unmarshal(resp.Body.Bytes(), &counts)).To(Succeed())
+	}
+	return resp, counts
+}
+
+func userNames(users []models.User) []string {
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.UserName)
+	}
+	return names
+}
+
+var _ = Describe("List users", func() {
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		// inserted so that the default ID order differs from the relevance order
+		resp := postBatch("atomic", []models.UserCreateRequest{
+			batchUser("bigjohn", "big@example.com"),
+			batchUser("someone", "john.doe@example.com"),
+			batchUser("johnny", "johnny@example.com"),
+			batchUser("john", "j@example.com"),
+			batchUser("alice", "alice@example.com"),
+			batchUser("underone", "under_score@example.com"),
+			batchUser("undertwo", "underxscore@example.com"),
+		})
+		Expect(resp.Code).To(Equal(http.StatusCreated))
+	})
+
+	It("should search case-insensitively across user name and email", func() {
+		resp, users := listUsers("?q=JOHN")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(userNames(users)).To(Equal([]string{"bigjohn", "someone", "johnny", "john"}))
+	})
+
+	It("should rank an exact match above prefix and substring matches", func() {
+		resp, users := listUsers("?q=john&sort=relevance")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(userNames(users)).To(Equal([]string{"john", "johnny", "someone", "bigjohn"}))
+	})
+
+	It("should list every user for an empty query", func() {
+		resp, users := listUsers("?q=%20")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(users).To(HaveLen(7))
+	})
+
+	It("should treat LIKE wildcards literally", func() {
+		resp, users := listUsers("?q=under_")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(userNames(users)).To(Equal([]string{"underone"}))
+	})
+
+	It("should keep the default order when relevance is requested without a query", func() {
+		resp, users := listUsers("?sort=relevance")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(users).To(HaveLen(7))
+		Expect(users[0].UserName).To(Equal("bigjohn"))
+	})
+
+	It("should reject an unknown sort listing the allowed fields", func() {
+		resp, _ := listUsers("?sort=random")
+		Expect(resp.Code).To(Equal(http.StatusBadRequest))
+		Expect(resp.Body.String()).To(ContainSubstring("user_id, user_name, relevance"))
+	})
+
+	It("should reject a sort expression", func() {
+		resp, _ := listUsers("?sort=user_name%3BDROP%20TABLE%20users")
+		Expect(resp.Code).To(Equal(http.StatusBadRequest))
+		Expect(countUsers()).To(Equal(7))
+	})
+
+	It("should sort by a column in either direction", func() {
+		resp, users := listUsers("?sort=user_name")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(userNames(users)).To(Equal([]string{"alice", "bigjohn", "john", "johnny", "someone", "underone", "undertwo"}))
+
+		resp, users = listUsers("?sort=user_name&order=DESC")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(userNames(users)).To(Equal([]string{"undertwo", "underone", "someone", "johnny", "john", "bigjohn", "alice"}))
+
+		resp, users = listUsers("?sort=user_id&order=desc&limit=2")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(userNames(users)).To(Equal([]string{"undertwo", "underone"}))
+	})
+
+	It("should break ties by user ID", func() {
+		resp, users := listUsers("?sort=last_name&order=desc&limit=3")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(userNames(users)).To(Equal([]string{"bigjohn", "someone", "johnny"}))
+	})
+
+	It("should reject an unknown order", func() {
+		resp, _ := listUsers("?sort=user_name&order=up")
+		Expect(resp.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should default the page size and report the total", func() {
+		resp, list := listUsersPage("")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(list.Users).To(HaveLen(7))
+		Expect(list.Total).To(Equal(7))
+		Expect(list.Limit).To(Equal(models.DefaultListLimit))
+		Expect(list.Offset).To(BeZero())
+	})
+
+	It("should return the requested page", func() {
+		resp, list := listUsersPage("?limit=2&offset=2")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(userNames(list.Users)).To(Equal([]string{"johnny", "john"}))
+		Expect(list.Total).To(Equal(7))
+		Expect(list.Limit).To(Equal(2))
+		Expect(list.Offset).To(Equal(2))
+	})
+
+	It("should count every match of a search, not just the page", func() {
+		resp, list := listUsersPage("?q=john&sort=relevance&limit=1&offset=1")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(userNames(list.Users)).To(Equal([]string{"johnny"}))
+		Expect(list.Total).To(Equal(4))
+	})
+
+	It("should cap the page size", func() {
+		resp, list := listUsersPage("?limit=100000")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(list.Limit).To(Equal(models.MaxListLimit))
+	})
+
+	DescribeTable("should reject invalid paging",
+		func(query string) {
+			resp, _ := listUsersPage(query)
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+		},
+		Entry("negative limit", "?limit=-1"),
+		Entry("zero limit", "?limit=0"),
+		Entry("non-numeric limit", "?limit=ten"),
+		Entry("negative offset", "?offset=-5"),
+		Entry("non-numeric offset", "?offset=abc"),
+	)
+
+	It("should follow the cursor pages without skipping users deleted in between", func() {
+		resp, first := listUsersPage("?limit=3")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(userNames(first.Users)).To(Equal([]string{"bigjohn", "someone", "johnny"}))
+		Expect(first.NextCursor).NotTo(BeEmpty())
+
+		// an offset page would now skip "john"
+		Expect(sendAs("", http.MethodDelete, "/users/1", nil).Code).To(Equal(http.StatusAccepted))
+
+		resp, second := listUsersPage("?limit=3&cursor=" + first.NextCursor)
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(userNames(second.Users)).To(Equal([]string{"john", "alice", "underone"}))
+		Expect(second.Total).To(Equal(6), "the total counts every user, not only the ones after the cursor")
+
+		_, last := listUsersPage("?limit=3&cursor=" + second.NextCursor)
+		Expect(userNames(last.Users)).To(Equal([]string{"undertwo"}))
+		Expect(last.NextCursor).To(BeEmpty())
+	})
+
+	It("should only return a next cursor in the user_id order", func() {
+		_, list := listUsersPage("?limit=2&sort=user_name")
+		Expect(list.NextCursor).To(BeEmpty())
+
+		_, list = listUsersPage("?limit=2&sort=user_id&order=desc")
+		Expect(list.NextCursor).To(BeEmpty())
+	})
+
+	DescribeTable("should reject invalid cursors",
+		func(query string) {
+			resp, _ := listUsersPage(query)
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body.String()).To(ContainSubstring(`"name":"cursor"`))
+		},
+		Entry("not base64", "?cursor=%21%21"),
+		Entry("not an ID", "?cursor=YWJj"),
+		Entry("combined with an offset", "?cursor=Mg&offset=2"),
+		Entry("combined with another sort", "?cursor=Mg&sort=last_name"),
+		Entry("combined with the descending order", "?cursor=Mg&order=desc"),
+	)
+
+	It("should link the next cursor page in JSON:API documents", func() {
+		req := httptest.NewRequest(http.MethodGet, "/users?limit=2&cursor=Mg", nil)
+		req.Header.Set("Accept", handlers.MIMEApplicationJSONAPI)
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		Expect(resp.Code).To(Equal(http.StatusOK))
+
+		var doc struct {
+			Links map[string]string `json:"links"`
+		}
+		Expect(json.Unmarshal(resp.Body.Bytes(), &doc)).To(Succeed())
+		Expect(doc.Links).To(HaveKeyWithValue("first", "/users?limit=2"))
+		// after the user 4
+		Expect(doc.Links).To(HaveKeyWithValue("next", "/users?cursor=NA&limit=2"))
+		Expect(doc.Links).NotTo(HaveKey("prev"))
+	})
+
+	It("should link the neighbouring pages in JSON:API documents", func() {
+		req := httptest.NewRequest(http.MethodGet, "/users?q=e&limit=2&offset=2", nil)
+		req.Header.Set("Accept", handlers.MIMEApplicationJSONAPI)
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		Expect(resp.Code).To(Equal(http.StatusOK))
+
+		var doc struct {
+			Links map[string]string `json:"links"`
+			Meta  map[string]int    `json:"meta"`
+		}
+		Expect(json.Unmarshal(resp.Body.Bytes(), &doc)).To(Succeed())
+		Expect(doc.Meta).To(Equal(map[string]int{"total": 7, "limit": 2, "offset": 2}))
+		Expect(doc.Links).To(HaveKeyWithValue("first", "/users?limit=2&offset=0&q=e"))
+		Expect(doc.Links).To(HaveKeyWithValue("prev", "/users?limit=2&offset=0&q=e"))
+		Expect(doc.Links).To(HaveKeyWithValue("next", "/users?limit=2&offset=4&q=e"))
+	})
+})
+
+var _ = Describe("Filter users", func() {
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		member := func(userName string, status models.UserStatus, department string) models.UserCreateRequest {
+			user := batchUser(userName, userName+"@example.com")
+			user.UserStatus = status
+			user.Department = department
+			return user
+		}
+
+		resp := postBatch("atomic", []models.UserCreateRequest{
+			member("engineer", models.UserStatusActive, "Engineering"),
+			member("manager", models.UserStatusInactive, "Engineering Management"),
+			member("retired", models.UserStatusTerminated, "Engineering"),
+			member("seller", models.UserStatusActive, "Sales"),
+		})
+		Expect(resp.Code).To(Equal(http.StatusCreated))
+	})
+
+	It("should filter by status", func() {
+		resp, list := listUsersPage("?status=A")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(userNames(list.Users)).To(Equal([]string{"engineer", "seller"}))
+		Expect(list.Total).To(Equal(2))
+	})
+
+	It("should match the department exactly", func() {
+		resp, users := listUsers("?department=Engineering")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(userNames(users)).To(Equal([]string{"engineer", "retired"}))
+	})
+
+	It("should match part of the department", func() {
+		resp, users := listUsers("?department_like=engineering")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(userNames(users)).To(Equal([]string{"engineer", "manager", "retired"}))
+	})
+
+	It("should combine the filters", func() {
+		resp, users := listUsers("?status=T&department=Engineering")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(userNames(users)).To(Equal([]string{"retired"}))
+	})
+
+	It("should reject an unknown status", func() {
+		resp, _ := listUsers("?status=X")
+		Expect(resp.Code).To(Equal(http.StatusBadRequest))
+		Expect(resp.Body.String()).To(ContainSubstring("invalid status"))
+	})
+
+	It("should count every user per status", func() {
+		resp, counts := countUsersBy("")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(counts.Total).To(Equal(4))
+		Expect(counts.ByStatus).To(Equal(map[models.UserStatus]int{
+			models.UserStatusActive:     2,
+			models.UserStatusInactive:   1,
+			models.UserStatusTerminated: 1,
+		}))
+	})
+
+	It("should apply the list filters to the counts", func() {
+		resp, counts := countUsersBy("?department=Engineering")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(counts.Total).To(Equal(2))
+		Expect(counts.ByStatus).To(Equal(map[models.UserStatus]int{
+			models.UserStatusActive:     1,
+			models.UserStatusInactive:   0,
+			models.UserStatusTerminated: 1,
+		}))
+
+		resp, counts = countUsersBy("?status=A&department_like=sal")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(counts.Total).To(Equal(1))
+		Expect(counts.ByStatus[models.UserStatusActive]).To(Equal(1))
+		Expect(counts.ByStatus[models.UserStatusTerminated]).To(BeZero())
+	})
+
+	It("should reject invalid count filters", func() {
+		resp, _ := countUsersBy("?status=X")
+		Expect(resp.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should reject conflicting department filters", func() {
+		resp, _ := listUsers("?department=Engineering&department_like=eng")
+		Expect(resp.Code).To(Equal(http.StatusBadRequest))
+		Expect(resp.Body.String()).To(ContainSubstring("invalid department_like"))
+	})
+
+	It("should report every invalid parameter at once", func() {
+		resp, _ := listUsers("?sort=random&order=up&status=X&limit=ten&offset=-1&department=Sales&department_like=sal")
+		Expect(resp.Code).To(Equal(http.StatusBadRequest))
+
+		var body models.InvalidParamsResponse
+		Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+		names := make([]string, len(body.InvalidParams))
+		for i, param := range body.InvalidParams {
+			names[i] = param.Name
+			Expect(body.Message).To(ContainSubstring("invalid " + param.Name + ": " + param.Reason))
+		}
+		Expect(names).To(ConsistOf("sort", "order", "status", "limit", "offset", "department_like"))
+	})
+})
+
+var _ = Describe("List users by date", func() {
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		inactive := batchUser("lastyear", "lastyear@example.com")
+		inactive.UserStatus = models.UserStatusInactive
+		Expect(postBatch("atomic", []models.UserCreateRequest{
+			batchUser("january", "january@example.com"),
+			batchUser("february", "february@example.com"),
+			inactive,
+			batchUser("today", "today@example.com"),
+		}).Code).To(Equal(http.StatusCreated))
+
+		backdate := func(userName string, createdAt time.Time) {
+			_, err := db.NewUpdate().Model((*models.User)(nil)).
+				Set("created_at = ?", createdAt).
+				Set("updated_at = ?", createdAt).
+				Where("user_name = ?", userName).
+				Exec(context.TODO())
+			Expect(err).NotTo(HaveOccurred())
+		}
+		backdate("january", time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC))
+		backdate("february", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+		backdate("lastyear", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	})
+
+	It("should keep the users created in the range, its end excluded", func() {
+		resp, users := listUsers("?createdAfter=2025-01-01T00:00:00Z&createdBefore=2025-02-01T00:00:00Z")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(userNames(users)).To(Equal([]string{"january"}))
+
+		// the offset is honored, this is 2025-02-01T00:00:00Z
+		_, users = listUsers("?createdAfter=2025-02-01T01:00:00%2B01:00")
+		Expect(userNames(users)).To(Equal([]string{"february", "today"}))
+	})
+
+	It("should combine the ranges with the other filters", func() {
+		_, users := listUsers("?updatedBefore=2025-03-01T00:00:00Z")
+		Expect(userNames(users)).To(Equal([]string{"january", "february", "lastyear"}))
+
+		_, users = listUsers("?updatedBefore=2025-03-01T00:00:00Z&status=A")
+		Expect(userNames(users)).To(Equal([]string{"january", "february"}))
+
+		_, counts := countUsersBy("?createdBefore=2025-01-01T00:00:00Z")
+		Expect(counts.Total).To(Equal(1))
+		Expect(counts.ByStatus[models.UserStatusInactive]).To(Equal(1))
+	})
+
+	It("should reject malformed or empty ranges", func() {
+		resp, _ := listUsers("?createdAfter=2025-01-01&updatedAfter=2025-03-01T00:00:00Z&updatedBefore=2025-01-01T00:00:00Z")
+		Expect(resp.Code).To(Equal(http.StatusBadRequest))
+
+		var body models.InvalidParamsResponse
+		Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+		Expect(body.InvalidParams).To(ConsistOf(
+			HaveField("Name", "createdAfter"),
+			HaveField("Name", "updatedBefore"),
+		))
+	})
+})
@@ -0,0 +1,72 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	//revive:enable:dot-imports
+
+	"user-management/internal/models"
+)
+
+var _ = Describe("Bulk status update", func() {
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		terminated := batchUser("departed", "departed@example.com")
+		terminated.UserStatus = models.UserStatusTerminated
+		Expect(postBatch("atomic", []models.UserCreateRequest{
+			batchUser("first", "first@example.com"),
+			batchUser("second", "second@example.com"),
+			terminated,
+			batchUser("staying", "staying@example.com"),
+		}).Code).To(Equal(http.StatusCreated))
+	})
+
+	It("should set the status of the existing users and list the missing ones", func() {
+		resp := sendAs("hr", http.MethodPost, "/users/status", models.UserStatusUpdateRequest{
+			IDs:    []int64{1, 2, 3, 99, 99},
+			Status: models.UserStatusTerminated,
+		})
+		Expect(resp.Code).To(Equal(http.StatusOK))
+
+		var result models.UserStatusUpdateResult
+		Expect(json.Unmarshal(resp.Body.Bytes(), &result)).To(Succeed())
+		Expect(result.Count).To(Equal(2), "departed was terminated already")
+		Expect(result.NotFound).To(Equal([]int64{99}))
+
+		Expect(storedStatus("first")).To(Equal(models.UserStatusTerminated))
+		Expect(storedStatus("second")).To(Equal(models.UserStatusTerminated))
+		Expect(storedStatus("staying")).To(Equal(models.UserStatusActive))
+
+		_, entries := getHistory("/users/1/history")
+		Expect(entries[0].Actor).To(Equal("hr"))
+		Expect(entries[0].Diff).To(Equal(map[string]models.AuditChange{"userStatus": {Old: "A", New: "T"}}))
+	})
+
+	It("should change nothing on a dry run", func() {
+		resp := dryRun(http.MethodPost, "/users/status", models.UserStatusUpdateRequest{IDs: []int64{1}, Status: models.UserStatusInactive})
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(ContainSubstring(`"count":1`))
+		Expect(storedStatus("first")).To(Equal(models.UserStatusActive))
+	})
+
+	It("should reject an empty, oversized or invalid request", func() {
+		Expect(sendAs("", http.MethodPost, "/users/status", models.UserStatusUpdateRequest{Status: models.UserStatusTerminated}).Code).
+			To(Equal(http.StatusBadRequest))
+		Expect(sendAs("", http.MethodPost, "/users/status", models.UserStatusUpdateRequest{
+			IDs:    make([]int64, 501),
+			Status: models.UserStatusTerminated,
+		}).Code).To(Equal(http.StatusBadRequest))
+
+		resp := sendAs("", http.MethodPost, "/users/status", models.UserStatusUpdateRequest{IDs: []int64{1}, Status: "X"})
+		Expect(validationErrors(resp.Code, resp.Body.Bytes())).To(ConsistOf(HaveField("Field", "status")))
+		resp = sendAs("", http.MethodPost, "/users/status", models.UserStatusUpdateRequest{IDs: []int64{1, 0}, Status: "T"})
+		Expect(validationErrors(resp.Code, resp.Body.Bytes())).To(ConsistOf(HaveField("Field", "ids[1]")))
+	})
+})
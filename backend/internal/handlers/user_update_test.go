@@ -0,0 +1,228 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	//revive:enable:dot-imports
+
+	"user-management/internal/handlers"
+	"user-management/internal/models"
+)
+
+func putUser(id string, update models.UserCommon) models.User {
+	jsonBody, err := json.Marshal(models.UserUpdateRequest{UserCommon: update})
+	Expect(err).NotTo(HaveOccurred())
+	req := httptest.NewRequest(http.MethodPut, "/users/"+id, bytes.NewReader(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.ServeHTTP(resp, req)
+	Expect(resp.Code).To(Equal(http.StatusOK))
+
+	var user models.User
+	Expect(json.Unmarshal(resp.Body.Bytes(), &user)).To(Succeed())
+	return user
+}
+
+var _ = Describe("Field update timestamps", func() {
+	var original models.UserCommon
+
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		resp := postBatch("atomic", []models.UserCreateRequest{batchUser("tracked", "tracked@example.com")})
+		Expect(resp.Code).To(Equal(http.StatusCreated))
+
+		var result models.UserBatchCreateResult
+		Expect(json.Unmarshal(resp.Body.Bytes(), &result)).To(Succeed())
+		Expect(result.Created[0].EmailUpdatedAt).To(BeNil())
+		Expect(result.Created[0].StatusUpdatedAt).To(BeNil())
+		original = result.Created[0].UserCommon
+	})
+
+	It("should not move when other fields change", func() {
+		update := original
+		update.FirstName = "Renamed"
+		update.Department = "Elsewhere"
+
+		user := putUser("1", update)
+		Expect(user.EmailUpdatedAt).To(BeNil())
+		Expect(user.StatusUpdatedAt).To(BeNil())
+	})
+
+	It("should only bump the field that changed", func() {
+		update := original
+		update.Email = "changed@example.com"
+
+		user := putUser("1", update)
+		Expect(user.EmailUpdatedAt).NotTo(BeNil())
+		Expect(user.StatusUpdatedAt).To(BeNil())
+		emailUpdatedAt := *user.EmailUpdatedAt
+
+		update.UserStatus = models.UserStatusInactive
+		user = putUser("1", update)
+		Expect(user.StatusUpdatedAt).NotTo(BeNil())
+		Expect(*user.EmailUpdatedAt).To(BeTemporally("~", emailUpdatedAt, time.Millisecond))
+
+		// persisted, not only reflected in the response
+		req := httptest.NewRequest(http.MethodGet, "/users/1", http.NoBody)
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		var stored models.User
+		Expect(json.Unmarshal(resp.Body.Bytes(), &stored)).To(Succeed())
+		Expect(*stored.EmailUpdatedAt).To(BeTemporally("~", emailUpdatedAt, time.Millisecond))
+		Expect(*stored.StatusUpdatedAt).To(BeTemporally("~", *user.StatusUpdatedAt, time.Millisecond))
+	})
+})
+
+var _ = Describe("Optimistic concurrency", func() {
+	var original models.UserCommon
+
+	updateWith := func(version string, body models.UserUpdateRequest) *httptest.ResponseRecorder {
+		jsonBody, err := json.Marshal(body)
+		Expect(err).NotTo(HaveOccurred())
+		req := httptest.NewRequest(http.MethodPut, "/users/1", bytes.NewReader(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		if version != "" {
+			req.Header.Set(handlers.HeaderIfMatch, version)
+		}
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		return resp
+	}
+
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		resp := postBatch("atomic", []models.UserCreateRequest{batchUser("versioned", "versioned@example.com")})
+		Expect(resp.Code).To(Equal(http.StatusCreated))
+
+		var result models.UserBatchCreateResult
+		Expect(json.Unmarshal(resp.Body.Bytes(), &result)).To(Succeed())
+		Expect(result.Created[0].Version).To(Equal(int64(1)))
+		original = result.Created[0].UserCommon
+	})
+
+	It("should bump the version on every update", func() {
+		Expect(putUser("1", original).Version).To(Equal(int64(2)))
+		Expect(putUser("1", original).Version).To(Equal(int64(3)))
+	})
+
+	It("should reject an update based on a stale version", func() {
+		resp := updateWith(`"1"`, models.UserUpdateRequest{UserCommon: original})
+		Expect(resp.Code).To(Equal(http.StatusOK))
+
+		// a second editor still holding version 1
+		update := original
+		update.FirstName = "Overwritten"
+		resp = updateWith(`"1"`, models.UserUpdateRequest{UserCommon: update})
+		Expect(resp.Code).To(Equal(http.StatusConflict))
+
+		resp = updateWith("", models.UserUpdateRequest{UserCommon: update, Version: 1})
+		Expect(resp.Code).To(Equal(http.StatusConflict))
+
+		var stored models.User
+		Expect(db.NewSelect().Model(&stored).Where("user_id = 1").Scan(context.TODO())).To(Succeed())
+		Expect(stored.FirstName).To(Equal(original.FirstName))
+		Expect(stored.Version).To(Equal(int64(2)))
+	})
+
+	It("should accept the current version from the header or the body", func() {
+		Expect(updateWith("1", models.UserUpdateRequest{UserCommon: original}).Code).To(Equal(http.StatusOK))
+		Expect(updateWith("", models.UserUpdateRequest{UserCommon: original, Version: 2}).Code).To(Equal(http.StatusOK))
+		Expect(updateWith("*", models.UserUpdateRequest{UserCommon: original}).Code).To(Equal(http.StatusOK))
+	})
+
+	It("should let the header take precedence over the body", func() {
+		resp := updateWith(`"1"`, models.UserUpdateRequest{UserCommon: original, Version: 7})
+		Expect(resp.Code).To(Equal(http.StatusOK))
+	})
+
+	It("should reject a malformed If-Match", func() {
+		Expect(updateWith(`"abc"`, models.UserUpdateRequest{UserCommon: original}).Code).To(Equal(http.StatusBadRequest))
+	})
+})
+
+var _ = Describe("Conditional updates with If-Unmodified-Since", func() {
+	var original models.UserCommon
+
+	updateSince := func(since string, headers map[string]string, update models.UserCommon) *httptest.ResponseRecorder {
+		jsonBody, err := json.Marshal(models.UserUpdateRequest{UserCommon: update})
+		Expect(err).NotTo(HaveOccurred())
+		req := httptest.NewRequest(http.MethodPut, "/users/1", bytes.NewReader(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(handlers.HeaderIfUnmodifiedSince, since)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		return resp
+	}
+
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		resp := postBatch("atomic", []models.UserCreateRequest{batchUser("dated", "dated@example.com")})
+		Expect(resp.Code).To(Equal(http.StatusCreated))
+
+		var result models.UserBatchCreateResult
+		Expect(json.Unmarshal(resp.Body.Bytes(), &result)).To(Succeed())
+		original = result.Created[0].UserCommon
+	})
+
+	It("should report the update time as Last-Modified", func() {
+		req := httptest.NewRequest(http.MethodGet, "/users/1", http.NoBody)
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		Expect(resp.Code).To(Equal(http.StatusOK))
+
+		var user models.User
+		Expect(json.Unmarshal(resp.Body.Bytes(), &user)).To(Succeed())
+		lastModified, err := http.ParseTime(resp.Header().Get(handlers.HeaderLastModified))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lastModified).To(Equal(user.UpdatedAt.Truncate(time.Second).UTC()))
+	})
+
+	It("should update a user unmodified since the given time", func() {
+		req := httptest.NewRequest(http.MethodGet, "/users/1", http.NoBody)
+		get := httptest.NewRecorder()
+		srv.ServeHTTP(get, req)
+
+		resp := updateSince(get.Header().Get(handlers.HeaderLastModified), nil, original)
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get(handlers.HeaderLastModified)).NotTo(BeEmpty())
+	})
+
+	It("should reject with 412 an update of a user modified since", func() {
+		update := original
+		update.FirstName = "Overwritten"
+		resp := updateSince(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), nil, update)
+		Expect(resp.Code).To(Equal(http.StatusPreconditionFailed))
+
+		var body models.ErrorResponse
+		Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+		Expect(body.Code).To(Equal("precondition_failed"))
+
+		var stored models.User
+		Expect(db.NewSelect().Model(&stored).Where("user_id = 1").Scan(context.TODO())).To(Succeed())
+		Expect(stored.FirstName).To(Equal(original.FirstName))
+	})
+
+	It("should ignore an invalid date, or the header along with If-Match", func() {
+		stale := time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+		Expect(updateSince("yesterday", nil, original).Code).To(Equal(http.StatusOK))
+		Expect(updateSince(stale, map[string]string{handlers.HeaderIfMatch: "*"}, original).Code).To(Equal(http.StatusOK))
+	})
+})
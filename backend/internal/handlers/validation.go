@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"user-management/internal/models"
+	"user-management/internal/services"
+	vld "user-management/internal/validator"
+)
+
+// fieldErrors converts the validation failures to one FieldError per rejected field, with the translated message.
+// prefix is prepended to the field names, e.g. "[2]." for the third item of a batch.
+func fieldErrors(err error, prefix string) []models.FieldError {
+	var validationErr *vld.Error
+	if !errors.As(err, &validationErr) {
+		return []models.FieldError{{Field: prefix, Tag: "invalid", Message: err.Error()}}
+	}
+
+	fields := make([]models.FieldError, 0, len(validationErr.Fields))
+	for _, fe := range validationErr.Fields {
+		fields = append(fields, models.FieldError{
+			Field:   prefix + fe.Field(),
+			Tag:     fe.Tag(),
+			Message: validationErr.Translate(fe),
+		})
+	}
+	return fields
+}
+
+// validationFailed is the message of the 422 responses listing the fields rejected by the validation rules
+const validationFailed = "the request failed validation"
+
+// fieldErrorsResponse returns the body of the error response listing the rejected fields
+func fieldErrorsResponse(status int, message string, fields ...models.FieldError) models.ErrorResponse {
+	body := models.NewErrorResponse(status, message)
+	body.Errors = fields
+	return body
+}
+
+// respondFieldErrors writes the error response listing the rejected fields
+func respondFieldErrors(c echo.Context, status int, message string, fields ...models.FieldError) error {
+	return c.JSON(status, fieldErrorsResponse(status, message, fields...))
+}
+
+// respondValidationError writes the 422 response listing every rejected field
+func respondValidationError(c echo.Context, err error, prefix string) error {
+	return respondFieldErrors(c, http.StatusUnprocessableEntity, validationFailed, fieldErrors(err, prefix)...)
+}
+
+// respondBatchItemError writes the response rejecting the field of the batch item at index,
+// prefixed with the item position like the validation errors of the batch
+func respondBatchItemError(c echo.Context, status int, err error, index int, field, tag string) error {
+	return respondFieldErrors(c, status, err.Error(), models.FieldError{
+		Field:   fmt.Sprintf("[%d].%s", index, field),
+		Tag:     tag,
+		Message: err.Error(),
+	})
+}
+
+// unknownDepartmentResponse returns the body of the 422 response rejecting the department of a user,
+// the field is prefixed with the position of the batch item when batch is set
+func unknownDepartmentResponse(err *services.UnknownDepartmentError, batch bool) models.ErrorResponse {
+	field := "department"
+	if batch {
+		field = fmt.Sprintf("[%d].%s", err.Index, field)
+	}
+	return fieldErrorsResponse(http.StatusUnprocessableEntity, err.Error(), models.FieldError{
+		Field: field, Tag: "exists", Message: err.Error(),
+	})
+}
+
+// respondUnknownDepartment writes the 422 response of unknownDepartmentResponse
+func respondUnknownDepartment(c echo.Context, err *services.UnknownDepartmentError, batch bool) error {
+	return c.JSON(http.StatusUnprocessableEntity, unknownDepartmentResponse(err, batch))
+}
+
+// invalidManagerResponse returns the body of the 422 response rejecting the manager of a user, tagged exists
+// for an unknown manager and cycle for a management cycle. The field is prefixed as by unknownDepartmentResponse.
+func invalidManagerResponse(err *services.InvalidManagerError, batch bool) models.ErrorResponse {
+	field, tag := "managerId", "exists"
+	if batch {
+		field = fmt.Sprintf("[%d].%s", err.Index, field)
+	}
+	if errors.Is(err, services.ErrManagerCycle) {
+		tag = "cycle"
+	}
+	return fieldErrorsResponse(http.StatusUnprocessableEntity, err.Error(), models.FieldError{
+		Field: field, Tag: tag, Message: err.Error(),
+	})
+}
+
+// respondInvalidManager writes the 422 response of invalidManagerResponse
+func respondInvalidManager(c echo.Context, err *services.InvalidManagerError, batch bool) error {
+	return c.JSON(http.StatusUnprocessableEntity, invalidManagerResponse(err, batch))
+}
@@ -0,0 +1,76 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	//revive:enable:dot-imports
+
+	"user-management/internal/models"
+)
+
+func validationErrors(code int, body []byte) []models.FieldError {
+	Expect(code).To(Equal(http.StatusUnprocessableEntity))
+
+	var resp models.ErrorResponse
+	Expect(json.Unmarshal(body, &resp)).To(Succeed())
+	Expect(resp.Code).To(Equal("unprocessable_entity"))
+	return resp.Errors
+}
+
+var _ = Describe("Validation error responses", func() {
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		Expect(postBatch("atomic", []models.UserCreateRequest{batchUser("taken", "taken@example.com")}).Code).To(Equal(http.StatusCreated))
+	})
+
+	It("should list every rejected field of a create by its JSON key", func() {
+		user := batchUser("abc", "not-an-email").UserCommon
+		user.UserStatus = "X"
+
+		resp := sendUser(http.MethodPost, "/users", user)
+		Expect(validationErrors(resp.Code, resp.Body.Bytes())).To(ConsistOf(
+			models.FieldError{Field: "userName", Tag: "min", Message: "userName must be at least 4 characters in length"},
+			models.FieldError{Field: "email", Tag: "email", Message: "email must be a valid email address"},
+			models.FieldError{Field: "userStatus", Tag: "oneof", Message: "userStatus must be one of [A I T]"},
+		))
+	})
+
+	It("should report the missing fields of an update", func() {
+		resp := sendUser(http.MethodPut, "/users/1", models.UserCommon{UserName: "taken", FirstName: "Taken", LastName: "User"})
+		Expect(validationErrors(resp.Code, resp.Body.Bytes())).To(ConsistOf(
+			models.FieldError{Field: "email", Tag: "required", Message: "email is a required field"},
+			models.FieldError{Field: "userStatus", Tag: "required", Message: "userStatus is a required field"},
+		))
+	})
+
+	It("should accept an E.164 phone and reject other formats", func() {
+		user := batchUser("phoned", "phoned@example.com").UserCommon
+		user.Phone = "+14155552671"
+		resp := sendUser(http.MethodPost, "/users", user)
+		Expect(resp.Code).To(Equal(http.StatusCreated))
+		Expect(decodeUser(resp).Phone).To(Equal("+14155552671"))
+
+		user.Phone = "(415) 555-2671"
+		resp = sendUser(http.MethodPut, "/users/2", user)
+		Expect(validationErrors(resp.Code, resp.Body.Bytes())).To(ConsistOf(
+			models.FieldError{Field: "phone", Tag: "e164", Message: "phone must be a valid E.164 formatted phone number"},
+		))
+	})
+
+	It("should prefix the fields of a batch item with its position", func() {
+		resp := postBatch("atomic", []models.UserCreateRequest{
+			batchUser("first", "first@example.com"),
+			batchUser("second", "second-example.com"),
+		})
+		Expect(validationErrors(resp.Code, resp.Body.Bytes())).To(ConsistOf(
+			models.FieldError{Field: "[1].email", Tag: "email", Message: "email must be a valid email address"},
+		))
+	})
+})
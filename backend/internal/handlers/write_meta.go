@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"user-management/internal/services"
+)
+
+const (
+	// HeaderResourceAction reports the outcome of a write operation: created, updated or deleted.
+	HeaderResourceAction = "X-Resource-Action"
+	// HeaderServerTime carries the server clock (RFC 3339, UTC) at the time of the write,
+	// so clients can reconcile their clocks.
+	HeaderServerTime = "X-Server-Time"
+
+	// HeaderPrefer is the RFC 7240 request header used to ask for a minimal response.
+	HeaderPrefer = "Prefer"
+	// HeaderPreferenceApplied acknowledges the honored Prefer value.
+	HeaderPreferenceApplied = "Preference-Applied"
+
+	preferReturnMinimal = "return=minimal"
+
+	// HeaderDryRun asks for a write to be validated and simulated without persisting anything,
+	// the response carries it back to confirm the dry run.
+	HeaderDryRun = "X-Dry-Run"
+
+	// HeaderIfMatch carries the user version an update is based on.
+	HeaderIfMatch = "If-Match"
+	// HeaderIfUnmodifiedSince carries the Last-Modified time an update is based on.
+	HeaderIfUnmodifiedSince = "If-Unmodified-Since"
+
+	// HeaderActor names who makes the change, recorded as the user's createdBy/updatedBy.
+	// It's ignored on the requests authenticated by a token or an API key, whose subject is the actor.
+	HeaderActor = "X-Actor"
+
+	maxActorLength = 255
+)
+
+// Write operation outcomes reported via HeaderResourceAction.
+const (
+	actionCreated = "created"
+	actionUpdated = "updated"
+	actionDeleted = "deleted"
+)
+
+// setWriteMeta adds the write operation metadata headers to the response.
+func setWriteMeta(c echo.Context, action string) {
+	header := c.Response().Header()
+	header.Set(HeaderResourceAction, action)
+	header.Set(HeaderServerTime, time.Now().UTC().Format(time.RFC3339Nano))
+}
+
+// prefersMinimal reports whether the client sent "Prefer: return=minimal".
+func prefersMinimal(c echo.Context) bool {
+	for _, value := range c.Request().Header.Values(HeaderPrefer) {
+		for _, preference := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(preference), preferReturnMinimal) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeContext returns the request context carrying the X-Actor header as the actor, unless the credential's
+// subject is, marked for a dry run when asked by the X-Dry-Run header or the dryRun query parameter.
+func writeContext(c echo.Context) (context.Context, bool, error) {
+	ctx := c.Request().Context()
+
+	if actor := strings.TrimSpace(c.Request().Header.Get(HeaderActor)); actor != "" && !services.ActorAuthenticated(ctx) {
+		if len(actor) > maxActorLength {
+			return ctx, false, fmt.Errorf("invalid actor: must be at most %d characters", maxActorLength)
+		}
+		ctx = services.WithActor(ctx, actor)
+	}
+
+	for _, raw := range []string{c.Request().Header.Get(HeaderDryRun), c.QueryParam("dryRun")} {
+		if raw == "" {
+			continue
+		}
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			return ctx, false, errors.New("invalid dry run flag: must be a boolean")
+		}
+		if dryRun {
+			return services.WithDryRun(ctx), true, nil
+		}
+	}
+
+	return ctx, false, nil
+}
+
+// finishWrite sets the write operation headers and returns the status to respond with:
+// a dry run is answered with 200 and the dry-run header, without the write metadata.
+func finishWrite(c echo.Context, dryRun bool, status int, action string) int {
+	if dryRun {
+		c.Response().Header().Set(HeaderDryRun, "true")
+		return http.StatusOK
+	}
+	setWriteMeta(c, action)
+	return status
+}
+
+// ifMatchVersion reads the user version from the If-Match header, quoted like an ETag ("3") or bare (3).
+// It returns zero when the header is missing or "*", which matches any version.
+func ifMatchVersion(c echo.Context) (int64, error) {
+	raw := strings.TrimSpace(c.Request().Header.Get(HeaderIfMatch))
+	if raw == "" || raw == "*" {
+		return 0, nil
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(raw, `"`), 10, 64)
+	if err != nil || version < 1 {
+		return 0, errors.New("invalid If-Match: must be a user version")
+	}
+	return version, nil
+}
+
+// ifUnmodifiedSince reads the time from the If-Unmodified-Since header, zero when it's missing.
+// As RFC 9110 mandates, it's ignored when it isn't an HTTP date or along with If-Match, which takes precedence.
+func ifUnmodifiedSince(c echo.Context) time.Time {
+	if c.Request().Header.Get(HeaderIfMatch) != "" {
+		return time.Time{}
+	}
+	since, err := http.ParseTime(c.Request().Header.Get(HeaderIfUnmodifiedSince))
+	if err != nil {
+		return time.Time{}
+	}
+	return since
+}
@@ -0,0 +1,65 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	//revive:disable:dot-imports
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	//revive:enable:dot-imports
+
+	"user-management/internal/handlers"
+	"user-management/internal/models"
+)
+
+var _ = Describe("Write metadata", func() {
+	var user models.User
+
+	BeforeEach(func() {
+		resetUsers()
+		DeferCleanup(resetUsers)
+
+		jsonBody, err := json.Marshal(batchUser("metadata", "meta@example.com"))
+		Expect(err).NotTo(HaveOccurred())
+		req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+		Expect(resp.Code).To(Equal(http.StatusCreated))
+		Expect(resp.Header().Get(handlers.HeaderResourceAction)).To(Equal("created"))
+		Expect(json.Unmarshal(resp.Body.Bytes(), &user)).To(Succeed())
+	})
+
+	It("should report the update and the server time", func() {
+		jsonBody, err := json.Marshal(models.UserUpdateRequest{UserCommon: user.UserCommon})
+		Expect(err).NotTo(HaveOccurred())
+		req := httptest.NewRequest(http.MethodPut, "/users/1", bytes.NewReader(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get(handlers.HeaderResourceAction)).To(Equal("updated"))
+		serverTime, err := time.Parse(time.RFC3339Nano, resp.Header().Get(handlers.HeaderServerTime))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(serverTime).To(BeTemporally("~", time.Now(), time.Minute))
+	})
+
+	It("should omit the delete body when a minimal response is preferred", func() {
+		req := httptest.NewRequest(http.MethodDelete, "/users/1", http.NoBody)
+		req.Header.Set(handlers.HeaderPrefer, "respond-async, return=minimal")
+		resp := httptest.NewRecorder()
+		srv.ServeHTTP(resp, req)
+
+		Expect(resp.Code).To(Equal(http.StatusAccepted))
+		Expect(resp.Header().Get(handlers.HeaderResourceAction)).To(Equal("deleted"))
+		Expect(resp.Header().Get(handlers.HeaderPreferenceApplied)).To(Equal("return=minimal"))
+		Expect(resp.Body.Len()).To(BeZero())
+		Expect(countUsers()).To(BeZero())
+	})
+})
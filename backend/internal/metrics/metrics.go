@@ -0,0 +1,130 @@
+// Package metrics exposes the Prometheus metrics of the HTTP server and the user operations.
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "user_management"
+
+// DurationBuckets are the upper bounds, in seconds, of the request duration histogram:
+// 5ms up to 10s, the Prometheus client defaults
+var DurationBuckets = prometheus.DefBuckets
+
+// Metrics holds the collectors, registered on a dedicated registry served by Handler
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+
+	usersCreated prometheus.Counter
+	usersUpdated prometheus.Counter
+	usersDeleted prometheus.Counter
+}
+
+// New creates the collectors along with the Go runtime and process ones
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of the HTTP requests by method, route and status code.",
+			Buckets:   DurationBuckets,
+		}, []string{"method", "route", "status"}),
+		usersCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "users_created_total",
+			Help:      "Number of created users.",
+		}),
+		usersUpdated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "users_updated_total",
+			Help:      "Number of updated users.",
+		}),
+		usersDeleted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "users_deleted_total",
+			Help:      "Number of deleted users.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.duration,
+		m.usersCreated,
+		m.usersUpdated,
+		m.usersDeleted,
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() echo.HandlerFunc {
+	return echo.WrapHandler(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+}
+
+// Middleware records the count and duration of the requests. They're labeled by the route pattern
+// (e.g. /api/v1/users/:id) rather than the path, so the IDs don't blow up the number of series.
+func (m *Metrics) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			// the error handler writes the response after the middlewares returned
+			status := c.Response().Status
+			if err != nil {
+				var httpErr *echo.HTTPError
+				if errors.As(err, &httpErr) {
+					status = httpErr.Code
+				} else {
+					status = http.StatusInternalServerError
+				}
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+
+			labels := prometheus.Labels{"method": c.Request().Method, "route": route, "status": strconv.Itoa(status)}
+			m.requests.With(labels).Inc()
+			m.duration.With(labels).Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}
+}
+
+// UsersCreated counts n created users
+func (m *Metrics) UsersCreated(n int) {
+	m.usersCreated.Add(float64(n))
+}
+
+// UsersUpdated counts n updated users
+func (m *Metrics) UsersUpdated(n int) {
+	m.usersUpdated.Add(float64(n))
+}
+
+// UsersDeleted counts n deleted users
+func (m *Metrics) UsersDeleted(n int) {
+	m.usersDeleted.Add(float64(n))
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	e := echo.New()
+	e.Use(m.Middleware())
+	e.GET("/metrics", m.Handler())
+	e.GET("/users/:id", func(c echo.Context) error {
+		if c.Param("id") == "0" {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return c.String(http.StatusOK, "ok")
+	})
+
+	for _, path := range []string{"/users/1", "/users/2", "/users/0", "/nowhere"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, http.NoBody))
+	}
+
+	// the requests are grouped by route, not by path
+	assert.InDelta(t, 2, testutil.ToFloat64(m.requests.WithLabelValues(http.MethodGet, "/users/:id", "200")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.requests.WithLabelValues(http.MethodGet, "/users/:id", "404")), 0)
+	assert.Equal(t, 3, testutil.CollectAndCount(m.requests))
+	assert.Equal(t, 3, testutil.CollectAndCount(m.duration))
+
+	m.UsersCreated(3)
+	m.UsersDeleted(1)
+
+	resp := httptest.NewRecorder()
+	e.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `user_management_http_requests_total{method="GET",route="/users/:id",status="200"} 2`)
+	assert.Contains(t, resp.Body.String(), "user_management_users_created_total 3")
+	assert.Contains(t, resp.Body.String(), "user_management_users_deleted_total 1")
+	assert.Contains(t, resp.Body.String(), "go_goroutines")
+}
@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"context"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// usersColumns returns the columns the users table is created with, the ones of models.User before the later
+// migrations added theirs. The email is unique, the status one of the UserStatus values.
+func usersColumns(db *bun.DB) []column {
+	id, now := "BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY", "CURRENT_TIMESTAMP"
+	if db.Dialect().Name() == dialect.MySQL {
+		id, now = "BIGINT AUTO_INCREMENT PRIMARY KEY", "CURRENT_TIMESTAMP(6)"
+	}
+
+	return []column{
+		{Name: "user_id", Type: id},
+		{Name: "user_name", Type: "VARCHAR(255) NOT NULL"},
+		{Name: "first_name", Type: "VARCHAR(255) NOT NULL"},
+		{Name: "last_name", Type: "VARCHAR(255) NOT NULL"},
+		{Name: "email", Type: "VARCHAR(255) NOT NULL UNIQUE"},
+		{Name: "user_status", Type: "VARCHAR(1) NOT NULL CHECK (user_status IN ('A', 'I', 'T'))"},
+		{Name: "department", Type: "VARCHAR(255)"},
+		{Name: "created_at", Type: timestampType(db) + " NOT NULL DEFAULT " + now},
+		{Name: "updated_at", Type: timestampType(db) + " NOT NULL DEFAULT " + now},
+	}
+}
+
+// creates the users table, so that a fresh database is brought up by the migrations alone.
+// The databases created before it, from the models, already have the table and are left as they are.
+func init() { //nolint:gochecknoinits
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		definitions := make([]string, 0, len(usersColumns(db)))
+		for _, c := range usersColumns(db) {
+			definitions = append(definitions, c.Name+" "+c.Type)
+		}
+
+		_, err := db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS users (\n\t"+strings.Join(definitions, ",\n\t")+"\n)")
+		return err
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS users`)
+		return err
+	})
+}
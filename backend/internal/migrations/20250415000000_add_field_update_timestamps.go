@@ -0,0 +1,18 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+// adds the columns tracking when the sensitive fields last changed, NULL meaning never since creation
+func init() { //nolint:gochecknoinits
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		return addColumns(ctx, db, "users",
+			column{Name: "email_updated_at", Type: timestampType(db)},
+			column{Name: "status_updated_at", Type: timestampType(db)})
+	}, func(ctx context.Context, db *bun.DB) error {
+		return dropColumns(ctx, db, "users", "email_updated_at", "status_updated_at")
+	})
+}
@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+// adds the column tracking the last login, NULL meaning the user never logged in
+func init() { //nolint:gochecknoinits
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		return addColumns(ctx, db, "users", column{Name: "last_login_at", Type: timestampType(db)})
+	}, func(ctx context.Context, db *bun.DB) error {
+		return dropColumns(ctx, db, "users", "last_login_at")
+	})
+}
@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+// adds the optimistic locking version, existing users start at 1
+func init() { //nolint:gochecknoinits
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		return addColumns(ctx, db, "users", column{Name: "version", Type: "BIGINT NOT NULL DEFAULT 1"})
+	}, func(ctx context.Context, db *bun.DB) error {
+		return dropColumns(ctx, db, "users", "version")
+	})
+}
@@ -0,0 +1,18 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+// adds who created and last changed each user, existing users are attributed to the system
+func init() { //nolint:gochecknoinits
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		return addColumns(ctx, db, "users",
+			column{Name: "created_by", Type: "VARCHAR(255) NOT NULL DEFAULT 'system'"},
+			column{Name: "updated_by", Type: "VARCHAR(255) NOT NULL DEFAULT 'system'"})
+	}, func(ctx context.Context, db *bun.DB) error {
+		return dropColumns(ctx, db, "users", "created_by", "updated_by")
+	})
+}
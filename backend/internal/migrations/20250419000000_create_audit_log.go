@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// creates the audit log of the user changes. The entries have no foreign key,
+// the history of a user outlives the user.
+func init() { //nolint:gochecknoinits
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		if db.Dialect().Name() == dialect.MySQL {
+			// MySQL has no CREATE INDEX IF NOT EXISTS, the index is declared with the table
+			_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS audit_log (
+			id BIGINT AUTO_INCREMENT PRIMARY KEY,
+			user_id BIGINT NOT NULL,
+			action VARCHAR(16) NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+			actor VARCHAR(255) NOT NULL,
+			created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+			diff JSON,
+			INDEX audit_log_user_id_idx (user_id, created_at DESC)
+		)`)
+			return err
+		}
+
+		if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS audit_log (
+			id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+			user_id BIGINT NOT NULL,
+			action VARCHAR(16) NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+			actor VARCHAR(255) NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			diff JSONB
+		)`); err != nil {
+			return err
+		}
+		_, err := db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS audit_log_user_id_idx
+			ON audit_log (user_id, created_at DESC)`)
+		return err
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS audit_log`)
+		return err
+	})
+}
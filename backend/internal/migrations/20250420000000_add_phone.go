@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+// adds the optional contact phone, stored in E.164 format (at most 15 digits and the leading +)
+func init() { //nolint:gochecknoinits
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		return addColumns(ctx, db, "users", column{Name: "phone", Type: "VARCHAR(16)"})
+	}, func(ctx context.Context, db *bun.DB) error {
+		return dropColumns(ctx, db, "users", "phone")
+	})
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// creates the departments the users' department has to name, backfilled with the departments already in use.
+// The users keep referring to their department by name and have no foreign key, an empty department names none.
+func init() { //nolint:gochecknoinits
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// the departments of a rerun are already there
+		backfill := `INSERT INTO departments (name)
+			SELECT DISTINCT department FROM users WHERE department IS NOT NULL AND department <> ''
+			ON CONFLICT (name) DO NOTHING`
+
+		if db.Dialect().Name() == dialect.MySQL {
+			if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS departments (
+			id BIGINT AUTO_INCREMENT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL UNIQUE,
+			created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+		)`); err != nil {
+				return err
+			}
+			backfill = `INSERT IGNORE INTO departments (name)
+			SELECT DISTINCT department FROM users WHERE department IS NOT NULL AND department <> ''`
+		} else if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS departments (
+			id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+			name VARCHAR(255) NOT NULL UNIQUE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`); err != nil {
+			return err
+		}
+
+		_, err := db.ExecContext(ctx, backfill)
+		return err
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS departments`)
+		return err
+	})
+}
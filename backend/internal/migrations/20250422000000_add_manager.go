@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"context"
+	"slices"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// adds the optional manager of the users, another user. Deleting a manager leaves their reports without one,
+// the index serves the listing of a manager's direct reports.
+func init() { //nolint:gochecknoinits
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		if db.Dialect().Name() == dialect.MySQL {
+			// MySQL ignores an inline REFERENCES, and indexes the foreign key itself
+			existing, err := tableColumns(ctx, db, "users")
+			if err != nil {
+				return err
+			}
+			if slices.Contains(existing, "manager_id") {
+				return nil
+			}
+			return alterTable(ctx, db, "users", []string{
+				"ADD COLUMN manager_id BIGINT",
+				"ADD CONSTRAINT users_manager_id_fkey FOREIGN KEY (manager_id) REFERENCES users (user_id) ON DELETE SET NULL",
+			})
+		}
+
+		if err := addColumns(ctx, db, "users", column{
+			Name: "manager_id",
+			Type: "BIGINT REFERENCES users (user_id) ON DELETE SET NULL",
+		}); err != nil {
+			return err
+		}
+		_, err := db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS users_manager_id_idx ON users (manager_id)`)
+		return err
+	}, func(ctx context.Context, db *bun.DB) error {
+		if db.Dialect().Name() == dialect.MySQL {
+			existing, err := tableColumns(ctx, db, "users")
+			if err != nil {
+				return err
+			}
+			if !slices.Contains(existing, "manager_id") {
+				return nil
+			}
+			return alterTable(ctx, db, "users", []string{
+				"DROP FOREIGN KEY users_manager_id_fkey",
+				"DROP COLUMN manager_id",
+			})
+		}
+
+		// the index and the foreign key go with the column
+		return dropColumns(ctx, db, "users", "manager_id")
+	})
+}
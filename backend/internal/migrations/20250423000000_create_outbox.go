@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// creates the outbox of the user events, written in the transaction of the changes and posted to the webhooks
+// by the background poller. The unsent entries are read in ID order, the partial index keeps that cheap on pg.
+func init() { //nolint:gochecknoinits
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		if db.Dialect().Name() == dialect.MySQL {
+			// MySQL has no partial index, the sent_at index still skips the sent entries
+			_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS outbox (
+			id BIGINT AUTO_INCREMENT PRIMARY KEY,
+			event JSON NOT NULL,
+			created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+			sent_at DATETIME(6) NULL,
+			attempts INT NOT NULL DEFAULT 0,
+			INDEX outbox_sent_at_idx (sent_at, id)
+		)`)
+			return err
+		}
+
+		if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS outbox (
+			id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+			event JSONB NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			sent_at TIMESTAMP WITH TIME ZONE,
+			attempts INT NOT NULL DEFAULT 0
+		)`); err != nil {
+			return err
+		}
+		_, err := db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS outbox_unsent_idx ON outbox (id) WHERE sent_at IS NULL`)
+		return err
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS outbox`)
+		return err
+	})
+}
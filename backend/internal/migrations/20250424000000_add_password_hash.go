@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+// adds the bcrypt hash of the user's password, NULL for the users who don't have one
+func init() { //nolint:gochecknoinits
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		return addColumns(ctx, db, "users", column{Name: "password_hash", Type: "VARCHAR(255)"})
+	}, func(ctx context.Context, db *bun.DB) error {
+		return dropColumns(ctx, db, "users", "password_hash")
+	})
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// creates the refresh tokens exchanged for access tokens, looked up by the unique digest of the token
+func init() { //nolint:gochecknoinits
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		if db.Dialect().Name() == dialect.MySQL {
+			_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS refresh_tokens (
+			id BIGINT AUTO_INCREMENT PRIMARY KEY,
+			token_hash VARCHAR(64) NOT NULL UNIQUE,
+			subject VARCHAR(255) NOT NULL,
+			roles VARCHAR(1024) NOT NULL DEFAULT '',
+			created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+			expires_at DATETIME(6) NOT NULL,
+			revoked_at DATETIME(6) NULL
+		)`)
+			return err
+		}
+
+		_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS refresh_tokens (
+			id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+			token_hash VARCHAR(64) NOT NULL UNIQUE,
+			subject VARCHAR(255) NOT NULL,
+			roles VARCHAR(1024) NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			revoked_at TIMESTAMP WITH TIME ZONE
+		)`)
+		return err
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS refresh_tokens`)
+		return err
+	})
+}
@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+// userIndexes serve the lookups by user name and the filters by department and status. The filters are
+// followed by the user_id of the default order, so that a filtered page is read in order without sorting.
+// The email's unique index comes with the table, the search's LOWER(...) LIKE '%term%' can't use any.
+var userIndexes = []index{
+	{Name: "users_user_name_key", Columns: "user_name", Unique: true},
+	{Name: "users_department_idx", Columns: "department, user_id"},
+	{Name: "users_user_status_idx", Columns: "user_status, user_id"},
+}
+
+// adds the indexes of the common queries, the user names becoming unique in the database as they are in the API
+func init() { //nolint:gochecknoinits
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// a duplicate would fail the unique index with a less helpful error
+		var duplicates []string
+		if err := db.NewSelect().
+			TableExpr("users").
+			ColumnExpr("user_name").
+			GroupExpr("user_name").
+			Having("COUNT(*) > 1").
+			OrderExpr("user_name").
+			Limit(10).
+			Scan(ctx, &duplicates); err != nil {
+			return err
+		}
+		if len(duplicates) > 0 {
+			return fmt.Errorf("user names shared by several users, rename them before migrating: %s",
+				strings.Join(duplicates, ", "))
+		}
+
+		return createIndexes(ctx, db, "users", userIndexes...)
+	}, func(ctx context.Context, db *bun.DB) error {
+		names := make([]string, 0, len(userIndexes))
+		for _, idx := range userIndexes {
+			names = append(names, idx.Name)
+		}
+		return dropIndexes(ctx, db, "users", names...)
+	})
+}
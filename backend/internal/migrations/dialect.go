@@ -0,0 +1,172 @@
+package migrations
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// column is a column added by a migration
+type column struct {
+	Name string
+	// Type is the column definition, e.g. VARCHAR(255) NOT NULL DEFAULT 'system'
+	Type string
+}
+
+// timestampType returns the column type of a time zone aware timestamp.
+// MySQL has none, its DATETIME columns hold the UTC times the connection is configured with.
+func timestampType(db *bun.DB) string {
+	if db.Dialect().Name() == dialect.MySQL {
+		return "DATETIME(6)"
+	}
+	return "TIMESTAMP WITH TIME ZONE"
+}
+
+// addColumns adds the columns missing from the table. MySQL has no ADD COLUMN IF NOT EXISTS,
+// the columns it already has are looked up instead.
+func addColumns(ctx context.Context, db *bun.DB, table string, columns ...column) error {
+	var existing []string
+	if db.Dialect().Name() == dialect.MySQL {
+		var err error
+		if existing, err = tableColumns(ctx, db, table); err != nil {
+			return err
+		}
+	}
+
+	var clauses []string
+	for _, c := range columns {
+		switch {
+		case db.Dialect().Name() != dialect.MySQL:
+			clauses = append(clauses, "ADD COLUMN IF NOT EXISTS "+c.Name+" "+c.Type)
+		case !slices.Contains(existing, c.Name):
+			clauses = append(clauses, "ADD COLUMN "+c.Name+" "+c.Type)
+		}
+	}
+	return alterTable(ctx, db, table, clauses)
+}
+
+// dropColumns drops the columns the table has, see addColumns
+func dropColumns(ctx context.Context, db *bun.DB, table string, names ...string) error {
+	var existing []string
+	if db.Dialect().Name() == dialect.MySQL {
+		var err error
+		if existing, err = tableColumns(ctx, db, table); err != nil {
+			return err
+		}
+	}
+
+	var clauses []string
+	for _, name := range names {
+		switch {
+		case db.Dialect().Name() != dialect.MySQL:
+			clauses = append(clauses, "DROP COLUMN IF EXISTS "+name)
+		case slices.Contains(existing, name):
+			clauses = append(clauses, "DROP COLUMN "+name)
+		}
+	}
+	return alterTable(ctx, db, table, clauses)
+}
+
+// tableColumns returns the names of the columns of the table in the current MySQL database
+func tableColumns(ctx context.Context, db *bun.DB, table string) ([]string, error) {
+	var names []string
+	err := db.NewSelect().
+		TableExpr("information_schema.columns").
+		ColumnExpr("column_name").
+		Where("table_schema = DATABASE()").
+		Where("table_name = ?", table).
+		Scan(ctx, &names)
+	return names, err
+}
+
+// alterTable applies the clauses in a single ALTER TABLE statement, nothing is run without clauses
+func alterTable(ctx context.Context, db *bun.DB, table string, clauses []string) error {
+	if len(clauses) == 0 {
+		return nil
+	}
+	_, err := db.ExecContext(ctx, "ALTER TABLE "+table+"\n\t"+strings.Join(clauses, ",\n\t"))
+	return err
+}
+
+// index is an index created by a migration
+type index struct {
+	Name    string
+	Columns string
+	Unique  bool
+}
+
+// createIndexes creates the indexes missing from the table. MySQL has no CREATE INDEX IF NOT EXISTS,
+// the indexes it already has are looked up instead and the missing ones added in a single ALTER TABLE.
+func createIndexes(ctx context.Context, db *bun.DB, table string, indexes ...index) error {
+	if db.Dialect().Name() != dialect.MySQL {
+		for _, idx := range indexes {
+			unique := ""
+			if idx.Unique {
+				unique = "UNIQUE "
+			}
+			if _, err := db.ExecContext(ctx,
+				"CREATE "+unique+"INDEX IF NOT EXISTS "+idx.Name+" ON "+table+" ("+idx.Columns+")"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	existing, err := tableIndexes(ctx, db, table)
+	if err != nil {
+		return err
+	}
+
+	var clauses []string
+	for _, idx := range indexes {
+		if slices.Contains(existing, idx.Name) {
+			continue
+		}
+		if idx.Unique {
+			clauses = append(clauses, "ADD UNIQUE INDEX "+idx.Name+" ("+idx.Columns+")")
+		} else {
+			clauses = append(clauses, "ADD INDEX "+idx.Name+" ("+idx.Columns+")")
+		}
+	}
+	return alterTable(ctx, db, table, clauses)
+}
+
+// dropIndexes drops the indexes the table has, see createIndexes
+func dropIndexes(ctx context.Context, db *bun.DB, table string, names ...string) error {
+	if db.Dialect().Name() != dialect.MySQL {
+		for _, name := range names {
+			if _, err := db.ExecContext(ctx, "DROP INDEX IF EXISTS "+name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	existing, err := tableIndexes(ctx, db, table)
+	if err != nil {
+		return err
+	}
+
+	var clauses []string
+	for _, name := range names {
+		if slices.Contains(existing, name) {
+			clauses = append(clauses, "DROP INDEX "+name)
+		}
+	}
+	return alterTable(ctx, db, table, clauses)
+}
+
+// tableIndexes returns the names of the indexes of the table in the current MySQL database
+func tableIndexes(ctx context.Context, db *bun.DB, table string) ([]string, error) {
+	var names []string
+	err := db.NewSelect().
+		TableExpr("information_schema.statistics").
+		ColumnExpr("DISTINCT index_name").
+		Where("table_schema = DATABASE()").
+		Where("table_name = ?", table).
+		Scan(ctx, &names)
+	return names, err
+}
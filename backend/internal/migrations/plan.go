@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/uptrace/bun/migrate"
+)
+
+// UpTo returns the migrations of ms up to and including target, so migrating with them stops at the target.
+// The target is either the version of a migration (e.g. 20250424000000) or its full name (20250424000000_add_password_hash).
+func UpTo(ms *migrate.Migrations, target string) (*migrate.Migrations, error) {
+	upTo := migrate.NewMigrations()
+	for _, m := range ms.Sorted() {
+		upTo.Add(m)
+		if m.Name == target || m.String() == target {
+			return upTo, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown migration %q", target)
+}
+
+// RollbackPlan returns the groups rolling back steps times would revert, the latest first,
+// each one with its migrations in the order they'd be reverted. ms are the migrations with their status.
+func RollbackPlan(ms migrate.MigrationSlice, steps int) []migrate.MigrationGroup {
+	// applied latest first, so are the migrations of each group
+	applied := ms.Applied()
+
+	var plan []migrate.MigrationGroup
+	for len(plan) < steps {
+		group := applied.LastGroup()
+		if group.IsZero() {
+			break
+		}
+		plan = append(plan, *group)
+		applied = slices.DeleteFunc(applied, func(m migrate.Migration) bool { return m.GroupID == group.ID })
+	}
+	return plan
+}
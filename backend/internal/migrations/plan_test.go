@@ -0,0 +1,70 @@
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun/migrate"
+)
+
+func TestUpTo(t *testing.T) {
+	t.Parallel()
+
+	ms := migrate.NewMigrations()
+	require.NoError(t, ms.Discover(fstest.MapFS{
+		"20250101000000_first.up.sql":  sqlFile(),
+		"20250201000000_second.up.sql": sqlFile(),
+		"20250301000000_third.up.sql":  sqlFile(),
+	}))
+
+	names := func(ms *migrate.Migrations) []string {
+		var names []string
+		for _, m := range ms.Sorted() {
+			names = append(names, m.Name)
+		}
+		return names
+	}
+
+	for _, target := range []string{"20250201000000", "20250201000000_second"} {
+		upTo, err := UpTo(ms, target)
+		require.NoError(t, err, target)
+		assert.Equal(t, []string{"20250101000000", "20250201000000"}, names(upTo), target)
+	}
+
+	_, err := UpTo(ms, "20250401000000")
+	require.ErrorContains(t, err, `unknown migration "20250401000000"`)
+}
+
+func TestRollbackPlan(t *testing.T) {
+	t.Parallel()
+
+	// two groups applied, the last migration pending
+	ms := migrate.MigrationSlice{
+		{Name: "20250101000000", ID: 1, GroupID: 1},
+		{Name: "20250201000000", ID: 2, GroupID: 2},
+		{Name: "20250301000000", ID: 3, GroupID: 2},
+		{Name: "20250401000000"},
+	}
+
+	names := func(group migrate.MigrationGroup) []string {
+		var names []string
+		for _, m := range group.Migrations {
+			names = append(names, m.Name)
+		}
+		return names
+	}
+
+	plan := RollbackPlan(ms, 1)
+	require.Len(t, plan, 1)
+	assert.Equal(t, int64(2), plan[0].ID)
+	assert.Equal(t, []string{"20250301000000", "20250201000000"}, names(plan[0]))
+
+	// capped by the applied groups
+	plan = RollbackPlan(ms, 5)
+	require.Len(t, plan, 2)
+	assert.Equal(t, []string{"20250101000000"}, names(plan[1]))
+
+	assert.Empty(t, RollbackPlan(ms[3:], 1))
+}
@@ -0,0 +1,59 @@
+package migrations
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/uptrace/bun/migrate"
+)
+
+// builtInSource names the migrations shipped with the application in error messages
+const builtInSource = "built-in"
+
+// Source is an additional set of SQL migrations,
+// e.g. a deployment specific directory or a filesystem embedded by a plugin.
+type Source struct {
+	// Name identifies the source in error messages
+	Name string
+	FS   fs.FS
+}
+
+// DirSource returns a source reading the SQL migrations of the directory.
+func DirSource(dir string) Source {
+	return Source{Name: dir, FS: os.DirFS(dir)}
+}
+
+// Combine returns the built-in migrations together with the SQL migrations discovered in the sources.
+// It fails when two sources define the same migration version, since the migrator would only keep track of one of them.
+func Combine(sources ...Source) (*migrate.Migrations, error) {
+	combined := migrate.NewMigrations()
+	owners := make(map[string]string)
+
+	add := func(source string, ms migrate.MigrationSlice) error {
+		for _, m := range ms {
+			if owner, ok := owners[m.Name]; ok {
+				return fmt.Errorf("migration version %s from %q collides with the one from %q", m.Name, source, owner)
+			}
+			owners[m.Name] = source
+			combined.Add(m)
+		}
+		return nil
+	}
+
+	if err := add(builtInSource, Migrations.Sorted()); err != nil {
+		return nil, err
+	}
+
+	for _, source := range sources {
+		discovered := migrate.NewMigrations()
+		if err := discovered.Discover(source.FS); err != nil {
+			return nil, fmt.Errorf("failed to discover migrations in %q: %w", source.Name, err)
+		}
+		if err := add(source.Name, discovered.Sorted()); err != nil {
+			return nil, err
+		}
+	}
+
+	return combined, nil
+}
@@ -0,0 +1,81 @@
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun/migrate"
+)
+
+func sqlFile() *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte("SELECT 1;")}
+}
+
+func TestCombine(t *testing.T) {
+	t.Parallel()
+
+	builtIn := len(Migrations.Sorted())
+
+	t.Run("merges the sources", func(t *testing.T) {
+		t.Parallel()
+
+		combined, err := Combine(
+			Source{Name: "plugin", FS: fstest.MapFS{
+				"20250101000000_plugin.up.sql":   sqlFile(),
+				"20250101000000_plugin.down.sql": sqlFile(),
+			}},
+			Source{Name: "custom", FS: fstest.MapFS{
+				"sql/20250201000000_custom.tx.up.sql": sqlFile(),
+				"README.md":                           sqlFile(),
+			}},
+		)
+		require.NoError(t, err)
+
+		byName := make(map[string]migrate.Migration)
+		for _, m := range combined.Sorted() {
+			byName[m.Name] = m
+		}
+		require.Len(t, byName, builtIn+2)
+		require.Contains(t, byName, "20250101000000")
+		assert.NotNil(t, byName["20250101000000"].Up)
+		assert.NotNil(t, byName["20250101000000"].Down)
+		assert.Contains(t, byName, "20250201000000")
+	})
+
+	t.Run("rejects version collisions between sources", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Combine(
+			Source{Name: "plugin", FS: fstest.MapFS{"20250101000000_plugin.up.sql": sqlFile()}},
+			Source{Name: "custom", FS: fstest.MapFS{"20250101000000_custom.up.sql": sqlFile()}},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `migration version 20250101000000 from "custom" collides with the one from "plugin"`)
+	})
+
+	t.Run("rejects collisions with the built-in migrations", func(t *testing.T) {
+		t.Parallel()
+
+		builtInName := Migrations.Sorted()[0].Name
+		_, err := Combine(Source{Name: "custom", FS: fstest.MapFS{builtInName + "_custom.up.sql": sqlFile()}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `collides with the one from "built-in"`)
+	})
+
+	t.Run("rejects malformed file names", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Combine(Source{Name: "custom", FS: fstest.MapFS{"add_index.up.sql": sqlFile()}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"custom"`)
+	})
+
+	t.Run("rejects a missing directory", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Combine(DirSource(t.TempDir() + "/missing"))
+		require.Error(t, err)
+	})
+}
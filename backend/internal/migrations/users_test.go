@@ -0,0 +1,97 @@
+package migrations
+
+import (
+	"database/sql"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/mysqldialect"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+
+	"user-management/internal/models"
+)
+
+// TestUsersColumnsMatchModel checks the users table of the migrations against the bun tags of models.User
+func TestUsersColumnsMatchModel(t *testing.T) {
+	t.Parallel()
+
+	// the columns the later migrations add
+	added := []string{
+		"email_updated_at", "status_updated_at", "last_login_at", "version", "created_by", "updated_by",
+		"phone", "manager_id", "password_hash",
+	}
+
+	sqldb, err := sql.Open(sqliteshim.ShimName, "file::memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqldb.Close() })
+
+	for _, db := range []*bun.DB{bun.NewDB(sqldb, pgdialect.New()), bun.NewDB(sqldb, mysqldialect.New())} {
+		t.Run(db.Dialect().Name().String(), func(t *testing.T) {
+			table := db.Table(reflect.TypeFor[models.User]())
+
+			var names []string
+			for _, c := range usersColumns(db) {
+				names = append(names, c.Name)
+
+				field, ok := table.FieldMap[c.Name]
+				require.True(t, ok, "%s isn't a column of the model", c.Name)
+				if field.IsPK {
+					assert.Contains(t, c.Type, "PRIMARY KEY", c.Name)
+					continue
+				}
+				assert.Equal(t, field.NotNull, strings.Contains(c.Type, "NOT NULL"), c.Name)
+				unique := strings.Contains(c.Type, "UNIQUE")
+				for _, idx := range userIndexes {
+					unique = unique || idx.Unique && idx.Columns == c.Name
+				}
+				assert.Equal(t, field.Tag.HasOption("unique"), unique, c.Name)
+				assert.Equal(t, field.SQLDefault != "", strings.Contains(c.Type, "DEFAULT"), c.Name)
+				if check := field.StructField.Tag.Get("check"); check != "" {
+					assert.Contains(t, c.Type, "CHECK ("+check+")", c.Name)
+				}
+			}
+
+			var columns []string
+			for _, field := range table.Fields {
+				columns = append(columns, field.Name)
+			}
+			assert.ElementsMatch(t, columns, append(names, added...))
+		})
+	}
+}
+
+// TestE2ESeedMatchesMigrations checks that the schema of the e2e stack, loaded from seed.sql instead of migrated,
+// has the columns of models.User and records every migration as applied
+func TestE2ESeedMatchesMigrations(t *testing.T) {
+	t.Parallel()
+
+	seed, err := os.ReadFile("../../e2e/seed.sql")
+	require.NoError(t, err)
+
+	_, users, found := strings.Cut(string(seed), "CREATE TABLE IF NOT EXISTS users (")
+	require.True(t, found)
+	users, _, found = strings.Cut(users, "\n);")
+	require.True(t, found)
+
+	var columns []string
+	for _, line := range strings.Split(users, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "--") {
+			columns = append(columns, strings.Fields(line)[0])
+		}
+	}
+	var fields []string
+	for _, field := range bun.NewDB(nil, pgdialect.New()).Table(reflect.TypeFor[models.User]()).Fields {
+		fields = append(fields, field.Name)
+	}
+	assert.ElementsMatch(t, fields, columns)
+
+	for _, m := range Migrations.Sorted() {
+		assert.Contains(t, string(seed), "('"+m.Name+"', 1)", "migration %s isn't recorded", m.Name)
+	}
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// AuditAction is the kind of change recorded by an audit entry
+type AuditAction string
+
+const (
+	// AuditActionCreate records a created user
+	AuditActionCreate AuditAction = "create"
+	// AuditActionUpdate records an updated user, including a status change by the stale users deactivation
+	AuditActionUpdate AuditAction = "update"
+	// AuditActionDelete records a deleted user
+	AuditActionDelete AuditAction = "delete"
+)
+
+// AuditChange holds the values of a field before and after a change,
+// Old is omitted for a create and New for a delete
+type AuditChange struct {
+	Old any `json:"old,omitempty" swaggertype:"string" example:"john.doe@example.com"`
+	New any `json:"new,omitempty" swaggertype:"string" example:"john@example.com"`
+} // @name AuditChange
+
+// AuditEntry records a change of a user, written in the same transaction as the change
+type AuditEntry struct {
+	bun.BaseModel `bun:"table:audit_log,alias:a" tstype:"-"`
+
+	ID int64 `bun:"id,pk,autoincrement" json:"id" example:"1"`
+	// ID of the changed user, kept once the user is deleted
+	UserID int64       `bun:"user_id,notnull" json:"userId" example:"1"`
+	Action AuditAction `bun:"action,notnull,type:varchar(16)" json:"action" example:"update" enums:"create,update,delete"`
+	// Who made the change
+	Actor     string    `bun:"actor,notnull" json:"actor" example:"jane.admin"`
+	CreatedAt time.Time `bun:"created_at,notnull,default:current_timestamp" json:"createdAt" format:"date-time" example:"2025-03-27T10:23:51.495798-05:00"`
+	// Changed fields by their JSON key
+	Diff map[string]AuditChange `bun:"diff,type:jsonb" json:"diff"`
+} // @name AuditEntry
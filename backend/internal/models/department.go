@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Department is a department the users can belong to, they refer to it by its name
+type Department struct {
+	bun.BaseModel `bun:"table:departments,alias:d" tstype:"-"`
+
+	ID int64 `bun:"id,pk,autoincrement" json:"id" example:"1"`
+	// Name, unique among the departments
+	Name      string    `bun:"name,unique,notnull" json:"name" example:"Engineering"`
+	CreatedAt time.Time `bun:"created_at,notnull,default:current_timestamp" json:"createdAt" format:"date-time" example:"2025-03-27T10:23:51.495798-05:00"`
+} // @name Department
+
+// DepartmentCreateRequest is the request body for creating a department
+// swagger:model DepartmentCreateRequest
+//
+//	@required	["name"]
+type DepartmentCreateRequest struct {
+	// Name of the department, the value of the users' department field
+	//	@maxLength	255
+	//	@example	Engineering
+	Name string `json:"name" validate:"required,max=255,alphaNumUnicodeWithSpaces" example:"Engineering"`
+} // @name DepartmentCreateRequest
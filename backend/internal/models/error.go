@@ -0,0 +1,26 @@
+package models
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	// Machine readable code of the error, the snake_case text of the HTTP status
+	Code string `json:"code" example:"not_found"`
+	// English sentence describing the error
+	Message string `json:"message" example:"user not found"`
+	// Every rejected field at once, set on the validation errors and the errors of a batch item
+	Errors []FieldError `json:"errors,omitempty"`
+	// ID of the request, as in the X-Request-ID header, added to every error body by the server
+	RequestID string `json:"requestId,omitempty" example:"rrpSsGO3dQxyEtTXzbpNwzHbnwkfyRtn"`
+} // @name ErrorResponse
+
+// NewErrorResponse returns the body of an error response with the status, coded after it
+func NewErrorResponse(status int, message string) ErrorResponse {
+	return ErrorResponse{
+		Code:    strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_"),
+		Message: message,
+	}
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// UserEventType is the kind of change a user event notifies
+type UserEventType string
+
+const (
+	// UserEventCreated notifies a created user
+	UserEventCreated UserEventType = "user.created"
+	// UserEventUpdated notifies an updated user, including a status change or a reassigned manager
+	UserEventUpdated UserEventType = "user.updated"
+	// UserEventDeleted notifies a deleted user, holding the user as it was before the delete
+	UserEventDeleted UserEventType = "user.deleted"
+)
+
+// UserEvent is the body posted to the webhooks after a committed change of a user
+type UserEvent struct {
+	Type      UserEventType `json:"type" example:"user.created"`
+	User      User          `json:"user"`
+	Timestamp time.Time     `json:"timestamp" example:"2025-04-01T12:00:00Z"`
+	// ID of the request that made the change, also sent as the X-Request-ID header of the webhook
+	RequestID string `json:"requestId,omitempty" example:"rrpSsGO3dQxyEtTXzbpNwzHbnwkfyRtn"`
+} // @name UserEvent
@@ -0,0 +1,39 @@
+package models
+
+// HealthStatus is the report of GET /status, meant for humans and monitoring
+type HealthStatus struct {
+	// Memory allocated by the process
+	MemUsage string `json:"mem_usage" example:"12 MiB"`
+	// Number of live goroutines
+	NumGoroutine int `json:"num_goroutine" example:"24"`
+	// Number of completed GC cycles
+	NumGC uint32 `json:"num_gc" example:"57"`
+	// Memory of the heap spans in use
+	HeapInuse string `json:"heap_inuse" example:"14 MiB"`
+	// Memory obtained from the OS
+	Sys string `json:"sys" example:"31 MiB"`
+	// Uptime of the process
+	OnlineTime string `json:"online_t" example:"1h2m3s"`
+	// Whether the database can be reached
+	DBStatus string `json:"db_status" enums:"OK,FAIL" example:"OK"`
+	// Drift of the database clock from the app clock
+	ClockSkew string `json:"clock_skew" example:"120ms"`
+	// DEGRADED once the clock skew exceeds the configured threshold
+	ClockStatus string `json:"clock_status" enums:"OK,DEGRADED,FAIL" example:"OK"`
+	// Whether every migration is applied to the database, unknown when it can't be told, e.g. on a database never migrated
+	Migrations string `json:"migrations" enums:"up_to_date,pending,unknown" example:"up_to_date"`
+	// Number of migrations not applied yet
+	PendingMigrations int `json:"pending_migrations,omitempty" example:"0"`
+} // @name HealthStatus
+
+// The migration statuses of HealthStatus
+const (
+	MigrationsUpToDate = "up_to_date"
+	MigrationsPending  = "pending"
+	MigrationsUnknown  = "unknown"
+)
+
+// ProbeStatus is the body of the Kubernetes probes, GET /healthz and GET /readyz
+type ProbeStatus struct {
+	Status string `json:"status" enums:"OK,FAIL" example:"OK"`
+} // @name ProbeStatus
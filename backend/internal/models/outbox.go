@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// OutboxEntry is a user event waiting to be posted to the webhooks,
+// written in the same transaction as the change so a committed change is never left out
+type OutboxEntry struct {
+	bun.BaseModel `bun:"table:outbox,alias:o" tstype:"-"`
+
+	ID        int64      `bun:"id,pk,autoincrement" json:"id"`
+	Event     UserEvent  `bun:"event,type:jsonb,notnull" json:"event"`
+	CreatedAt time.Time  `bun:"created_at,notnull,default:current_timestamp" json:"createdAt"`
+	SentAt    *time.Time `bun:"sent_at" json:"sentAt,omitempty"`
+	// Failed delivery attempts
+	Attempts int `bun:"attempts,notnull,default:0" json:"attempts"`
+} // @name OutboxEntry
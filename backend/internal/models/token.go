@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// RefreshToken is a long-lived token exchanged for short-lived access tokens, stored by the SHA-256 digest
+// of the token so a leaked table can't be replayed
+type RefreshToken struct {
+	bun.BaseModel `bun:"table:refresh_tokens,alias:rt" tstype:"-"`
+
+	ID int64 `bun:"id,pk,autoincrement" json:"id"`
+	// Hex SHA-256 digest of the token
+	TokenHash string `bun:"token_hash,notnull,unique" json:"-" tstype:"-"`
+	// Subject of the access tokens
+	Subject string `bun:"subject,notnull" json:"subject"`
+	// Space-separated roles of the access tokens
+	Roles     string     `bun:"roles,notnull,default:''" json:"roles"`
+	CreatedAt time.Time  `bun:"created_at,notnull,default:current_timestamp" json:"createdAt"`
+	ExpiresAt time.Time  `bun:"expires_at,notnull" json:"expiresAt"`
+	RevokedAt *time.Time `bun:"revoked_at" json:"revokedAt,omitempty"`
+} // @name RefreshToken
+
+// RefreshTokenRequest is the request body for refreshing an access token or revoking a refresh token
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required" example:"3q2-7wX9yF0c8kQ1n5vLzT4mH6pA2sDgE0rUbJiKoNw"`
+} // @name RefreshTokenRequest
+
+// RefreshTokenIssueRequest is the request body for issuing a refresh token
+type RefreshTokenIssueRequest struct {
+	// Subject of the access tokens, e.g. the user name
+	Subject string `json:"subject" validate:"required,max=255" example:"jane"`
+	// Roles of the access tokens
+	Roles []string `json:"roles" validate:"max=20,dive,required,max=64,excludesrune= " example:"admin"`
+} // @name RefreshTokenIssueRequest
+
+// RefreshTokenIssueResponse is the response body for an issued refresh token, which can't be read back
+type RefreshTokenIssueResponse struct {
+	RefreshToken string `json:"refreshToken" example:"3q2-7wX9yF0c8kQ1n5vLzT4mH6pA2sDgE0rUbJiKoNw"`
+} // @name RefreshTokenIssueResponse
+
+// AccessTokenResponse is the response body for a refreshed access token
+type AccessTokenResponse struct {
+	// JWT signed with HS256, sent as "Authorization: Bearer <accessToken>"
+	AccessToken string `json:"accessToken" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenType   string `json:"tokenType" example:"Bearer"`
+	// Lifetime of the access token in seconds
+	ExpiresIn int `json:"expiresIn" example:"900"`
+} // @name AccessTokenResponse
@@ -15,7 +15,7 @@ type UserCommon struct {
 	//	@maxLength	255
 	//	@pattern	^[a-zA-Z0-9]+$
 	//	@example	johndoe
-	UserName string `json:"userName" validate:"required,min=4,max=255,alphanum" bun:"user_name,notnull" example:"johndoe"`
+	UserName string `json:"userName" validate:"required,min=4,max=255,alphanum" bun:"user_name,unique,notnull" example:"johndoe"`
 
 	//  First name
 	//	@minLength	1
@@ -46,6 +46,17 @@ type UserCommon struct {
 	//	@maxLength	255
 	//	@example	Engineering
 	Department string `json:"department" validate:"omitempty,max=255,alphaNumUnicodeWithSpaces" bun:"department" example:"Engineering"`
+
+	// Contact phone number in E.164 format
+	//	@maxLength	16
+	//	@pattern	^\+[1-9]?[0-9]{7,14}$
+	//	@example	+14155552671
+	Phone string `json:"phone,omitempty" validate:"omitempty,e164" bun:"phone" example:"+14155552671"`
+
+	// ID of the user's manager, omitted when they have none
+	//	@minimum	1
+	//	@example	2
+	ManagerID *int64 `json:"managerId,omitempty" validate:"omitempty,gt=0" bun:"manager_id" example:"2"`
 } // @name UserCommon
 
 // User represents a user in the system
@@ -58,6 +69,26 @@ type User struct {
 
 	CreatedAt time.Time `bun:"created_at,notnull,default:current_timestamp" json:"createdAt" format:"date-time" example:"2025-03-27T10:23:51.495798-05:00"`
 	UpdatedAt time.Time `bun:"updated_at,notnull,default:current_timestamp" json:"updatedAt" format:"date-time" example:"2025-03-27T10:23:51.495798-05:00"`
+	// Who created the user, "system" for the changes made without an actor (e.g. by the CLI)
+	CreatedBy string `bun:"created_by,notnull,default:'system'" json:"createdBy" readonly:"true" example:"jane.admin"`
+	// Who last changed the user
+	UpdatedBy string `bun:"updated_by,notnull,default:'system'" json:"updatedBy" readonly:"true" example:"jane.admin"`
+
+	// When the email last changed, omitted if it never changed since the creation
+	EmailUpdatedAt *time.Time `bun:"email_updated_at,nullzero" json:"emailUpdatedAt,omitempty" format:"date-time" readonly:"true" example:"2025-03-28T08:12:03.120412-05:00"`
+	// When the status last changed, omitted if it never changed since the creation
+	StatusUpdatedAt *time.Time `bun:"status_updated_at,nullzero" json:"statusUpdatedAt,omitempty" format:"date-time" readonly:"true" example:"2025-03-28T08:12:03.120412-05:00"`
+	// Incremented by every update, send it back (as If-Match or in the body) to detect concurrent changes
+	Version int64 `bun:"version,notnull,default:1" json:"version" readonly:"true" example:"1"`
+
+	// When the user last logged in, omitted if they never did
+	LastLoginAt *time.Time `bun:"last_login_at,nullzero" json:"lastLoginAt,omitempty" format:"date-time" readonly:"true" example:"2025-03-28T08:12:03.120412-05:00"`
+
+	// The user's manager, only loaded by GET /users/{id}, without their own manager
+	Manager *User `bun:"rel:belongs-to,join:manager_id=user_id" json:"manager,omitempty" readonly:"true"`
+
+	// bcrypt hash of the password, NULL for the users without one, never serialized
+	PasswordHash string `bun:"password_hash,nullzero" json:"-" tstype:"-" swaggerignore:"true"`
 } // @name User
 
 // UserCreateRequest is the request body for creating a user
@@ -66,6 +97,12 @@ type User struct {
 //	@required	["userName", "firstName", "lastName", "email", "userStatus"]
 type UserCreateRequest struct {
 	UserCommon `tstype:",extends"`
+
+	// Password, write-only: only its bcrypt hash is stored and it's never returned
+	//	@minLength	8
+	//	@maxLength	72
+	//	@format		password
+	Password string `json:"password,omitempty" validate:"omitempty,min=8,max=72" format:"password" example:"correct-horse-battery"`
 } // @name UserCreateRequest
 
 // UserUpdateRequest is the request body for updating a user
@@ -74,4 +111,60 @@ type UserCreateRequest struct {
 //	@required	["userName", "firstName", "lastName", "email", "userStatus"]
 type UserUpdateRequest struct {
 	UserCommon `tstype:",extends"`
+
+	// Version the update is based on, a stale version is rejected with 409. Omit it to overwrite unconditionally
+	Version int64 `json:"version,omitempty" validate:"omitempty,gt=0" example:"1"`
+
+	// UnmodifiedSince rejects the update when the user was updated after it, to the second, set from the
+	// If-Unmodified-Since header rather than the body
+	UnmodifiedSince time.Time `json:"-" tstype:"-" swaggerignore:"true"`
 } // @name UserUpdateRequest
+
+// MinPasswordLength and MaxPasswordLength bound the length of a password, bcrypt ignores the bytes past 72
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+// UserDeleteResponse is the response body for a deleted user
+type UserDeleteResponse struct {
+	Deleted bool  `json:"deleted" example:"true"`
+	UserID  int64 `json:"id" example:"1"`
+} // @name UserDeleteResponse
+
+// UserListResponse is the response body for a page of users
+type UserListResponse struct {
+	Users []User `json:"users"`
+	// Total number of users matching the criteria, regardless of the page
+	Total int `json:"total" example:"120"`
+	// Applied page size
+	Limit int `json:"limit" example:"50"`
+	// Applied number of skipped users
+	Offset int `json:"offset" example:"0"`
+	// Cursor of the next page (the cursor query parameter), set when the page is full and ordered by user_id ascending
+	NextCursor string `json:"nextCursor,omitempty" example:"NTA"`
+} // @name UserListResponse
+
+// UserCountResponse is the response body for counting users
+type UserCountResponse struct {
+	// Number of users matching the criteria
+	Total int `json:"total" example:"120"`
+	// Number of matching users per status, every status is listed
+	ByStatus map[UserStatus]int `json:"byStatus"`
+} // @name UserCountResponse
+
+// UserAvailabilityResponse is the response body for checking whether an email and a user name are still free,
+// only the checked ones are set
+type UserAvailabilityResponse struct {
+	EmailAvailable    *bool `json:"emailAvailable,omitempty" example:"true"`
+	UsernameAvailable *bool `json:"usernameAvailable,omitempty" example:"false"`
+} // @name UserAvailabilityResponse
+
+// UserDeactivateStaleResult is the response body for deactivating the users who haven't logged in for a while
+type UserDeactivateStaleResult struct {
+	// Whether the users were only reported, not deactivated
+	DryRun bool `json:"dryRun" example:"false"`
+	// Number of affected users
+	Count int    `json:"count" example:"1"`
+	Users []User `json:"users"`
+} // @name UserDeactivateStaleResult
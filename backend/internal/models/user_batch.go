@@ -0,0 +1,63 @@
+package models
+
+// ConflictMode defines how a batch create treats users that already exist
+type ConflictMode string
+
+const (
+	// ConflictModeAtomic rolls back the whole batch on the first duplicate
+	ConflictModeAtomic ConflictMode = "atomic"
+	// ConflictModeIgnore skips duplicates and inserts the remaining users
+	ConflictModeIgnore ConflictMode = "ignore"
+)
+
+// UserBatchSkipped describes a batch item that was skipped as a duplicate
+type UserBatchSkipped struct {
+	// Position of the item in the request
+	Index int `json:"index" example:"2"`
+	// Field that collided with an existing user
+	Field string `json:"field" example:"email"`
+	// Value that collided with an existing user
+	Value string `json:"value" example:"john.doe@example.com"`
+} // @name UserBatchSkipped
+
+// UserBatchCreateResult is the response body for a batch create
+type UserBatchCreateResult struct {
+	Created []User             `json:"created"`
+	Skipped []UserBatchSkipped `json:"skipped"`
+} // @name UserBatchCreateResult
+
+// UserBatchUpdateItem is a single item of a batch update
+//
+//	@required	["id", "userName", "firstName", "lastName", "email", "userStatus"]
+type UserBatchUpdateItem struct {
+	// ID of the user to update
+	UserID int64 `json:"id" validate:"required,gt=0" example:"1"`
+
+	UserCommon `tstype:",extends"`
+} // @name UserBatchUpdateItem
+
+// UserBatchUpdateResult is the response body for a batch update
+type UserBatchUpdateResult struct {
+	// Updated users, in the order of the request items
+	Updated []User `json:"updated"`
+} // @name UserBatchUpdateResult
+
+// UserStatusUpdateRequest is the request body for setting the status of several users at once
+//
+//	@required	["ids", "status"]
+type UserStatusUpdateRequest struct {
+	// IDs of the users to update
+	IDs []int64 `json:"ids" validate:"dive,gt=0" example:"1,2,3"`
+	// Status set on every user
+	//	@enum		A,I,T
+	//	@example	T
+	Status UserStatus `json:"status" validate:"required,oneof=A I T" tstype:"UserStatus" example:"T" enums:"A,I,T"`
+} // @name UserStatusUpdateRequest
+
+// UserStatusUpdateResult is the response body for setting the status of several users
+type UserStatusUpdateResult struct {
+	// Number of users whose status changed, the users already in the status are left untouched
+	Count int `json:"count" example:"2"`
+	// Requested IDs no user has
+	NotFound []int64 `json:"notFound"`
+} // @name UserStatusUpdateResult
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// SortRelevance orders search results by how well they match the query
+const SortRelevance = "relevance"
+
+// Sort directions
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+const (
+	// DefaultListLimit is the page size used when none is requested
+	DefaultListLimit = 50
+	// MaxListLimit is the largest page size served in one response
+	MaxListLimit = 500
+)
+
+// ListParams holds the optional criteria for listing users
+type ListParams struct {
+	// Query is matched case-insensitively as a substring of the user name, first name, last name and email
+	Query string
+	// Sort selects the field to order by, empty keeps the default user_id order
+	Sort string
+	// Order is the sort direction, asc (default) or desc, it doesn't apply to the relevance sort
+	Order string
+	// Status keeps only the users with the given status
+	Status UserStatus
+	// Department keeps only the users of the given department (exact match)
+	Department string
+	// DepartmentLike keeps only the users whose department contains the value (case-insensitive)
+	DepartmentLike string
+	// CreatedAfter and CreatedBefore keep only the users created in the range, from CreatedAfter included
+	// to CreatedBefore excluded. A zero time leaves that end of the range open
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// UpdatedAfter and UpdatedBefore are the same range for the last update time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+	// Limit caps the number of returned users, zero means no limit
+	Limit int
+	// Offset skips the given number of users
+	Offset int
+	// AfterID switches to keyset pagination: only the users with a greater user_id are listed.
+	// It requires the default user_id ascending order and no offset
+	AfterID int64
+}
+
+// InvalidParam describes a rejected query parameter
+type InvalidParam struct {
+	Name   string `json:"name" example:"limit"`
+	Reason string `json:"reason" example:"must be a positive integer"`
+} // @name InvalidParam
+
+// InvalidParamsResponse is the error response body listing every rejected query parameter at once
+type InvalidParamsResponse struct {
+	ErrorResponse
+	InvalidParams []InvalidParam `json:"invalidParams"`
+} // @name InvalidParamsResponse
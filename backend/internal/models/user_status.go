@@ -11,3 +11,13 @@ const (
 	// UserStatusTerminated represents a terminated user
 	UserStatusTerminated UserStatus = "T"
 )
+
+// IsValid reports whether the status is one of the defined user statuses
+func (s UserStatus) IsValid() bool {
+	switch s {
+	case UserStatusActive, UserStatusInactive, UserStatusTerminated:
+		return true
+	default:
+		return false
+	}
+}
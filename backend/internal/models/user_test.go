@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -23,6 +24,17 @@ func TestUserModel(t *testing.T) {
 		assert.Equal(t, UserStatus("T"), UserStatusTerminated)
 	})
 
+	t.Run("UserStatusIsValid", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, UserStatusActive.IsValid())
+		assert.True(t, UserStatusInactive.IsValid())
+		assert.True(t, UserStatusTerminated.IsValid())
+		assert.False(t, UserStatus("X").IsValid())
+		assert.False(t, UserStatus("a").IsValid())
+		assert.False(t, UserStatus("").IsValid())
+	})
+
 	t.Run("UserFieldsInitialization", func(t *testing.T) {
 		t.Parallel()
 
@@ -51,6 +63,14 @@ func TestUserModel(t *testing.T) {
 		assert.Equal(t, now, user.CreatedAt)
 		assert.Equal(t, now, user.UpdatedAt)
 	})
+
+	t.Run("PasswordHashNotSerialized", func(t *testing.T) {
+		t.Parallel()
+
+		body, err := json.Marshal(User{UserID: 1, PasswordHash: "$2a$10$hash"})
+		require.NoError(t, err)
+		assert.NotContains(t, string(body), "hash")
+	})
 }
 
 func TestUserCreateRequestValidation(t *testing.T) {
@@ -70,7 +90,7 @@ func TestUserCreateRequestValidation(t *testing.T) {
 		{
 			name: "Valid Request",
 			request: UserCreateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "Valid",
 					LastName:   "User",
@@ -81,10 +101,26 @@ func TestUserCreateRequestValidation(t *testing.T) {
 			},
 			expectedError: false,
 		},
+		{
+			name: "Password Too Short",
+			request: UserCreateRequest{
+				UserCommon: UserCommon{
+					UserName:   "validuser",
+					FirstName:  "Valid",
+					LastName:   "User",
+					Email:      "valid@example.com",
+					UserStatus: UserStatusActive,
+				},
+				Password: "short",
+			},
+			expectedError: true,
+			errorField:    "Password",
+			errorTag:      "min",
+		},
 		{
 			name: "Username Too Short",
 			request: UserCreateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "usr",
 					FirstName:  "Valid",
 					LastName:   "User",
@@ -100,7 +136,7 @@ func TestUserCreateRequestValidation(t *testing.T) {
 		{
 			name: "Username With Non-Alphanumeric Characters",
 			request: UserCreateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "user-name",
 					FirstName:  "Valid",
 					LastName:   "User",
@@ -116,7 +152,7 @@ func TestUserCreateRequestValidation(t *testing.T) {
 		{
 			name: "Missing First Name",
 			request: UserCreateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "",
 					LastName:   "User",
@@ -132,7 +168,7 @@ func TestUserCreateRequestValidation(t *testing.T) {
 		{
 			name: "First Name With Special Characters",
 			request: UserCreateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "First@Name",
 					LastName:   "User",
@@ -148,7 +184,7 @@ func TestUserCreateRequestValidation(t *testing.T) {
 		{
 			name: "Missing Last Name",
 			request: UserCreateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "Valid",
 					LastName:   "",
@@ -164,7 +200,7 @@ func TestUserCreateRequestValidation(t *testing.T) {
 		{
 			name: "Last Name With Special Characters",
 			request: UserCreateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "Valid",
 					LastName:   "Last@Name",
@@ -180,7 +216,7 @@ func TestUserCreateRequestValidation(t *testing.T) {
 		{
 			name: "Invalid Email Format",
 			request: UserCreateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "Valid",
 					LastName:   "User",
@@ -196,7 +232,7 @@ func TestUserCreateRequestValidation(t *testing.T) {
 		{
 			name: "Missing Email",
 			request: UserCreateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "Valid",
 					LastName:   "User",
@@ -212,7 +248,7 @@ func TestUserCreateRequestValidation(t *testing.T) {
 		{
 			name: "Invalid User Status",
 			request: UserCreateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "Valid",
 					LastName:   "User",
@@ -228,7 +264,7 @@ func TestUserCreateRequestValidation(t *testing.T) {
 		{
 			name: "Empty User Status",
 			request: UserCreateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "Valid",
 					LastName:   "User",
@@ -244,7 +280,7 @@ func TestUserCreateRequestValidation(t *testing.T) {
 		{
 			name: "Department With Special Characters",
 			request: UserCreateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "Valid",
 					LastName:   "User",
@@ -260,7 +296,7 @@ func TestUserCreateRequestValidation(t *testing.T) {
 		{
 			name: "Optional Department Can Be Empty",
 			request: UserCreateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "Valid",
 					LastName:   "User",
@@ -321,7 +357,7 @@ func TestUserUpdateRequestValidation(t *testing.T) {
 		{
 			name: "Valid Update Request",
 			request: UserUpdateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "Valid",
 					LastName:   "User",
@@ -335,7 +371,7 @@ func TestUserUpdateRequestValidation(t *testing.T) {
 		{
 			name: "Username Too Short",
 			request: UserUpdateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "usr",
 					FirstName:  "Valid",
 					LastName:   "User",
@@ -351,7 +387,7 @@ func TestUserUpdateRequestValidation(t *testing.T) {
 		{
 			name: "Username With Non-Alphanumeric Characters",
 			request: UserUpdateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "user-name",
 					FirstName:  "Valid",
 					LastName:   "User",
@@ -367,7 +403,7 @@ func TestUserUpdateRequestValidation(t *testing.T) {
 		{
 			name: "Missing Username",
 			request: UserUpdateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "",
 					FirstName:  "Valid",
 					LastName:   "User",
@@ -383,7 +419,7 @@ func TestUserUpdateRequestValidation(t *testing.T) {
 		{
 			name: "Missing First Name",
 			request: UserUpdateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "",
 					LastName:   "User",
@@ -399,7 +435,7 @@ func TestUserUpdateRequestValidation(t *testing.T) {
 		{
 			name: "First Name With Special Characters",
 			request: UserUpdateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "First@Name",
 					LastName:   "User",
@@ -415,7 +451,7 @@ func TestUserUpdateRequestValidation(t *testing.T) {
 		{
 			name: "Missing Last Name",
 			request: UserUpdateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "Valid",
 					LastName:   "",
@@ -431,7 +467,7 @@ func TestUserUpdateRequestValidation(t *testing.T) {
 		{
 			name: "Last Name With Special Characters",
 			request: UserUpdateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "Valid",
 					LastName:   "Last@Name",
@@ -446,7 +482,7 @@ func TestUserUpdateRequestValidation(t *testing.T) {
 		{
 			name: "Invalid Email Format",
 			request: UserUpdateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "Valid",
 					LastName:   "User",
@@ -462,7 +498,7 @@ func TestUserUpdateRequestValidation(t *testing.T) {
 		{
 			name: "Missing Email",
 			request: UserUpdateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "Valid",
 					LastName:   "User",
@@ -477,7 +513,7 @@ func TestUserUpdateRequestValidation(t *testing.T) {
 		{
 			name: "Invalid User Status",
 			request: UserUpdateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "Valid",
 					LastName:   "User",
@@ -493,7 +529,7 @@ func TestUserUpdateRequestValidation(t *testing.T) {
 		{
 			name: "Empty User Status",
 			request: UserUpdateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "Valid",
 					LastName:   "User",
@@ -509,7 +545,7 @@ func TestUserUpdateRequestValidation(t *testing.T) {
 		{
 			name: "Department With Special Characters",
 			request: UserUpdateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "Valid",
 					LastName:   "User",
@@ -525,7 +561,7 @@ func TestUserUpdateRequestValidation(t *testing.T) {
 		{
 			name: "Optional Department Can Be Empty",
 			request: UserUpdateRequest{
-				UserCommon{
+				UserCommon: UserCommon{
 					UserName:   "validuser",
 					FirstName:  "Valid",
 					LastName:   "User",
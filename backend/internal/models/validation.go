@@ -0,0 +1,11 @@
+package models
+
+// FieldError describes a request body field rejected by the validation rules
+type FieldError struct {
+	// JSON key of the field, prefixed by the item position for the batch endpoints
+	Field string `json:"field" example:"email"`
+	// Validation rule the field failed
+	Tag string `json:"tag" example:"email"`
+	// English sentence describing the failure
+	Message string `json:"message" example:"email must be a valid email address"`
+} // @name FieldError
@@ -0,0 +1,129 @@
+// Package outbox posts the user events of the outbox to the webhooks in the background.
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.uber.org/fx"
+
+	"user-management/internal/config"
+	"user-management/internal/models"
+	"user-management/internal/repository"
+	"user-management/internal/webhook"
+)
+
+// BatchSize is the number of entries read from the outbox at once
+const BatchSize = 100
+
+// Sender delivers an event, an error leaves it in the outbox to be sent again
+type Sender interface {
+	Send(ctx context.Context, event models.UserEvent) error
+}
+
+// Poller sends the unsent outbox entries every interval, oldest first, and marks them sent.
+// An entry is only marked once sent, so an entry whose mark is lost to a crash is sent again: the delivery
+// is at least once. A failed entry stops the round, so the events of a user are never sent out of order.
+type Poller struct {
+	repo     repository.UserRepository
+	sender   Sender
+	interval time.Duration
+
+	// cancel aborts the round in progress, done is closed once the loop returned
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewPoller creates a poller sending the entries of the repository's outbox through the sender
+func NewPoller(repo repository.UserRepository, sender Sender, interval time.Duration) *Poller {
+	return &Poller{repo: repo, sender: sender, interval: interval}
+}
+
+// Register starts the poller of the webhooks with the application and stops it with it,
+// there's nothing to poll when no webhook URL is configured.
+func Register(lc fx.Lifecycle, cfg *config.Config, repo repository.UserRepository) {
+	if len(cfg.Webhooks.URLs) == 0 {
+		return
+	}
+
+	sender := webhook.New(cfg.Webhooks.URLs, cfg.Webhooks.Timeout, cfg.Webhooks.MaxRetries, cfg.Webhooks.Backoff)
+	p := NewPoller(repo, sender, cfg.Webhooks.PollInterval)
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			p.Start()
+			return nil
+		},
+		OnStop: p.Stop,
+	})
+
+	slog.With("urls", len(cfg.Webhooks.URLs)).Info("Webhooks enabled")
+}
+
+// Start runs the polling loop in the background
+func (p *Poller) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.done = make(chan struct{})
+
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			p.Poll(ctx)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the polling loop, aborting the round in progress, and waits for it until ctx is done.
+// The entries left unsent are sent on the next start.
+func (p *Poller) Stop(ctx context.Context) error {
+	p.cancel()
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Poll sends the unsent entries, a batch at a time, until the outbox is empty or an entry fails
+func (p *Poller) Poll(ctx context.Context) {
+	outbox := p.repo.Outbox()
+	for ctx.Err() == nil {
+		entries, err := outbox.ListUnsent(ctx, BatchSize)
+		if err != nil {
+			slog.With("error", err).Error("failed to read the outbox")
+			return
+		}
+
+		for _, entry := range entries {
+			if err := p.sender.Send(ctx, entry.Event); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				// the failure is logged by the sender, the entry is retried on the next round
+				if err := outbox.MarkFailed(ctx, entry.ID); err != nil {
+					slog.With("error", err).With("id", entry.ID).Error("failed to count the outbox attempt")
+				}
+				return
+			}
+			if err := outbox.MarkSent(ctx, entry.ID, time.Now().UTC()); err != nil {
+				slog.With("error", err).With("id", entry.ID).Error("failed to mark the outbox entry sent")
+				return
+			}
+		}
+
+		if len(entries) < BatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-management/internal/models"
+	"user-management/internal/repository"
+)
+
+// fakeSender records the sent user IDs, failing for the IDs in fail
+type fakeSender struct {
+	mu   sync.Mutex
+	sent []int64
+	fail map[int64]bool
+}
+
+func (s *fakeSender) Send(_ context.Context, event models.UserEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fail[event.User.UserID] {
+		return errors.New("unavailable")
+	}
+	s.sent = append(s.sent, event.User.UserID)
+	return nil
+}
+
+func (s *fakeSender) sentIDs() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int64(nil), s.sent...)
+}
+
+func addEvents(t *testing.T, repo repository.UserRepository, ids ...int64) {
+	t.Helper()
+
+	for _, id := range ids {
+		require.NoError(t, repo.Outbox().Add(context.Background(), &models.OutboxEntry{Event: models.UserEvent{
+			Type: models.UserEventCreated,
+			User: models.User{UserID: id},
+		}}))
+	}
+}
+
+func unsent(t *testing.T, repo repository.UserRepository) []models.OutboxEntry {
+	t.Helper()
+
+	entries, err := repo.Outbox().ListUnsent(context.Background(), BatchSize)
+	require.NoError(t, err)
+	return entries
+}
+
+func TestPoll(t *testing.T) {
+	t.Parallel()
+
+	repo := repository.NewInMemoryUserRepository()
+	sender := &fakeSender{fail: map[int64]bool{3: true}}
+	p := NewPoller(repo, sender, time.Hour)
+	ctx := context.Background()
+
+	addEvents(t, repo, 1, 2, 3, 4)
+	p.Poll(ctx)
+
+	// the failed entry stops the round, so the next ones keep their order
+	assert.Equal(t, []int64{1, 2}, sender.sentIDs())
+	entries := unsent(t, repo)
+	require.Len(t, entries, 2)
+	assert.Equal(t, 1, entries[0].Attempts)
+
+	delete(sender.fail, 3)
+	p.Poll(ctx)
+	assert.Equal(t, []int64{1, 2, 3, 4}, sender.sentIDs())
+	assert.Empty(t, unsent(t, repo))
+}
+
+func TestPollBatches(t *testing.T) {
+	t.Parallel()
+
+	repo := repository.NewInMemoryUserRepository()
+	sender := &fakeSender{}
+	ids := make([]int64, BatchSize+5)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	addEvents(t, repo, ids...)
+
+	NewPoller(repo, sender, time.Hour).Poll(context.Background())
+	assert.Equal(t, ids, sender.sentIDs())
+}
+
+func TestPollerStartStop(t *testing.T) {
+	t.Parallel()
+
+	repo := repository.NewInMemoryUserRepository()
+	sender := &fakeSender{}
+	p := NewPoller(repo, sender, 10*time.Millisecond)
+
+	addEvents(t, repo, 1)
+	p.Start()
+	// the entries added while running are picked up by the next round
+	addEvents(t, repo, 2)
+	assert.Eventually(t, func() bool { return len(sender.sentIDs()) == 2 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, p.Stop(context.Background()))
+
+	// left for the next start
+	addEvents(t, repo, 3)
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, []int64{1, 2}, sender.sentIDs())
+	assert.Len(t, unsent(t, repo), 1)
+}
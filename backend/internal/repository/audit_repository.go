@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+
+	"user-management/internal/models"
+)
+
+// AuditRepository stores the audit log of the user changes
+type AuditRepository interface {
+	Record(ctx context.Context, entries ...*models.AuditEntry) error
+	// ListByUser returns the entries of the user, newest first
+	ListByUser(ctx context.Context, userID int64) ([]models.AuditEntry, error)
+}
+
+type auditRepository struct {
+	db bun.IDB
+}
+
+// NewAuditRepository creates a new audit repository.
+// Use UserRepository.Audit to record the entries in the transaction of the changes.
+func NewAuditRepository(db *bun.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Record(ctx context.Context, entries ...*models.AuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	_, err := r.db.NewInsert().Model(&entries).Exec(ctx)
+	return err
+}
+
+func (r *auditRepository) ListByUser(ctx context.Context, userID int64) ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+	err := r.db.NewSelect().
+		Model(&entries).
+		Where("user_id = ?", userID).
+		// the IDs break the ties of the entries recorded in the same transaction
+		Order("created_at DESC", "id DESC").
+		Scan(ctx)
+	return entries, err
+}
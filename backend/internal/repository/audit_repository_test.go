@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-management/internal/models"
+)
+
+func TestAuditListByUser(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	audit := newTestRepository(t).Audit()
+
+	at := time.Date(2025, 3, 27, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, audit.Record(ctx,
+		&models.AuditEntry{UserID: 1, Action: models.AuditActionCreate, Actor: "alice", CreatedAt: at,
+			Diff: map[string]models.AuditChange{"email": {New: "john@example.com"}}},
+		&models.AuditEntry{UserID: 2, Action: models.AuditActionCreate, Actor: "alice", CreatedAt: at},
+	))
+	require.NoError(t, audit.Record(ctx,
+		&models.AuditEntry{UserID: 1, Action: models.AuditActionUpdate, Actor: "bob", CreatedAt: at.Add(time.Hour),
+			Diff: map[string]models.AuditChange{"email": {Old: "john@example.com", New: "johnny@example.com"}}},
+	))
+	// recorded in the same transaction as the update, so with the same time
+	require.NoError(t, audit.Record(ctx,
+		&models.AuditEntry{UserID: 1, Action: models.AuditActionDelete, Actor: "bob", CreatedAt: at.Add(time.Hour)},
+	))
+
+	entries, err := audit.ListByUser(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	actions := []models.AuditAction{entries[0].Action, entries[1].Action, entries[2].Action}
+	assert.Equal(t, []models.AuditAction{models.AuditActionDelete, models.AuditActionUpdate, models.AuditActionCreate}, actions)
+	assert.Equal(t, "bob", entries[1].Actor)
+	assert.Equal(t, map[string]models.AuditChange{"email": {Old: "john@example.com", New: "johnny@example.com"}}, entries[1].Diff)
+
+	entries, err = audit.ListByUser(ctx, 3)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
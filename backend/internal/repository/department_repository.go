@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/feature"
+
+	"user-management/internal/models"
+)
+
+// DepartmentRepository stores the departments the users can belong to
+type DepartmentRepository interface {
+	// List returns every department, ordered by name
+	List(ctx context.Context) ([]models.Department, error)
+	// Create inserts the department and reads the persisted row back into it.
+	// A taken name is returned as *UniqueViolationError.
+	Create(ctx context.Context, department *models.Department) error
+	// CreateIfNotExists inserts the department unless its name is taken, reporting whether it was inserted
+	CreateIfNotExists(ctx context.Context, department *models.Department) (bool, error)
+	ExistsByName(ctx context.Context, name string) (bool, error)
+}
+
+type departmentRepository struct {
+	db bun.IDB
+}
+
+// NewDepartmentRepository creates a new department repository.
+// Use UserRepository.Departments to check the departments in the transaction of a user change.
+func NewDepartmentRepository(db *bun.DB) DepartmentRepository {
+	return &departmentRepository{db: db}
+}
+
+func (r *departmentRepository) List(ctx context.Context) ([]models.Department, error) {
+	departments := []models.Department{}
+	err := r.db.NewSelect().Model(&departments).Order("name ASC").Scan(ctx)
+	return departments, err
+}
+
+func (r *departmentRepository) Create(ctx context.Context, department *models.Department) error {
+	query := r.db.NewInsert().Model(department)
+	returning := r.db.Dialect().Features().Has(feature.InsertReturning)
+	if returning {
+		query = query.Returning("*")
+	}
+	if _, err := query.Exec(ctx); err != nil {
+		return uniqueViolation(err)
+	}
+	if !returning {
+		return r.db.NewSelect().Model(department).WherePK().Scan(ctx)
+	}
+	return nil
+}
+
+func (r *departmentRepository) CreateIfNotExists(ctx context.Context, department *models.Department) (bool, error) {
+	res, err := r.db.NewInsert().Model(department).Ignore().Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *departmentRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
+	return r.db.NewSelect().Model((*models.Department)(nil)).Where("name = ?", name).Exists(ctx)
+}
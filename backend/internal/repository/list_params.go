@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"user-management/internal/models"
+)
+
+// InvalidParamsError reports every rejected list parameter, so clients can fix them all at once
+type InvalidParamsError struct {
+	Params []models.InvalidParam
+}
+
+func (e *InvalidParamsError) Error() string {
+	problems := make([]string, len(e.Params))
+	for i, param := range e.Params {
+		problems[i] = fmt.Sprintf("invalid %s: %s", param.Name, param.Reason)
+	}
+	return strings.Join(problems, "; ")
+}
+
+// Is matches ErrInvalidSort when the sort field is among the rejected parameters
+func (e *InvalidParamsError) Is(target error) bool {
+	return target == ErrInvalidSort && slices.ContainsFunc(e.Params, func(param models.InvalidParam) bool {
+		return param.Name == "sort"
+	})
+}
+
+// IsKeysetOrder reports whether the users are listed by ascending user_id, the order keyset pagination follows
+func IsKeysetOrder(params models.ListParams) bool {
+	return (params.Sort == "" || params.Sort == "user_id") && params.Order != models.OrderDesc
+}
+
+// ValidateListParams checks the list parameters against the whitelists and each other,
+// returning an *InvalidParamsError listing every problem rather than only the first one.
+func ValidateListParams(params models.ListParams) error {
+	var invalid []models.InvalidParam
+
+	if _, ok := sortColumns[params.Sort]; !ok && params.Sort != "" && params.Sort != models.SortRelevance {
+		invalid = append(invalid, models.InvalidParam{
+			Name:   "sort",
+			Reason: fmt.Sprintf("unknown field %q, allowed fields are %s", params.Sort, strings.Join(SortFields(), ", ")),
+		})
+	}
+
+	switch params.Order {
+	case "", models.OrderAsc, models.OrderDesc:
+	default:
+		invalid = append(invalid, models.InvalidParam{
+			Name:   "order",
+			Reason: fmt.Sprintf("must be one of %s, %s", models.OrderAsc, models.OrderDesc),
+		})
+	}
+
+	if params.Status != "" && !params.Status.IsValid() {
+		invalid = append(invalid, models.InvalidParam{
+			Name: "status",
+			Reason: fmt.Sprintf("must be one of %s, %s, %s",
+				models.UserStatusActive, models.UserStatusInactive, models.UserStatusTerminated),
+		})
+	}
+
+	// the exact match makes the substring match redundant at best, contradictory at worst
+	if params.Department != "" && params.DepartmentLike != "" {
+		invalid = append(invalid, models.InvalidParam{
+			Name:   "department_like",
+			Reason: "can't be combined with department",
+		})
+	}
+
+	// an empty range is most likely swapped ends
+	if !params.CreatedAfter.IsZero() && !params.CreatedBefore.IsZero() && !params.CreatedAfter.Before(params.CreatedBefore) {
+		invalid = append(invalid, models.InvalidParam{Name: "createdBefore", Reason: "must be after createdAfter"})
+	}
+	if !params.UpdatedAfter.IsZero() && !params.UpdatedBefore.IsZero() && !params.UpdatedAfter.Before(params.UpdatedBefore) {
+		invalid = append(invalid, models.InvalidParam{Name: "updatedBefore", Reason: "must be after updatedAfter"})
+	}
+
+	if params.Limit < 0 {
+		invalid = append(invalid, models.InvalidParam{Name: "limit", Reason: "must not be negative"})
+	}
+	if params.Offset < 0 {
+		invalid = append(invalid, models.InvalidParam{Name: "offset", Reason: "must not be negative"})
+	}
+
+	// the keyset only follows the user_id order, and skipping rows after it would skip unseen users
+	switch {
+	case params.AfterID < 0:
+		invalid = append(invalid, models.InvalidParam{Name: "cursor", Reason: "must not be negative"})
+	case params.AfterID > 0 && params.Offset > 0:
+		invalid = append(invalid, models.InvalidParam{Name: "cursor", Reason: "can't be combined with offset"})
+	case params.AfterID > 0 && !IsKeysetOrder(params):
+		invalid = append(invalid, models.InvalidParam{Name: "cursor", Reason: "only applies to the default user_id ascending order"})
+	}
+
+	if len(invalid) > 0 {
+		return &InvalidParamsError{Params: invalid}
+	}
+	return nil
+}
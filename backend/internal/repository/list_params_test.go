@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-management/internal/models"
+)
+
+func TestValidateListParams(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		params   models.ListParams
+		expected []string
+	}{
+		{
+			name:   "defaults",
+			params: models.ListParams{},
+		},
+		{
+			name:   "every criterion set",
+			params: models.ListParams{Query: "john", Sort: "last_name", Order: models.OrderDesc, Status: models.UserStatusActive, Department: "Sales", Limit: 10, Offset: 20},
+		},
+		{
+			name:     "unknown sort",
+			params:   models.ListParams{Sort: "password"},
+			expected: []string{"sort"},
+		},
+		{
+			name:     "conflicting department filters",
+			params:   models.ListParams{Department: "Sales", DepartmentLike: "sal"},
+			expected: []string{"department_like"},
+		},
+		{
+			name:   "cursor in the user_id order",
+			params: models.ListParams{Sort: "user_id", AfterID: 10, Limit: 10},
+		},
+		{
+			name:     "cursor with an offset",
+			params:   models.ListParams{AfterID: 10, Offset: 20},
+			expected: []string{"cursor"},
+		},
+		{
+			name:     "cursor in another order",
+			params:   models.ListParams{AfterID: 10, Order: models.OrderDesc},
+			expected: []string{"cursor"},
+		},
+		{
+			name: "date ranges",
+			params: models.ListParams{
+				CreatedAfter:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				CreatedBefore: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAfter:  time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "empty date ranges",
+			params: models.ListParams{
+				CreatedAfter:  time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+				CreatedBefore: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAfter:  time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedBefore: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expected: []string{"createdBefore", "updatedBefore"},
+		},
+		{
+			name:     "every problem at once",
+			params:   models.ListParams{Sort: "random", Order: "up", Status: "X", Department: "Sales", DepartmentLike: "sal", Limit: -1, Offset: -1},
+			expected: []string{"sort", "order", "status", "department_like", "limit", "offset"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateListParams(tc.params)
+			if tc.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var invalidErr *InvalidParamsError
+			require.ErrorAs(t, err, &invalidErr)
+			names := make([]string, len(invalidErr.Params))
+			for i, param := range invalidErr.Params {
+				names[i] = param.Name
+			}
+			assert.Equal(t, tc.expected, names)
+			assert.Equal(t, slices.Contains(tc.expected, "sort"), errors.Is(err, ErrInvalidSort))
+		})
+	}
+}
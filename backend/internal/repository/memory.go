@@ -0,0 +1,729 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"user-management/internal/models"
+)
+
+// the unique constraints of the emails and user names, named as SQLite and MySQL report them
+const (
+	memoryEmailConstraint    = "users.email"
+	memoryUserNameConstraint = "users.user_name"
+)
+
+// memoryDefaultActor is the database default of the created_by and updated_by columns
+const memoryDefaultActor = "system"
+
+// memoryData holds the rows of an InMemoryUserRepository
+type memoryData struct {
+	users       map[int64]models.User
+	audit       []models.AuditEntry
+	departments map[string]models.Department
+	outbox      []models.OutboxEntry
+	tokens      []models.RefreshToken
+	lastUserID  int64
+	lastAuditID int64
+	lastDeptID  int64
+}
+
+// clone copies the rows, so a transaction works on a snapshot it can discard
+func (d *memoryData) clone() *memoryData {
+	c := *d
+	c.users = maps.Clone(d.users)
+	c.audit = slices.Clone(d.audit)
+	c.departments = maps.Clone(d.departments)
+	c.outbox = slices.Clone(d.outbox)
+	c.tokens = slices.Clone(d.tokens)
+	return &c
+}
+
+// InMemoryUserRepository is a UserRepository keeping the users in a map, for the tests that don't need a database.
+// It behaves like the SQL repository: the emails are unique, a missing user is sql.ErrNoRows for GetByID and
+// ErrUserNotFound for the other lookups, and a transaction works on a snapshot, applied only when it succeeds.
+// Transactions are serialized with the other operations, as SQLite does.
+type InMemoryUserRepository struct {
+	mu   sync.Mutex
+	data *memoryData
+	// now returns the time stamped on the created and updated users
+	now func() time.Time
+}
+
+var _ UserRepository = (*InMemoryUserRepository)(nil)
+
+// NewInMemoryUserRepository creates an empty in-memory user repository.
+func NewInMemoryUserRepository() *InMemoryUserRepository {
+	return &InMemoryUserRepository{
+		data: &memoryData{users: map[int64]models.User{}, departments: map[string]models.Department{}},
+		now:  func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// users returns copies of the users matching the filter, ordered by user_id
+func (r *InMemoryUserRepository) users(match func(user *models.User) bool) []models.User {
+	var users []models.User
+	for _, user := range r.data.users {
+		if match(&user) {
+			users = append(users, cloneUser(user))
+		}
+	}
+	slices.SortFunc(users, func(a, b models.User) int { return cmp.Compare(a.UserID, b.UserID) })
+	return users
+}
+
+func (r *InMemoryUserRepository) List(_ context.Context, params models.ListParams) ([]models.User, int, error) {
+	if err := ValidateListParams(params); err != nil {
+		return nil, 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	users := r.users(func(user *models.User) bool { return matchesFilters(user, params) })
+	total := len(users)
+
+	slices.SortStableFunc(users, compareUsers(params))
+
+	if params.AfterID > 0 {
+		users = slices.DeleteFunc(users, func(user models.User) bool { return user.UserID <= params.AfterID })
+	}
+	users = users[min(params.Offset, len(users)):]
+	if params.Limit > 0 && params.Limit < len(users) {
+		users = users[:params.Limit]
+	}
+	return users, total, nil
+}
+
+func (r *InMemoryUserRepository) Stream(ctx context.Context, params models.ListParams, fn func(user *models.User) error) error {
+	// the page is copied out of the lock, fn may call back into the repository
+	users, _, err := r.List(ctx, params)
+	if err != nil {
+		return err
+	}
+	for i := range users {
+		if err := fn(&users[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compareUsers orders the users by the sort field of the params, the users being sorted by user_id already
+func compareUsers(params models.ListParams) func(a, b models.User) int {
+	var compare func(a, b *models.User) int
+	switch params.Sort {
+	case "", "user_id":
+		compare = func(a, b *models.User) int { return cmp.Compare(a.UserID, b.UserID) }
+	case "created_at":
+		compare = func(a, b *models.User) int { return a.CreatedAt.Compare(b.CreatedAt) }
+	case "last_name":
+		compare = func(a, b *models.User) int { return strings.Compare(a.LastName, b.LastName) }
+	case "user_name":
+		compare = func(a, b *models.User) int { return strings.Compare(a.UserName, b.UserName) }
+	case models.SortRelevance:
+		// the relevance always ranks the best matches first
+		term := strings.ToLower(params.Query)
+		return func(a, b models.User) int { return cmp.Compare(relevance(&a, term), relevance(&b, term)) }
+	}
+
+	return func(a, b models.User) int {
+		if params.Order == models.OrderDesc {
+			return compare(&b, &a)
+		}
+		return compare(&a, &b)
+	}
+}
+
+// relevance ranks the user as orderByRelevance does, term being lowercase
+func relevance(user *models.User, term string) int {
+	userName, email := strings.ToLower(user.UserName), strings.ToLower(user.Email)
+	switch {
+	case term == "", userName == term || email == term:
+		return 0
+	case strings.HasPrefix(userName, term):
+		return 1
+	case strings.HasPrefix(strings.ToLower(user.FirstName), term),
+		strings.HasPrefix(strings.ToLower(user.LastName), term),
+		strings.HasPrefix(email, term):
+		return 2
+	default:
+		return 3
+	}
+}
+
+// matchesFilters reports whether the user matches the search and filter criteria of the params, see applyFilters
+func matchesFilters(user *models.User, params models.ListParams) bool {
+	switch {
+	case params.Query != "" && !matchesSearch(user, params.Query):
+		return false
+	case params.Status != "" && user.UserStatus != params.Status:
+		return false
+	case params.Department != "" && user.Department != params.Department:
+		return false
+	case params.DepartmentLike != "" &&
+		!strings.Contains(strings.ToLower(user.Department), strings.ToLower(params.DepartmentLike)):
+		return false
+	case !inRange(user.CreatedAt, params.CreatedAfter, params.CreatedBefore),
+		!inRange(user.UpdatedAt, params.UpdatedAfter, params.UpdatedBefore):
+		return false
+	}
+	return true
+}
+
+// inRange reports whether t is in the [after, before) range, a zero end leaves it open
+func inRange(t, after, before time.Time) bool {
+	return (after.IsZero() || !t.Before(after)) && (before.IsZero() || t.Before(before))
+}
+
+// matchesSearch reports whether the user name, first name, last name or email contains the term, see applySearch
+func matchesSearch(user *models.User, term string) bool {
+	term = strings.ToLower(term)
+	return slices.ContainsFunc([]string{user.UserName, user.FirstName, user.LastName, user.Email}, func(value string) bool {
+		return strings.Contains(strings.ToLower(value), term)
+	})
+}
+
+func (r *InMemoryUserRepository) SearchUsers(_ context.Context, query string) ([]models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	query = strings.TrimSpace(query)
+	return r.users(func(user *models.User) bool { return query == "" || matchesSearch(user, query) }), nil
+}
+
+func (r *InMemoryUserRepository) Count(_ context.Context, params models.ListParams) (int, error) {
+	if err := ValidateListParams(params); err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.users(func(user *models.User) bool { return matchesFilters(user, params) })), nil
+}
+
+func (r *InMemoryUserRepository) CountByStatus(_ context.Context, params models.ListParams) (map[models.UserStatus]int, error) {
+	if err := ValidateListParams(params); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := map[models.UserStatus]int{}
+	for _, user := range r.users(func(user *models.User) bool { return matchesFilters(user, params) }) {
+		counts[user.UserStatus]++
+	}
+	return counts, nil
+}
+
+// GetByID returns sql.ErrNoRows when the user doesn't exist, like the SQL repository
+func (r *InMemoryUserRepository) GetByID(_ context.Context, id int64) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.data.users[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	user = cloneUser(user)
+	return &user, nil
+}
+
+func (r *InMemoryUserRepository) GetWithManager(ctx context.Context, id int64) (*models.User, error) {
+	user, err := r.GetByID(ctx, id)
+	if err != nil || user.ManagerID == nil {
+		return user, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// a missing manager is left out, as by the LEFT JOIN
+	if manager, ok := r.data.users[*user.ManagerID]; ok {
+		manager = cloneUser(manager)
+		user.Manager = &manager
+	}
+	return user, nil
+}
+
+func (r *InMemoryUserRepository) ListReports(_ context.Context, managerID int64) ([]models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reports := r.users(func(user *models.User) bool {
+		return user.ManagerID != nil && *user.ManagerID == managerID
+	})
+	if reports == nil {
+		reports = []models.User{}
+	}
+	return reports, nil
+}
+
+func (r *InMemoryUserRepository) GetByUserName(_ context.Context, userName string) (*models.User, error) {
+	return r.getBy(func(user *models.User) bool { return user.UserName == userName })
+}
+
+func (r *InMemoryUserRepository) GetByEmail(_ context.Context, email string) (*models.User, error) {
+	return r.getBy(func(user *models.User) bool { return user.Email == email })
+}
+
+// getBy returns the oldest user matching the filter, or ErrUserNotFound
+func (r *InMemoryUserRepository) getBy(match func(user *models.User) bool) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	users := r.users(match)
+	if len(users) == 0 {
+		return nil, ErrUserNotFound
+	}
+	return &users[0], nil
+}
+
+// GetByIDForUpdate is GetByID, the transactions are serialized already
+func (r *InMemoryUserRepository) GetByIDForUpdate(ctx context.Context, id int64) (*models.User, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *InMemoryUserRepository) ListByIDsForUpdate(_ context.Context, ids []int64) ([]models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	users := r.users(func(user *models.User) bool { return slices.Contains(ids, user.UserID) })
+	if users == nil {
+		users = []models.User{}
+	}
+	return users, nil
+}
+
+func (r *InMemoryUserRepository) ListStale(_ context.Context, before time.Time) ([]models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.users(func(user *models.User) bool {
+		lastSeen := user.CreatedAt
+		if user.LastLoginAt != nil {
+			lastSeen = *user.LastLoginAt
+		}
+		return user.UserStatus == models.UserStatusActive && lastSeen.Before(before)
+	}), nil
+}
+
+// ListStaleForUpdate is ListStale, the transactions are serialized already
+func (r *InMemoryUserRepository) ListStaleForUpdate(ctx context.Context, before time.Time) ([]models.User, error) {
+	return r.ListStale(ctx, before)
+}
+
+func (r *InMemoryUserRepository) UpdateStatus(_ context.Context, ids []int64, status models.UserStatus, by string) (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	at := r.now()
+	for _, id := range ids {
+		user, ok := r.data.users[id]
+		if !ok {
+			continue
+		}
+		user.UserStatus = status
+		user.StatusUpdatedAt = &at
+		user.UpdatedAt = at
+		user.UpdatedBy = by
+		user.Version++
+		r.data.users[id] = user
+	}
+	return at, nil
+}
+
+func (r *InMemoryUserRepository) Create(_ context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.insert(user)
+}
+
+func (r *InMemoryUserRepository) CreateIfNotExists(_ context.Context, user *models.User) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var violation *UniqueViolationError
+	if err := r.insert(user); errors.As(err, &violation) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// insert stores the user with the defaults the database would set, and copies the stored row back into it
+func (r *InMemoryUserRepository) insert(user *models.User) error {
+	if err := r.checkUnique(user); err != nil {
+		return err
+	}
+
+	stored := cloneUser(*user)
+	if stored.UserID == 0 {
+		stored.UserID = r.data.lastUserID + 1
+	}
+	r.data.lastUserID = max(r.data.lastUserID, stored.UserID)
+
+	now := r.now()
+	if stored.CreatedAt.IsZero() {
+		stored.CreatedAt = now
+	}
+	if stored.UpdatedAt.IsZero() {
+		stored.UpdatedAt = now
+	}
+	if stored.CreatedBy == "" {
+		stored.CreatedBy = memoryDefaultActor
+	}
+	if stored.UpdatedBy == "" {
+		stored.UpdatedBy = memoryDefaultActor
+	}
+	if stored.Version == 0 {
+		stored.Version = 1
+	}
+
+	r.data.users[stored.UserID] = stored
+	*user = cloneUser(stored)
+	return nil
+}
+
+// checkUnique returns a *UniqueViolationError when a user has the ID, the email or the user name of the new user
+func (r *InMemoryUserRepository) checkUnique(user *models.User) error {
+	if _, ok := r.data.users[user.UserID]; ok && user.UserID != 0 {
+		return &UniqueViolationError{Constraint: "users.user_id", Err: errors.New("duplicate user_id")}
+	}
+	return r.checkUniqueFields(user)
+}
+
+// checkUniqueFields returns a *UniqueViolationError when another user has the email or the user name of the user
+func (r *InMemoryUserRepository) checkUniqueFields(user *models.User) error {
+	for _, other := range r.data.users {
+		if other.UserID == user.UserID {
+			continue
+		}
+		if other.Email == user.Email {
+			return &UniqueViolationError{Constraint: memoryEmailConstraint, Err: errors.New("duplicate email")}
+		}
+		if other.UserName == user.UserName {
+			return &UniqueViolationError{Constraint: memoryUserNameConstraint, Err: errors.New("duplicate user_name")}
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryUserRepository) Update(_ context.Context, user *models.User, stamped ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.data.users[user.UserID]
+	if !ok || stored.Version != user.Version {
+		return ErrVersionConflict
+	}
+	if err := r.checkUniqueFields(user); err != nil {
+		return err
+	}
+
+	// the creation time is never rewritten
+	createdAt := stored.CreatedAt
+	stored = cloneUser(*user)
+	stored.CreatedAt = createdAt
+	stored.UpdatedAt = r.now()
+	for _, column := range stamped {
+		at := stored.UpdatedAt
+		switch column {
+		case EmailUpdatedAtColumn:
+			stored.EmailUpdatedAt = &at
+		case StatusUpdatedAtColumn:
+			stored.StatusUpdatedAt = &at
+		}
+	}
+	stored.Version++
+	r.data.users[stored.UserID] = stored
+
+	// the stored user keeps pointers of its own
+	updated := cloneUser(stored)
+	user.UpdatedAt = updated.UpdatedAt
+	user.EmailUpdatedAt = updated.EmailUpdatedAt
+	user.StatusUpdatedAt = updated.StatusUpdatedAt
+	user.Version = updated.Version
+	return nil
+}
+
+func (r *InMemoryUserRepository) Delete(_ context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.data.users[id]; !ok {
+		return ErrUserNotFound
+	}
+	delete(r.data.users, id)
+	return nil
+}
+
+func (r *InMemoryUserRepository) ExistsByID(_ context.Context, id int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.data.users[id]
+	return ok, nil
+}
+
+func (r *InMemoryUserRepository) ExistsByUserName(_ context.Context, userName string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.users(func(user *models.User) bool { return user.UserName == userName })) > 0, nil
+}
+
+func (r *InMemoryUserRepository) ExistsByEmail(_ context.Context, email string, excludeID int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.users(func(user *models.User) bool {
+		return user.Email == email && (excludeID == 0 || user.UserID != excludeID)
+	})) > 0, nil
+}
+
+func (r *InMemoryUserRepository) Audit() AuditRepository {
+	return &inMemoryAuditRepository{repo: r}
+}
+
+func (r *InMemoryUserRepository) Departments() DepartmentRepository {
+	return &inMemoryDepartmentRepository{repo: r}
+}
+
+func (r *InMemoryUserRepository) Outbox() OutboxRepository {
+	return &inMemoryOutboxRepository{repo: r}
+}
+
+func (r *InMemoryUserRepository) RefreshTokens() RefreshTokenRepository {
+	return &inMemoryRefreshTokenRepository{repo: r}
+}
+
+// RunInTx runs fn on a snapshot of the users, which replaces them only when fn succeeds.
+// The other operations wait for the transaction to end.
+func (r *InMemoryUserRepository) RunInTx(ctx context.Context, fn func(ctx context.Context, repo UserRepository) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx := &InMemoryUserRepository{data: r.data.clone(), now: r.now}
+	if err := fn(ctx, tx); err != nil {
+		return err
+	}
+	r.data = tx.data
+	return nil
+}
+
+// cloneUser copies the user, including the timestamps and the manager ID it points to.
+// The manager itself is a relation, which isn't stored and has to be loaded.
+func cloneUser(user models.User) models.User {
+	for _, at := range []**time.Time{&user.EmailUpdatedAt, &user.StatusUpdatedAt, &user.LastLoginAt} {
+		if *at != nil {
+			t := **at
+			*at = &t
+		}
+	}
+	if user.ManagerID != nil {
+		id := *user.ManagerID
+		user.ManagerID = &id
+	}
+	user.Manager = nil
+	return user
+}
+
+// inMemoryAuditRepository records the audit log along the users of an InMemoryUserRepository
+type inMemoryAuditRepository struct {
+	repo *InMemoryUserRepository
+}
+
+func (a *inMemoryAuditRepository) Record(_ context.Context, entries ...*models.AuditEntry) error {
+	a.repo.mu.Lock()
+	defer a.repo.mu.Unlock()
+
+	for _, entry := range entries {
+		a.repo.data.lastAuditID++
+		entry.ID = a.repo.data.lastAuditID
+		if entry.CreatedAt.IsZero() {
+			entry.CreatedAt = a.repo.now()
+		}
+		a.repo.data.audit = append(a.repo.data.audit, *entry)
+	}
+	return nil
+}
+
+func (a *inMemoryAuditRepository) ListByUser(_ context.Context, userID int64) ([]models.AuditEntry, error) {
+	a.repo.mu.Lock()
+	defer a.repo.mu.Unlock()
+
+	var entries []models.AuditEntry
+	for _, entry := range a.repo.data.audit {
+		if entry.UserID == userID {
+			entries = append(entries, entry)
+		}
+	}
+	slices.SortFunc(entries, func(x, y models.AuditEntry) int {
+		return cmp.Or(y.CreatedAt.Compare(x.CreatedAt), cmp.Compare(y.ID, x.ID))
+	})
+	return entries, nil
+}
+
+// inMemoryDepartmentRepository keeps the departments along the users of an InMemoryUserRepository, by name
+type inMemoryDepartmentRepository struct {
+	repo *InMemoryUserRepository
+}
+
+func (d *inMemoryDepartmentRepository) List(_ context.Context) ([]models.Department, error) {
+	d.repo.mu.Lock()
+	defer d.repo.mu.Unlock()
+
+	departments := slices.AppendSeq(make([]models.Department, 0, len(d.repo.data.departments)), maps.Values(d.repo.data.departments))
+	slices.SortFunc(departments, func(x, y models.Department) int { return strings.Compare(x.Name, y.Name) })
+	return departments, nil
+}
+
+func (d *inMemoryDepartmentRepository) Create(_ context.Context, department *models.Department) error {
+	d.repo.mu.Lock()
+	defer d.repo.mu.Unlock()
+
+	return d.insert(department)
+}
+
+func (d *inMemoryDepartmentRepository) CreateIfNotExists(_ context.Context, department *models.Department) (bool, error) {
+	d.repo.mu.Lock()
+	defer d.repo.mu.Unlock()
+
+	var violation *UniqueViolationError
+	if err := d.insert(department); errors.As(err, &violation) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// insert stores the department with the defaults the database would set, and copies the stored row back into it
+func (d *inMemoryDepartmentRepository) insert(department *models.Department) error {
+	if _, ok := d.repo.data.departments[department.Name]; ok {
+		return &UniqueViolationError{Constraint: "departments.name", Err: errors.New("duplicate name")}
+	}
+
+	d.repo.data.lastDeptID++
+	department.ID = d.repo.data.lastDeptID
+	if department.CreatedAt.IsZero() {
+		department.CreatedAt = d.repo.now()
+	}
+	d.repo.data.departments[department.Name] = *department
+	return nil
+}
+
+func (d *inMemoryDepartmentRepository) ExistsByName(_ context.Context, name string) (bool, error) {
+	d.repo.mu.Lock()
+	defer d.repo.mu.Unlock()
+
+	_, ok := d.repo.data.departments[name]
+	return ok, nil
+}
+
+// inMemoryOutboxRepository keeps the outbox along the users of an InMemoryUserRepository, in ID order
+type inMemoryOutboxRepository struct {
+	repo *InMemoryUserRepository
+}
+
+func (o *inMemoryOutboxRepository) Add(_ context.Context, entries ...*models.OutboxEntry) error {
+	o.repo.mu.Lock()
+	defer o.repo.mu.Unlock()
+
+	for _, entry := range entries {
+		// the IDs are the positions in the outbox, plus one
+		entry.ID = int64(len(o.repo.data.outbox)) + 1
+		if entry.CreatedAt.IsZero() {
+			entry.CreatedAt = o.repo.now()
+		}
+		o.repo.data.outbox = append(o.repo.data.outbox, *entry)
+	}
+	return nil
+}
+
+func (o *inMemoryOutboxRepository) ListUnsent(_ context.Context, limit int) ([]models.OutboxEntry, error) {
+	o.repo.mu.Lock()
+	defer o.repo.mu.Unlock()
+
+	var entries []models.OutboxEntry
+	for _, entry := range o.repo.data.outbox {
+		if len(entries) == limit {
+			break
+		}
+		if entry.SentAt == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (o *inMemoryOutboxRepository) MarkSent(_ context.Context, id int64, at time.Time) error {
+	return o.update(id, func(entry *models.OutboxEntry) { entry.SentAt = &at })
+}
+
+func (o *inMemoryOutboxRepository) MarkFailed(_ context.Context, id int64) error {
+	return o.update(id, func(entry *models.OutboxEntry) { entry.Attempts++ })
+}
+
+// update applies fn to the entry, a missing entry is ignored like an UPDATE matching no row
+func (o *inMemoryOutboxRepository) update(id int64, fn func(entry *models.OutboxEntry)) error {
+	o.repo.mu.Lock()
+	defer o.repo.mu.Unlock()
+
+	if id > 0 && id <= int64(len(o.repo.data.outbox)) {
+		fn(&o.repo.data.outbox[id-1])
+	}
+	return nil
+}
+
+// inMemoryRefreshTokenRepository keeps the refresh tokens along the users of an InMemoryUserRepository, in ID order
+type inMemoryRefreshTokenRepository struct {
+	repo *InMemoryUserRepository
+}
+
+func (t *inMemoryRefreshTokenRepository) Create(_ context.Context, token *models.RefreshToken) error {
+	t.repo.mu.Lock()
+	defer t.repo.mu.Unlock()
+
+	// the IDs are the positions in the list, plus one
+	token.ID = int64(len(t.repo.data.tokens)) + 1
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = t.repo.now()
+	}
+	t.repo.data.tokens = append(t.repo.data.tokens, *token)
+	return nil
+}
+
+func (t *inMemoryRefreshTokenRepository) GetByHash(_ context.Context, hash string) (*models.RefreshToken, error) {
+	t.repo.mu.Lock()
+	defer t.repo.mu.Unlock()
+
+	for _, token := range t.repo.data.tokens {
+		if token.TokenHash == hash {
+			return &token, nil
+		}
+	}
+	return nil, ErrRefreshTokenNotFound
+}
+
+func (t *inMemoryRefreshTokenRepository) Revoke(_ context.Context, id int64, at time.Time) error {
+	t.repo.mu.Lock()
+	defer t.repo.mu.Unlock()
+
+	if id > 0 && id <= int64(len(t.repo.data.tokens)) && t.repo.data.tokens[id-1].RevokedAt == nil {
+		t.repo.data.tokens[id-1].RevokedAt = &at
+	}
+	return nil
+}
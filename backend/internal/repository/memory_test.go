@@ -0,0 +1,414 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-management/internal/models"
+)
+
+// newInMemoryTestRepository is newTestRepository over an InMemoryUserRepository
+func newInMemoryTestRepository(t *testing.T, userNames ...string) UserRepository {
+	t.Helper()
+
+	repo := NewInMemoryUserRepository()
+	for _, userName := range userNames {
+		require.NoError(t, repo.Create(context.Background(), &models.User{UserCommon: models.UserCommon{
+			UserName:   userName,
+			FirstName:  "Test",
+			LastName:   "User",
+			Email:      userName + "@example.com",
+			UserStatus: models.UserStatusActive,
+		}}))
+	}
+	return repo
+}
+
+// repositories builds the SQL and the in-memory repository, which must behave the same
+var repositories = map[string]func(t *testing.T, userNames ...string) UserRepository{
+	"sql":    newTestRepository,
+	"memory": newInMemoryTestRepository,
+}
+
+func TestInMemoryCreateReturnsPersistedRow(t *testing.T) {
+	t.Parallel()
+	testCreateReturnsPersistedRow(t, newInMemoryTestRepository(t, "alice"))
+}
+
+func TestInMemoryUpdateTimestamps(t *testing.T) {
+	t.Parallel()
+	testUpdateTimestamps(t, newInMemoryTestRepository(t, "alice"))
+}
+
+func TestRepositoriesConflicts(t *testing.T) {
+	t.Parallel()
+
+	for name, newRepo := range repositories {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newRepo(t, "alice", "bob")
+			ctx := context.Background()
+			duplicate := func() *models.User {
+				return &models.User{UserCommon: models.UserCommon{
+					UserName:   "alice2",
+					FirstName:  "Test",
+					LastName:   "User",
+					Email:      "alice@example.com",
+					UserStatus: models.UserStatusActive,
+				}}
+			}
+
+			var violation *UniqueViolationError
+			require.ErrorAs(t, repo.Create(ctx, duplicate()), &violation)
+			assert.Contains(t, violation.Constraint, "email")
+
+			inserted, err := repo.CreateIfNotExists(ctx, duplicate())
+			require.NoError(t, err)
+			assert.False(t, inserted)
+
+			bob, err := repo.GetByID(ctx, 2)
+			require.NoError(t, err)
+			bob.Email = "alice@example.com"
+			require.ErrorAs(t, repo.Update(ctx, bob), &violation)
+			assert.Contains(t, violation.Constraint, "email")
+			assert.Equal(t, int64(1), bob.Version, "the version is left untouched on conflict")
+
+			stale := *bob
+			bob.Email = "bobby@example.com"
+			require.NoError(t, repo.Update(ctx, bob))
+			require.ErrorIs(t, repo.Update(ctx, &stale), ErrVersionConflict)
+
+			exists, err := repo.ExistsByEmail(ctx, "bobby@example.com", 2)
+			require.NoError(t, err)
+			assert.False(t, exists, "the user itself is excluded")
+
+			// user names are unique too
+			user := duplicate()
+			user.UserName = "alice"
+			user.Email = "alice.bis@example.com"
+			require.ErrorAs(t, repo.Create(ctx, user), &violation)
+			assert.Contains(t, violation.Constraint, "user_name")
+		})
+	}
+}
+
+func TestRepositoriesNotFound(t *testing.T) {
+	t.Parallel()
+
+	for name, newRepo := range repositories {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newRepo(t, "alice")
+			ctx := context.Background()
+
+			_, err := repo.GetByID(ctx, 999)
+			require.ErrorIs(t, err, sql.ErrNoRows)
+			_, err = repo.GetByIDForUpdate(ctx, 999)
+			require.ErrorIs(t, err, sql.ErrNoRows)
+			_, err = repo.GetByUserName(ctx, "carol")
+			require.ErrorIs(t, err, ErrUserNotFound)
+			_, err = repo.GetByEmail(ctx, "carol@example.com")
+			require.ErrorIs(t, err, ErrUserNotFound)
+
+			alice, err := repo.GetByID(ctx, 1)
+			require.NoError(t, err)
+			require.NoError(t, repo.Delete(ctx, 1))
+			require.ErrorIs(t, repo.Delete(ctx, 1), ErrUserNotFound)
+			require.ErrorIs(t, repo.Update(ctx, alice), ErrVersionConflict, "a deleted user can't be updated")
+		})
+	}
+}
+
+func TestRepositoriesExistsByID(t *testing.T) {
+	t.Parallel()
+
+	for name, newRepo := range repositories {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newRepo(t, "alice")
+			ctx := context.Background()
+
+			exists, err := repo.ExistsByID(ctx, 1)
+			require.NoError(t, err)
+			assert.True(t, exists)
+
+			exists, err = repo.ExistsByID(ctx, 999)
+			require.NoError(t, err)
+			assert.False(t, exists)
+
+			require.NoError(t, repo.Delete(ctx, 1))
+			exists, err = repo.ExistsByID(ctx, 1)
+			require.NoError(t, err)
+			assert.False(t, exists, "deleted")
+		})
+	}
+}
+
+func TestRepositoriesRollback(t *testing.T) {
+	t.Parallel()
+
+	for name, newRepo := range repositories {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newRepo(t, "alice")
+			ctx := context.Background()
+			errRollback := errors.New("rollback")
+
+			err := repo.RunInTx(ctx, func(ctx context.Context, tx UserRepository) error {
+				require.NoError(t, tx.Delete(ctx, 1))
+				require.NoError(t, tx.Audit().Record(ctx, &models.AuditEntry{UserID: 1, Action: models.AuditActionDelete, Actor: "test"}))
+				return errRollback
+			})
+			require.ErrorIs(t, err, errRollback)
+
+			_, err = repo.GetByID(ctx, 1)
+			require.NoError(t, err, "the delete was rolled back")
+			entries, err := repo.Audit().ListByUser(ctx, 1)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+
+			err = repo.RunInTx(ctx, func(ctx context.Context, tx UserRepository) error {
+				return tx.Delete(ctx, 1)
+			})
+			require.NoError(t, err)
+			_, err = repo.GetByID(ctx, 1)
+			require.ErrorIs(t, err, sql.ErrNoRows, "the delete was committed")
+		})
+	}
+}
+
+func TestRepositoriesList(t *testing.T) {
+	t.Parallel()
+
+	userNames := []string{"carol", "alice", "bob", "dave", "alicia"}
+	testCases := []models.ListParams{
+		{},
+		{Sort: "user_name"},
+		{Sort: "user_name", Order: models.OrderDesc, Limit: 2, Offset: 1},
+		{Query: "ALI"},
+		{Query: "a", Sort: models.SortRelevance},
+		{Query: "alice", Sort: models.SortRelevance},
+		{AfterID: 2, Limit: 2},
+		{Status: models.UserStatusInactive},
+		{CreatedAfter: time.Now().Add(-time.Hour), CreatedBefore: time.Now().Add(time.Hour), Query: "ali"},
+		{UpdatedBefore: time.Now().Add(-time.Hour)},
+		{UpdatedAfter: time.Now().Add(time.Hour)},
+	}
+
+	lists := map[string][][]string{}
+	for name, newRepo := range repositories {
+		repo := newRepo(t, userNames...)
+		for _, params := range testCases {
+			users, total, err := repo.List(context.Background(), params)
+			require.NoError(t, err)
+
+			names := []string{}
+			for _, user := range users {
+				names = append(names, user.UserName)
+			}
+			lists[name] = append(lists[name], append(names, "total", fmt.Sprint(total)))
+		}
+	}
+
+	for i, params := range testCases {
+		assert.Equal(t, lists["sql"][i], lists["memory"][i], "%+v", params)
+	}
+}
+
+func TestRepositoriesDepartments(t *testing.T) {
+	t.Parallel()
+
+	for name, newRepo := range repositories {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			departments := newRepo(t).Departments()
+			ctx := context.Background()
+
+			engineering := &models.Department{Name: "Engineering"}
+			require.NoError(t, departments.Create(ctx, engineering))
+			assert.NotZero(t, engineering.ID)
+			assert.False(t, engineering.CreatedAt.IsZero())
+
+			var violation *UniqueViolationError
+			require.ErrorAs(t, departments.Create(ctx, &models.Department{Name: "Engineering"}), &violation)
+			assert.Contains(t, violation.Constraint, "name")
+
+			inserted, err := departments.CreateIfNotExists(ctx, &models.Department{Name: "Engineering"})
+			require.NoError(t, err)
+			assert.False(t, inserted)
+			inserted, err = departments.CreateIfNotExists(ctx, &models.Department{Name: "Accounting"})
+			require.NoError(t, err)
+			assert.True(t, inserted)
+
+			exists, err := departments.ExistsByName(ctx, "Accounting")
+			require.NoError(t, err)
+			assert.True(t, exists)
+			exists, err = departments.ExistsByName(ctx, "Research")
+			require.NoError(t, err)
+			assert.False(t, exists)
+
+			list, err := departments.List(ctx)
+			require.NoError(t, err)
+			require.Len(t, list, 2)
+			assert.Equal(t, "Accounting", list[0].Name)
+			assert.Equal(t, "Engineering", list[1].Name)
+		})
+	}
+}
+
+func TestRepositoriesOutbox(t *testing.T) {
+	t.Parallel()
+
+	for name, newRepo := range repositories {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newRepo(t)
+			ctx := context.Background()
+			event := func(id int64) *models.OutboxEntry {
+				return &models.OutboxEntry{Event: models.UserEvent{
+					Type:      models.UserEventUpdated,
+					User:      models.User{UserID: id, UserCommon: models.UserCommon{UserName: "john.doe"}},
+					Timestamp: time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC),
+				}}
+			}
+
+			first := event(1)
+			require.NoError(t, repo.Outbox().Add(ctx, first, event(2), event(3)))
+			assert.NotZero(t, first.ID)
+
+			// rolled back with the transaction
+			require.Error(t, repo.RunInTx(ctx, func(ctx context.Context, repo UserRepository) error {
+				require.NoError(t, repo.Outbox().Add(ctx, event(4)))
+				return errors.New("rollback")
+			}))
+
+			require.NoError(t, repo.Outbox().MarkSent(ctx, first.ID, time.Now().UTC()))
+			entries, err := repo.Outbox().ListUnsent(ctx, 10)
+			require.NoError(t, err)
+			require.Len(t, entries, 2)
+			assert.Equal(t, int64(2), entries[0].Event.User.UserID)
+			assert.Equal(t, int64(3), entries[1].Event.User.UserID)
+			assert.Equal(t, "john.doe", entries[0].Event.User.UserName)
+			assert.True(t, entries[0].Event.Timestamp.Equal(time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)))
+
+			require.NoError(t, repo.Outbox().MarkFailed(ctx, entries[0].ID))
+			entries, err = repo.Outbox().ListUnsent(ctx, 1)
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+			assert.Equal(t, 1, entries[0].Attempts)
+			assert.Nil(t, entries[0].SentAt)
+		})
+	}
+}
+
+func TestRepositoriesRefreshTokens(t *testing.T) {
+	t.Parallel()
+
+	for name, newRepo := range repositories {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newRepo(t)
+			ctx := context.Background()
+			expiresAt := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+			token := &models.RefreshToken{TokenHash: "abc", Subject: "jane", Roles: "admin reader", ExpiresAt: expiresAt}
+			require.NoError(t, repo.RefreshTokens().Create(ctx, token))
+			assert.NotZero(t, token.ID)
+
+			_, err := repo.RefreshTokens().GetByHash(ctx, "def")
+			require.ErrorIs(t, err, ErrRefreshTokenNotFound)
+
+			revokedAt := time.Date(2025, 4, 2, 12, 0, 0, 0, time.UTC)
+			require.NoError(t, repo.RefreshTokens().Revoke(ctx, token.ID, revokedAt))
+			// the first revocation is kept
+			require.NoError(t, repo.RefreshTokens().Revoke(ctx, token.ID, revokedAt.Add(time.Hour)))
+
+			stored, err := repo.RefreshTokens().GetByHash(ctx, "abc")
+			require.NoError(t, err)
+			assert.Equal(t, "jane", stored.Subject)
+			assert.Equal(t, "admin reader", stored.Roles)
+			assert.True(t, stored.ExpiresAt.Equal(expiresAt))
+			require.NotNil(t, stored.RevokedAt)
+			assert.True(t, stored.RevokedAt.Equal(revokedAt))
+		})
+	}
+}
+
+func TestRepositoriesManager(t *testing.T) {
+	t.Parallel()
+
+	for name, newRepo := range repositories {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newRepo(t, "alice", "bob", "carol")
+			ctx := context.Background()
+
+			aliceID := int64(1)
+			for _, id := range []int64{2, 3} {
+				user, err := repo.GetByID(ctx, id)
+				require.NoError(t, err)
+				user.ManagerID = &aliceID
+				require.NoError(t, repo.Update(ctx, user))
+			}
+
+			bob, err := repo.GetWithManager(ctx, 2)
+			require.NoError(t, err)
+			require.NotNil(t, bob.Manager)
+			assert.Equal(t, "alice", bob.Manager.UserName)
+
+			alice, err := repo.GetWithManager(ctx, 1)
+			require.NoError(t, err)
+			assert.Nil(t, alice.Manager)
+			_, err = repo.GetWithManager(ctx, 999)
+			require.ErrorIs(t, err, sql.ErrNoRows)
+
+			reports, err := repo.ListReports(ctx, 1)
+			require.NoError(t, err)
+			require.Len(t, reports, 2)
+			assert.Equal(t, []int64{2, 3}, []int64{reports[0].UserID, reports[1].UserID})
+
+			reports, err = repo.ListReports(ctx, 2)
+			require.NoError(t, err)
+			assert.NotNil(t, reports)
+			assert.Empty(t, reports)
+		})
+	}
+}
+
+func TestRepositoriesListByIDs(t *testing.T) {
+	t.Parallel()
+
+	for name, newRepo := range repositories {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newRepo(t, "alice", "bob", "carol")
+			ctx := context.Background()
+
+			users, err := repo.ListByIDsForUpdate(ctx, []int64{3, 999, 1})
+			require.NoError(t, err)
+			require.Len(t, users, 2)
+			assert.Equal(t, []int64{1, 3}, []int64{users[0].UserID, users[1].UserID})
+
+			users, err = repo.ListByIDsForUpdate(ctx, nil)
+			require.NoError(t, err)
+			assert.NotNil(t, users)
+			assert.Empty(t, users)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"user-management/internal/models"
+)
+
+// OutboxRepository stores the user events waiting to be posted to the webhooks
+type OutboxRepository interface {
+	Add(ctx context.Context, entries ...*models.OutboxEntry) error
+	// ListUnsent returns up to limit entries not sent yet, oldest first
+	ListUnsent(ctx context.Context, limit int) ([]models.OutboxEntry, error)
+	MarkSent(ctx context.Context, id int64, at time.Time) error
+	// MarkFailed counts a failed delivery attempt of the entry
+	MarkFailed(ctx context.Context, id int64) error
+}
+
+type outboxRepository struct {
+	db bun.IDB
+}
+
+// NewOutboxRepository creates a new outbox repository.
+// Use UserRepository.Outbox to add the entries in the transaction of the changes.
+func NewOutboxRepository(db *bun.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Add(ctx context.Context, entries ...*models.OutboxEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	_, err := r.db.NewInsert().Model(&entries).Exec(ctx)
+	return err
+}
+
+func (r *outboxRepository) ListUnsent(ctx context.Context, limit int) ([]models.OutboxEntry, error) {
+	var entries []models.OutboxEntry
+	err := r.db.NewSelect().
+		Model(&entries).
+		Where("sent_at IS NULL").
+		Order("id ASC").
+		Limit(limit).
+		Scan(ctx)
+	return entries, err
+}
+
+func (r *outboxRepository) MarkSent(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.OutboxEntry)(nil)).
+		Set("sent_at = ?", at).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id int64) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.OutboxEntry)(nil)).
+		Set("attempts = attempts + 1").
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
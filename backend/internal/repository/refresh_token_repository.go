@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"user-management/internal/models"
+)
+
+// ErrRefreshTokenNotFound is returned by GetByHash when no refresh token has the digest
+var ErrRefreshTokenNotFound = errors.New("refresh token not found")
+
+// RefreshTokenRepository stores the refresh tokens, by the digest of the token
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	// GetByHash returns the refresh token with the hex SHA-256 digest, revoked or expired alike
+	GetByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	// Revoke records the revocation time of the token, unless it's already revoked
+	Revoke(ctx context.Context, id int64, at time.Time) error
+}
+
+type refreshTokenRepository struct {
+	db bun.IDB
+}
+
+// NewRefreshTokenRepository creates a new refresh token repository
+func NewRefreshTokenRepository(db *bun.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	_, err := r.db.NewInsert().Model(token).Exec(ctx)
+	return err
+}
+
+func (r *refreshTokenRepository) GetByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	token := new(models.RefreshToken)
+	err := r.db.NewSelect().Model(token).Where("token_hash = ?", hash).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (r *refreshTokenRepository) Revoke(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.RefreshToken)(nil)).
+		Set("revoked_at = ?", at).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	return err
+}
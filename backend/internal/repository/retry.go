@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/uptrace/bun/driver/pgdriver"
+
+	"user-management/internal/models"
+)
+
+// RetryPolicy is how the reads failing on a transient connection error, e.g. during a failover, are retried
+type RetryPolicy struct {
+	// MaxRetries after the first attempt, 0 disables the retries
+	MaxRetries int
+	// Backoff is the wait before the first retry, doubled for every further retry up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// retryingUserRepository retries the reads on transient connection errors.
+// The writes aren't retried, a write whose connection dropped may have been applied already, and neither
+// is anything inside RunInTx, a transaction doesn't survive its connection, nor Stream, whose users may be
+// handed out already.
+type retryingUserRepository struct {
+	UserRepository
+	policy RetryPolicy
+}
+
+// NewRetryingUserRepository wraps the repository so its reads are retried on transient connection errors
+func NewRetryingUserRepository(next UserRepository, policy RetryPolicy) UserRepository {
+	if policy.MaxRetries <= 0 {
+		return next
+	}
+	return &retryingUserRepository{UserRepository: next, policy: policy}
+}
+
+// retry calls fn until it succeeds, fails with a non-transient error, runs out of retries or the context is done.
+// The last error of fn is returned.
+func retry[T any](ctx context.Context, policy RetryPolicy, op string, fn func() (T, error)) (T, error) {
+	wait := policy.Backoff
+	for attempt := 0; ; attempt++ {
+		result, err := fn()
+		if err == nil || attempt == policy.MaxRetries || ctx.Err() != nil || !isTransient(err) {
+			return result, err
+		}
+
+		slog.With("error", err).
+			With("op", op).
+			With("attempt", attempt+1).
+			Warn("transient database error, retrying")
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		}
+		wait = min(wait*2, policy.MaxBackoff)
+	}
+}
+
+// isTransient reports whether err is a lost or refused connection, which a retry on another connection may fix
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// SQLSTATE class 08 is connection exception, 57P01-57P03 the server shutting down or starting up
+	var pgErr pgdriver.Error
+	if errors.As(err, &pgErr) {
+		code := pgErr.Field('C')
+		return strings.HasPrefix(code, "08") || code == "57P01" || code == "57P02" || code == "57P03"
+	}
+
+	return false
+}
+
+func (r *retryingUserRepository) List(ctx context.Context, params models.ListParams) ([]models.User, int, error) {
+	type page struct {
+		users []models.User
+		total int
+	}
+	result, err := retry(ctx, r.policy, "List", func() (page, error) {
+		users, total, err := r.UserRepository.List(ctx, params)
+		return page{users: users, total: total}, err
+	})
+	return result.users, result.total, err
+}
+
+func (r *retryingUserRepository) SearchUsers(ctx context.Context, query string) ([]models.User, error) {
+	return retry(ctx, r.policy, "SearchUsers", func() ([]models.User, error) {
+		return r.UserRepository.SearchUsers(ctx, query)
+	})
+}
+
+func (r *retryingUserRepository) Count(ctx context.Context, params models.ListParams) (int, error) {
+	return retry(ctx, r.policy, "Count", func() (int, error) {
+		return r.UserRepository.Count(ctx, params)
+	})
+}
+
+func (r *retryingUserRepository) CountByStatus(ctx context.Context, params models.ListParams) (map[models.UserStatus]int, error) {
+	return retry(ctx, r.policy, "CountByStatus", func() (map[models.UserStatus]int, error) {
+		return r.UserRepository.CountByStatus(ctx, params)
+	})
+}
+
+func (r *retryingUserRepository) GetByID(ctx context.Context, id int64) (*models.User, error) {
+	return retry(ctx, r.policy, "GetByID", func() (*models.User, error) {
+		return r.UserRepository.GetByID(ctx, id)
+	})
+}
+
+func (r *retryingUserRepository) GetWithManager(ctx context.Context, id int64) (*models.User, error) {
+	return retry(ctx, r.policy, "GetWithManager", func() (*models.User, error) {
+		return r.UserRepository.GetWithManager(ctx, id)
+	})
+}
+
+func (r *retryingUserRepository) ListReports(ctx context.Context, managerID int64) ([]models.User, error) {
+	return retry(ctx, r.policy, "ListReports", func() ([]models.User, error) {
+		return r.UserRepository.ListReports(ctx, managerID)
+	})
+}
+
+func (r *retryingUserRepository) GetByUserName(ctx context.Context, userName string) (*models.User, error) {
+	return retry(ctx, r.policy, "GetByUserName", func() (*models.User, error) {
+		return r.UserRepository.GetByUserName(ctx, userName)
+	})
+}
+
+func (r *retryingUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return retry(ctx, r.policy, "GetByEmail", func() (*models.User, error) {
+		return r.UserRepository.GetByEmail(ctx, email)
+	})
+}
+
+func (r *retryingUserRepository) ExistsByID(ctx context.Context, id int64) (bool, error) {
+	return retry(ctx, r.policy, "ExistsByID", func() (bool, error) {
+		return r.UserRepository.ExistsByID(ctx, id)
+	})
+}
+
+func (r *retryingUserRepository) ExistsByUserName(ctx context.Context, userName string) (bool, error) {
+	return retry(ctx, r.policy, "ExistsByUserName", func() (bool, error) {
+		return r.UserRepository.ExistsByUserName(ctx, userName)
+	})
+}
+
+func (r *retryingUserRepository) ExistsByEmail(ctx context.Context, email string, excludeID int64) (bool, error) {
+	return retry(ctx, r.policy, "ExistsByEmail", func() (bool, error) {
+		return r.UserRepository.ExistsByEmail(ctx, email, excludeID)
+	})
+}
+
+func (r *retryingUserRepository) Audit() AuditRepository {
+	return &retryingAuditRepository{AuditRepository: r.UserRepository.Audit(), policy: r.policy}
+}
+
+func (r *retryingUserRepository) Departments() DepartmentRepository {
+	return &retryingDepartmentRepository{DepartmentRepository: r.UserRepository.Departments(), policy: r.policy}
+}
+
+type retryingAuditRepository struct {
+	AuditRepository
+	policy RetryPolicy
+}
+
+func (r *retryingAuditRepository) ListByUser(ctx context.Context, userID int64) ([]models.AuditEntry, error) {
+	return retry(ctx, r.policy, "Audit.ListByUser", func() ([]models.AuditEntry, error) {
+		return r.AuditRepository.ListByUser(ctx, userID)
+	})
+}
+
+type retryingDepartmentRepository struct {
+	DepartmentRepository
+	policy RetryPolicy
+}
+
+func (r *retryingDepartmentRepository) List(ctx context.Context) ([]models.Department, error) {
+	return retry(ctx, r.policy, "Departments.List", func() ([]models.Department, error) {
+		return r.DepartmentRepository.List(ctx)
+	})
+}
+
+func (r *retryingDepartmentRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
+	return retry(ctx, r.policy, "Departments.ExistsByName", func() (bool, error) {
+		return r.DepartmentRepository.ExistsByName(ctx, name)
+	})
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-management/internal/models"
+)
+
+func TestRetryingUserRepository(t *testing.T) {
+	t.Parallel()
+
+	policy := RetryPolicy{MaxRetries: 2, Backoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+	// failing returns a mock failing the reads and writes with errs in turn, then succeeding
+	failing := func(errs ...error) (*UserRepositoryMock, *int) {
+		calls := 0
+		next := func() error {
+			calls++
+			if calls <= len(errs) {
+				return errs[calls-1]
+			}
+			return nil
+		}
+		return &UserRepositoryMock{
+			GetByIDFunc: func(ctx context.Context, id int64) (*models.User, error) {
+				if err := next(); err != nil {
+					return nil, err
+				}
+				return &models.User{UserID: id}, nil
+			},
+			DeleteFunc: func(ctx context.Context, id int64) error {
+				return next()
+			},
+		}, &calls
+	}
+
+	t.Run("RetriesTransientErrors", func(t *testing.T) {
+		t.Parallel()
+		mock, calls := failing(driver.ErrBadConn, fmt.Errorf("dial: %w", syscall.ECONNREFUSED))
+		user, err := NewRetryingUserRepository(mock, policy).GetByID(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), user.UserID)
+		assert.Equal(t, 3, *calls)
+	})
+
+	t.Run("GivesUpAfterMaxRetries", func(t *testing.T) {
+		t.Parallel()
+		mock, calls := failing(driver.ErrBadConn, driver.ErrBadConn, driver.ErrBadConn)
+		_, err := NewRetryingUserRepository(mock, policy).GetByID(context.Background(), 1)
+		require.ErrorIs(t, err, driver.ErrBadConn)
+		assert.Equal(t, 3, *calls)
+	})
+
+	t.Run("DoesNotRetryOtherErrors", func(t *testing.T) {
+		t.Parallel()
+		mock, calls := failing(ErrUserNotFound)
+		_, err := NewRetryingUserRepository(mock, policy).GetByID(context.Background(), 1)
+		require.ErrorIs(t, err, ErrUserNotFound)
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("DoesNotRetryWrites", func(t *testing.T) {
+		t.Parallel()
+		mock, calls := failing(driver.ErrBadConn)
+		err := NewRetryingUserRepository(mock, policy).Delete(context.Background(), 1)
+		require.ErrorIs(t, err, driver.ErrBadConn)
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("StopsOnContextDone", func(t *testing.T) {
+		t.Parallel()
+		mock, calls := failing(driver.ErrBadConn, driver.ErrBadConn)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		slow := RetryPolicy{MaxRetries: 2, Backoff: time.Hour, MaxBackoff: time.Hour}
+		_, err := NewRetryingUserRepository(mock, slow).GetByID(ctx, 1)
+		require.ErrorIs(t, err, driver.ErrBadConn)
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		mock, _ := failing()
+		assert.Same(t, mock, NewRetryingUserRepository(mock, RetryPolicy{}))
+	})
+}
+
+func TestIsTransient(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isTransient(driver.ErrBadConn))
+	assert.True(t, isTransient(fmt.Errorf("read: %w", syscall.ECONNRESET)))
+	assert.False(t, isTransient(ErrUserNotFound))
+	assert.False(t, isTransient(errors.New("syntax error")))
+	assert.False(t, isTransient(context.DeadlineExceeded))
+}
@@ -0,0 +1,342 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"user-management/internal/models"
+)
+
+// tracedUserRepository wraps every call in a repo.<Method> span.
+// User names and emails are left out of the attributes, they're personal data.
+type tracedUserRepository struct {
+	next   UserRepository
+	tracer trace.Tracer
+}
+
+// NewTracedUserRepository wraps the repository so every call is traced as a child span of the context's span
+func NewTracedUserRepository(next UserRepository, tracer trace.Tracer) UserRepository {
+	return &tracedUserRepository{next: next, tracer: tracer}
+}
+
+func (r *tracedUserRepository) start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return r.tracer.Start(ctx, "repo."+name, trace.WithAttributes(attrs...))
+}
+
+// end records the error, if any, and ends the span
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (r *tracedUserRepository) List(ctx context.Context, params models.ListParams) ([]models.User, int, error) {
+	ctx, span := r.start(ctx, "List", attribute.Int("list.limit", params.Limit), attribute.Int("list.offset", params.Offset))
+	users, total, err := r.next.List(ctx, params)
+	span.SetAttributes(attribute.Int("list.total", total))
+	end(span, err)
+	return users, total, err
+}
+
+func (r *tracedUserRepository) Stream(ctx context.Context, params models.ListParams, fn func(user *models.User) error) error {
+	ctx, span := r.start(ctx, "Stream", attribute.Int("list.limit", params.Limit), attribute.Int("list.offset", params.Offset))
+	streamed := 0
+	err := r.next.Stream(ctx, params, func(user *models.User) error {
+		streamed++
+		return fn(user)
+	})
+	span.SetAttributes(attribute.Int("list.streamed", streamed))
+	end(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) SearchUsers(ctx context.Context, query string) ([]models.User, error) {
+	ctx, span := r.start(ctx, "SearchUsers")
+	users, err := r.next.SearchUsers(ctx, query)
+	end(span, err)
+	return users, err
+}
+
+func (r *tracedUserRepository) Count(ctx context.Context, params models.ListParams) (int, error) {
+	ctx, span := r.start(ctx, "Count")
+	total, err := r.next.Count(ctx, params)
+	end(span, err)
+	return total, err
+}
+
+func (r *tracedUserRepository) CountByStatus(ctx context.Context, params models.ListParams) (map[models.UserStatus]int, error) {
+	ctx, span := r.start(ctx, "CountByStatus")
+	counts, err := r.next.CountByStatus(ctx, params)
+	end(span, err)
+	return counts, err
+}
+
+func (r *tracedUserRepository) GetByID(ctx context.Context, id int64) (*models.User, error) {
+	ctx, span := r.start(ctx, "GetByID", attribute.Int64("user.id", id))
+	user, err := r.next.GetByID(ctx, id)
+	end(span, err)
+	return user, err
+}
+
+func (r *tracedUserRepository) GetByUserName(ctx context.Context, userName string) (*models.User, error) {
+	ctx, span := r.start(ctx, "GetByUserName")
+	user, err := r.next.GetByUserName(ctx, userName)
+	end(span, err)
+	return user, err
+}
+
+func (r *tracedUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	ctx, span := r.start(ctx, "GetByEmail")
+	user, err := r.next.GetByEmail(ctx, email)
+	end(span, err)
+	return user, err
+}
+
+func (r *tracedUserRepository) GetByIDForUpdate(ctx context.Context, id int64) (*models.User, error) {
+	ctx, span := r.start(ctx, "GetByIDForUpdate", attribute.Int64("user.id", id))
+	user, err := r.next.GetByIDForUpdate(ctx, id)
+	end(span, err)
+	return user, err
+}
+
+func (r *tracedUserRepository) ListByIDsForUpdate(ctx context.Context, ids []int64) ([]models.User, error) {
+	ctx, span := r.start(ctx, "ListByIDsForUpdate", attribute.Int("batch.size", len(ids)))
+	users, err := r.next.ListByIDsForUpdate(ctx, ids)
+	end(span, err)
+	return users, err
+}
+
+func (r *tracedUserRepository) ListStale(ctx context.Context, before time.Time) ([]models.User, error) {
+	ctx, span := r.start(ctx, "ListStale")
+	users, err := r.next.ListStale(ctx, before)
+	span.SetAttributes(attribute.Int("users.count", len(users)))
+	end(span, err)
+	return users, err
+}
+
+func (r *tracedUserRepository) ListStaleForUpdate(ctx context.Context, before time.Time) ([]models.User, error) {
+	ctx, span := r.start(ctx, "ListStaleForUpdate")
+	users, err := r.next.ListStaleForUpdate(ctx, before)
+	span.SetAttributes(attribute.Int("users.count", len(users)))
+	end(span, err)
+	return users, err
+}
+
+func (r *tracedUserRepository) UpdateStatus(ctx context.Context, ids []int64, status models.UserStatus, by string) (time.Time, error) {
+	ctx, span := r.start(ctx, "UpdateStatus", attribute.Int("users.count", len(ids)), attribute.String("user.status", string(status)))
+	at, err := r.next.UpdateStatus(ctx, ids, status, by)
+	end(span, err)
+	return at, err
+}
+
+func (r *tracedUserRepository) Create(ctx context.Context, user *models.User) error {
+	ctx, span := r.start(ctx, "Create")
+	err := r.next.Create(ctx, user)
+	span.SetAttributes(attribute.Int64("user.id", user.UserID))
+	end(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) Update(ctx context.Context, user *models.User, stamped ...string) error {
+	ctx, span := r.start(ctx, "Update", attribute.Int64("user.id", user.UserID))
+	err := r.next.Update(ctx, user, stamped...)
+	end(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) Delete(ctx context.Context, id int64) error {
+	ctx, span := r.start(ctx, "Delete", attribute.Int64("user.id", id))
+	err := r.next.Delete(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) GetWithManager(ctx context.Context, id int64) (*models.User, error) {
+	ctx, span := r.start(ctx, "GetWithManager", attribute.Int64("user.id", id))
+	user, err := r.next.GetWithManager(ctx, id)
+	end(span, err)
+	return user, err
+}
+
+func (r *tracedUserRepository) ListReports(ctx context.Context, managerID int64) ([]models.User, error) {
+	ctx, span := r.start(ctx, "ListReports", attribute.Int64("user.id", managerID))
+	users, err := r.next.ListReports(ctx, managerID)
+	end(span, err)
+	return users, err
+}
+
+func (r *tracedUserRepository) ExistsByID(ctx context.Context, id int64) (bool, error) {
+	ctx, span := r.start(ctx, "ExistsByID", attribute.Int64("user.id", id))
+	exists, err := r.next.ExistsByID(ctx, id)
+	end(span, err)
+	return exists, err
+}
+
+func (r *tracedUserRepository) ExistsByUserName(ctx context.Context, userName string) (bool, error) {
+	ctx, span := r.start(ctx, "ExistsByUserName")
+	exists, err := r.next.ExistsByUserName(ctx, userName)
+	end(span, err)
+	return exists, err
+}
+
+func (r *tracedUserRepository) ExistsByEmail(ctx context.Context, email string, excludeID int64) (bool, error) {
+	ctx, span := r.start(ctx, "ExistsByEmail")
+	exists, err := r.next.ExistsByEmail(ctx, email, excludeID)
+	end(span, err)
+	return exists, err
+}
+
+func (r *tracedUserRepository) CreateIfNotExists(ctx context.Context, user *models.User) (bool, error) {
+	ctx, span := r.start(ctx, "CreateIfNotExists")
+	inserted, err := r.next.CreateIfNotExists(ctx, user)
+	span.SetAttributes(attribute.Bool("user.inserted", inserted))
+	end(span, err)
+	return inserted, err
+}
+
+func (r *tracedUserRepository) Audit() AuditRepository {
+	return &tracedAuditRepository{next: r.next.Audit(), tracer: r.tracer}
+}
+
+func (r *tracedUserRepository) Departments() DepartmentRepository {
+	return &tracedDepartmentRepository{next: r.next.Departments(), tracer: r.tracer}
+}
+
+func (r *tracedUserRepository) Outbox() OutboxRepository {
+	return &tracedOutboxRepository{next: r.next.Outbox(), tracer: r.tracer}
+}
+
+func (r *tracedUserRepository) RefreshTokens() RefreshTokenRepository {
+	return &tracedRefreshTokenRepository{next: r.next.RefreshTokens(), tracer: r.tracer}
+}
+
+// RunInTx traces the whole transaction, with the calls made through the transaction's repository as children
+func (r *tracedUserRepository) RunInTx(ctx context.Context, fn func(ctx context.Context, repo UserRepository) error) error {
+	ctx, span := r.start(ctx, "RunInTx")
+	err := r.next.RunInTx(ctx, func(ctx context.Context, repo UserRepository) error {
+		return fn(ctx, &tracedUserRepository{next: repo, tracer: r.tracer})
+	})
+	end(span, err)
+	return err
+}
+
+// tracedAuditRepository wraps every call in a repo.Audit.<Method> span
+type tracedAuditRepository struct {
+	next   AuditRepository
+	tracer trace.Tracer
+}
+
+func (r *tracedAuditRepository) Record(ctx context.Context, entries ...*models.AuditEntry) error {
+	ctx, span := r.tracer.Start(ctx, "repo.Audit.Record", trace.WithAttributes(attribute.Int("audit.count", len(entries))))
+	err := r.next.Record(ctx, entries...)
+	end(span, err)
+	return err
+}
+
+func (r *tracedAuditRepository) ListByUser(ctx context.Context, userID int64) ([]models.AuditEntry, error) {
+	ctx, span := r.tracer.Start(ctx, "repo.Audit.ListByUser", trace.WithAttributes(attribute.Int64("user.id", userID)))
+	entries, err := r.next.ListByUser(ctx, userID)
+	end(span, err)
+	return entries, err
+}
+
+// tracedDepartmentRepository wraps every call in a repo.Departments.<Method> span
+type tracedDepartmentRepository struct {
+	next   DepartmentRepository
+	tracer trace.Tracer
+}
+
+func (r *tracedDepartmentRepository) List(ctx context.Context) ([]models.Department, error) {
+	ctx, span := r.tracer.Start(ctx, "repo.Departments.List")
+	departments, err := r.next.List(ctx)
+	end(span, err)
+	return departments, err
+}
+
+func (r *tracedDepartmentRepository) Create(ctx context.Context, department *models.Department) error {
+	ctx, span := r.tracer.Start(ctx, "repo.Departments.Create")
+	err := r.next.Create(ctx, department)
+	end(span, err)
+	return err
+}
+
+func (r *tracedDepartmentRepository) CreateIfNotExists(ctx context.Context, department *models.Department) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "repo.Departments.CreateIfNotExists")
+	inserted, err := r.next.CreateIfNotExists(ctx, department)
+	end(span, err)
+	return inserted, err
+}
+
+func (r *tracedDepartmentRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "repo.Departments.ExistsByName")
+	exists, err := r.next.ExistsByName(ctx, name)
+	end(span, err)
+	return exists, err
+}
+
+// tracedOutboxRepository wraps every call in a repo.Outbox.<Method> span
+type tracedOutboxRepository struct {
+	next   OutboxRepository
+	tracer trace.Tracer
+}
+
+func (r *tracedOutboxRepository) Add(ctx context.Context, entries ...*models.OutboxEntry) error {
+	ctx, span := r.tracer.Start(ctx, "repo.Outbox.Add", trace.WithAttributes(attribute.Int("outbox.count", len(entries))))
+	err := r.next.Add(ctx, entries...)
+	end(span, err)
+	return err
+}
+
+func (r *tracedOutboxRepository) ListUnsent(ctx context.Context, limit int) ([]models.OutboxEntry, error) {
+	ctx, span := r.tracer.Start(ctx, "repo.Outbox.ListUnsent", trace.WithAttributes(attribute.Int("outbox.limit", limit)))
+	entries, err := r.next.ListUnsent(ctx, limit)
+	end(span, err)
+	return entries, err
+}
+
+func (r *tracedOutboxRepository) MarkSent(ctx context.Context, id int64, at time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "repo.Outbox.MarkSent", trace.WithAttributes(attribute.Int64("outbox.id", id)))
+	err := r.next.MarkSent(ctx, id, at)
+	end(span, err)
+	return err
+}
+
+func (r *tracedOutboxRepository) MarkFailed(ctx context.Context, id int64) error {
+	ctx, span := r.tracer.Start(ctx, "repo.Outbox.MarkFailed", trace.WithAttributes(attribute.Int64("outbox.id", id)))
+	err := r.next.MarkFailed(ctx, id)
+	end(span, err)
+	return err
+}
+
+// tracedRefreshTokenRepository wraps every call in a repo.RefreshTokens.<Method> span, without the token digests
+type tracedRefreshTokenRepository struct {
+	next   RefreshTokenRepository
+	tracer trace.Tracer
+}
+
+func (r *tracedRefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	ctx, span := r.tracer.Start(ctx, "repo.RefreshTokens.Create")
+	err := r.next.Create(ctx, token)
+	end(span, err)
+	return err
+}
+
+func (r *tracedRefreshTokenRepository) GetByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	ctx, span := r.tracer.Start(ctx, "repo.RefreshTokens.GetByHash")
+	token, err := r.next.GetByHash(ctx, hash)
+	end(span, err)
+	return token, err
+}
+
+func (r *tracedRefreshTokenRepository) Revoke(ctx context.Context, id int64, at time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "repo.RefreshTokens.Revoke", trace.WithAttributes(attribute.Int64("refresh_token.id", id)))
+	err := r.next.Revoke(ctx, id, at)
+	end(span, err)
+	return err
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracedUserRepository(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+	repo := NewTracedUserRepository(newTestRepository(t, "alice"), tracer)
+
+	ctx, parent := tracer.Start(context.Background(), "request")
+	err := repo.RunInTx(ctx, func(ctx context.Context, tx UserRepository) error {
+		_, err := tx.GetByIDForUpdate(ctx, 1)
+		return err
+	})
+	require.NoError(t, err)
+	require.ErrorIs(t, repo.Delete(ctx, 42), ErrUserNotFound)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+
+	lock, tx, del := spans[0], spans[1], spans[2]
+	assert.Equal(t, "repo.GetByIDForUpdate", lock.Name())
+	assert.Equal(t, "repo.RunInTx", tx.Name())
+	assert.Equal(t, "repo.Delete", del.Name())
+
+	// the calls made in the transaction are children of its span, which is a child of the request span
+	assert.Equal(t, tx.SpanContext().SpanID(), lock.Parent().SpanID())
+	assert.Equal(t, parent.SpanContext().SpanID(), tx.Parent().SpanID())
+	assert.Contains(t, lock.Attributes(), attribute.Int64("user.id", 1))
+
+	assert.Equal(t, codes.Error, del.Status().Code)
+	assert.Equal(t, codes.Unset, lock.Status().Code)
+}
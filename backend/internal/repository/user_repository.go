@@ -3,25 +3,96 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/driver/pgdriver"
 
 	"user-management/internal/models"
 )
 
+//go:generate moq -rm -out user_repository_mock.go . UserRepository
+
+// The columns of the per-field timestamps, stamped by Update when asked
+const (
+	EmailUpdatedAtColumn  = "email_updated_at"
+	StatusUpdatedAtColumn = "status_updated_at"
+)
+
 // UserRepository provides user-related data access operations.
 type UserRepository interface {
-	List(ctx context.Context) ([]models.User, error)
+	List(ctx context.Context, params models.ListParams) ([]models.User, int, error)
+	// Stream calls fn with the users List would return, one at a time, without loading them all.
+	// Its paging is the same, a zero limit streams every matching user. An error of fn stops the stream and is returned.
+	Stream(ctx context.Context, params models.ListParams, fn func(user *models.User) error) error
+	SearchUsers(ctx context.Context, query string) ([]models.User, error)
+	// Count returns the number of users matching the search and filter criteria of params, paging and sort are ignored
+	Count(ctx context.Context, params models.ListParams) (int, error)
+	// CountByStatus is like Count, broken down by status. Statuses without users are left out
+	CountByStatus(ctx context.Context, params models.ListParams) (map[models.UserStatus]int, error)
 	GetByID(ctx context.Context, id int64) (*models.User, error)
+	// GetWithManager is GetByID also loading the user's manager, when they have one
+	GetWithManager(ctx context.Context, id int64) (*models.User, error)
+	// ListReports returns the users the manager directly manages, ordered by user_id
+	ListReports(ctx context.Context, managerID int64) ([]models.User, error)
+	// GetByUserName returns ErrUserNotFound when no user has the name
+	GetByUserName(ctx context.Context, userName string) (*models.User, error)
+	// GetByEmail returns ErrUserNotFound when no user has the email
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	// GetByIDForUpdate loads the user and locks its row until the end of the surrounding transaction
+	// (see RunInTx), so concurrent read-modify-write cycles on the same user serialize.
+	GetByIDForUpdate(ctx context.Context, id int64) (*models.User, error)
+	// ListByIDsForUpdate returns the existing users among ids, ordered by user_id, locked like GetByIDForUpdate
+	ListByIDsForUpdate(ctx context.Context, ids []int64) ([]models.User, error)
+	// ListStale returns the active users who haven't logged in since before, judging the users who never
+	// logged in by their creation time
+	ListStale(ctx context.Context, before time.Time) ([]models.User, error)
+	// ListStaleForUpdate is ListStale locking the rows like GetByIDForUpdate
+	ListStaleForUpdate(ctx context.Context, before time.Time) ([]models.User, error)
+	// UpdateStatus sets the status of the given users, recording by as the author of the change,
+	// and returns the time of the change, stamped by the database clock
+	UpdateStatus(ctx context.Context, ids []int64, status models.UserStatus, by string) (time.Time, error)
+	// Create inserts the user and reads the persisted row back into it, with the ID and the timestamps
+	// set by the database. A unique constraint violation is returned as *UniqueViolationError.
 	Create(ctx context.Context, user *models.User) error
-	Update(ctx context.Context, user *models.User) error
+	// Update saves the user unless its row changed since it was read, then it returns ErrVersionConflict.
+	// On success the user's version is incremented and its update time read back from the database, which sets it,
+	// as it sets the stamped columns, e.g. the StatusUpdatedAtColumn of a changed status.
+	// The creation time is left untouched. A unique constraint violation is returned as *UniqueViolationError.
+	Update(ctx context.Context, user *models.User, stamped ...string) error
 	Delete(ctx context.Context, id int64) error
+	// ExistsByID reports whether the user exists, without loading it
+	ExistsByID(ctx context.Context, id int64) (bool, error)
 	ExistsByUserName(ctx context.Context, userName string) (bool, error)
 	ExistsByEmail(ctx context.Context, email string, excludeID int64) (bool, error)
+
+	// CreateIfNotExists inserts the user unless it conflicts with a unique constraint,
+	// reporting whether the row was inserted. An inserted row is read back into the user, as by Create.
+	CreateIfNotExists(ctx context.Context, user *models.User) (bool, error)
+
+	// Audit returns the audit log repository bound to the same database or transaction
+	Audit() AuditRepository
+	// Departments returns the department repository bound to the same database or transaction
+	Departments() DepartmentRepository
+	// Outbox returns the outbox repository bound to the same database or transaction
+	Outbox() OutboxRepository
+	// RefreshTokens returns the refresh token repository bound to the same database or transaction
+	RefreshTokens() RefreshTokenRepository
+
+	// RunInTx runs fn inside a database transaction and passes it a repository bound to that transaction.
+	// The transaction is rolled back if fn returns an error.
+	RunInTx(ctx context.Context, fn func(ctx context.Context, repo UserRepository) error) error
 }
 
 type userRepository struct {
-	db *bun.DB
+	db bun.IDB
 }
 
 // NewUserRepository creates a new user repository.
@@ -29,12 +100,281 @@ func NewUserRepository(db *bun.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) List(ctx context.Context) ([]models.User, error) {
+// ErrInvalidSort matches the *InvalidParamsError returned by List for a sort field outside the whitelist
+var ErrInvalidSort = errors.New("invalid sort")
+
+// ErrUserNotFound is returned by Delete, GetByUserName and GetByEmail when there's no such user
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrVersionConflict is returned by Update when the user was changed or deleted since it was read
+var ErrVersionConflict = errors.New("user was modified concurrently")
+
+// UniqueViolationError is returned by Create and Update when the row violates a unique constraint,
+// e.g. because a concurrent transaction inserted the same email after it was checked
+type UniqueViolationError struct {
+	// Constraint names the violated constraint, e.g. users_email_key
+	Constraint string
+	Err        error
+}
+
+func (e *UniqueViolationError) Error() string {
+	return "unique constraint " + e.Constraint + " violated: " + e.Err.Error()
+}
+
+func (e *UniqueViolationError) Unwrap() error {
+	return e.Err
+}
+
+// sqliteUniquePrefix starts the message of SQLite's unique constraint errors, followed by the table.column
+const sqliteUniquePrefix = "UNIQUE constraint failed: "
+
+// mysqlDuplicateEntry is the number of MySQL's duplicate key error (ER_DUP_ENTRY)
+const mysqlDuplicateEntry = 1062
+
+// uniqueViolation wraps the unique constraint violations (SQLSTATE 23505 on PostgreSQL,
+// error 1062 on MySQL) into *UniqueViolationError
+func uniqueViolation(err error) error {
+	var pgErr pgdriver.Error
+	if errors.As(err, &pgErr) && pgErr.Field('C') == "23505" {
+		return &UniqueViolationError{Constraint: pgErr.Field('n'), Err: err}
+	}
+
+	// MySQL names the key in the message: Duplicate entry '...' for key 'users.email'
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		_, key, _ := strings.Cut(mysqlErr.Message, "for key '")
+		return &UniqueViolationError{Constraint: strings.TrimSuffix(key, "'"), Err: err}
+	}
+
+	// the tests run against SQLite, which doesn't name the constraint
+	if _, column, ok := strings.Cut(err.Error(), sqliteUniquePrefix); ok {
+		return &UniqueViolationError{Constraint: column, Err: err}
+	}
+
+	return err
+}
+
+// sortColumns whitelists the columns users can be sorted by, keyed by the sort field.
+// Only these values ever reach the ORDER BY clause.
+var sortColumns = map[string]string{
+	"user_id":    "user_id",
+	"created_at": "created_at",
+	"last_name":  "last_name",
+	"user_name":  "user_name",
+}
+
+// SortFields returns the accepted sort fields
+func SortFields() []string {
+	fields := make([]string, 0, len(sortColumns)+1)
+	for field := range sortColumns {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	return append(fields, models.SortRelevance)
+}
+
+func (r *userRepository) List(ctx context.Context, params models.ListParams) ([]models.User, int, error) {
+	if err := ValidateListParams(params); err != nil {
+		return nil, 0, err
+	}
+
 	var users []models.User
-	err := r.db.NewSelect().Model(&users).Order("user_id ASC").Scan(ctx)
+	query := pageQuery(applyFilters(r.db.NewSelect().Model(&users), params), params)
+
+	if params.AfterID > 0 {
+		// the total still counts every matching user, not only the ones after the cursor
+		total, err := applyFilters(r.db.NewSelect().Model((*models.User)(nil)), params).Count(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		err = query.Where("user_id > ?", params.AfterID).Scan(ctx)
+		return users, total, err
+	}
+
+	total, err := query.ScanAndCount(ctx)
+	return users, total, err
+}
+
+// Stream calls fn with every user of the list, read one at a time from a cursor over the query
+// so the memory stays flat however many users match. An error of fn stops the stream and is returned.
+func (r *userRepository) Stream(ctx context.Context, params models.ListParams, fn func(user *models.User) error) error {
+	if err := ValidateListParams(params); err != nil {
+		return err
+	}
+
+	query := pageQuery(applyFilters(r.db.NewSelect().Model((*models.User)(nil)), params), params)
+	if params.AfterID > 0 {
+		query = query.Where("user_id > ?", params.AfterID)
+	}
+
+	rows, err := query.Rows(ctx)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user models.User
+		if err := query.DB().ScanRow(ctx, rows, &user); err != nil {
+			return err
+		}
+		if err := fn(&user); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// pageQuery orders the query by the sort field of the params, and selects the page of the limit and the offset
+func pageQuery(query *bun.SelectQuery, params models.ListParams) *bun.SelectQuery {
+	switch column, ok := sortColumns[params.Sort]; {
+	case params.Sort == "":
+	case params.Sort == models.SortRelevance:
+		if params.Query != "" {
+			query = orderByRelevance(query, params.Query)
+		}
+	case ok:
+		direction := "ASC"
+		if params.Order == models.OrderDesc {
+			direction = "DESC"
+		}
+		query = query.OrderExpr("? "+direction, bun.Ident(column))
+	}
+
+	if params.Limit > 0 {
+		query = query.Limit(params.Limit)
+	}
+	if params.Offset > 0 {
+		query = query.Offset(params.Offset)
+	}
+
+	// user_id keeps the order stable between pages when the sort column has duplicates
+	if params.Sort != "user_id" {
+		query = query.Order("user_id ASC")
+	}
+	return query
+}
+
+func (r *userRepository) Count(ctx context.Context, params models.ListParams) (int, error) {
+	if err := ValidateListParams(params); err != nil {
+		return 0, err
+	}
+	return applyFilters(r.db.NewSelect().Model((*models.User)(nil)), params).Count(ctx)
+}
+
+func (r *userRepository) CountByStatus(ctx context.Context, params models.ListParams) (map[models.UserStatus]int, error) {
+	if err := ValidateListParams(params); err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		UserStatus models.UserStatus `bun:"user_status"`
+		Count      int               `bun:"count"`
+	}
+	err := applyFilters(r.db.NewSelect().Model((*models.User)(nil)), params).
+		Column("user_status").
+		ColumnExpr("COUNT(*) AS count").
+		Group("user_status").
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[models.UserStatus]int, len(rows))
+	for _, row := range rows {
+		counts[row.UserStatus] = row.Count
+	}
+	return counts, nil
+}
+
+// applyFilters restricts the query to the users matching the search and filter criteria of the params
+func applyFilters(query *bun.SelectQuery, params models.ListParams) *bun.SelectQuery {
+	if params.Query != "" {
+		query = applySearch(query, params.Query)
+	}
+	if params.Status != "" {
+		query = query.Where("user_status = ?", params.Status)
+	}
+	if params.Department != "" {
+		query = query.Where("department = ?", params.Department)
+	}
+	if params.DepartmentLike != "" {
+		query = query.Where("LOWER(department) LIKE ? ESCAPE '"+likeEscape+"'", containsPattern(params.DepartmentLike))
+	}
+	// the times are stored in UTC, which MySQL's DATETIME columns don't record
+	if !params.CreatedAfter.IsZero() {
+		query = query.Where("created_at >= ?", params.CreatedAfter.UTC())
+	}
+	if !params.CreatedBefore.IsZero() {
+		query = query.Where("created_at < ?", params.CreatedBefore.UTC())
+	}
+	if !params.UpdatedAfter.IsZero() {
+		query = query.Where("updated_at >= ?", params.UpdatedAfter.UTC())
+	}
+	if !params.UpdatedBefore.IsZero() {
+		query = query.Where("updated_at < ?", params.UpdatedBefore.UTC())
+	}
+	return query
+}
+
+// likeEscape is the escape character used in LIKE patterns,
+// a backslash isn't portable since MySQL treats it as a string literal escape.
+const likeEscape = "!"
+
+// likeEscaper escapes the LIKE wildcards, so they are matched literally
+var likeEscaper = strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_")
+
+// SearchUsers returns all users whose user name, first name, last name or email contains the query,
+// an empty query returns every user.
+func (r *userRepository) SearchUsers(ctx context.Context, query string) ([]models.User, error) {
+	var users []models.User
+	q := r.db.NewSelect().Model(&users)
+
+	if query = strings.TrimSpace(query); query != "" {
+		q = applySearch(q, query)
+	}
+
+	err := q.Order("user_id ASC").Scan(ctx)
 	return users, err
 }
 
+// containsPattern returns the lowercase LIKE pattern matching values that contain the term
+func containsPattern(term string) string {
+	return "%" + likeEscaper.Replace(strings.ToLower(term)) + "%"
+}
+
+// applySearch filters users whose user name, first name, last name or email contains the term.
+// LOWER() ... LIKE is used instead of ILIKE to stay portable across dialects.
+func applySearch(query *bun.SelectQuery, term string) *bun.SelectQuery {
+	pattern := containsPattern(term)
+
+	return query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+		for _, column := range []string{"user_name", "first_name", "last_name", "email"} {
+			q = q.WhereOr("LOWER(?) LIKE ? ESCAPE '"+likeEscape+"'", bun.Ident(column), pattern)
+		}
+		return q
+	})
+}
+
+// orderByRelevance ranks search results:
+//  0. exact (case-insensitive) match of the user name or email
+//  1. user name starting with the term
+//  2. first name, last name or email starting with the term
+//  3. any other substring match
+func orderByRelevance(query *bun.SelectQuery, term string) *bun.SelectQuery {
+	exact := strings.ToLower(term)
+	prefix := likeEscaper.Replace(exact) + "%"
+
+	return query.OrderExpr(`CASE
+		WHEN LOWER(user_name) = ? OR LOWER(email) = ? THEN 0
+		WHEN LOWER(user_name) LIKE ? ESCAPE '`+likeEscape+`' THEN 1
+		WHEN LOWER(first_name) LIKE ? ESCAPE '`+likeEscape+`'
+			OR LOWER(last_name) LIKE ? ESCAPE '`+likeEscape+`'
+			OR LOWER(email) LIKE ? ESCAPE '`+likeEscape+`' THEN 2
+		ELSE 3
+	END ASC`, exact, exact, prefix, prefix, prefix, prefix)
+}
+
 func (r *userRepository) GetByID(ctx context.Context, id int64) (*models.User, error) {
 	user := new(models.User)
 	err := r.db.NewSelect().Model(user).Where("user_id = ?", id).Scan(ctx)
@@ -44,19 +384,241 @@ func (r *userRepository) GetByID(ctx context.Context, id int64) (*models.User, e
 	return user, nil
 }
 
+func (r *userRepository) GetWithManager(ctx context.Context, id int64) (*models.User, error) {
+	user := new(models.User)
+	err := r.db.NewSelect().Model(user).Relation("Manager").Where("?TableAlias.user_id = ?", id).Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *userRepository) ListReports(ctx context.Context, managerID int64) ([]models.User, error) {
+	users := []models.User{}
+	err := r.db.NewSelect().Model(&users).Where("manager_id = ?", managerID).OrderExpr("user_id ASC").Scan(ctx)
+	return users, err
+}
+
+func (r *userRepository) GetByUserName(ctx context.Context, userName string) (*models.User, error) {
+	return r.getBy(ctx, "user_name", userName)
+}
+
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.getBy(ctx, "email", email)
+}
+
+// getBy returns the first user whose column equals value, or ErrUserNotFound
+func (r *userRepository) getBy(ctx context.Context, column string, value string) (*models.User, error) {
+	user := new(models.User)
+	err := r.db.NewSelect().Model(user).Where("? = ?", bun.Ident(column), value).OrderExpr("user_id ASC").Limit(1).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *userRepository) GetByIDForUpdate(ctx context.Context, id int64) (*models.User, error) {
+	user := new(models.User)
+	if err := r.forUpdate(r.db.NewSelect().Model(user).Where("user_id = ?", id)).Scan(ctx); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *userRepository) ListByIDsForUpdate(ctx context.Context, ids []int64) ([]models.User, error) {
+	users := []models.User{}
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := r.forUpdate(r.db.NewSelect().Model(&users).Where("user_id IN (?)", bun.In(ids)).OrderExpr("user_id ASC")).Scan(ctx)
+	return users, err
+}
+
+func (r *userRepository) ListStale(ctx context.Context, before time.Time) ([]models.User, error) {
+	var users []models.User
+	err := r.selectStale(&users, before).Scan(ctx)
+	return users, err
+}
+
+func (r *userRepository) ListStaleForUpdate(ctx context.Context, before time.Time) ([]models.User, error) {
+	var users []models.User
+	err := r.forUpdate(r.selectStale(&users, before)).Scan(ctx)
+	return users, err
+}
+
+// selectStale selects the stale users of ListStale into users
+func (r *userRepository) selectStale(users *[]models.User, before time.Time) *bun.SelectQuery {
+	return r.db.NewSelect().Model(users).
+		Where("user_status = ?", models.UserStatusActive).
+		Where("COALESCE(last_login_at, created_at) < ?", before).
+		OrderExpr("user_id ASC")
+}
+
+func (r *userRepository) UpdateStatus(ctx context.Context, ids []int64, status models.UserStatus, by string) (time.Time, error) {
+	if len(ids) == 0 {
+		return time.Time{}, nil
+	}
+
+	// the database clock is the same for the whole statement
+	var stamps []time.Time
+	query := r.db.NewUpdate().Model((*models.User)(nil)).
+		Set("user_status = ?", status).
+		Set("status_updated_at = "+r.now()).
+		Set("updated_at = "+r.now()).
+		Set("updated_by = ?", by).
+		Set("version = version + 1").
+		Where("user_id IN (?)", bun.In(ids))
+	returning := r.hasFeature(feature.Returning)
+	if returning {
+		query = query.Returning("updated_at")
+	}
+	if _, err := query.Exec(ctx, &stamps); err != nil {
+		return time.Time{}, err
+	}
+
+	if !returning {
+		err := r.db.NewSelect().Model((*models.User)(nil)).Column("updated_at").
+			Where("user_id IN (?)", bun.In(ids)).
+			Scan(ctx, &stamps)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	// none of the users exists
+	if len(stamps) == 0 {
+		return time.Time{}, nil
+	}
+	return stamps[0], nil
+}
+
+// now returns the SQL expression of the database clock. PostgreSQL's CURRENT_TIMESTAMP is the start
+// of the transaction, which may predate a change committed while the transaction waited for a row lock.
+// MySQL's CURRENT_TIMESTAMP is truncated to the second unless asked for a precision.
+func (r *userRepository) now() string {
+	switch r.db.Dialect().Name() {
+	case dialect.PG:
+		return "statement_timestamp()"
+	case dialect.MySQL:
+		return "CURRENT_TIMESTAMP(6)"
+	default:
+		return "CURRENT_TIMESTAMP"
+	}
+}
+
+// hasFeature reports whether the database dialect supports the feature, e.g. RETURNING which MySQL lacks
+func (r *userRepository) hasFeature(f feature.Feature) bool {
+	return r.db.Dialect().Features().Has(f)
+}
+
+// readBack reloads the given columns (all when none) of the user from its row,
+// in place of a RETURNING clause on the dialects without one
+func (r *userRepository) readBack(ctx context.Context, user *models.User, columns ...string) error {
+	return r.db.NewSelect().Model(user).Column(columns...).WherePK().Scan(ctx)
+}
+
+// forUpdate locks the selected rows until the end of the transaction.
+// SQLite has no row locks, it serializes the writers on the whole database instead.
+func (r *userRepository) forUpdate(query *bun.SelectQuery) *bun.SelectQuery {
+	if r.db.Dialect().Name() == dialect.SQLite {
+		return query
+	}
+	return query.For("UPDATE")
+}
+
 func (r *userRepository) Create(ctx context.Context, user *models.User) error {
-	_, err := r.db.NewInsert().Model(user).Exec(ctx)
-	return err
+	// read the whole row back, so the user holds exactly what was persisted, including the database defaults
+	query := r.db.NewInsert().Model(user)
+	returning := r.hasFeature(feature.InsertReturning)
+	if returning {
+		query = query.Returning("*")
+	}
+	if _, err := query.Exec(ctx); err != nil {
+		return uniqueViolation(err)
+	}
+	if !returning {
+		return r.readBack(ctx, user)
+	}
+	return nil
 }
 
-func (r *userRepository) Update(ctx context.Context, user *models.User) error {
-	_, err := r.db.NewUpdate().Model(user).WherePK().Exec(ctx)
-	return err
+func (r *userRepository) CreateIfNotExists(ctx context.Context, user *models.User) (bool, error) {
+	// ON CONFLICT DO NOTHING, or INSERT IGNORE on MySQL
+	query := r.db.NewInsert().Model(user).Ignore()
+	returning := r.hasFeature(feature.InsertReturning)
+	if returning {
+		query = query.Returning("*")
+	}
+	res, err := query.Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if n > 0 && !returning {
+		if err := r.readBack(ctx, user); err != nil {
+			return false, err
+		}
+	}
+	return n > 0, nil
+}
+
+func (r *userRepository) Update(ctx context.Context, user *models.User, stamped ...string) error {
+	expected := user.Version
+	user.Version++
+
+	// the database clock stamps the change, the creation time is never rewritten
+	query := r.db.NewUpdate().Model(user).
+		ExcludeColumn("created_at").
+		Value("updated_at", r.now())
+	for _, column := range stamped {
+		query = query.Value(column, r.now())
+	}
+	readBack := append([]string{"updated_at"}, stamped...)
+	res, err := query.
+		WherePK().
+		Where("version = ?", expected).
+		Returning(strings.Join(readBack, ", ")).
+		Exec(ctx)
+	if err == nil {
+		var affected int64
+		if affected, err = res.RowsAffected(); err == nil && affected == 0 {
+			err = ErrVersionConflict
+		}
+	}
+	if err == nil && !r.hasFeature(feature.Returning) {
+		err = r.readBack(ctx, user, readBack...)
+	}
+	if err != nil {
+		user.Version = expected
+		return uniqueViolation(err)
+	}
+	return nil
 }
 
 func (r *userRepository) Delete(ctx context.Context, id int64) error {
-	_, err := r.db.NewDelete().Model((*models.User)(nil)).Where("user_id = ?", id).Exec(ctx)
-	return err
+	res, err := r.db.NewDelete().Model((*models.User)(nil)).Where("user_id = ?", id).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) ExistsByID(ctx context.Context, id int64) (bool, error) {
+	return r.db.NewSelect().Model((*models.User)(nil)).Where("user_id = ?", id).Exists(ctx)
 }
 
 func (r *userRepository) ExistsByUserName(ctx context.Context, userName string) (bool, error) {
@@ -75,3 +637,25 @@ func (r *userRepository) ExistsByEmail(ctx context.Context, email string, exclud
 	exists, err := query.Exists(ctx)
 	return exists, err
 }
+
+func (r *userRepository) Audit() AuditRepository {
+	return &auditRepository{db: r.db}
+}
+
+func (r *userRepository) Departments() DepartmentRepository {
+	return &departmentRepository{db: r.db}
+}
+
+func (r *userRepository) Outbox() OutboxRepository {
+	return &outboxRepository{db: r.db}
+}
+
+func (r *userRepository) RefreshTokens() RefreshTokenRepository {
+	return &refreshTokenRepository{db: r.db}
+}
+
+func (r *userRepository) RunInTx(ctx context.Context, fn func(ctx context.Context, repo UserRepository) error) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return fn(ctx, &userRepository{db: tx})
+	})
+}
@@ -0,0 +1,1361 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+	"user-management/internal/models"
+)
+
+// Ensure, that UserRepositoryMock does implement UserRepository.
+// If this is not the case, regenerate this file with moq.
+var _ UserRepository = &UserRepositoryMock{}
+
+// UserRepositoryMock is a mock implementation of UserRepository.
+//
+//	func TestSomethingThatUsesUserRepository(t *testing.T) {
+//
+//		// make and configure a mocked UserRepository
+//		mockedUserRepository := &UserRepositoryMock{
+//			AuditFunc: func() AuditRepository {
+//				panic("mock out the Audit method")
+//			},
+//			CountFunc: func(ctx context.Context, params models.ListParams) (int, error) {
+//				panic("mock out the Count method")
+//			},
+//			CountByStatusFunc: func(ctx context.Context, params models.ListParams) (map[models.UserStatus]int, error) {
+//				panic("mock out the CountByStatus method")
+//			},
+//			CreateFunc: func(ctx context.Context, user *models.User) error {
+//				panic("mock out the Create method")
+//			},
+//			CreateIfNotExistsFunc: func(ctx context.Context, user *models.User) (bool, error) {
+//				panic("mock out the CreateIfNotExists method")
+//			},
+//			DeleteFunc: func(ctx context.Context, id int64) error {
+//				panic("mock out the Delete method")
+//			},
+//			DepartmentsFunc: func() DepartmentRepository {
+//				panic("mock out the Departments method")
+//			},
+//			ExistsByEmailFunc: func(ctx context.Context, email string, excludeID int64) (bool, error) {
+//				panic("mock out the ExistsByEmail method")
+//			},
+//			ExistsByIDFunc: func(ctx context.Context, id int64) (bool, error) {
+//				panic("mock out the ExistsByID method")
+//			},
+//			ExistsByUserNameFunc: func(ctx context.Context, userName string) (bool, error) {
+//				panic("mock out the ExistsByUserName method")
+//			},
+//			GetByEmailFunc: func(ctx context.Context, email string) (*models.User, error) {
+//				panic("mock out the GetByEmail method")
+//			},
+//			GetByIDFunc: func(ctx context.Context, id int64) (*models.User, error) {
+//				panic("mock out the GetByID method")
+//			},
+//			GetByIDForUpdateFunc: func(ctx context.Context, id int64) (*models.User, error) {
+//				panic("mock out the GetByIDForUpdate method")
+//			},
+//			GetByUserNameFunc: func(ctx context.Context, userName string) (*models.User, error) {
+//				panic("mock out the GetByUserName method")
+//			},
+//			GetWithManagerFunc: func(ctx context.Context, id int64) (*models.User, error) {
+//				panic("mock out the GetWithManager method")
+//			},
+//			ListFunc: func(ctx context.Context, params models.ListParams) ([]models.User, int, error) {
+//				panic("mock out the List method")
+//			},
+//			ListByIDsForUpdateFunc: func(ctx context.Context, ids []int64) ([]models.User, error) {
+//				panic("mock out the ListByIDsForUpdate method")
+//			},
+//			ListReportsFunc: func(ctx context.Context, managerID int64) ([]models.User, error) {
+//				panic("mock out the ListReports method")
+//			},
+//			ListStaleFunc: func(ctx context.Context, before time.Time) ([]models.User, error) {
+//				panic("mock out the ListStale method")
+//			},
+//			ListStaleForUpdateFunc: func(ctx context.Context, before time.Time) ([]models.User, error) {
+//				panic("mock out the ListStaleForUpdate method")
+//			},
+//			OutboxFunc: func() OutboxRepository {
+//				panic("mock out the Outbox method")
+//			},
+//			RefreshTokensFunc: func() RefreshTokenRepository {
+//				panic("mock out the RefreshTokens method")
+//			},
+//			RunInTxFunc: func(ctx context.Context, fn func(ctx context.Context, repo UserRepository) error) error {
+//				panic("mock out the RunInTx method")
+//			},
+//			SearchUsersFunc: func(ctx context.Context, query string) ([]models.User, error) {
+//				panic("mock out the SearchUsers method")
+//			},
+//			StreamFunc: func(ctx context.Context, params models.ListParams, fn func(user *models.User) error) error {
+//				panic("mock out the Stream method")
+//			},
+//			UpdateFunc: func(ctx context.Context, user *models.User, stamped ...string) error {
+//				panic("mock out the Update method")
+//			},
+//			UpdateStatusFunc: func(ctx context.Context, ids []int64, status models.UserStatus, by string) (time.Time, error) {
+//				panic("mock out the UpdateStatus method")
+//			},
+//		}
+//
+//		// use mockedUserRepository in code that requires UserRepository
+//		// and then make assertions.
+//
+//	}
+type UserRepositoryMock struct {
+	// AuditFunc mocks the Audit method.
+	AuditFunc func() AuditRepository
+
+	// CountFunc mocks the Count method.
+	CountFunc func(ctx context.Context, params models.ListParams) (int, error)
+
+	// CountByStatusFunc mocks the CountByStatus method.
+	CountByStatusFunc func(ctx context.Context, params models.ListParams) (map[models.UserStatus]int, error)
+
+	// CreateFunc mocks the Create method.
+	CreateFunc func(ctx context.Context, user *models.User) error
+
+	// CreateIfNotExistsFunc mocks the CreateIfNotExists method.
+	CreateIfNotExistsFunc func(ctx context.Context, user *models.User) (bool, error)
+
+	// DeleteFunc mocks the Delete method.
+	DeleteFunc func(ctx context.Context, id int64) error
+
+	// DepartmentsFunc mocks the Departments method.
+	DepartmentsFunc func() DepartmentRepository
+
+	// ExistsByEmailFunc mocks the ExistsByEmail method.
+	ExistsByEmailFunc func(ctx context.Context, email string, excludeID int64) (bool, error)
+
+	// ExistsByIDFunc mocks the ExistsByID method.
+	ExistsByIDFunc func(ctx context.Context, id int64) (bool, error)
+
+	// ExistsByUserNameFunc mocks the ExistsByUserName method.
+	ExistsByUserNameFunc func(ctx context.Context, userName string) (bool, error)
+
+	// GetByEmailFunc mocks the GetByEmail method.
+	GetByEmailFunc func(ctx context.Context, email string) (*models.User, error)
+
+	// GetByIDFunc mocks the GetByID method.
+	GetByIDFunc func(ctx context.Context, id int64) (*models.User, error)
+
+	// GetByIDForUpdateFunc mocks the GetByIDForUpdate method.
+	GetByIDForUpdateFunc func(ctx context.Context, id int64) (*models.User, error)
+
+	// GetByUserNameFunc mocks the GetByUserName method.
+	GetByUserNameFunc func(ctx context.Context, userName string) (*models.User, error)
+
+	// GetWithManagerFunc mocks the GetWithManager method.
+	GetWithManagerFunc func(ctx context.Context, id int64) (*models.User, error)
+
+	// ListFunc mocks the List method.
+	ListFunc func(ctx context.Context, params models.ListParams) ([]models.User, int, error)
+
+	// ListByIDsForUpdateFunc mocks the ListByIDsForUpdate method.
+	ListByIDsForUpdateFunc func(ctx context.Context, ids []int64) ([]models.User, error)
+
+	// ListReportsFunc mocks the ListReports method.
+	ListReportsFunc func(ctx context.Context, managerID int64) ([]models.User, error)
+
+	// ListStaleFunc mocks the ListStale method.
+	ListStaleFunc func(ctx context.Context, before time.Time) ([]models.User, error)
+
+	// ListStaleForUpdateFunc mocks the ListStaleForUpdate method.
+	ListStaleForUpdateFunc func(ctx context.Context, before time.Time) ([]models.User, error)
+
+	// OutboxFunc mocks the Outbox method.
+	OutboxFunc func() OutboxRepository
+
+	// RefreshTokensFunc mocks the RefreshTokens method.
+	RefreshTokensFunc func() RefreshTokenRepository
+
+	// RunInTxFunc mocks the RunInTx method.
+	RunInTxFunc func(ctx context.Context, fn func(ctx context.Context, repo UserRepository) error) error
+
+	// SearchUsersFunc mocks the SearchUsers method.
+	SearchUsersFunc func(ctx context.Context, query string) ([]models.User, error)
+
+	// StreamFunc mocks the Stream method.
+	StreamFunc func(ctx context.Context, params models.ListParams, fn func(user *models.User) error) error
+
+	// UpdateFunc mocks the Update method.
+	UpdateFunc func(ctx context.Context, user *models.User, stamped ...string) error
+
+	// UpdateStatusFunc mocks the UpdateStatus method.
+	UpdateStatusFunc func(ctx context.Context, ids []int64, status models.UserStatus, by string) (time.Time, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Audit holds details about calls to the Audit method.
+		Audit []struct {
+		}
+		// Count holds details about calls to the Count method.
+		Count []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Params is the params argument value.
+			Params models.ListParams
+		}
+		// CountByStatus holds details about calls to the CountByStatus method.
+		CountByStatus []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Params is the params argument value.
+			Params models.ListParams
+		}
+		// Create holds details about calls to the Create method.
+		Create []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// User is the user argument value.
+			User *models.User
+		}
+		// CreateIfNotExists holds details about calls to the CreateIfNotExists method.
+		CreateIfNotExists []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// User is the user argument value.
+			User *models.User
+		}
+		// Delete holds details about calls to the Delete method.
+		Delete []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
+		// Departments holds details about calls to the Departments method.
+		Departments []struct {
+		}
+		// ExistsByEmail holds details about calls to the ExistsByEmail method.
+		ExistsByEmail []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Email is the email argument value.
+			Email string
+			// ExcludeID is the excludeID argument value.
+			ExcludeID int64
+		}
+		// ExistsByID holds details about calls to the ExistsByID method.
+		ExistsByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
+		// ExistsByUserName holds details about calls to the ExistsByUserName method.
+		ExistsByUserName []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserName is the userName argument value.
+			UserName string
+		}
+		// GetByEmail holds details about calls to the GetByEmail method.
+		GetByEmail []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Email is the email argument value.
+			Email string
+		}
+		// GetByID holds details about calls to the GetByID method.
+		GetByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
+		// GetByIDForUpdate holds details about calls to the GetByIDForUpdate method.
+		GetByIDForUpdate []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
+		// GetByUserName holds details about calls to the GetByUserName method.
+		GetByUserName []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserName is the userName argument value.
+			UserName string
+		}
+		// GetWithManager holds details about calls to the GetWithManager method.
+		GetWithManager []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
+		// List holds details about calls to the List method.
+		List []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Params is the params argument value.
+			Params models.ListParams
+		}
+		// ListByIDsForUpdate holds details about calls to the ListByIDsForUpdate method.
+		ListByIDsForUpdate []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Ids is the ids argument value.
+			Ids []int64
+		}
+		// ListReports holds details about calls to the ListReports method.
+		ListReports []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ManagerID is the managerID argument value.
+			ManagerID int64
+		}
+		// ListStale holds details about calls to the ListStale method.
+		ListStale []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Before is the before argument value.
+			Before time.Time
+		}
+		// ListStaleForUpdate holds details about calls to the ListStaleForUpdate method.
+		ListStaleForUpdate []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Before is the before argument value.
+			Before time.Time
+		}
+		// Outbox holds details about calls to the Outbox method.
+		Outbox []struct {
+		}
+		// RefreshTokens holds details about calls to the RefreshTokens method.
+		RefreshTokens []struct {
+		}
+		// RunInTx holds details about calls to the RunInTx method.
+		RunInTx []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Fn is the fn argument value.
+			Fn func(ctx context.Context, repo UserRepository) error
+		}
+		// SearchUsers holds details about calls to the SearchUsers method.
+		SearchUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query string
+		}
+		// Stream holds details about calls to the Stream method.
+		Stream []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Params is the params argument value.
+			Params models.ListParams
+			// Fn is the fn argument value.
+			Fn func(user *models.User) error
+		}
+		// Update holds details about calls to the Update method.
+		Update []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// User is the user argument value.
+			User *models.User
+			// Stamped is the stamped argument value.
+			Stamped []string
+		}
+		// UpdateStatus holds details about calls to the UpdateStatus method.
+		UpdateStatus []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Ids is the ids argument value.
+			Ids []int64
+			// Status is the status argument value.
+			Status models.UserStatus
+			// By is the by argument value.
+			By string
+		}
+	}
+	lockAudit              sync.RWMutex
+	lockCount              sync.RWMutex
+	lockCountByStatus      sync.RWMutex
+	lockCreate             sync.RWMutex
+	lockCreateIfNotExists  sync.RWMutex
+	lockDelete             sync.RWMutex
+	lockDepartments        sync.RWMutex
+	lockExistsByEmail      sync.RWMutex
+	lockExistsByID         sync.RWMutex
+	lockExistsByUserName   sync.RWMutex
+	lockGetByEmail         sync.RWMutex
+	lockGetByID            sync.RWMutex
+	lockGetByIDForUpdate   sync.RWMutex
+	lockGetByUserName      sync.RWMutex
+	lockGetWithManager     sync.RWMutex
+	lockList               sync.RWMutex
+	lockListByIDsForUpdate sync.RWMutex
+	lockListReports        sync.RWMutex
+	lockListStale          sync.RWMutex
+	lockListStaleForUpdate sync.RWMutex
+	lockOutbox             sync.RWMutex
+	lockRefreshTokens      sync.RWMutex
+	lockRunInTx            sync.RWMutex
+	lockSearchUsers        sync.RWMutex
+	lockStream             sync.RWMutex
+	lockUpdate             sync.RWMutex
+	lockUpdateStatus       sync.RWMutex
+}
+
+// Audit calls AuditFunc.
+func (mock *UserRepositoryMock) Audit() AuditRepository {
+	if mock.AuditFunc == nil {
+		panic("UserRepositoryMock.AuditFunc: method is nil but UserRepository.Audit was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockAudit.Lock()
+	mock.calls.Audit = append(mock.calls.Audit, callInfo)
+	mock.lockAudit.Unlock()
+	return mock.AuditFunc()
+}
+
+// AuditCalls gets all the calls that were made to Audit.
+// Check the length with:
+//
+//	len(mockedUserRepository.AuditCalls())
+func (mock *UserRepositoryMock) AuditCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockAudit.RLock()
+	calls = mock.calls.Audit
+	mock.lockAudit.RUnlock()
+	return calls
+}
+
+// Count calls CountFunc.
+func (mock *UserRepositoryMock) Count(ctx context.Context, params models.ListParams) (int, error) {
+	if mock.CountFunc == nil {
+		panic("UserRepositoryMock.CountFunc: method is nil but UserRepository.Count was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Params models.ListParams
+	}{
+		Ctx:    ctx,
+		Params: params,
+	}
+	mock.lockCount.Lock()
+	mock.calls.Count = append(mock.calls.Count, callInfo)
+	mock.lockCount.Unlock()
+	return mock.CountFunc(ctx, params)
+}
+
+// CountCalls gets all the calls that were made to Count.
+// Check the length with:
+//
+//	len(mockedUserRepository.CountCalls())
+func (mock *UserRepositoryMock) CountCalls() []struct {
+	Ctx    context.Context
+	Params models.ListParams
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Params models.ListParams
+	}
+	mock.lockCount.RLock()
+	calls = mock.calls.Count
+	mock.lockCount.RUnlock()
+	return calls
+}
+
+// CountByStatus calls CountByStatusFunc.
+func (mock *UserRepositoryMock) CountByStatus(ctx context.Context, params models.ListParams) (map[models.UserStatus]int, error) {
+	if mock.CountByStatusFunc == nil {
+		panic("UserRepositoryMock.CountByStatusFunc: method is nil but UserRepository.CountByStatus was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Params models.ListParams
+	}{
+		Ctx:    ctx,
+		Params: params,
+	}
+	mock.lockCountByStatus.Lock()
+	mock.calls.CountByStatus = append(mock.calls.CountByStatus, callInfo)
+	mock.lockCountByStatus.Unlock()
+	return mock.CountByStatusFunc(ctx, params)
+}
+
+// CountByStatusCalls gets all the calls that were made to CountByStatus.
+// Check the length with:
+//
+//	len(mockedUserRepository.CountByStatusCalls())
+func (mock *UserRepositoryMock) CountByStatusCalls() []struct {
+	Ctx    context.Context
+	Params models.ListParams
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Params models.ListParams
+	}
+	mock.lockCountByStatus.RLock()
+	calls = mock.calls.CountByStatus
+	mock.lockCountByStatus.RUnlock()
+	return calls
+}
+
+// Create calls CreateFunc.
+func (mock *UserRepositoryMock) Create(ctx context.Context, user *models.User) error {
+	if mock.CreateFunc == nil {
+		panic("UserRepositoryMock.CreateFunc: method is nil but UserRepository.Create was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		User *models.User
+	}{
+		Ctx:  ctx,
+		User: user,
+	}
+	mock.lockCreate.Lock()
+	mock.calls.Create = append(mock.calls.Create, callInfo)
+	mock.lockCreate.Unlock()
+	return mock.CreateFunc(ctx, user)
+}
+
+// CreateCalls gets all the calls that were made to Create.
+// Check the length with:
+//
+//	len(mockedUserRepository.CreateCalls())
+func (mock *UserRepositoryMock) CreateCalls() []struct {
+	Ctx  context.Context
+	User *models.User
+} {
+	var calls []struct {
+		Ctx  context.Context
+		User *models.User
+	}
+	mock.lockCreate.RLock()
+	calls = mock.calls.Create
+	mock.lockCreate.RUnlock()
+	return calls
+}
+
+// CreateIfNotExists calls CreateIfNotExistsFunc.
+func (mock *UserRepositoryMock) CreateIfNotExists(ctx context.Context, user *models.User) (bool, error) {
+	if mock.CreateIfNotExistsFunc == nil {
+		panic("UserRepositoryMock.CreateIfNotExistsFunc: method is nil but UserRepository.CreateIfNotExists was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		User *models.User
+	}{
+		Ctx:  ctx,
+		User: user,
+	}
+	mock.lockCreateIfNotExists.Lock()
+	mock.calls.CreateIfNotExists = append(mock.calls.CreateIfNotExists, callInfo)
+	mock.lockCreateIfNotExists.Unlock()
+	return mock.CreateIfNotExistsFunc(ctx, user)
+}
+
+// CreateIfNotExistsCalls gets all the calls that were made to CreateIfNotExists.
+// Check the length with:
+//
+//	len(mockedUserRepository.CreateIfNotExistsCalls())
+func (mock *UserRepositoryMock) CreateIfNotExistsCalls() []struct {
+	Ctx  context.Context
+	User *models.User
+} {
+	var calls []struct {
+		Ctx  context.Context
+		User *models.User
+	}
+	mock.lockCreateIfNotExists.RLock()
+	calls = mock.calls.CreateIfNotExists
+	mock.lockCreateIfNotExists.RUnlock()
+	return calls
+}
+
+// Delete calls DeleteFunc.
+func (mock *UserRepositoryMock) Delete(ctx context.Context, id int64) error {
+	if mock.DeleteFunc == nil {
+		panic("UserRepositoryMock.DeleteFunc: method is nil but UserRepository.Delete was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockDelete.Lock()
+	mock.calls.Delete = append(mock.calls.Delete, callInfo)
+	mock.lockDelete.Unlock()
+	return mock.DeleteFunc(ctx, id)
+}
+
+// DeleteCalls gets all the calls that were made to Delete.
+// Check the length with:
+//
+//	len(mockedUserRepository.DeleteCalls())
+func (mock *UserRepositoryMock) DeleteCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockDelete.RLock()
+	calls = mock.calls.Delete
+	mock.lockDelete.RUnlock()
+	return calls
+}
+
+// Departments calls DepartmentsFunc.
+func (mock *UserRepositoryMock) Departments() DepartmentRepository {
+	if mock.DepartmentsFunc == nil {
+		panic("UserRepositoryMock.DepartmentsFunc: method is nil but UserRepository.Departments was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockDepartments.Lock()
+	mock.calls.Departments = append(mock.calls.Departments, callInfo)
+	mock.lockDepartments.Unlock()
+	return mock.DepartmentsFunc()
+}
+
+// DepartmentsCalls gets all the calls that were made to Departments.
+// Check the length with:
+//
+//	len(mockedUserRepository.DepartmentsCalls())
+func (mock *UserRepositoryMock) DepartmentsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockDepartments.RLock()
+	calls = mock.calls.Departments
+	mock.lockDepartments.RUnlock()
+	return calls
+}
+
+// ExistsByEmail calls ExistsByEmailFunc.
+func (mock *UserRepositoryMock) ExistsByEmail(ctx context.Context, email string, excludeID int64) (bool, error) {
+	if mock.ExistsByEmailFunc == nil {
+		panic("UserRepositoryMock.ExistsByEmailFunc: method is nil but UserRepository.ExistsByEmail was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		Email     string
+		ExcludeID int64
+	}{
+		Ctx:       ctx,
+		Email:     email,
+		ExcludeID: excludeID,
+	}
+	mock.lockExistsByEmail.Lock()
+	mock.calls.ExistsByEmail = append(mock.calls.ExistsByEmail, callInfo)
+	mock.lockExistsByEmail.Unlock()
+	return mock.ExistsByEmailFunc(ctx, email, excludeID)
+}
+
+// ExistsByEmailCalls gets all the calls that were made to ExistsByEmail.
+// Check the length with:
+//
+//	len(mockedUserRepository.ExistsByEmailCalls())
+func (mock *UserRepositoryMock) ExistsByEmailCalls() []struct {
+	Ctx       context.Context
+	Email     string
+	ExcludeID int64
+} {
+	var calls []struct {
+		Ctx       context.Context
+		Email     string
+		ExcludeID int64
+	}
+	mock.lockExistsByEmail.RLock()
+	calls = mock.calls.ExistsByEmail
+	mock.lockExistsByEmail.RUnlock()
+	return calls
+}
+
+// ExistsByID calls ExistsByIDFunc.
+func (mock *UserRepositoryMock) ExistsByID(ctx context.Context, id int64) (bool, error) {
+	if mock.ExistsByIDFunc == nil {
+		panic("UserRepositoryMock.ExistsByIDFunc: method is nil but UserRepository.ExistsByID was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockExistsByID.Lock()
+	mock.calls.ExistsByID = append(mock.calls.ExistsByID, callInfo)
+	mock.lockExistsByID.Unlock()
+	return mock.ExistsByIDFunc(ctx, id)
+}
+
+// ExistsByIDCalls gets all the calls that were made to ExistsByID.
+// Check the length with:
+//
+//	len(mockedUserRepository.ExistsByIDCalls())
+func (mock *UserRepositoryMock) ExistsByIDCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockExistsByID.RLock()
+	calls = mock.calls.ExistsByID
+	mock.lockExistsByID.RUnlock()
+	return calls
+}
+
+// ExistsByUserName calls ExistsByUserNameFunc.
+func (mock *UserRepositoryMock) ExistsByUserName(ctx context.Context, userName string) (bool, error) {
+	if mock.ExistsByUserNameFunc == nil {
+		panic("UserRepositoryMock.ExistsByUserNameFunc: method is nil but UserRepository.ExistsByUserName was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		UserName string
+	}{
+		Ctx:      ctx,
+		UserName: userName,
+	}
+	mock.lockExistsByUserName.Lock()
+	mock.calls.ExistsByUserName = append(mock.calls.ExistsByUserName, callInfo)
+	mock.lockExistsByUserName.Unlock()
+	return mock.ExistsByUserNameFunc(ctx, userName)
+}
+
+// ExistsByUserNameCalls gets all the calls that were made to ExistsByUserName.
+// Check the length with:
+//
+//	len(mockedUserRepository.ExistsByUserNameCalls())
+func (mock *UserRepositoryMock) ExistsByUserNameCalls() []struct {
+	Ctx      context.Context
+	UserName string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		UserName string
+	}
+	mock.lockExistsByUserName.RLock()
+	calls = mock.calls.ExistsByUserName
+	mock.lockExistsByUserName.RUnlock()
+	return calls
+}
+
+// GetByEmail calls GetByEmailFunc.
+func (mock *UserRepositoryMock) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	if mock.GetByEmailFunc == nil {
+		panic("UserRepositoryMock.GetByEmailFunc: method is nil but UserRepository.GetByEmail was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Email string
+	}{
+		Ctx:   ctx,
+		Email: email,
+	}
+	mock.lockGetByEmail.Lock()
+	mock.calls.GetByEmail = append(mock.calls.GetByEmail, callInfo)
+	mock.lockGetByEmail.Unlock()
+	return mock.GetByEmailFunc(ctx, email)
+}
+
+// GetByEmailCalls gets all the calls that were made to GetByEmail.
+// Check the length with:
+//
+//	len(mockedUserRepository.GetByEmailCalls())
+func (mock *UserRepositoryMock) GetByEmailCalls() []struct {
+	Ctx   context.Context
+	Email string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Email string
+	}
+	mock.lockGetByEmail.RLock()
+	calls = mock.calls.GetByEmail
+	mock.lockGetByEmail.RUnlock()
+	return calls
+}
+
+// GetByID calls GetByIDFunc.
+func (mock *UserRepositoryMock) GetByID(ctx context.Context, id int64) (*models.User, error) {
+	if mock.GetByIDFunc == nil {
+		panic("UserRepositoryMock.GetByIDFunc: method is nil but UserRepository.GetByID was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetByID.Lock()
+	mock.calls.GetByID = append(mock.calls.GetByID, callInfo)
+	mock.lockGetByID.Unlock()
+	return mock.GetByIDFunc(ctx, id)
+}
+
+// GetByIDCalls gets all the calls that were made to GetByID.
+// Check the length with:
+//
+//	len(mockedUserRepository.GetByIDCalls())
+func (mock *UserRepositoryMock) GetByIDCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockGetByID.RLock()
+	calls = mock.calls.GetByID
+	mock.lockGetByID.RUnlock()
+	return calls
+}
+
+// GetByIDForUpdate calls GetByIDForUpdateFunc.
+func (mock *UserRepositoryMock) GetByIDForUpdate(ctx context.Context, id int64) (*models.User, error) {
+	if mock.GetByIDForUpdateFunc == nil {
+		panic("UserRepositoryMock.GetByIDForUpdateFunc: method is nil but UserRepository.GetByIDForUpdate was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetByIDForUpdate.Lock()
+	mock.calls.GetByIDForUpdate = append(mock.calls.GetByIDForUpdate, callInfo)
+	mock.lockGetByIDForUpdate.Unlock()
+	return mock.GetByIDForUpdateFunc(ctx, id)
+}
+
+// GetByIDForUpdateCalls gets all the calls that were made to GetByIDForUpdate.
+// Check the length with:
+//
+//	len(mockedUserRepository.GetByIDForUpdateCalls())
+func (mock *UserRepositoryMock) GetByIDForUpdateCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockGetByIDForUpdate.RLock()
+	calls = mock.calls.GetByIDForUpdate
+	mock.lockGetByIDForUpdate.RUnlock()
+	return calls
+}
+
+// GetByUserName calls GetByUserNameFunc.
+func (mock *UserRepositoryMock) GetByUserName(ctx context.Context, userName string) (*models.User, error) {
+	if mock.GetByUserNameFunc == nil {
+		panic("UserRepositoryMock.GetByUserNameFunc: method is nil but UserRepository.GetByUserName was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		UserName string
+	}{
+		Ctx:      ctx,
+		UserName: userName,
+	}
+	mock.lockGetByUserName.Lock()
+	mock.calls.GetByUserName = append(mock.calls.GetByUserName, callInfo)
+	mock.lockGetByUserName.Unlock()
+	return mock.GetByUserNameFunc(ctx, userName)
+}
+
+// GetByUserNameCalls gets all the calls that were made to GetByUserName.
+// Check the length with:
+//
+//	len(mockedUserRepository.GetByUserNameCalls())
+func (mock *UserRepositoryMock) GetByUserNameCalls() []struct {
+	Ctx      context.Context
+	UserName string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		UserName string
+	}
+	mock.lockGetByUserName.RLock()
+	calls = mock.calls.GetByUserName
+	mock.lockGetByUserName.RUnlock()
+	return calls
+}
+
+// GetWithManager calls GetWithManagerFunc.
+func (mock *UserRepositoryMock) GetWithManager(ctx context.Context, id int64) (*models.User, error) {
+	if mock.GetWithManagerFunc == nil {
+		panic("UserRepositoryMock.GetWithManagerFunc: method is nil but UserRepository.GetWithManager was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetWithManager.Lock()
+	mock.calls.GetWithManager = append(mock.calls.GetWithManager, callInfo)
+	mock.lockGetWithManager.Unlock()
+	return mock.GetWithManagerFunc(ctx, id)
+}
+
+// GetWithManagerCalls gets all the calls that were made to GetWithManager.
+// Check the length with:
+//
+//	len(mockedUserRepository.GetWithManagerCalls())
+func (mock *UserRepositoryMock) GetWithManagerCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockGetWithManager.RLock()
+	calls = mock.calls.GetWithManager
+	mock.lockGetWithManager.RUnlock()
+	return calls
+}
+
+// List calls ListFunc.
+func (mock *UserRepositoryMock) List(ctx context.Context, params models.ListParams) ([]models.User, int, error) {
+	if mock.ListFunc == nil {
+		panic("UserRepositoryMock.ListFunc: method is nil but UserRepository.List was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Params models.ListParams
+	}{
+		Ctx:    ctx,
+		Params: params,
+	}
+	mock.lockList.Lock()
+	mock.calls.List = append(mock.calls.List, callInfo)
+	mock.lockList.Unlock()
+	return mock.ListFunc(ctx, params)
+}
+
+// ListCalls gets all the calls that were made to List.
+// Check the length with:
+//
+//	len(mockedUserRepository.ListCalls())
+func (mock *UserRepositoryMock) ListCalls() []struct {
+	Ctx    context.Context
+	Params models.ListParams
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Params models.ListParams
+	}
+	mock.lockList.RLock()
+	calls = mock.calls.List
+	mock.lockList.RUnlock()
+	return calls
+}
+
+// ListByIDsForUpdate calls ListByIDsForUpdateFunc.
+func (mock *UserRepositoryMock) ListByIDsForUpdate(ctx context.Context, ids []int64) ([]models.User, error) {
+	if mock.ListByIDsForUpdateFunc == nil {
+		panic("UserRepositoryMock.ListByIDsForUpdateFunc: method is nil but UserRepository.ListByIDsForUpdate was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Ids []int64
+	}{
+		Ctx: ctx,
+		Ids: ids,
+	}
+	mock.lockListByIDsForUpdate.Lock()
+	mock.calls.ListByIDsForUpdate = append(mock.calls.ListByIDsForUpdate, callInfo)
+	mock.lockListByIDsForUpdate.Unlock()
+	return mock.ListByIDsForUpdateFunc(ctx, ids)
+}
+
+// ListByIDsForUpdateCalls gets all the calls that were made to ListByIDsForUpdate.
+// Check the length with:
+//
+//	len(mockedUserRepository.ListByIDsForUpdateCalls())
+func (mock *UserRepositoryMock) ListByIDsForUpdateCalls() []struct {
+	Ctx context.Context
+	Ids []int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		Ids []int64
+	}
+	mock.lockListByIDsForUpdate.RLock()
+	calls = mock.calls.ListByIDsForUpdate
+	mock.lockListByIDsForUpdate.RUnlock()
+	return calls
+}
+
+// ListReports calls ListReportsFunc.
+func (mock *UserRepositoryMock) ListReports(ctx context.Context, managerID int64) ([]models.User, error) {
+	if mock.ListReportsFunc == nil {
+		panic("UserRepositoryMock.ListReportsFunc: method is nil but UserRepository.ListReports was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		ManagerID int64
+	}{
+		Ctx:       ctx,
+		ManagerID: managerID,
+	}
+	mock.lockListReports.Lock()
+	mock.calls.ListReports = append(mock.calls.ListReports, callInfo)
+	mock.lockListReports.Unlock()
+	return mock.ListReportsFunc(ctx, managerID)
+}
+
+// ListReportsCalls gets all the calls that were made to ListReports.
+// Check the length with:
+//
+//	len(mockedUserRepository.ListReportsCalls())
+func (mock *UserRepositoryMock) ListReportsCalls() []struct {
+	Ctx       context.Context
+	ManagerID int64
+} {
+	var calls []struct {
+		Ctx       context.Context
+		ManagerID int64
+	}
+	mock.lockListReports.RLock()
+	calls = mock.calls.ListReports
+	mock.lockListReports.RUnlock()
+	return calls
+}
+
+// ListStale calls ListStaleFunc.
+func (mock *UserRepositoryMock) ListStale(ctx context.Context, before time.Time) ([]models.User, error) {
+	if mock.ListStaleFunc == nil {
+		panic("UserRepositoryMock.ListStaleFunc: method is nil but UserRepository.ListStale was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Before time.Time
+	}{
+		Ctx:    ctx,
+		Before: before,
+	}
+	mock.lockListStale.Lock()
+	mock.calls.ListStale = append(mock.calls.ListStale, callInfo)
+	mock.lockListStale.Unlock()
+	return mock.ListStaleFunc(ctx, before)
+}
+
+// ListStaleCalls gets all the calls that were made to ListStale.
+// Check the length with:
+//
+//	len(mockedUserRepository.ListStaleCalls())
+func (mock *UserRepositoryMock) ListStaleCalls() []struct {
+	Ctx    context.Context
+	Before time.Time
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Before time.Time
+	}
+	mock.lockListStale.RLock()
+	calls = mock.calls.ListStale
+	mock.lockListStale.RUnlock()
+	return calls
+}
+
+// ListStaleForUpdate calls ListStaleForUpdateFunc.
+func (mock *UserRepositoryMock) ListStaleForUpdate(ctx context.Context, before time.Time) ([]models.User, error) {
+	if mock.ListStaleForUpdateFunc == nil {
+		panic("UserRepositoryMock.ListStaleForUpdateFunc: method is nil but UserRepository.ListStaleForUpdate was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Before time.Time
+	}{
+		Ctx:    ctx,
+		Before: before,
+	}
+	mock.lockListStaleForUpdate.Lock()
+	mock.calls.ListStaleForUpdate = append(mock.calls.ListStaleForUpdate, callInfo)
+	mock.lockListStaleForUpdate.Unlock()
+	return mock.ListStaleForUpdateFunc(ctx, before)
+}
+
+// ListStaleForUpdateCalls gets all the calls that were made to ListStaleForUpdate.
+// Check the length with:
+//
+//	len(mockedUserRepository.ListStaleForUpdateCalls())
+func (mock *UserRepositoryMock) ListStaleForUpdateCalls() []struct {
+	Ctx    context.Context
+	Before time.Time
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Before time.Time
+	}
+	mock.lockListStaleForUpdate.RLock()
+	calls = mock.calls.ListStaleForUpdate
+	mock.lockListStaleForUpdate.RUnlock()
+	return calls
+}
+
+// Outbox calls OutboxFunc.
+func (mock *UserRepositoryMock) Outbox() OutboxRepository {
+	if mock.OutboxFunc == nil {
+		panic("UserRepositoryMock.OutboxFunc: method is nil but UserRepository.Outbox was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockOutbox.Lock()
+	mock.calls.Outbox = append(mock.calls.Outbox, callInfo)
+	mock.lockOutbox.Unlock()
+	return mock.OutboxFunc()
+}
+
+// OutboxCalls gets all the calls that were made to Outbox.
+// Check the length with:
+//
+//	len(mockedUserRepository.OutboxCalls())
+func (mock *UserRepositoryMock) OutboxCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockOutbox.RLock()
+	calls = mock.calls.Outbox
+	mock.lockOutbox.RUnlock()
+	return calls
+}
+
+// RefreshTokens calls RefreshTokensFunc.
+func (mock *UserRepositoryMock) RefreshTokens() RefreshTokenRepository {
+	if mock.RefreshTokensFunc == nil {
+		panic("UserRepositoryMock.RefreshTokensFunc: method is nil but UserRepository.RefreshTokens was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockRefreshTokens.Lock()
+	mock.calls.RefreshTokens = append(mock.calls.RefreshTokens, callInfo)
+	mock.lockRefreshTokens.Unlock()
+	return mock.RefreshTokensFunc()
+}
+
+// RefreshTokensCalls gets all the calls that were made to RefreshTokens.
+// Check the length with:
+//
+//	len(mockedUserRepository.RefreshTokensCalls())
+func (mock *UserRepositoryMock) RefreshTokensCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockRefreshTokens.RLock()
+	calls = mock.calls.RefreshTokens
+	mock.lockRefreshTokens.RUnlock()
+	return calls
+}
+
+// RunInTx calls RunInTxFunc.
+func (mock *UserRepositoryMock) RunInTx(ctx context.Context, fn func(ctx context.Context, repo UserRepository) error) error {
+	if mock.RunInTxFunc == nil {
+		panic("UserRepositoryMock.RunInTxFunc: method is nil but UserRepository.RunInTx was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Fn  func(ctx context.Context, repo UserRepository) error
+	}{
+		Ctx: ctx,
+		Fn:  fn,
+	}
+	mock.lockRunInTx.Lock()
+	mock.calls.RunInTx = append(mock.calls.RunInTx, callInfo)
+	mock.lockRunInTx.Unlock()
+	return mock.RunInTxFunc(ctx, fn)
+}
+
+// RunInTxCalls gets all the calls that were made to RunInTx.
+// Check the length with:
+//
+//	len(mockedUserRepository.RunInTxCalls())
+func (mock *UserRepositoryMock) RunInTxCalls() []struct {
+	Ctx context.Context
+	Fn  func(ctx context.Context, repo UserRepository) error
+} {
+	var calls []struct {
+		Ctx context.Context
+		Fn  func(ctx context.Context, repo UserRepository) error
+	}
+	mock.lockRunInTx.RLock()
+	calls = mock.calls.RunInTx
+	mock.lockRunInTx.RUnlock()
+	return calls
+}
+
+// SearchUsers calls SearchUsersFunc.
+func (mock *UserRepositoryMock) SearchUsers(ctx context.Context, query string) ([]models.User, error) {
+	if mock.SearchUsersFunc == nil {
+		panic("UserRepositoryMock.SearchUsersFunc: method is nil but UserRepository.SearchUsers was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query string
+	}{
+		Ctx:   ctx,
+		Query: query,
+	}
+	mock.lockSearchUsers.Lock()
+	mock.calls.SearchUsers = append(mock.calls.SearchUsers, callInfo)
+	mock.lockSearchUsers.Unlock()
+	return mock.SearchUsersFunc(ctx, query)
+}
+
+// SearchUsersCalls gets all the calls that were made to SearchUsers.
+// Check the length with:
+//
+//	len(mockedUserRepository.SearchUsersCalls())
+func (mock *UserRepositoryMock) SearchUsersCalls() []struct {
+	Ctx   context.Context
+	Query string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query string
+	}
+	mock.lockSearchUsers.RLock()
+	calls = mock.calls.SearchUsers
+	mock.lockSearchUsers.RUnlock()
+	return calls
+}
+
+// Stream calls StreamFunc.
+func (mock *UserRepositoryMock) Stream(ctx context.Context, params models.ListParams, fn func(user *models.User) error) error {
+	if mock.StreamFunc == nil {
+		panic("UserRepositoryMock.StreamFunc: method is nil but UserRepository.Stream was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Params models.ListParams
+		Fn     func(user *models.User) error
+	}{
+		Ctx:    ctx,
+		Params: params,
+		Fn:     fn,
+	}
+	mock.lockStream.Lock()
+	mock.calls.Stream = append(mock.calls.Stream, callInfo)
+	mock.lockStream.Unlock()
+	return mock.StreamFunc(ctx, params, fn)
+}
+
+// StreamCalls gets all the calls that were made to Stream.
+// Check the length with:
+//
+//	len(mockedUserRepository.StreamCalls())
+func (mock *UserRepositoryMock) StreamCalls() []struct {
+	Ctx    context.Context
+	Params models.ListParams
+	Fn     func(user *models.User) error
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Params models.ListParams
+		Fn     func(user *models.User) error
+	}
+	mock.lockStream.RLock()
+	calls = mock.calls.Stream
+	mock.lockStream.RUnlock()
+	return calls
+}
+
+// Update calls UpdateFunc.
+func (mock *UserRepositoryMock) Update(ctx context.Context, user *models.User, stamped ...string) error {
+	if mock.UpdateFunc == nil {
+		panic("UserRepositoryMock.UpdateFunc: method is nil but UserRepository.Update was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		User    *models.User
+		Stamped []string
+	}{
+		Ctx:     ctx,
+		User:    user,
+		Stamped: stamped,
+	}
+	mock.lockUpdate.Lock()
+	mock.calls.Update = append(mock.calls.Update, callInfo)
+	mock.lockUpdate.Unlock()
+	return mock.UpdateFunc(ctx, user, stamped...)
+}
+
+// UpdateCalls gets all the calls that were made to Update.
+// Check the length with:
+//
+//	len(mockedUserRepository.UpdateCalls())
+func (mock *UserRepositoryMock) UpdateCalls() []struct {
+	Ctx     context.Context
+	User    *models.User
+	Stamped []string
+} {
+	var calls []struct {
+		Ctx     context.Context
+		User    *models.User
+		Stamped []string
+	}
+	mock.lockUpdate.RLock()
+	calls = mock.calls.Update
+	mock.lockUpdate.RUnlock()
+	return calls
+}
+
+// UpdateStatus calls UpdateStatusFunc.
+func (mock *UserRepositoryMock) UpdateStatus(ctx context.Context, ids []int64, status models.UserStatus, by string) (time.Time, error) {
+	if mock.UpdateStatusFunc == nil {
+		panic("UserRepositoryMock.UpdateStatusFunc: method is nil but UserRepository.UpdateStatus was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Ids    []int64
+		Status models.UserStatus
+		By     string
+	}{
+		Ctx:    ctx,
+		Ids:    ids,
+		Status: status,
+		By:     by,
+	}
+	mock.lockUpdateStatus.Lock()
+	mock.calls.UpdateStatus = append(mock.calls.UpdateStatus, callInfo)
+	mock.lockUpdateStatus.Unlock()
+	return mock.UpdateStatusFunc(ctx, ids, status, by)
+}
+
+// UpdateStatusCalls gets all the calls that were made to UpdateStatus.
+// Check the length with:
+//
+//	len(mockedUserRepository.UpdateStatusCalls())
+func (mock *UserRepositoryMock) UpdateStatusCalls() []struct {
+	Ctx    context.Context
+	Ids    []int64
+	Status models.UserStatus
+	By     string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Ids    []int64
+		Status models.UserStatus
+		By     string
+	}
+	mock.lockUpdateStatus.RLock()
+	calls = mock.calls.UpdateStatus
+	mock.lockUpdateStatus.RUnlock()
+	return calls
+}
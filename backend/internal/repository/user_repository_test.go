@@ -0,0 +1,431 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+
+	"user-management/internal/models"
+)
+
+func newTestRepository(t *testing.T, userNames ...string) UserRepository {
+	t.Helper()
+	return newTestRepositoryWithDialect(t, sqlitedialect.New(), userNames...)
+}
+
+// testDialect returns the SQLite dialect, without RETURNING support unless returning
+func testDialect(returning bool) *sqlitedialect.Dialect {
+	if returning {
+		return sqlitedialect.New()
+	}
+	return sqlitedialect.New(sqlitedialect.WithoutFeature(feature.InsertReturning | feature.Returning))
+}
+
+// newTestRepositoryWithDialect is newTestRepository over an in-memory SQLite database with the given dialect,
+// e.g. one without RETURNING to behave like MySQL
+func newTestRepositoryWithDialect(t *testing.T, sqlDialect *sqlitedialect.Dialect, userNames ...string) UserRepository {
+	t.Helper()
+
+	sqldb, err := sql.Open(sqliteshim.ShimName, "file::memory:")
+	require.NoError(t, err)
+	// a single connection keeps the in-memory database alive between queries
+	sqldb.SetMaxOpenConns(1)
+
+	db := bun.NewDB(sqldb, sqlDialect)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	_, err = db.NewCreateTable().Model((*models.User)(nil)).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewCreateTable().Model((*models.AuditEntry)(nil)).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewCreateTable().Model((*models.Department)(nil)).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewCreateTable().Model((*models.OutboxEntry)(nil)).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewCreateTable().Model((*models.RefreshToken)(nil)).Exec(ctx)
+	require.NoError(t, err)
+
+	repo := NewUserRepository(db)
+	for _, userName := range userNames {
+		require.NoError(t, repo.Create(ctx, &models.User{UserCommon: models.UserCommon{
+			UserName:   userName,
+			FirstName:  "Test",
+			LastName:   "User",
+			Email:      userName + "@example.com",
+			UserStatus: models.UserStatusActive,
+		}}))
+	}
+
+	return repo
+}
+
+func TestSearchUsers(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepository(t, "john_doe", "johnxdoe", "JOHNNY", "percent%off", "bang!user", "alice")
+
+	testCases := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{
+			name:     "empty query lists every user",
+			query:    "  ",
+			expected: []string{"john_doe", "johnxdoe", "JOHNNY", "percent%off", "bang!user", "alice"},
+		},
+		{
+			name:     "case-insensitive substring",
+			query:    "John",
+			expected: []string{"john_doe", "johnxdoe", "JOHNNY"},
+		},
+		{
+			name:     "underscore is literal",
+			query:    "n_d",
+			expected: []string{"john_doe"},
+		},
+		{
+			name:     "percent is literal",
+			query:    "%",
+			expected: []string{"percent%off"},
+		},
+		{
+			name:     "escape character is literal",
+			query:    "!u",
+			expected: []string{"bang!user"},
+		},
+		{
+			name:     "matches the email",
+			query:    "alice@EXAMPLE",
+			expected: []string{"alice"},
+		},
+		{
+			name:     "quotes are parameterized",
+			query:    "' OR '1'='1",
+			expected: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			users, err := repo.SearchUsers(context.Background(), tc.query)
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(users))
+			for _, user := range users {
+				names = append(names, user.UserName)
+			}
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+}
+
+func TestContainsPattern(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "%john%", containsPattern("JOHN"))
+	assert.Equal(t, "%a!_b!%c!!d%", containsPattern("a_b%c!d"))
+}
+
+func TestListAfterID(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepository(t, "alice", "bob", "carol", "dave")
+	ctx := context.Background()
+
+	users, total, err := repo.List(ctx, models.ListParams{AfterID: 1, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, []int64{users[0].UserID, users[1].UserID})
+	assert.Equal(t, 4, total, "the total ignores the cursor")
+
+	// the filters still apply to both
+	users, total, err = repo.List(ctx, models.ListParams{Query: "bob", AfterID: 2, Limit: 2})
+	require.NoError(t, err)
+	assert.Empty(t, users)
+	assert.Equal(t, 1, total)
+}
+
+func TestStream(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepository(t, "alice", "bob", "carol", "dave")
+	ctx := context.Background()
+
+	var streamed []string
+	collect := func(user *models.User) error {
+		streamed = append(streamed, user.UserName)
+		return nil
+	}
+
+	require.NoError(t, repo.Stream(ctx, models.ListParams{Sort: "user_name", Order: models.OrderDesc}, collect))
+	assert.Equal(t, []string{"dave", "carol", "bob", "alice"}, streamed, "a zero limit streams every user")
+
+	streamed = nil
+	require.NoError(t, repo.Stream(ctx, models.ListParams{AfterID: 1, Limit: 2}, collect))
+	assert.Equal(t, []string{"bob", "carol"}, streamed)
+
+	// an error of fn stops the stream
+	stop := errors.New("stop")
+	calls := 0
+	err := repo.Stream(ctx, models.ListParams{}, func(*models.User) error {
+		calls++
+		return stop
+	})
+	require.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+
+	err = repo.Stream(ctx, models.ListParams{Sort: "password"}, collect)
+	require.ErrorIs(t, err, ErrInvalidSort)
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepository(t, "alice")
+	ctx := context.Background()
+
+	require.NoError(t, repo.Delete(ctx, 1))
+	assert.ErrorIs(t, repo.Delete(ctx, 1), ErrUserNotFound, "already deleted")
+	assert.ErrorIs(t, repo.Delete(ctx, 999), ErrUserNotFound, "never existed")
+}
+
+func TestUpdateVersionConflict(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepository(t, "alice")
+	ctx := context.Background()
+
+	first, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	second, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+
+	first.FirstName = "First"
+	require.NoError(t, repo.Update(ctx, first))
+	assert.Equal(t, second.Version+1, first.Version)
+
+	// second was read before the first update landed
+	second.FirstName = "Second"
+	require.ErrorIs(t, repo.Update(ctx, second), ErrVersionConflict)
+	assert.Equal(t, first.Version-1, second.Version, "the version is left untouched on conflict")
+
+	stored, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "First", stored.FirstName)
+	assert.Equal(t, first.Version, stored.Version)
+}
+
+func TestPasswordHash(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepository(t, "alice")
+	ctx := context.Background()
+
+	// the users without a password have a NULL hash
+	user, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, user.PasswordHash)
+
+	user.PasswordHash = "$2a$10$hash"
+	require.NoError(t, repo.Update(ctx, user))
+	stored, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "$2a$10$hash", stored.PasswordHash)
+}
+
+func TestCreateReturnsPersistedRow(t *testing.T) {
+	t.Parallel()
+
+	// without RETURNING, as on MySQL, the row is selected back after the insert
+	for _, returning := range []bool{true, false} {
+		t.Run(fmt.Sprintf("returning=%t", returning), func(t *testing.T) {
+			t.Parallel()
+			testCreateReturnsPersistedRow(t, newTestRepositoryWithDialect(t, testDialect(returning), "alice"))
+		})
+	}
+}
+
+func testCreateReturnsPersistedRow(t *testing.T, repo UserRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	testCases := []struct {
+		name   string
+		create func(user *models.User) error
+	}{
+		{"Create", func(user *models.User) error { return repo.Create(ctx, user) }},
+		{"CreateIfNotExists", func(user *models.User) error {
+			inserted, err := repo.CreateIfNotExists(ctx, user)
+			assert.True(t, inserted)
+			return err
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userName := "new" + tc.name
+			user := &models.User{UserCommon: models.UserCommon{
+				UserName:   userName,
+				FirstName:  "New",
+				LastName:   "User",
+				Email:      userName + "@example.com",
+				UserStatus: models.UserStatusActive,
+			}}
+			require.NoError(t, tc.create(user))
+
+			assert.NotZero(t, user.UserID)
+			assert.False(t, user.CreatedAt.IsZero())
+			assert.False(t, user.UpdatedAt.IsZero())
+			assert.Equal(t, "system", user.CreatedBy, "the database default")
+			assert.Equal(t, int64(1), user.Version, "the database default")
+
+			stored, err := repo.GetByID(ctx, user.UserID)
+			require.NoError(t, err)
+			assert.True(t, stored.CreatedAt.Equal(user.CreatedAt))
+			assert.True(t, stored.UpdatedAt.Equal(user.UpdatedAt))
+		})
+	}
+}
+
+func TestUpdateTimestamps(t *testing.T) {
+	t.Parallel()
+
+	for _, returning := range []bool{true, false} {
+		t.Run(fmt.Sprintf("returning=%t", returning), func(t *testing.T) {
+			t.Parallel()
+			testUpdateTimestamps(t, newTestRepositoryWithDialect(t, testDialect(returning), "alice"))
+		})
+	}
+}
+
+func testUpdateTimestamps(t *testing.T, repo UserRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	user, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	createdAt := user.CreatedAt
+
+	// neither value may reach the database
+	user.CreatedAt = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	user.UpdatedAt = time.Time{}
+	user.FirstName = "Changed"
+	require.NoError(t, repo.Update(ctx, user))
+	assert.False(t, user.UpdatedAt.IsZero(), "the update time is read back from the database")
+
+	stored, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, createdAt.Equal(stored.CreatedAt))
+	assert.True(t, user.UpdatedAt.Equal(stored.UpdatedAt))
+
+	// the stamped columns get the update time, the others keep their value
+	future := time.Now().Add(time.Hour)
+	user.StatusUpdatedAt = &future
+	user.UserStatus = models.UserStatusInactive
+	require.NoError(t, repo.Update(ctx, user, StatusUpdatedAtColumn))
+	require.NotNil(t, user.StatusUpdatedAt)
+	assert.True(t, user.UpdatedAt.Equal(*user.StatusUpdatedAt), "the stamped column is read back")
+
+	stored, err = repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	require.NotNil(t, stored.StatusUpdatedAt)
+	assert.True(t, stored.UpdatedAt.Equal(*stored.StatusUpdatedAt))
+	assert.Nil(t, stored.EmailUpdatedAt)
+}
+
+func TestUpdateStatusTimestamps(t *testing.T) {
+	t.Parallel()
+
+	for _, returning := range []bool{true, false} {
+		t.Run(fmt.Sprintf("returning=%t", returning), func(t *testing.T) {
+			t.Parallel()
+
+			repo := newTestRepositoryWithDialect(t, testDialect(returning), "alice", "bob")
+			ctx := context.Background()
+
+			at, err := repo.UpdateStatus(ctx, []int64{1, 2}, models.UserStatusInactive, "hr")
+			require.NoError(t, err)
+			assert.False(t, at.IsZero(), "the time of the change is read back from the database")
+
+			for _, id := range []int64{1, 2} {
+				stored, err := repo.GetByID(ctx, id)
+				require.NoError(t, err)
+				assert.Equal(t, models.UserStatusInactive, stored.UserStatus)
+				assert.True(t, at.Equal(stored.UpdatedAt))
+				require.NotNil(t, stored.StatusUpdatedAt)
+				assert.True(t, at.Equal(*stored.StatusUpdatedAt))
+				assert.Equal(t, "hr", stored.UpdatedBy)
+			}
+		})
+	}
+}
+
+func TestCreateUniqueViolation(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepository(t, "alice")
+	ctx := context.Background()
+
+	err := repo.Create(ctx, &models.User{UserCommon: models.UserCommon{
+		UserName:   "alice2",
+		FirstName:  "Test",
+		LastName:   "User",
+		Email:      "alice@example.com",
+		UserStatus: models.UserStatusActive,
+	}})
+
+	var violation *UniqueViolationError
+	require.ErrorAs(t, err, &violation)
+	assert.Contains(t, violation.Constraint, "email")
+}
+
+func TestUniqueViolationMySQL(t *testing.T) {
+	t.Parallel()
+
+	err := uniqueViolation(&mysql.MySQLError{
+		Number:  mysqlDuplicateEntry,
+		Message: "Duplicate entry 'alice@example.com' for key 'users.email'",
+	})
+
+	var violation *UniqueViolationError
+	require.ErrorAs(t, err, &violation)
+	assert.Equal(t, "users.email", violation.Constraint)
+
+	other := &mysql.MySQLError{Number: 1048, Message: "Column 'email' cannot be null"}
+	assert.Same(t, other, uniqueViolation(other))
+}
+
+func TestGetByUserNameAndEmail(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepository(t, "alice", "bob")
+	ctx := context.Background()
+
+	user, err := repo.GetByUserName(ctx, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), user.UserID)
+
+	user, err = repo.GetByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", user.UserName)
+
+	_, err = repo.GetByUserName(ctx, "carol")
+	require.ErrorIs(t, err, ErrUserNotFound)
+
+	_, err = repo.GetByEmail(ctx, "carol@example.com")
+	require.ErrorIs(t, err, ErrUserNotFound)
+}
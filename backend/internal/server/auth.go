@@ -0,0 +1,268 @@
+package server
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+
+	"user-management/internal/config"
+	"user-management/internal/models"
+	"user-management/internal/services"
+)
+
+// ClaimsKey is the echo context key of the claims of the request's credential, see Claims
+const ClaimsKey = "jwt.claims"
+
+// HeaderAPIKey carries the API key of the machine clients, instead of a bearer token
+const HeaderAPIKey = "X-API-Key"
+
+// APIKeySubject is the subject claim of the requests authenticated by an API key
+const APIKeySubject = "api-key"
+
+// AuthKeys are the keys the credentials are verified with, the algorithm of a missing key is rejected
+type AuthKeys struct {
+	// Secret verifies the HS256 tokens
+	Secret []byte
+	// PublicKey verifies the RS256 tokens
+	PublicKey *rsa.PublicKey
+	// APIKeys are the SHA-256 digests of the accepted API keys, the keys themselves aren't kept
+	APIKeys [][sha256.Size]byte
+	// APIKeyRoles are the roles of the requests authenticated by an API key
+	APIKeyRoles []string
+}
+
+// LoadAuthKeys reads the keys of the auth configuration. Without any key, the API would reject every request,
+// so it's an error unless the authentication is disabled, in which case no key is loaded.
+func LoadAuthKeys(cfg *config.Config) (AuthKeys, error) {
+	var keys AuthKeys
+	if cfg.Auth.Disabled {
+		return keys, nil
+	}
+	if cfg.Auth.JWTSecret == "" && cfg.Auth.JWTPublicKey == "" && len(cfg.Auth.APIKeyHashes) == 0 {
+		return keys, errors.New("either a JWT secret, a JWT public key or an API key hash must be provided, unless the authentication is disabled")
+	}
+
+	if cfg.Auth.JWTSecret != "" {
+		keys.Secret = []byte(cfg.Auth.JWTSecret)
+	}
+	if cfg.Auth.JWTPublicKey != "" {
+		pem, err := os.ReadFile(cfg.Auth.JWTPublicKey)
+		if err != nil {
+			return keys, fmt.Errorf("failed to read the JWT public key: %w", err)
+		}
+		if keys.PublicKey, err = jwt.ParseRSAPublicKeyFromPEM(pem); err != nil {
+			return keys, fmt.Errorf("invalid JWT public key %s: %w", cfg.Auth.JWTPublicKey, err)
+		}
+	}
+
+	for _, hash := range cfg.Auth.APIKeyHashes {
+		digest, err := hex.DecodeString(strings.TrimSpace(hash))
+		if err != nil || len(digest) != sha256.Size {
+			return keys, fmt.Errorf("invalid API key hash %q: must be a hex SHA-256 digest", hash)
+		}
+		keys.APIKeys = append(keys.APIKeys, [sha256.Size]byte(digest))
+	}
+	keys.APIKeyRoles = cfg.Auth.APIKeyRoles
+	return keys, nil
+}
+
+// The rejected credentials, their messages are the ones of the 401 responses
+var (
+	errMissingCredential = errors.New("missing bearer token or API key")
+	errInvalidAPIKey     = errors.New("invalid API key")
+	errInvalidToken      = errors.New("invalid bearer token")
+	errExpiredToken      = errors.New("expired bearer token")
+)
+
+// credentialVerifier verifies the credentials of the requests with the keys, see Authenticate
+type credentialVerifier struct {
+	keys    AuthKeys
+	parser  *jwt.Parser
+	keyFunc jwt.Keyfunc
+	// roles of the API key claims
+	roles []any
+}
+
+func newCredentialVerifier(keys AuthKeys) *credentialVerifier {
+	var methods []string
+	if keys.Secret != nil {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if keys.PublicKey != nil {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	// the valid methods guarantee the key matches the token's algorithm
+	parser := jwt.NewParser(jwt.WithValidMethods(methods), jwt.WithExpirationRequired())
+	keyFunc := func(token *jwt.Token) (any, error) {
+		if token.Method == jwt.SigningMethodRS256 {
+			return keys.PublicKey, nil
+		}
+		return keys.Secret, nil
+	}
+
+	roles := make([]any, len(keys.APIKeyRoles))
+	for i, role := range keys.APIKeyRoles {
+		roles[i] = role
+	}
+
+	return &credentialVerifier{keys: keys, parser: parser, keyFunc: keyFunc, roles: roles}
+}
+
+// verify returns the claims of the API key, or else of the bearer token of the authorization header value
+func (v *credentialVerifier) verify(apiKey, authorization string) (jwt.MapClaims, error) {
+	if apiKey != "" {
+		if !v.keys.matchAPIKey(apiKey) {
+			return nil, errInvalidAPIKey
+		}
+		return jwt.MapClaims{"sub": APIKeySubject, RolesClaim: v.roles}, nil
+	}
+
+	raw, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok || raw == "" {
+		return nil, errMissingCredential
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(raw, claims, v.keyFunc); err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, errExpiredToken
+		}
+		return nil, errInvalidToken
+	}
+	return claims, nil
+}
+
+// Authenticate rejects with 401 the requests without a valid credential, which is either:
+//   - an "Authorization: Bearer <token>" header carrying a token signed with HS256 or RS256 by one of the keys,
+//     with an expiration time that hasn't passed yet (nor a not before time to come)
+//   - an X-API-Key header carrying one of the API keys, taking precedence over the bearer token
+//
+// The claims of the accepted tokens are set in the echo context under ClaimsKey, an API key gets the
+// APIKeySubject subject and the API key roles. Their subject is the actor of the request's writes.
+func Authenticate(keys AuthKeys, skipper middleware.Skipper) echo.MiddlewareFunc {
+	verifier := newCredentialVerifier(keys)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if skipper != nil && skipper(c) {
+				return next(c)
+			}
+
+			claims, err := verifier.verify(c.Request().Header.Get(HeaderAPIKey), c.Request().Header.Get(echo.HeaderAuthorization))
+			switch {
+			case errors.Is(err, errMissingCredential):
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+			case errors.Is(err, errInvalidToken), errors.Is(err, errExpiredToken):
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+			}
+			if err != nil {
+				return c.JSON(http.StatusUnauthorized, models.NewErrorResponse(http.StatusUnauthorized, err.Error()))
+			}
+
+			c.Set(ClaimsKey, claims)
+			req := c.Request()
+			c.SetRequest(req.WithContext(actorOf(req.Context(), claims)))
+			return next(c)
+		}
+	}
+}
+
+// actorOf returns the context carrying the subject of the credential's claims as the actor of the writes,
+// which the X-Actor header of the client doesn't override
+func actorOf(ctx context.Context, claims jwt.MapClaims) context.Context {
+	subject, _ := claims.GetSubject()
+	return services.WithAuthenticatedActor(ctx, subject)
+}
+
+// matchAPIKey reports whether the key's digest is one of the API keys', comparing every digest in constant time
+func (k AuthKeys) matchAPIKey(key string) bool {
+	digest := sha256.Sum256([]byte(key))
+	match := 0
+	for _, apiKey := range k.APIKeys {
+		match |= subtle.ConstantTimeCompare(digest[:], apiKey[:])
+	}
+	return match == 1
+}
+
+// RolesClaim is the claim listing the roles of the token's subject,
+// as an array of strings or a space-separated string
+const RolesClaim = "roles"
+
+// RequireRole rejects with 403 the POST, PUT, PATCH and DELETE requests whose token doesn't list the role
+// in its roles claim, the other methods are let through. An empty role disables the check.
+// It needs the claims set by Authenticate.
+func RequireRole(role string) echo.MiddlewareFunc {
+	if role == "" {
+		return func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			switch c.Request().Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+			default:
+				return next(c)
+			}
+
+			if err := CheckRole(c, role); err != nil {
+				return c.JSON(http.StatusForbidden, models.NewErrorResponse(http.StatusForbidden, err.Error()))
+			}
+			return next(c)
+		}
+	}
+}
+
+// CheckRole returns the error rejecting a request whose token doesn't list the role in its roles claim,
+// nil when it does or the role is empty
+func CheckRole(c echo.Context, role string) error {
+	return checkClaimsRole(Claims(c), role)
+}
+
+// checkClaimsRole is CheckRole on the claims of a credential
+func checkClaimsRole(claims jwt.MapClaims, role string) error {
+	if role != "" && !slices.Contains(claimsRoles(claims), role) {
+		return fmt.Errorf("the %s role is required", role)
+	}
+	return nil
+}
+
+// Roles returns the roles listed by the roles claim of the request's credential, nil without any
+func Roles(c echo.Context) []string {
+	return claimsRoles(Claims(c))
+}
+
+// claimsRoles returns the roles listed by the roles claim, nil without any
+func claimsRoles(claims jwt.MapClaims) []string {
+	switch roles := claims[RolesClaim].(type) {
+	case string:
+		return strings.Fields(roles)
+	case []any:
+		names := make([]string, 0, len(roles))
+		for _, role := range roles {
+			if name, ok := role.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	default:
+		return nil
+	}
+}
+
+// Claims returns the claims of the request's credential, nil when the request wasn't authenticated
+func Claims(c echo.Context) jwt.MapClaims {
+	claims, _ := c.Get(ClaimsKey).(jwt.MapClaims)
+	return claims
+}
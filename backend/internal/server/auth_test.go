@@ -0,0 +1,225 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-management/internal/config"
+)
+
+func sign(t *testing.T, method jwt.SigningMethod, key any, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	require.NoError(t, err)
+	return "Bearer " + token
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherRSAKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	secret := []byte("s3cret")
+
+	apiKey := sha256.Sum256([]byte("etl-key"))
+
+	newServer := func(keys AuthKeys) *echo.Echo {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error {
+			return c.String(http.StatusOK, Claims(c)["sub"].(string))
+		}, Authenticate(keys, nil))
+		return e
+	}
+	both := newServer(AuthKeys{Secret: secret, PublicKey: &rsaKey.PublicKey, APIKeys: [][sha256.Size]byte{apiKey}})
+	hsOnly := newServer(AuthKeys{Secret: secret})
+
+	valid := jwt.MapClaims{"sub": "jane", "exp": time.Now().Add(time.Hour).Unix()}
+	expired := jwt.MapClaims{"sub": "jane", "exp": time.Now().Add(-time.Minute).Unix()}
+	notYet := jwt.MapClaims{"sub": "jane", "exp": time.Now().Add(time.Hour).Unix(), "nbf": time.Now().Add(time.Minute).Unix()}
+	noExpiry := jwt.MapClaims{"sub": "jane"}
+
+	testCases := []struct {
+		name          string
+		srv           *echo.Echo
+		authorization string
+		apiKey        string
+		expected      int
+		errorMessage  string
+	}{
+		{"HS256", both, sign(t, jwt.SigningMethodHS256, secret, valid), "", http.StatusOK, ""},
+		{"RS256", both, sign(t, jwt.SigningMethodRS256, rsaKey, valid), "", http.StatusOK, ""},
+		{"API Key", both, "", "etl-key", http.StatusOK, ""},
+		{"Invalid API Key", both, "", "guess", http.StatusUnauthorized, "invalid API key"},
+		{"API Key Without Keys", hsOnly, "", "etl-key", http.StatusUnauthorized, "invalid API key"},
+		{"Invalid API Key With Token", both, sign(t, jwt.SigningMethodHS256, secret, valid), "guess", http.StatusUnauthorized, "invalid API key"},
+		{"Missing Header", both, "", "", http.StatusUnauthorized, "missing bearer token or API key"},
+		{"Wrong Scheme", both, "Basic amFuZTpzM2NyZXQ=", "", http.StatusUnauthorized, "missing bearer token or API key"},
+		{"Expired", both, sign(t, jwt.SigningMethodHS256, secret, expired), "", http.StatusUnauthorized, "expired bearer token"},
+		{"Not Valid Yet", both, sign(t, jwt.SigningMethodHS256, secret, notYet), "", http.StatusUnauthorized, "invalid bearer token"},
+		{"No Expiry", both, sign(t, jwt.SigningMethodHS256, secret, noExpiry), "", http.StatusUnauthorized, "invalid bearer token"},
+		{"Wrong Secret", both, sign(t, jwt.SigningMethodHS256, []byte("guess"), valid), "", http.StatusUnauthorized, "invalid bearer token"},
+		{"Wrong RSA Key", both, sign(t, jwt.SigningMethodRS256, otherRSAKey, valid), "", http.StatusUnauthorized, "invalid bearer token"},
+		{"Unsigned", both, sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, valid), "", http.StatusUnauthorized, "invalid bearer token"},
+		{"Other Algorithm", both, sign(t, jwt.SigningMethodHS512, secret, valid), "", http.StatusUnauthorized, "invalid bearer token"},
+		{"Algorithm Without Key", hsOnly, sign(t, jwt.SigningMethodRS256, rsaKey, valid), "", http.StatusUnauthorized, "invalid bearer token"},
+		{"Malformed", both, "Bearer not.a.token", "", http.StatusUnauthorized, "invalid bearer token"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/users", http.NoBody)
+			if tc.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tc.authorization)
+			}
+			if tc.apiKey != "" {
+				req.Header.Set(HeaderAPIKey, tc.apiKey)
+			}
+			resp := httptest.NewRecorder()
+			tc.srv.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expected, resp.Code)
+			if tc.expected == http.StatusOK {
+				subject := "jane"
+				if tc.apiKey != "" {
+					subject = APIKeySubject
+				}
+				assert.Equal(t, subject, resp.Body.String(), "the claims are in the context")
+				return
+			}
+			if tc.apiKey == "" {
+				assert.Contains(t, resp.Header().Get(echo.HeaderWWWAuthenticate), "Bearer")
+			}
+			assert.JSONEq(t, `{"code":"unauthorized","message":"`+tc.errorMessage+`"}`, resp.Body.String())
+		})
+	}
+}
+
+func TestLoadAuthKeys(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	pemFile := filepath.Join(t.TempDir(), "jwt.pem")
+	require.NoError(t, os.WriteFile(pemFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+	invalidFile := filepath.Join(t.TempDir(), "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidFile, []byte("not a key"), 0o600))
+
+	load := func(modify func(cfg *config.Config)) (AuthKeys, error) {
+		cfg := &config.Config{}
+		modify(cfg)
+		return LoadAuthKeys(cfg)
+	}
+
+	keys, err := load(func(cfg *config.Config) { cfg.Auth.JWTSecret = "s3cret"; cfg.Auth.JWTPublicKey = pemFile })
+	require.NoError(t, err)
+	assert.Equal(t, []byte("s3cret"), keys.Secret)
+	assert.True(t, rsaKey.PublicKey.Equal(keys.PublicKey))
+
+	digest := sha256.Sum256([]byte("etl-key"))
+	keys, err = load(func(cfg *config.Config) {
+		cfg.Auth.APIKeyHashes = []string{hex.EncodeToString(digest[:])}
+		cfg.Auth.APIKeyRoles = []string{"etl"}
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][sha256.Size]byte{digest}, keys.APIKeys)
+	assert.Equal(t, []string{"etl"}, keys.APIKeyRoles)
+	assert.Nil(t, keys.Secret, "an API key is enough")
+
+	keys, err = load(func(cfg *config.Config) { cfg.Auth.Disabled = true })
+	require.NoError(t, err)
+	assert.Nil(t, keys.Secret)
+
+	_, err = load(func(*config.Config) {})
+	require.ErrorContains(t, err, "unless the authentication is disabled")
+	_, err = load(func(cfg *config.Config) { cfg.Auth.JWTPublicKey = filepath.Join(t.TempDir(), "missing.pem") })
+	require.ErrorContains(t, err, "failed to read the JWT public key")
+	_, err = load(func(cfg *config.Config) { cfg.Auth.JWTPublicKey = invalidFile })
+	require.ErrorContains(t, err, "invalid JWT public key")
+	_, err = load(func(cfg *config.Config) { cfg.Auth.APIKeyHashes = []string{"etl-key"} })
+	require.ErrorContains(t, err, "invalid API key hash")
+	_, err = load(func(cfg *config.Config) { cfg.Auth.APIKeyHashes = []string{"abcd"} })
+	require.ErrorContains(t, err, "invalid API key hash")
+}
+
+func TestRequireRole(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(role string) *echo.Echo {
+		e := echo.New()
+		e.Any("/users", func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		}, func(next echo.HandlerFunc) echo.HandlerFunc {
+			// stands for Authenticate
+			return func(c echo.Context) error {
+				if raw := c.Request().Header.Get("X-Test-Roles"); raw != "" {
+					var roles any = raw
+					if raw == "array" {
+						roles = []any{"reader", "admin", 42}
+					}
+					c.Set(ClaimsKey, jwt.MapClaims{RolesClaim: roles})
+				}
+				return next(c)
+			}
+		}, RequireRole(role))
+		return e
+	}
+	admin := newServer("admin")
+
+	testCases := []struct {
+		name     string
+		srv      *echo.Echo
+		method   string
+		roles    string
+		expected int
+	}{
+		{"Read Without Role", admin, http.MethodGet, "reader", http.StatusOK},
+		{"Head Without Claims", admin, http.MethodHead, "", http.StatusOK},
+		{"Create With Role", admin, http.MethodPost, "reader admin", http.StatusOK},
+		{"Roles Array", admin, http.MethodDelete, "array", http.StatusOK},
+		{"Create Without Role", admin, http.MethodPost, "reader", http.StatusForbidden},
+		{"Update Without Role", admin, http.MethodPut, "administrator", http.StatusForbidden},
+		{"Patch Without Role", admin, http.MethodPatch, "reader", http.StatusForbidden},
+		{"Delete Without Claims", admin, http.MethodDelete, "", http.StatusForbidden},
+		{"No Required Role", newServer(""), http.MethodDelete, "", http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tc.method, "/users", http.NoBody)
+			if tc.roles != "" {
+				req.Header.Set("X-Test-Roles", tc.roles)
+			}
+			resp := httptest.NewRecorder()
+			tc.srv.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expected, resp.Code)
+			if tc.expected == http.StatusForbidden {
+				assert.JSONEq(t, `{"code":"forbidden","message":"the admin role is required"}`, resp.Body.String())
+			}
+		})
+	}
+}
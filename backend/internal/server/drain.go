@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"user-management/internal/models"
+)
+
+// Drainer tracks the connections of the server so that a shutdown waits for the in-flight requests,
+// and rejects the requests arriving on the open connections once the shutdown started.
+type Drainer struct {
+	draining atomic.Bool
+
+	mu    sync.Mutex
+	conns map[net.Conn]http.ConnState
+}
+
+// NewDrainer returns a Drainer tracking the connections of the servers, e.g. the HTTP and HTTPS ones of echo
+func NewDrainer(servers ...*http.Server) *Drainer {
+	d := &Drainer{conns: make(map[net.Conn]http.ConnState)}
+	for _, s := range servers {
+		s.ConnState = d.track
+	}
+	return d
+}
+
+func (d *Drainer) track(conn net.Conn, state http.ConnState) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch state {
+	case http.StateHijacked, http.StateClosed:
+		delete(d.conns, conn)
+	default:
+		d.conns[conn] = state
+	}
+}
+
+// Active returns the number of connections serving a request
+func (d *Drainer) Active() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	active := 0
+	for _, state := range d.conns {
+		if state == http.StateActive {
+			active++
+		}
+	}
+	return active
+}
+
+// Middleware answers 503 to the requests arriving once the shutdown started, e.g. the next request of a
+// keep-alive connection, and closes their connection so the client retries on another server.
+func (d *Drainer) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if d.draining.Load() {
+				c.Response().Header().Set(echo.HeaderConnection, "close")
+				return c.JSON(http.StatusServiceUnavailable,
+					models.NewErrorResponse(http.StatusServiceUnavailable, "the server is shutting down, retry later"))
+			}
+			return next(c)
+		}
+	}
+}
+
+// Shutdown stops accepting connections and waits up to timeout for the in-flight requests to complete.
+// The connections still active past it are closed, cutting their requests off.
+func (d *Drainer) Shutdown(ctx context.Context, e *echo.Echo, timeout time.Duration) error {
+	d.draining.Store(true)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := e.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	slog.With("active_connections", d.Active()).
+		With("timeout", timeout).
+		Warn("in-flight requests didn't complete in time, closing their connections")
+	return e.Close()
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startDrainedServer serves e on a random port, its requests waiting for release, and returns its URL
+func startDrainedServer(t *testing.T, release <-chan struct{}) (*echo.Echo, *Drainer, string) {
+	t.Helper()
+
+	e := echo.New()
+	e.HideBanner, e.HidePort = true, true
+	d := NewDrainer(e.Server)
+	e.Pre(d.Middleware())
+	e.GET("/slow", func(c echo.Context) error {
+		<-release
+		return c.NoContent(http.StatusOK)
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	e.Listener = ln
+	go func() { _ = e.Start("") }()
+
+	return e, d, "http://" + ln.Addr().String()
+}
+
+// inFlight sends a request in the background and waits until the server is serving it
+func inFlight(t *testing.T, d *Drainer, url string) <-chan error {
+	t.Helper()
+
+	done := make(chan error, 1)
+	go func() {
+		resp, err := http.Get(url + "/slow") //nolint:noctx
+		if err == nil {
+			err = resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				err = echo.NewHTTPError(resp.StatusCode)
+			}
+		}
+		done <- err
+	}()
+	require.Eventually(t, func() bool { return d.Active() == 1 }, time.Second, time.Millisecond)
+	return done
+}
+
+func TestDrainerShutdown(t *testing.T) {
+	t.Parallel()
+
+	t.Run("WaitsForInFlightRequests", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		e, d, url := startDrainedServer(t, release)
+		done := inFlight(t, d, url)
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+		}()
+		require.NoError(t, d.Shutdown(context.Background(), e, 5*time.Second))
+		require.NoError(t, <-done)
+
+		// the listener is closed, no new connection is accepted
+		_, err := http.Get(url + "/slow") //nolint:noctx,bodyclose
+		require.Error(t, err)
+	})
+
+	t.Run("ClosesConnectionsPastTheTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		t.Cleanup(func() { close(release) })
+		e, d, url := startDrainedServer(t, release)
+		done := inFlight(t, d, url)
+
+		start := time.Now()
+		require.NoError(t, d.Shutdown(context.Background(), e, 50*time.Millisecond))
+		assert.Less(t, time.Since(start), 5*time.Second)
+		require.Error(t, <-done, "the request is cut off")
+	})
+}
+
+func TestDrainerRejectsRequestsWhileDraining(t *testing.T) {
+	t.Parallel()
+
+	e := echo.New()
+	d := NewDrainer(e.Server)
+	e.Pre(d.Middleware())
+	e.GET("/users", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	resp := httptest.NewRecorder()
+	e.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/users", http.NoBody))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	d.draining.Store(true)
+	resp = httptest.NewRecorder()
+	e.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/users", http.NoBody))
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+	assert.Equal(t, "close", resp.Header().Get(echo.HeaderConnection))
+}
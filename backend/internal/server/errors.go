@@ -0,0 +1,41 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"user-management/internal/models"
+)
+
+// HTTPErrorHandler renders the errors returned by the handlers and middlewares, e.g. the 404 of an unknown route,
+// as an ErrorResponse like the error bodies the handlers write themselves.
+// The message of an error that isn't an *echo.HTTPError isn't leaked to the client, it's only logged.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	var he *echo.HTTPError
+	if !errors.As(err, &he) {
+		slog.With("error", err).Error("unhandled request error")
+		he = echo.ErrInternalServerError
+	}
+
+	message := http.StatusText(he.Code)
+	if he.Message != nil {
+		message = fmt.Sprint(he.Message)
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(he.Code)
+	} else {
+		err = c.JSON(he.Code, models.NewErrorResponse(he.Code, message))
+	}
+	if err != nil {
+		slog.With("error", err).Error("failed to write the error response")
+	}
+}
@@ -0,0 +1,441 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"user-management/internal/config"
+	"user-management/internal/models"
+	"user-management/internal/server/userpb"
+	"user-management/internal/services"
+	vld "user-management/internal/validator"
+)
+
+// The metadata keys of the gRPC calls, the lowercase headers of the REST API
+const (
+	metadataAPIKey        = "x-api-key"
+	metadataAuthorization = "authorization"
+	metadataActor         = "x-actor"
+
+	maxActorLength = 255
+)
+
+// grpcWriteMethods are the methods requiring the users write role
+var grpcWriteMethods = map[string]bool{
+	userpb.UserService_CreateUser_FullMethodName: true,
+	userpb.UserService_UpdateUser_FullMethodName: true,
+	userpb.UserService_DeleteUser_FullMethodName: true,
+}
+
+// grpcStatuses are the values of the UserStatus enum of the proto
+var grpcStatuses = map[models.UserStatus]userpb.UserStatus{
+	models.UserStatusActive:     userpb.UserStatus_USER_STATUS_ACTIVE,
+	models.UserStatusInactive:   userpb.UserStatus_USER_STATUS_INACTIVE,
+	models.UserStatusTerminated: userpb.UserStatus_USER_STATUS_TERMINATED,
+}
+
+// RegisterGRPC serves the user service over gRPC on the gRPC port alongside the HTTP server, nothing when it's 0
+func RegisterGRPC(lc fx.Lifecycle, cfg *config.Config, userService services.UserService, v echo.Validator) error {
+	if cfg.GRPC.Port == 0 {
+		return nil
+	}
+
+	keys, err := LoadAuthKeys(cfg)
+	if err != nil {
+		return err
+	}
+	s := NewGRPCServer(keys, cfg.Auth.Disabled, cfg.Auth.UsersWriteRole, userService, v)
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			// a taken port fails the start, rather than the server in the background
+			listener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPC.Port))
+			if err != nil {
+				return fmt.Errorf("failed to listen on the gRPC port: %w", err)
+			}
+
+			go func() {
+				if err := s.Serve(listener); err != nil {
+					slog.With("error", err).
+						Error("failed to start the gRPC server")
+				}
+			}()
+			return nil
+		},
+		OnStop: func(c context.Context) error {
+			slog.With("timeout", cfg.HTTP.ShutdownTimeout).
+				Info("Stopping the gRPC server, draining the in-flight calls")
+			return stopGRPC(c, s, cfg.HTTP.ShutdownTimeout)
+		},
+	})
+	return nil
+}
+
+// stopGRPC stops the server, waiting up to timeout for the in-flight calls to complete before cutting them off
+func stopGRPC(ctx context.Context, s *grpc.Server, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		slog.With("timeout", timeout).
+			Warn("in-flight gRPC calls didn't complete in time, closing their connections")
+		s.Stop()
+	}
+	return nil
+}
+
+// NewGRPCServer returns the gRPC server of the user service. Its calls carry the credential of the REST API
+// in their metadata, an x-api-key or an "authorization: Bearer <token>", unless the authentication is disabled,
+// and the writes require the write role as the REST writes do.
+func NewGRPCServer(keys AuthKeys, authDisabled bool, writeRole string, userService services.UserService, v echo.Validator) *grpc.Server {
+	var opts []grpc.ServerOption
+	if !authDisabled {
+		opts = append(opts, grpc.UnaryInterceptor(authInterceptor(newCredentialVerifier(keys), writeRole)))
+	}
+
+	s := grpc.NewServer(opts...)
+	userpb.RegisterUserServiceServer(s, &userServer{userService: userService, validator: v})
+	return s
+}
+
+// authInterceptor rejects the calls without a valid credential with UNAUTHENTICATED,
+// and the writes without the write role with PERMISSION_DENIED
+func authInterceptor(verifier *credentialVerifier, writeRole string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		claims, err := verifier.verify(firstMetadata(md, metadataAPIKey), firstMetadata(md, metadataAuthorization))
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		if grpcWriteMethods[info.FullMethod] {
+			if err := checkClaimsRole(claims, writeRole); err != nil {
+				return nil, status.Error(codes.PermissionDenied, err.Error())
+			}
+		}
+		return handler(actorOf(ctx, claims), req)
+	}
+}
+
+// firstMetadata returns the first value of the metadata key, empty when there's none
+func firstMetadata(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// userServer serves the user CRUD of the REST API, backed by the same service and validation
+type userServer struct {
+	userpb.UnimplementedUserServiceServer
+
+	userService services.UserService
+	validator   echo.Validator
+}
+
+// ListUsers lists a page of users, as GET /users
+func (s *userServer) ListUsers(ctx context.Context, req *userpb.ListUsersRequest) (*userpb.ListUsersResponse, error) {
+	params, err := listParamsOf(req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	users, total, err := s.userService.ListUsers(ctx, params)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &userpb.ListUsersResponse{
+		Users:  make([]*userpb.User, len(users)),
+		Total:  int64(total),
+		Limit:  int32(params.Limit),  //nolint:gosec
+		Offset: int32(params.Offset), //nolint:gosec
+	}
+	for i := range users {
+		resp.Users[i] = protoUser(&users[i])
+	}
+	if services.IsKeysetOrder(params) && len(users) == params.Limit {
+		resp.NextAfterId = users[len(users)-1].UserID
+	}
+	return resp, nil
+}
+
+// listParamsOf reads the list parameters of the request as parseListParams reads the query parameters,
+// every rejected parameter is reported at once in a *services.InvalidParamsError
+func listParamsOf(req *userpb.ListUsersRequest) (models.ListParams, error) {
+	params := models.ListParams{
+		Query:          strings.TrimSpace(req.GetQuery()),
+		Sort:           req.GetSort(),
+		Order:          strings.ToLower(req.GetOrder()),
+		Department:     req.GetDepartment(),
+		DepartmentLike: strings.TrimSpace(req.GetDepartmentLike()),
+		Limit:          models.DefaultListLimit,
+		AfterID:        req.GetAfterId(),
+	}
+
+	var invalid []models.InvalidParam
+
+	if req.GetStatus() != userpb.UserStatus_USER_STATUS_UNSPECIFIED {
+		params.Status = userStatusOf(req.GetStatus())
+		if params.Status == "" {
+			invalid = append(invalid, models.InvalidParam{Name: "status", Reason: "must be one of the UserStatus values"})
+		}
+	}
+
+	switch {
+	case req.GetLimit() < 0:
+		invalid = append(invalid, models.InvalidParam{Name: "limit", Reason: "must be a positive integer"})
+	case req.GetLimit() > 0:
+		params.Limit = min(int(req.GetLimit()), models.MaxListLimit)
+	}
+
+	if req.GetOffset() < 0 {
+		invalid = append(invalid, models.InvalidParam{Name: "offset", Reason: "must be a non-negative integer"})
+	} else {
+		params.Offset = int(req.GetOffset())
+	}
+
+	if req.GetAfterId() < 0 {
+		invalid = append(invalid, models.InvalidParam{Name: "after_id", Reason: "must be the next_after_id of a previous page"})
+	}
+
+	var invalidErr *services.InvalidParamsError
+	if err := services.ValidateListParams(params); errors.As(err, &invalidErr) {
+		invalid = append(invalid, invalidErr.Params...)
+	}
+
+	if len(invalid) > 0 {
+		return params, &services.InvalidParamsError{Params: invalid}
+	}
+	return params, nil
+}
+
+// GetUser returns the user, NOT_FOUND when they don't exist
+func (s *userServer) GetUser(ctx context.Context, req *userpb.GetUserRequest) (*userpb.User, error) {
+	user, err := s.userService.GetUser(ctx, req.GetId())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return protoUser(user), nil
+}
+
+// CreateUser creates the user
+func (s *userServer) CreateUser(ctx context.Context, req *userpb.CreateUserRequest) (*userpb.User, error) {
+	ctx, err := actorContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	create := models.UserCreateRequest{UserCommon: userCommonOf(req.GetUser()), Password: req.GetPassword()}
+	if err := s.validate(create); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userService.CreateUser(ctx, create)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return protoUser(user), nil
+}
+
+// UpdateUser replaces the fields of the user
+func (s *userServer) UpdateUser(ctx context.Context, req *userpb.UpdateUserRequest) (*userpb.User, error) {
+	ctx, err := actorContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	update := models.UserUpdateRequest{UserCommon: userCommonOf(req.GetUser()), Version: req.GetVersion()}
+	if err := s.validate(update); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userService.UpdateUser(ctx, req.GetId(), update)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return protoUser(user), nil
+}
+
+// DeleteUser deletes the user, whose direct reports are first moved to the reassign_to manager
+func (s *userServer) DeleteUser(ctx context.Context, req *userpb.DeleteUserRequest) (*userpb.DeleteUserResponse, error) {
+	ctx, err := actorContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetReassignTo() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid reassign_to: must be a user id")
+	}
+
+	if err := s.userService.DeleteUser(ctx, req.GetId(), req.GetReassignTo()); err != nil {
+		return nil, grpcError(err)
+	}
+	return &userpb.DeleteUserResponse{Deleted: true, Id: req.GetId()}, nil
+}
+
+// actorContext returns the context carrying the x-actor metadata as the actor, as the X-Actor header of the REST writes.
+// It's ignored on an authenticated call, whose actor is the subject of its credential.
+func actorContext(ctx context.Context) (context.Context, error) {
+	if services.ActorAuthenticated(ctx) {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	actor := strings.TrimSpace(firstMetadata(md, metadataActor))
+	if actor == "" {
+		return ctx, nil
+	}
+	if len(actor) > maxActorLength {
+		return ctx, status.Errorf(codes.InvalidArgument, "invalid actor: must be at most %d characters", maxActorLength)
+	}
+	return services.WithActor(ctx, actor), nil
+}
+
+// validate runs the validation rules of the REST requests, a rejected request is INVALID_ARGUMENT
+// with a BadRequest detail listing the rejected fields of the user
+func (s *userServer) validate(req any) error {
+	err := s.validator.Validate(req)
+	if err == nil {
+		return nil
+	}
+
+	st := status.New(codes.InvalidArgument, "the request failed validation")
+
+	var validationErr *vld.Error
+	if errors.As(err, &validationErr) {
+		violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(validationErr.Fields))
+		for _, fe := range validationErr.Fields {
+			field := snakeCase(fe.Field())
+			if !requestFields[field] {
+				field = "user." + field
+			}
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       field,
+				Description: validationErr.Translate(fe),
+			})
+		}
+		if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+			st = detailed
+		}
+	}
+	return st.Err()
+}
+
+// requestFields are the fields of the create and update requests outside of their UserFields
+var requestFields = map[string]bool{"password": true, "version": true}
+
+// snakeCase returns the proto field name of a JSON field name, e.g. user_name for userName
+func snakeCase(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsUpper(r) {
+			b.WriteByte('_')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// grpcError maps the service error to the gRPC status, as userErrorResponse does to the REST one
+func grpcError(err error) error {
+	var departmentErr *services.UnknownDepartmentError
+	var managerErr *services.InvalidManagerError
+	var invalidErr *services.InvalidParamsError
+
+	code := codes.Internal
+	switch {
+	case errors.As(err, &departmentErr), errors.As(err, &managerErr), errors.As(err, &invalidErr),
+		errors.Is(err, services.ErrInvalidStatus), errors.Is(err, services.ErrInvalidPassword):
+		code = codes.InvalidArgument
+	case errors.Is(err, services.ErrUserNotFound):
+		code = codes.NotFound
+	case errors.Is(err, services.ErrUsernameExists), errors.Is(err, services.ErrEmailExists):
+		code = codes.AlreadyExists
+	case errors.Is(err, services.ErrVersionConflict):
+		code = codes.Aborted
+	case errors.Is(err, services.ErrHasReports):
+		code = codes.FailedPrecondition
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	}
+	return status.Error(code, err.Error())
+}
+
+// userStatusOf returns the user status of a UserStatus enum value, the unknown ones are rejected by the validation
+func userStatusOf(value userpb.UserStatus) models.UserStatus {
+	for userStatus, enum := range grpcStatuses {
+		if enum == value {
+			return userStatus
+		}
+	}
+	return ""
+}
+
+// userCommonOf returns the fields common to the create and the update requests
+func userCommonOf(fields *userpb.UserFields) models.UserCommon {
+	common := models.UserCommon{
+		UserName:   fields.GetUserName(),
+		FirstName:  fields.GetFirstName(),
+		LastName:   fields.GetLastName(),
+		Email:      fields.GetEmail(),
+		UserStatus: userStatusOf(fields.GetUserStatus()),
+		Department: fields.GetDepartment(),
+		Phone:      fields.GetPhone(),
+	}
+	if fields != nil && fields.ManagerId != nil {
+		managerID := fields.GetManagerId()
+		common.ManagerID = &managerID
+	}
+	return common
+}
+
+// protoUser returns the proto message of the user
+func protoUser(user *models.User) *userpb.User {
+	msg := &userpb.User{
+		Id:         user.UserID,
+		UserName:   user.UserName,
+		FirstName:  user.FirstName,
+		LastName:   user.LastName,
+		Email:      user.Email,
+		UserStatus: grpcStatuses[user.UserStatus],
+		Department: user.Department,
+		Phone:      user.Phone,
+		ManagerId:  user.ManagerID,
+		CreatedAt:  timestamppb.New(user.CreatedAt),
+		UpdatedAt:  timestamppb.New(user.UpdatedAt),
+		CreatedBy:  user.CreatedBy,
+		UpdatedBy:  user.UpdatedBy,
+		Version:    user.Version,
+	}
+	if user.LastLoginAt != nil {
+		msg.LastLoginAt = timestamppb.New(*user.LastLoginAt)
+	}
+	return msg
+}
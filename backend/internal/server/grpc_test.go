@@ -0,0 +1,228 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"user-management/internal/models"
+	"user-management/internal/server/userpb"
+	"user-management/internal/services"
+	"user-management/internal/validator"
+)
+
+// dialGRPC serves the gRPC server in memory and returns a client of it
+func dialGRPC(t *testing.T, s *grpc.Server) userpb.UserServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = s.Serve(listener) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return userpb.NewUserServiceClient(conn)
+}
+
+func validFields() *userpb.UserFields {
+	return &userpb.UserFields{
+		UserName:   "janedoe",
+		FirstName:  "Jane",
+		LastName:   "Doe",
+		Email:      "jane@example.com",
+		UserStatus: userpb.UserStatus_USER_STATUS_ACTIVE,
+	}
+}
+
+func TestGRPCUserService(t *testing.T) {
+	t.Parallel()
+
+	managerID := int64(1)
+	var actor string
+	svc := &services.UserServiceMock{
+		ListUsersFunc: func(_ context.Context, params models.ListParams) ([]models.User, int, error) {
+			users := make([]models.User, params.Limit)
+			for i := range users {
+				users[i] = models.User{UserID: params.AfterID + int64(i) + 1, UserCommon: models.UserCommon{UserStatus: params.Status}}
+			}
+			return users, 10, nil
+		},
+		GetUserFunc: func(_ context.Context, id int64) (*models.User, error) {
+			if id != 1 {
+				return nil, services.ErrUserNotFound
+			}
+			return &models.User{UserID: 1, UserCommon: models.UserCommon{UserName: "janedoe", UserStatus: models.UserStatusInactive}}, nil
+		},
+		CreateUserFunc: func(ctx context.Context, req models.UserCreateRequest) (*models.User, error) {
+			if req.Email == "taken@example.com" {
+				return nil, services.ErrEmailExists
+			}
+			actor = services.ActorFrom(ctx)
+			return &models.User{UserID: 2, UserCommon: req.UserCommon, CreatedBy: actor, Version: 1, CreatedAt: time.Now()}, nil
+		},
+		UpdateUserFunc: func(context.Context, int64, models.UserUpdateRequest) (*models.User, error) {
+			return nil, services.ErrVersionConflict
+		},
+		DeleteUserFunc: func(_ context.Context, id int64, reassignTo int64) error {
+			if reassignTo == 0 {
+				return services.ErrHasReports
+			}
+			if reassignTo == 99 {
+				return &services.InvalidManagerError{ManagerID: 99, Err: services.ErrUnknownManager}
+			}
+			return nil
+		},
+	}
+	client := dialGRPC(t, NewGRPCServer(AuthKeys{}, true, "", svc, validator.NewEchoValidator()))
+	ctx := context.Background()
+
+	t.Run("list", func(t *testing.T) {
+		resp, err := client.ListUsers(ctx, &userpb.ListUsersRequest{Limit: 2, AfterId: 4, Status: userpb.UserStatus_USER_STATUS_ACTIVE})
+		require.NoError(t, err)
+		require.Len(t, resp.GetUsers(), 2)
+		assert.Equal(t, userpb.UserStatus_USER_STATUS_ACTIVE, resp.GetUsers()[0].GetUserStatus())
+		assert.Equal(t, int64(10), resp.GetTotal())
+		assert.Equal(t, int64(6), resp.GetNextAfterId())
+
+		_, err = client.ListUsers(ctx, &userpb.ListUsersRequest{Limit: -1, Sort: "password"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("get", func(t *testing.T) {
+		user, err := client.GetUser(ctx, &userpb.GetUserRequest{Id: 1})
+		require.NoError(t, err)
+		assert.Equal(t, "janedoe", user.GetUserName())
+		assert.Equal(t, userpb.UserStatus_USER_STATUS_INACTIVE, user.GetUserStatus())
+		assert.Nil(t, user.ManagerId)
+
+		_, err = client.GetUser(ctx, &userpb.GetUserRequest{Id: 7})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("create", func(t *testing.T) {
+		fields := validFields()
+		fields.ManagerId = &managerID
+		user, err := client.CreateUser(metadata.AppendToOutgoingContext(ctx, "x-actor", "etl"),
+			&userpb.CreateUserRequest{User: fields, Password: "correct-horse"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), user.GetId())
+		assert.Equal(t, managerID, user.GetManagerId())
+		assert.Equal(t, "etl", user.GetCreatedBy())
+		assert.Equal(t, "etl", actor)
+
+		fields = validFields()
+		fields.Email = "taken@example.com"
+		_, err = client.CreateUser(ctx, &userpb.CreateUserRequest{User: fields})
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		fields := validFields()
+		fields.UserName = "x"
+		_, err := client.CreateUser(ctx, &userpb.CreateUserRequest{User: fields, Password: "short"})
+		st := status.Convert(err)
+		require.Equal(t, codes.InvalidArgument, st.Code())
+		require.Len(t, st.Details(), 1)
+		badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+		require.True(t, ok)
+		var violated []string
+		for _, violation := range badRequest.GetFieldViolations() {
+			violated = append(violated, violation.GetField())
+		}
+		assert.ElementsMatch(t, []string{"user.user_name", "password"}, violated)
+
+		fields = validFields()
+		fields.UserStatus = userpb.UserStatus_USER_STATUS_UNSPECIFIED
+		_, err = client.UpdateUser(ctx, &userpb.UpdateUserRequest{Id: 1, User: fields})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("update and delete", func(t *testing.T) {
+		_, err := client.UpdateUser(ctx, &userpb.UpdateUserRequest{Id: 1, User: validFields(), Version: 3})
+		assert.Equal(t, codes.Aborted, status.Code(err))
+
+		_, err = client.DeleteUser(ctx, &userpb.DeleteUserRequest{Id: 1})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		_, err = client.DeleteUser(ctx, &userpb.DeleteUserRequest{Id: 1, ReassignTo: 99})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		resp, err := client.DeleteUser(ctx, &userpb.DeleteUserRequest{Id: 1, ReassignTo: 3})
+		require.NoError(t, err)
+		assert.True(t, resp.GetDeleted())
+		assert.Equal(t, int64(1), resp.GetId())
+	})
+}
+
+func TestGRPCAuth(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("s3cret")
+	apiKey := sha256.Sum256([]byte("etl-key"))
+	keys := AuthKeys{Secret: secret, APIKeys: [][sha256.Size]byte{apiKey}, APIKeyRoles: []string{"admin"}}
+
+	var actor string
+
+	svc := &services.UserServiceMock{
+		GetUserFunc: func(context.Context, int64) (*models.User, error) {
+			return &models.User{UserID: 1}, nil
+		},
+		DeleteUserFunc: func(ctx context.Context, _ int64, _ int64) error {
+			actor = services.ActorFrom(ctx)
+			return nil
+		},
+	}
+	client := dialGRPC(t, NewGRPCServer(keys, false, "admin", svc, validator.NewEchoValidator()))
+
+	exp := time.Now().Add(time.Hour).Unix()
+	reader := sign(t, jwt.SigningMethodHS256, secret, jwt.MapClaims{"sub": "jane", "exp": exp})
+	admin := sign(t, jwt.SigningMethodHS256, secret, jwt.MapClaims{"sub": "jane", "exp": exp, "roles": []string{"admin"}})
+
+	testCases := []struct {
+		name      string
+		metadata  []string
+		wantGet   codes.Code
+		wantDel   codes.Code
+		wantActor string
+	}{
+		{name: "no credential", wantGet: codes.Unauthenticated, wantDel: codes.Unauthenticated},
+		{name: "invalid token", metadata: []string{"authorization", "Bearer nope"}, wantGet: codes.Unauthenticated, wantDel: codes.Unauthenticated},
+		{name: "invalid API key", metadata: []string{"x-api-key", "other-key"}, wantGet: codes.Unauthenticated, wantDel: codes.Unauthenticated},
+		{name: "token without the role", metadata: []string{"authorization", reader}, wantGet: codes.OK, wantDel: codes.PermissionDenied},
+		{name: "token with the role", metadata: []string{"authorization", admin}, wantGet: codes.OK, wantDel: codes.OK, wantActor: "jane"},
+		{name: "API key", metadata: []string{"x-api-key", "etl-key"}, wantGet: codes.OK, wantDel: codes.OK, wantActor: APIKeySubject},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// the x-actor metadata doesn't override the credential's subject
+			ctx := metadata.AppendToOutgoingContext(context.Background(), append(tc.metadata, "x-actor", "mallory")...)
+
+			_, err := client.GetUser(ctx, &userpb.GetUserRequest{Id: 1})
+			assert.Equal(t, tc.wantGet, status.Code(err))
+
+			_, err = client.DeleteUser(ctx, &userpb.DeleteUserRequest{Id: 1})
+			assert.Equal(t, tc.wantDel, status.Code(err))
+			if tc.wantDel == codes.OK {
+				assert.Equal(t, tc.wantActor, actor)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/time/rate"
+
+	"user-management/internal/handlers"
+	"user-management/internal/models"
+	"user-management/internal/services"
+)
+
+// URLLengthLimit rejects requests whose URI is longer than maxURLLength with 414
+// and requests with a query parameter value longer than maxQueryParamLength with 400.
+// A non-positive limit disables the corresponding check.
+func URLLengthLimit(maxURLLength, maxQueryParamLength int) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			if maxURLLength > 0 && len(req.RequestURI) > maxURLLength {
+				message := fmt.Sprintf("request URI exceeds %d characters", maxURLLength)
+				return c.JSON(http.StatusRequestURITooLong, models.NewErrorResponse(http.StatusRequestURITooLong, message))
+			}
+
+			if maxQueryParamLength > 0 {
+				for name, values := range req.URL.Query() {
+					for _, value := range values {
+						if len(value) > maxQueryParamLength {
+							message := fmt.Sprintf("query parameter %q exceeds %d characters", name, maxQueryParamLength)
+							return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, message))
+						}
+					}
+				}
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// CacheControl advertises the stale-while-revalidate caching of successful responses to the client only, they
+// depend on the caller's credentials so shared caches must not keep them. The NDJSON streams aren't cached,
+// a zero maxAge leaves the responses untouched.
+func CacheControl(maxAge, staleWhileRevalidate time.Duration) echo.MiddlewareFunc {
+	value := fmt.Sprintf("private, max-age=%d, stale-while-revalidate=%d", int(maxAge.Seconds()), int(staleWhileRevalidate.Seconds()))
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if maxAge <= 0 {
+				return next(c)
+			}
+
+			res := c.Response()
+			res.Before(func() {
+				contentType := res.Header().Get(echo.HeaderContentType)
+				if res.Status == http.StatusOK && !strings.HasPrefix(contentType, handlers.MIMEApplicationNDJSON) {
+					res.Header().Set(echo.HeaderCacheControl, value)
+				}
+			})
+			return next(c)
+		}
+	}
+}
+
+// Compress gzips the responses of at least minLength bytes for the clients accepting it (Accept-Encoding),
+// at the given level (-1 for the default). /metrics is left alone, the Prometheus handler compresses on its own.
+func Compress(level, minLength int) echo.MiddlewareFunc {
+	return middleware.GzipWithConfig(middleware.GzipConfig{
+		Level:     level,
+		MinLength: minLength,
+		Skipper: func(c echo.Context) bool {
+			return c.Path() == "/metrics"
+		},
+	})
+}
+
+// RateLimit allows every client IP limit requests per window, in bursts of up to burst requests (limit when
+// not positive). The client IP is the one of echo's IP extractor. Requests over the limit get 429 with
+// a Retry-After header. A non-positive limit or window disables the rate limit.
+func RateLimit(limit, burst int, window time.Duration) echo.MiddlewareFunc {
+	if limit <= 0 || window <= 0 {
+		return func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+	}
+	if burst <= 0 {
+		burst = limit
+	}
+
+	// a client is let through again once its next token is issued
+	every := window / time.Duration(limit)
+	retryAfter := strconv.Itoa(max(1, int(math.Ceil(every.Seconds()))))
+
+	store := middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+		Rate:  rate.Every(every),
+		Burst: burst,
+		// idle clients are forgotten once their bucket is full again
+		ExpiresIn: max(3*time.Minute, window),
+	})
+
+	return middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		Store: store,
+		IdentifierExtractor: func(c echo.Context) (string, error) {
+			return c.RealIP(), nil
+		},
+		DenyHandler: func(c echo.Context, _ string, _ error) error {
+			c.Response().Header().Set(echo.HeaderRetryAfter, retryAfter)
+			return c.JSON(http.StatusTooManyRequests, models.NewErrorResponse(http.StatusTooManyRequests, "rate limit exceeded, retry later"))
+		},
+	})
+}
+
+// AdminAuth rejects with 401 the requests whose "Authorization: Bearer <token>" header doesn't carry the admin token.
+func AdminAuth(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			auth := c.Request().Header.Get(echo.HeaderAuthorization)
+			key, ok := strings.CutPrefix(auth, "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(key), []byte(token)) != 1 {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+				return c.JSON(http.StatusUnauthorized, models.NewErrorResponse(http.StatusUnauthorized, "invalid or missing admin token"))
+			}
+			return next(c)
+		}
+	}
+}
+
+// validRequestID matches the request IDs taken from the X-Request-ID header of the incoming requests:
+// up to 128 characters of the ones of UUIDs, base64 and the generated IDs, so they're safe to log and echo back
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:+/=-]{1,128}$`)
+
+// RequestID identifies every request with the X-Request-ID header of the incoming request, e.g. set by a gateway,
+// or a generated ID when it's missing or invalid. The ID is set on the response, on the request for the request logs,
+// and on the request context for the services to pass it on (see services.RequestIDFrom).
+func RequestID() echo.MiddlewareFunc {
+	requestID := middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+		RequestIDHandler: func(c echo.Context, id string) {
+			req := c.Request()
+			req.Header.Set(echo.HeaderXRequestID, id)
+			c.SetRequest(req.WithContext(services.WithRequestID(req.Context(), id)))
+		},
+	})
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		next = requestID(next)
+		return func(c echo.Context) error {
+			header := c.Request().Header
+			if id := header.Get(echo.HeaderXRequestID); id != "" && !validRequestID.MatchString(id) {
+				header.Del(echo.HeaderXRequestID)
+			}
+			return next(c)
+		}
+	}
+}
+
+// ResponseController sets the controller of the connection's response writer in the echo context under
+// handlers.ResponseControllerKey, for the streams to extend their write deadline past the writers the middlewares
+// wrap it in. It has to come before any of them.
+func ResponseController() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(handlers.ResponseControllerKey, http.NewResponseController(c.Response().Writer))
+			return next(c)
+		}
+	}
+}
+
+// RequestIDInErrors copies the request ID (the X-Request-ID response header set by the RequestID middleware)
+// into the JSON object bodies of the error responses as "requestId", so a reported error can be matched
+// with the server logs. Errors returned by the handlers are rendered by the error handler here, so they're covered too.
+// The encoded bodies (Content-Encoding) are passed through, the ID is added before compressing them by an instance
+// registered after Compress.
+func RequestIDInErrors() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			res := c.Response()
+			w := &errorBodyWriter{ResponseWriter: res.Writer, header: res.Header()}
+			res.Writer = w
+			defer func() { res.Writer = w.ResponseWriter }()
+
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			return w.flush(res.Header().Get(echo.HeaderXRequestID))
+		}
+	}
+}
+
+// errorBodyWriter holds back the JSON error responses until flush, the others are passed through
+type errorBodyWriter struct {
+	http.ResponseWriter
+
+	header http.Header
+	status int
+	body   []byte
+}
+
+func (w *errorBodyWriter) WriteHeader(status int) {
+	if status >= http.StatusBadRequest && strings.HasPrefix(w.header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) &&
+		w.header.Get(echo.HeaderContentEncoding) == "" {
+		w.status = status
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *errorBodyWriter) Write(b []byte) (int, error) {
+	if w.status != 0 {
+		w.body = append(w.body, b...)
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush flushes the streamed responses, e.g. the NDJSON user lists, nothing is sent of a held back error before flush
+func (w *errorBodyWriter) Flush() {
+	if w.status != 0 {
+		return
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. to extend the write deadline of a stream
+func (w *errorBodyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// flush writes the held back error response, with the request ID added when the body is a JSON object
+func (w *errorBodyWriter) flush(requestID string) error {
+	if w.status == 0 {
+		return nil
+	}
+
+	body := w.body
+	var fields map[string]json.RawMessage
+	if requestID != "" && json.Unmarshal(body, &fields) == nil && fields != nil {
+		if _, ok := fields["requestId"]; !ok {
+			fields["requestId"], _ = json.Marshal(requestID)
+			if withID, err := json.Marshal(fields); err == nil {
+				body = append(withID, '\n')
+			}
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	_, err := w.ResponseWriter.Write(body)
+	return err
+}
+
+// corsExposedHeaders are the response headers of the API the browsers let the cross-origin scripts read,
+// on top of the CORS-safelisted ones (e.g. Content-Type)
+var corsExposedHeaders = []string{
+	echo.HeaderXRequestID, echo.HeaderRetryAfter, echo.HeaderLocation, handlers.HeaderETag,
+	handlers.HeaderResourceAction, handlers.HeaderServerTime, handlers.HeaderPreferenceApplied, handlers.HeaderDryRun,
+}
+
+// CORS lets the browsers call the API from the allowed origins, with the allowed methods.
+// Without any origin the CORS headers aren't sent, so the browsers refuse the cross-origin requests.
+func CORS(origins, methods []string, allowCredentials bool) echo.MiddlewareFunc {
+	if len(origins) == 0 {
+		return func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+	}
+	return middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOrigins:     origins,
+		AllowMethods:     methods,
+		AllowCredentials: allowCredentials,
+		ExposeHeaders:    corsExposedHeaders,
+		// the preflight responses are cached for 10 minutes
+		MaxAge: 600,
+	})
+}
+
+// SecurityHeaders sets the security headers of every response, an empty value leaving its header out.
+// Strict-Transport-Security is only sent over HTTPS (or behind a proxy forwarding it), a zero max age leaves it out.
+func SecurityHeaders(contentTypeOptions, frameOptions, referrerPolicy string, hstsMaxAge time.Duration, hstsIncludeSubdomains bool) echo.MiddlewareFunc {
+	return middleware.SecureWithConfig(middleware.SecureConfig{
+		ContentTypeNosniff:    contentTypeOptions,
+		XFrameOptions:         frameOptions,
+		ReferrerPolicy:        referrerPolicy,
+		HSTSMaxAge:            int(hstsMaxAge.Seconds()),
+		HSTSExcludeSubdomains: !hstsIncludeSubdomains,
+	})
+}
+
+// Recover turns a panic of a handler into a 500 ErrorResponse, the panic value and the stack being logged
+// with the request ID rather than sent to the client.
+func Recover() echo.MiddlewareFunc {
+	return middleware.RecoverWithConfig(middleware.RecoverConfig{
+		// the stack of the panicking goroutine only
+		DisableStackAll: true,
+		LogErrorFunc: func(c echo.Context, err error, stack []byte) error {
+			slog.With("request_id", c.Response().Header().Get(echo.HeaderXRequestID)).
+				With("method", c.Request().Method).
+				With("path", c.Request().URL.Path).
+				With("panic", err.Error()).
+				With("stack", string(stack)).
+				Error("recovered from a panic")
+			return echo.ErrInternalServerError.WithInternal(err)
+		},
+	})
+}
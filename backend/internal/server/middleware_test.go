@@ -0,0 +1,514 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-management/internal/handlers"
+	"user-management/internal/models"
+	"user-management/internal/services"
+)
+
+func TestURLLengthLimit(t *testing.T) {
+	t.Parallel()
+
+	e := echo.New()
+	e.Pre(URLLengthLimit(100, 20))
+	e.GET("/users", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	testCases := []struct {
+		name     string
+		target   string
+		expected int
+	}{
+		{"Within Limits", "/users?q=john", http.StatusOK},
+		{"Parameter At Limit", "/users?q=" + strings.Repeat("a", 20), http.StatusOK},
+		{"Parameter Over Limit", "/users?q=" + strings.Repeat("a", 21), http.StatusBadRequest},
+		{"Repeated Parameter Over Limit", "/users?q=a&q=" + strings.Repeat("a", 21), http.StatusBadRequest},
+		{"URI Over Limit", "/users?" + strings.Repeat("a=1&", 30), http.StatusRequestURITooLong},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tc.target, http.NoBody)
+			resp := httptest.NewRecorder()
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expected, resp.Code)
+		})
+	}
+}
+
+func TestURLLengthLimitDisabled(t *testing.T) {
+	t.Parallel()
+
+	e := echo.New()
+	e.Pre(URLLengthLimit(0, 0))
+	e.GET("/users", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users?q="+strings.Repeat("a", 10000), http.NoBody)
+	resp := httptest.NewRecorder()
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestCacheControl(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(maxAge time.Duration) *echo.Echo {
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error {
+			if c.QueryParam("fail") != "" {
+				return c.NoContent(http.StatusBadRequest)
+			}
+			if c.QueryParam("stream") != "" {
+				return c.Blob(http.StatusOK, handlers.MIMEApplicationNDJSON, []byte("{}\n"))
+			}
+			return c.NoContent(http.StatusOK)
+		}, CacheControl(maxAge, 30*time.Second))
+		return e
+	}
+
+	serve := func(e *echo.Echo, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	enabled := newServer(10 * time.Second)
+	assert.Equal(t, "private, max-age=10, stale-while-revalidate=30", serve(enabled, "/users").Header().Get(echo.HeaderCacheControl))
+	assert.Empty(t, serve(enabled, "/users?fail=1").Header().Get(echo.HeaderCacheControl))
+	assert.Empty(t, serve(enabled, "/users?stream=1").Header().Get(echo.HeaderCacheControl))
+
+	disabled := newServer(0)
+	assert.Empty(t, serve(disabled, "/users").Header().Get(echo.HeaderCacheControl))
+}
+
+func TestAdminAuth(t *testing.T) {
+	t.Parallel()
+
+	e := echo.New()
+	e.POST("/admin", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, AdminAuth("s3cret"))
+
+	testCases := []struct {
+		name          string
+		authorization string
+		expected      int
+	}{
+		{"Valid Token", "Bearer s3cret", http.StatusOK},
+		{"Missing Header", "", http.StatusUnauthorized},
+		{"Wrong Token", "Bearer guess", http.StatusUnauthorized},
+		{"Token Prefix", "Bearer s3cre", http.StatusUnauthorized},
+		{"Wrong Scheme", "Basic s3cret", http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, "/admin", http.NoBody)
+			if tc.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tc.authorization)
+			}
+			resp := httptest.NewRecorder()
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expected, resp.Code)
+		})
+	}
+}
+
+func TestRequestIDInErrors(t *testing.T) {
+	t.Parallel()
+
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler
+	e.Pre(middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+		Generator: func() string { return "req-1" },
+	}), RequestIDInErrors())
+	e.GET("/invalid", func(c echo.Context) error {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid request"))
+	})
+	e.GET("/internal", func(echo.Context) error {
+		return errors.New("connection refused")
+	})
+	e.GET("/text", func(c echo.Context) error {
+		return c.String(http.StatusInternalServerError, "boom")
+	})
+	e.GET("/ok", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"status": "OK"})
+	})
+
+	testCases := []struct {
+		name         string
+		target       string
+		expectedCode int
+		expectedBody string
+	}{
+		{"Handler Error", "/invalid", http.StatusBadRequest, `{"code":"bad_request","message":"invalid request","requestId":"req-1"}`},
+		{"Returned Error", "/missing", http.StatusNotFound, `{"code":"not_found","message":"Not Found","requestId":"req-1"}`},
+		{"Internal Error Hidden", "/internal", http.StatusInternalServerError, `{"code":"internal_server_error","message":"Internal Server Error","requestId":"req-1"}`},
+		{"Success Untouched", "/ok", http.StatusOK, `{"status":"OK"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := httptest.NewRecorder()
+			e.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, tc.target, http.NoBody))
+
+			assert.Equal(t, tc.expectedCode, resp.Code)
+			assert.Equal(t, "req-1", resp.Header().Get(echo.HeaderXRequestID))
+			assert.JSONEq(t, tc.expectedBody, resp.Body.String())
+		})
+	}
+
+	t.Run("Non-JSON Error Untouched", func(t *testing.T) {
+		t.Parallel()
+
+		resp := httptest.NewRecorder()
+		e.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/text", http.NoBody))
+
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+		assert.Equal(t, "boom", resp.Body.String())
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(trustProxy bool) *echo.Echo {
+		e := echo.New()
+		e.IPExtractor = echo.ExtractIPDirect()
+		if trustProxy {
+			e.IPExtractor = echo.ExtractIPFromXFFHeader()
+		}
+		// 2 requests a minute in bursts of 3
+		e.GET("/users", func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		}, RateLimit(2, 3, time.Minute))
+		return e
+	}
+
+	serve := func(e *echo.Echo, remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/users", http.NoBody)
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Limits Each Client On Its Own", func(t *testing.T) {
+		t.Parallel()
+		e := newServer(false)
+
+		for range 3 {
+			assert.Equal(t, http.StatusOK, serve(e, "203.0.113.1:1234", "").Code)
+		}
+		denied := serve(e, "203.0.113.1:1234", "")
+		assert.Equal(t, http.StatusTooManyRequests, denied.Code)
+		assert.Equal(t, "30", denied.Header().Get(echo.HeaderRetryAfter))
+		assert.JSONEq(t, `{"code":"too_many_requests","message":"rate limit exceeded, retry later"}`, denied.Body.String())
+
+		assert.Equal(t, http.StatusOK, serve(e, "203.0.113.2:1234", "").Code, "another client isn't starved")
+	})
+
+	t.Run("Ignores X-Forwarded-For Unless Trusted", func(t *testing.T) {
+		t.Parallel()
+		e := newServer(false)
+
+		for i := range 3 {
+			assert.Equal(t, http.StatusOK, serve(e, "203.0.113.1:1234", fmt.Sprintf("198.51.100.%d", i)).Code)
+		}
+		assert.Equal(t, http.StatusTooManyRequests, serve(e, "203.0.113.1:1234", "198.51.100.9").Code)
+	})
+
+	t.Run("Keys On X-Forwarded-For Behind A Proxy", func(t *testing.T) {
+		t.Parallel()
+		e := newServer(true)
+
+		for range 3 {
+			assert.Equal(t, http.StatusOK, serve(e, "10.0.0.1:1234", "198.51.100.1").Code)
+		}
+		assert.Equal(t, http.StatusTooManyRequests, serve(e, "10.0.0.1:1234", "198.51.100.1").Code)
+		assert.Equal(t, http.StatusOK, serve(e, "10.0.0.1:1234", "198.51.100.2").Code, "same proxy, another client")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		}, RateLimit(0, 0, time.Second))
+
+		for range 10 {
+			assert.Equal(t, http.StatusOK, serve(e, "203.0.113.1:1234", "").Code)
+		}
+	})
+}
+
+func TestCompress(t *testing.T) {
+	t.Parallel()
+
+	users := make([]models.User, 200)
+	for i := range users {
+		users[i] = models.User{UserID: int64(i + 1), UserCommon: models.UserCommon{
+			UserName: fmt.Sprintf("user%d", i), Email: fmt.Sprintf("user%d@example.com", i),
+		}}
+	}
+
+	e := echo.New()
+	e.Use(Compress(-1, 1024))
+	e.GET("/users", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, users)
+	})
+	e.GET("/users/1", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, users[0])
+	})
+	// stands for the Prometheus handler, which compresses on its own
+	e.GET("/metrics", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentEncoding, "gzip")
+		return c.Blob(http.StatusOK, "text/plain", []byte(strings.Repeat("already compressed", 100)))
+	})
+
+	serve := func(target, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, http.NoBody)
+		if acceptEncoding != "" {
+			req.Header.Set(echo.HeaderAcceptEncoding, acceptEncoding)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Large List Compressed", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serve("/users", "gzip, deflate, br")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "gzip", rec.Header().Get(echo.HeaderContentEncoding))
+
+		reader, err := gzip.NewReader(rec.Body)
+		require.NoError(t, err)
+		var decoded []models.User
+		require.NoError(t, json.NewDecoder(reader).Decode(&decoded))
+		assert.Len(t, decoded, len(users))
+	})
+
+	t.Run("Not Accepted", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serve("/users", "")
+		assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding))
+		assert.True(t, json.Valid(rec.Body.Bytes()))
+	})
+
+	t.Run("Short Response Uncompressed", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serve("/users/1", "gzip")
+		assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding))
+		assert.True(t, json.Valid(rec.Body.Bytes()))
+	})
+
+	t.Run("Metrics Not Compressed Twice", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serve("/metrics", "gzip")
+		assert.Equal(t, "gzip", rec.Header().Get(echo.HeaderContentEncoding))
+		assert.Equal(t, strings.Repeat("already compressed", 100), rec.Body.String())
+	})
+}
+
+func TestCORS(t *testing.T) {
+	t.Parallel()
+
+	newEcho := func(origins []string) *echo.Echo {
+		e := echo.New()
+		e.Use(CORS(origins, []string{http.MethodGet, http.MethodPost}, false))
+		e.GET("/users", func(c echo.Context) error {
+			c.Response().Header().Set(echo.HeaderXRequestID, "abc")
+			return c.NoContent(http.StatusOK)
+		})
+		return e
+	}
+	request := func(e *echo.Echo, method, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/users", http.NoBody)
+		req.Header.Set(echo.HeaderOrigin, origin)
+		if method == http.MethodOptions {
+			req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodPost)
+		}
+		resp := httptest.NewRecorder()
+		e.ServeHTTP(resp, req)
+		return resp
+	}
+
+	t.Run("RefusesEveryOriginByDefault", func(t *testing.T) {
+		t.Parallel()
+
+		resp := request(newEcho(nil), http.MethodGet, "https://evil.example.com")
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Empty(t, resp.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	})
+
+	t.Run("AllowsTheListedOrigins", func(t *testing.T) {
+		t.Parallel()
+
+		e := newEcho([]string{"https://app.example.com"})
+
+		resp := request(e, http.MethodGet, "https://app.example.com")
+		assert.Equal(t, "https://app.example.com", resp.Header().Get(echo.HeaderAccessControlAllowOrigin))
+		assert.Contains(t, resp.Header().Get(echo.HeaderAccessControlExposeHeaders), echo.HeaderXRequestID)
+
+		preflight := request(e, http.MethodOptions, "https://app.example.com")
+		assert.Equal(t, http.StatusNoContent, preflight.Code)
+		assert.Equal(t, "GET,POST", preflight.Header().Get(echo.HeaderAccessControlAllowMethods))
+
+		other := request(e, http.MethodGet, "https://evil.example.com")
+		assert.Empty(t, other.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	})
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	t.Parallel()
+
+	e := echo.New()
+	e.Pre(SecurityHeaders("nosniff", "DENY", "no-referrer", 24*time.Hour, false))
+	e.GET("/users", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	resp := httptest.NewRecorder()
+	e.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/users", http.NoBody))
+	assert.Equal(t, "nosniff", resp.Header().Get(echo.HeaderXContentTypeOptions))
+	assert.Equal(t, "DENY", resp.Header().Get(echo.HeaderXFrameOptions))
+	assert.Equal(t, "no-referrer", resp.Header().Get(echo.HeaderReferrerPolicy))
+	assert.Empty(t, resp.Header().Get(echo.HeaderStrictTransportSecurity), "only sent over HTTPS")
+
+	// the unknown routes are covered too
+	req := httptest.NewRequest(http.MethodGet, "/missing", http.NoBody)
+	req.TLS = &tls.ConnectionState{}
+	resp = httptest.NewRecorder()
+	e.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Equal(t, "nosniff", resp.Header().Get(echo.HeaderXContentTypeOptions))
+	assert.Equal(t, "max-age=86400", resp.Header().Get(echo.HeaderStrictTransportSecurity))
+
+	// an empty value leaves its header out
+	e = echo.New()
+	e.Pre(SecurityHeaders("", "", "", 0, false))
+	req = httptest.NewRequest(http.MethodGet, "/users", http.NoBody)
+	req.TLS = &tls.ConnectionState{}
+	resp = httptest.NewRecorder()
+	e.ServeHTTP(resp, req)
+	for _, header := range []string{
+		echo.HeaderXContentTypeOptions, echo.HeaderXFrameOptions, echo.HeaderReferrerPolicy, echo.HeaderStrictTransportSecurity,
+	} {
+		assert.Empty(t, resp.Header().Get(header), header)
+	}
+}
+
+// not parallel, it swaps the default logger
+func TestRecover(t *testing.T) {
+	var out bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&out, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler
+	e.Pre(middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+		Generator: func() string { return "req-1" },
+	}), RequestIDInErrors())
+	e.Use(Recover())
+	e.GET("/panic", func(echo.Context) error {
+		panic("secret-token leaked in a panic")
+	})
+
+	resp := httptest.NewRecorder()
+	e.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/panic", http.NoBody))
+
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.JSONEq(t, `{"code":"internal_server_error","message":"Internal Server Error","requestId":"req-1"}`, resp.Body.String())
+
+	var entry map[string]any
+	require.NoError(t, json.NewDecoder(&out).Decode(&entry), "the panic is logged first")
+	assert.Equal(t, "ERROR", entry["level"])
+	assert.Equal(t, "recovered from a panic", entry["msg"])
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Equal(t, "/panic", entry["path"])
+	assert.Equal(t, "secret-token leaked in a panic", entry["panic"])
+	assert.Contains(t, entry["stack"], "TestRecover")
+}
+
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+
+	e := echo.New()
+	e.Pre(RequestID())
+	e.GET("/users", func(c echo.Context) error {
+		// the request logs read the request header, the services the context
+		return c.String(http.StatusOK, c.Request().Header.Get(echo.HeaderXRequestID)+" "+services.RequestIDFrom(c.Request().Context()))
+	})
+
+	testCases := []struct {
+		name     string
+		incoming string
+		reused   bool
+	}{
+		{"Reused", "3f2a9c1e-4b7d-4e8a-9f10-2c3d4e5f6a7b", true},
+		{"Missing", "", false},
+		{"Too Long", strings.Repeat("a", 129), false},
+		{"Invalid Characters", "req 1\"<script>", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/users", http.NoBody)
+			if tc.incoming != "" {
+				req.Header.Set(echo.HeaderXRequestID, tc.incoming)
+			}
+			resp := httptest.NewRecorder()
+			e.ServeHTTP(resp, req)
+
+			id := resp.Header().Get(echo.HeaderXRequestID)
+			if tc.reused {
+				assert.Equal(t, tc.incoming, id)
+			} else {
+				assert.Len(t, id, 32, "generated")
+			}
+			assert.Equal(t, id+" "+id, resp.Body.String())
+		})
+	}
+}
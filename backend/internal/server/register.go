@@ -1,36 +1,107 @@
 package server
 
 import (
-	"net/http"
+	"strings"
 	"user-management/internal/config"
 	"user-management/internal/handlers"
+	"user-management/internal/metrics"
 
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
-	"golang.org/x/time/rate"
 )
 
-// NewRegister will setup the middlewares request endpoint handlers and inject the necessary deps
-func NewRegister(e *echo.Echo, cfg *config.Config, userHandler *handlers.UserHandler, hc *handlers.Healthcheck) {
-	// Register validator
-	e.GET("/ping", func(c echo.Context) error {
-		return c.String(http.StatusOK, "pong")
-	})
+// APIBasePath prefixes the API routes, the @BasePath of the Swagger spec in cmd/rest/main.go has to match it
+const APIBasePath = "/api/v1"
+
+// NewRegister will setup the middlewares request endpoint handlers and inject the necessary deps.
+//
+// Only the /api/v1 group is rate limited and authenticated. The operational endpoints are registered on the root,
+// outside of any rate limited group, so probes and scrapers are never throttled nor asked for a token:
+// /metrics, /ping, /status, /version, /healthz, /readyz and /swagger.
+func NewRegister(
+	e *echo.Echo, cfg *config.Config, userHandler *handlers.UserHandler, departmentHandler *handlers.DepartmentHandler,
+	authHandler *handlers.AuthHandler, graphQLHandler *handlers.GraphQLHandler, hc *handlers.Healthcheck, m *metrics.Metrics,
+) error {
+	keys, err := LoadAuthKeys(cfg)
+	if err != nil {
+		return err
+	}
+
+	// request count and duration of every route, including the ones below
+	e.Use(m.Middleware())
+
+	// exempt from the rate limit, keep them out of the v1 group
+	e.GET("/metrics", m.Handler())
+	e.GET("/ping", hc.Ping)
 	e.GET("/status", hc.GetAPIStatus)
+	e.GET("/version", hc.GetVersion)
+	// Kubernetes probes, /status is meant for humans
+	e.GET("/healthz", hc.Liveness)
+	e.GET("/readyz", hc.Readiness)
 
-	v1 := e.Group("/api/v1")
+	v1 := e.Group(APIBasePath)
 	{ //nolint:gocritic,unused
-		// limit the application to 100 requests/sec TBD
-		v1.Use(middleware.RateLimiter(middleware.NewRateLimiterMemoryStore(rate.Limit(cfg.HTTP.RateLimit))))
+		// every client IP is limited on its own, so a noisy client doesn't starve the others
+		v1.Use(RateLimit(cfg.HTTP.RateLimit, cfg.HTTP.RateLimitBurst, cfg.HTTP.RateLimitWindow))
+		if !cfg.Auth.Disabled {
+			// the admin endpoints are authenticated by the admin token, carried by the same header,
+			// and the refresh token is the credential of the auth endpoints, whose access token may have expired
+			v1.Use(Authenticate(keys, func(c echo.Context) bool {
+				return strings.HasPrefix(c.Path(), APIBasePath+"/admin/") || strings.HasPrefix(c.Path(), APIBasePath+"/auth/")
+			}))
+		}
+
+		// the write role of each route group is only checked on the tokens of an authenticated API
+		usersWriteRole, departmentsWriteRole := cfg.Auth.UsersWriteRole, cfg.Auth.DepartmentsWriteRole
+		if cfg.Auth.Disabled {
+			usersWriteRole, departmentsWriteRole = "", ""
+		}
 
 		// Routes
-		v1.GET("/users", userHandler.ListUsers)
-		v1.POST("/users", userHandler.CreateUser)
-		v1.GET("/users/:id", userHandler.GetUser)
-		v1.PUT("/users/:id", userHandler.UpdateUser)
-		v1.DELETE("/users/:id", userHandler.DeleteUser)
+		users := v1.Group("/users", RequireRole(usersWriteRole))
+		users.GET("", userHandler.ListUsers, CacheControl(cfg.Cache.ListTTL, cfg.Cache.ListMaxStale))
+		users.GET("/count", userHandler.CountUsers)
+		users.GET("/availability", userHandler.CheckAvailability,
+			RateLimit(cfg.HTTP.AvailabilityRateLimit, 0, cfg.HTTP.AvailabilityRateLimitWindow))
+		users.POST("", userHandler.CreateUser)
+		users.POST("/batch", userHandler.CreateUsers)
+		users.PUT("/batch", userHandler.UpdateUsers)
+		users.POST("/status", userHandler.UpdateUsersStatus)
+		users.GET("/:id", userHandler.GetUser)
+		users.HEAD("/:id", userHandler.HeadUser)
+		users.GET("/:id/history", userHandler.GetUserHistory)
+		users.GET("/:id/reports", userHandler.GetUserReports)
+		users.PUT("/:id", userHandler.UpdateUser)
+		users.DELETE("/:id", userHandler.DeleteUser)
+
+		// the queries are POSTed too, the write role is only checked by the mutations
+		v1.POST("/graphql", graphQLHandler.Handler(func(c echo.Context) error {
+			return CheckRole(c, usersWriteRole)
+		}))
+
+		departments := v1.Group("/departments", RequireRole(departmentsWriteRole))
+		departments.GET("", departmentHandler.ListDepartments)
+		departments.POST("", departmentHandler.CreateDepartment)
+
+		// the access tokens are signed with the JWT secret, the auth endpoints are only exposed once it's configured
+		if !cfg.Auth.Disabled && cfg.Auth.JWTSecret != "" {
+			auth := v1.Group("/auth")
+			auth.POST("/refresh", authHandler.Refresh)
+			auth.POST("/logout", authHandler.Logout)
+		}
+
+		// admin endpoints are only exposed once an admin token is configured
+		if cfg.HTTP.AdminToken != "" {
+			admin := v1.Group("/admin", AdminAuth(cfg.HTTP.AdminToken))
+			admin.POST("/users/deactivate-stale", userHandler.DeactivateStaleUsers)
+			// the refresh tokens are only of use where the auth endpoints exchange them
+			if !cfg.Auth.Disabled && cfg.Auth.JWTSecret != "" {
+				admin.POST("/tokens", authHandler.IssueRefreshToken)
+			}
+		}
 	}
 
 	// Swagger documentation
 	e.GET("/swagger/*any", handlers.SwaggerHandler())
+
+	return nil
 }
@@ -0,0 +1,438 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+	"github.com/uptrace/bun/migrate"
+
+	"user-management/internal/config"
+	"user-management/internal/handlers"
+	"user-management/internal/metrics"
+	"user-management/internal/migrations"
+	"user-management/internal/models"
+	"user-management/internal/repository"
+	"user-management/internal/services"
+	"user-management/internal/validator"
+)
+
+func TestRateLimitExemptions(t *testing.T) {
+	t.Parallel()
+
+	sqldb, err := sql.Open(sqliteshim.ShimName, "file::memory:")
+	require.NoError(t, err)
+	sqldb.SetMaxOpenConns(1)
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	t.Cleanup(func() { _ = db.Close() })
+
+	// the migrations don't run on SQLite, they are only recorded as applied for the instance to be ready
+	migrator := migrate.NewMigrator(db, migrations.Migrations)
+	require.NoError(t, migrator.Init(context.Background()))
+	_, err = migrator.Migrate(context.Background(), migrate.WithNopMigration())
+	require.NoError(t, err)
+
+	// a single request a minute
+	cfg := &config.Config{}
+	cfg.HTTP.RateLimit = 1
+	cfg.HTTP.RateLimitWindow = time.Minute
+	cfg.Auth.Disabled = true
+
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	require.NoError(t, NewRegister(e, cfg, handlers.NewUserHandler(nil), handlers.NewDepartmentHandler(nil), nil, nil, handlers.NewHealthcheckHandler(services.NewHealthcheck(db, cfg)), metrics.New()))
+
+	serve := func(target string) int {
+		req := httptest.NewRequest(http.MethodGet, target, http.NoBody)
+		req.RemoteAddr = "203.0.113.1:1234"
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for _, target := range []string{"/readyz", "/healthz", "/version", "/metrics", "/ping", "/status"} {
+		for range 50 {
+			require.NotEqual(t, http.StatusTooManyRequests, serve(target), target)
+		}
+	}
+	assert.Equal(t, http.StatusOK, serve("/readyz"))
+
+	// the API is still limited for the same client
+	assert.Equal(t, http.StatusNotFound, serve("/api/v1/unknown"))
+	assert.Equal(t, http.StatusTooManyRequests, serve("/api/v1/unknown"))
+}
+
+func TestAuthExemptions(t *testing.T) {
+	t.Parallel()
+
+	sqldb, err := sql.Open(sqliteshim.ShimName, "file::memory:")
+	require.NoError(t, err)
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	t.Cleanup(func() { _ = db.Close() })
+
+	cfg := &config.Config{}
+	cfg.HTTP.AdminToken = "admin-s3cret"
+	cfg.Auth.JWTSecret = "jwt-s3cret"
+
+	svc := &services.UserServiceMock{
+		DeactivateStaleUsersFunc: func(context.Context, time.Duration, bool) (*models.UserDeactivateStaleResult, error) {
+			return &models.UserDeactivateStaleResult{Users: []models.User{}}, nil
+		},
+	}
+
+	e := echo.New()
+	require.NoError(t, NewRegister(e, cfg, handlers.NewUserHandler(svc), handlers.NewDepartmentHandler(nil), nil, nil, handlers.NewHealthcheckHandler(services.NewHealthcheck(db, cfg)), metrics.New()))
+
+	serve := func(method, target, authorization string) int {
+		req := httptest.NewRequest(method, target, http.NoBody)
+		if authorization != "" {
+			req.Header.Set(echo.HeaderAuthorization, authorization)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for _, target := range []string{"/ping", "/healthz", "/swagger/index.html"} {
+		assert.Equal(t, http.StatusOK, serve(http.MethodGet, target, ""), target)
+	}
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodGet, "/api/v1/users", ""))
+
+	// the admin endpoints take the admin token instead
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/api/v1/admin/users/deactivate-stale", "Bearer admin-s3cret"))
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodPost, "/api/v1/admin/users/deactivate-stale", ""))
+
+	t.Run("Missing Key", func(t *testing.T) {
+		t.Parallel()
+
+		err := NewRegister(echo.New(), &config.Config{}, nil, nil, nil, nil, nil, metrics.New())
+		require.ErrorContains(t, err, "JWT")
+	})
+}
+
+func TestWriteRoles(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{}
+	cfg.Auth.JWTSecret = "jwt-s3cret"
+	cfg.Auth.UsersWriteRole = "admin"
+	cfg.Auth.DepartmentsWriteRole = "hr"
+	apiKey := sha256.Sum256([]byte("etl-key"))
+	cfg.Auth.APIKeyHashes = []string{hex.EncodeToString(apiKey[:])}
+	cfg.Auth.APIKeyRoles = []string{"admin"}
+
+	svc := &services.UserServiceMock{
+		ListUsersFunc: func(context.Context, models.ListParams) ([]models.User, int, error) {
+			return []models.User{}, 0, nil
+		},
+		DeleteUserFunc: func(context.Context, int64, int64) error {
+			return nil
+		},
+	}
+
+	e := echo.New()
+	graphQLHandler := handlers.NewGraphQLHandler(svc, validator.NewEchoValidator())
+	require.NoError(t, NewRegister(e, cfg, handlers.NewUserHandler(svc), handlers.NewDepartmentHandler(nil), nil, graphQLHandler, nil, metrics.New()))
+
+	token := func(roles ...string) string {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub":   "jane",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"roles": roles,
+		}).SignedString([]byte("jwt-s3cret"))
+		require.NoError(t, err)
+		return "Bearer " + signed
+	}
+	serve := func(method, target, authorization string) int {
+		req := httptest.NewRequest(method, target, http.NoBody)
+		req.Header.Set(echo.HeaderAuthorization, authorization)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// any authenticated user reads
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/api/v1/users", token()))
+
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodDelete, "/api/v1/users/1", token("hr")))
+	assert.Equal(t, http.StatusAccepted, serve(http.MethodDelete, "/api/v1/users/1", token("admin")))
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, "/api/v1/users/status", token()))
+
+	// each group has its own role
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, "/api/v1/departments", token("admin")))
+
+	// the GraphQL queries are POSTed too, only the mutations need the role
+	graphQL := func(query, authorization string) string {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/graphql", strings.NewReader(`{"query": "`+query+`"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(echo.HeaderAuthorization, authorization)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		return rec.Body.String()
+	}
+	assert.JSONEq(t, `{"data": {"users": {"total": 0}}}`, graphQL("{ users { total } }", token()))
+	assert.Contains(t, graphQL("mutation { deleteUser(id: 1) { deleted } }", token("hr")), "the admin role is required")
+	assert.JSONEq(t, `{"data": {"deleteUser": {"deleted": true}}}`, graphQL("mutation { deleteUser(id: 1) { deleted } }", token("admin")))
+
+	// the API key clients get the configured roles
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/users/1", http.NoBody)
+	req.Header.Set(HeaderAPIKey, "etl-key")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+}
+
+func TestWriteActor(t *testing.T) {
+	t.Parallel()
+
+	apiKey := sha256.Sum256([]byte("etl-key"))
+	var actor string
+	svc := &services.UserServiceMock{
+		DeleteUserFunc: func(ctx context.Context, _ int64, _ int64) error {
+			actor = services.ActorFrom(ctx)
+			return nil
+		},
+	}
+
+	newServer := func(disabled bool) *echo.Echo {
+		cfg := &config.Config{}
+		cfg.Auth.Disabled = disabled
+		cfg.Auth.JWTSecret = "jwt-s3cret"
+		cfg.Auth.APIKeyHashes = []string{hex.EncodeToString(apiKey[:])}
+
+		e := echo.New()
+		require.NoError(t, NewRegister(e, cfg, handlers.NewUserHandler(svc), handlers.NewDepartmentHandler(nil), nil, nil, nil, metrics.New()))
+		return e
+	}
+	authenticated, open := newServer(false), newServer(true)
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "jane",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("jwt-s3cret"))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		srv      *echo.Echo
+		header   string
+		value    string
+		expected string
+	}{
+		{name: "token", srv: authenticated, header: echo.HeaderAuthorization, value: "Bearer " + signed, expected: "jane"},
+		{name: "API key", srv: authenticated, header: HeaderAPIKey, value: "etl-key", expected: APIKeySubject},
+		{name: "authentication disabled", srv: open, expected: "mallory"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// the X-Actor header of an authenticated request doesn't override the credential's subject
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/users/1", http.NoBody)
+			req.Header.Set(handlers.HeaderActor, "mallory")
+			if tc.header != "" {
+				req.Header.Set(tc.header, tc.value)
+			}
+			rec := httptest.NewRecorder()
+			tc.srv.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusAccepted, rec.Code)
+			assert.Equal(t, tc.expected, actor)
+		})
+	}
+}
+
+func TestRefreshTokens(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{}
+	cfg.Auth.JWTSecret = "jwt-s3cret"
+	cfg.Auth.AccessTokenTTL = time.Minute
+	cfg.Auth.RefreshTokenTTL = time.Hour
+	cfg.HTTP.AdminToken = "admin-s3cret"
+
+	tokens := services.NewTokenService(repository.NewInMemoryUserRepository(), cfg)
+	svc := &services.UserServiceMock{
+		ListUsersFunc: func(context.Context, models.ListParams) ([]models.User, int, error) {
+			return []models.User{}, 0, nil
+		},
+	}
+
+	e := echo.New()
+	e.Validator = validator.NewEchoValidator()
+	require.NoError(t, NewRegister(e, cfg, handlers.NewUserHandler(svc), handlers.NewDepartmentHandler(nil), handlers.NewAuthHandler(tokens), nil, nil, metrics.New()))
+
+	serveAs := func(authorization, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if authorization != "" {
+			req.Header.Set(echo.HeaderAuthorization, authorization)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	serve := func(target, body string) *httptest.ResponseRecorder {
+		return serveAs("", target, body)
+	}
+
+	// the admin token issues the refresh tokens
+	issueBody := `{"subject":"jane","roles":["admin"]}`
+	assert.Equal(t, http.StatusUnauthorized, serve("/api/v1/admin/tokens", issueBody).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, serveAs("Bearer admin-s3cret", "/api/v1/admin/tokens", `{"roles":["admin"]}`).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, serveAs("Bearer admin-s3cret", "/api/v1/admin/tokens", `{"subject":"jane","roles":["a b"]}`).Code)
+	rec := serveAs("Bearer admin-s3cret", "/api/v1/admin/tokens", issueBody)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "no-store", rec.Header().Get(echo.HeaderCacheControl))
+	var issued models.RefreshTokenIssueResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &issued))
+	require.NotEmpty(t, issued.RefreshToken)
+	body := `{"refreshToken":"` + issued.RefreshToken + `"}`
+
+	// the auth endpoints don't take an access token
+	rec = serve("/api/v1/auth/refresh", body)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp models.AccessTokenResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "Bearer", resp.TokenType)
+	assert.Equal(t, 60, resp.ExpiresIn)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users", http.NoBody)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+resp.AccessToken)
+	listRec := httptest.NewRecorder()
+	e.ServeHTTP(listRec, req)
+	assert.Equal(t, http.StatusOK, listRec.Code, "the access token is accepted")
+
+	claims, err := newCredentialVerifier(AuthKeys{Secret: []byte("jwt-s3cret")}).verify("", "Bearer "+resp.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "jane", claims["sub"])
+	assert.Equal(t, []string{"admin"}, claimsRoles(claims), "the roles of the issued refresh token")
+
+	assert.Equal(t, http.StatusNoContent, serve("/api/v1/auth/logout", body).Code)
+	assert.Equal(t, http.StatusNoContent, serve("/api/v1/auth/logout", body).Code, "already revoked")
+
+	rec = serve("/api/v1/auth/refresh", body)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "revoked")
+	assert.JSONEq(t, `{"code":"unauthorized","message":"invalid refresh token"}`, rec.Body.String())
+	assert.Equal(t, http.StatusUnauthorized, serve("/api/v1/auth/refresh", `{"refreshToken":"guess"}`).Code)
+	assert.Equal(t, http.StatusUnauthorized, serve("/api/v1/auth/logout", `{"refreshToken":"guess"}`).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, serve("/api/v1/auth/refresh", `{}`).Code)
+
+	t.Run("Without Secret", func(t *testing.T) {
+		t.Parallel()
+
+		apiKey := sha256.Sum256([]byte("etl-key"))
+		cfg := &config.Config{}
+		cfg.Auth.APIKeyHashes = []string{hex.EncodeToString(apiKey[:])}
+		cfg.HTTP.AdminToken = "admin-s3cret"
+		e := echo.New()
+		require.NoError(t, NewRegister(e, cfg, nil, nil, handlers.NewAuthHandler(tokens), nil, nil, metrics.New()))
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", strings.NewReader(body))
+		req.Header.Set(HeaderAPIKey, "etl-key")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code, "no access token to sign")
+
+		req = httptest.NewRequest(http.MethodPost, "/api/v1/admin/tokens", strings.NewReader(`{"subject":"jane"}`))
+		req.Header.Set(echo.HeaderAuthorization, "Bearer admin-s3cret")
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code, "no refresh token to exchange")
+	})
+}
+
+func TestAvailabilityRateLimit(t *testing.T) {
+	t.Parallel()
+
+	// the availability limit is stricter than the API's
+	cfg := &config.Config{}
+	cfg.HTTP.RateLimit = 100
+	cfg.HTTP.RateLimitWindow = time.Minute
+	cfg.HTTP.AvailabilityRateLimit = 2
+	cfg.HTTP.AvailabilityRateLimitWindow = time.Minute
+	cfg.Auth.Disabled = true
+
+	svc := &services.UserServiceMock{
+		CheckAvailabilityFunc: func(context.Context, string, string) (*models.UserAvailabilityResponse, error) {
+			return &models.UserAvailabilityResponse{}, nil
+		},
+		CountUsersFunc: func(context.Context, models.ListParams) (*models.UserCountResponse, error) {
+			return &models.UserCountResponse{}, nil
+		},
+	}
+
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	require.NoError(t, NewRegister(e, cfg, handlers.NewUserHandler(svc), handlers.NewDepartmentHandler(nil), nil, nil, nil, metrics.New()))
+
+	serve := func(target, ip string) int {
+		req := httptest.NewRequest(http.MethodGet, target, http.NoBody)
+		req.RemoteAddr = ip + ":1234"
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve("/api/v1/users/availability?email=jane@example.com", "203.0.113.1"))
+	assert.Equal(t, http.StatusOK, serve("/api/v1/users/availability?username=jane", "203.0.113.1"))
+	assert.Equal(t, http.StatusTooManyRequests, serve("/api/v1/users/availability?username=john", "203.0.113.1"))
+
+	// the other endpoints and clients have their own limits
+	assert.Equal(t, http.StatusOK, serve("/api/v1/users/count", "203.0.113.1"))
+	assert.Equal(t, http.StatusOK, serve("/api/v1/users/availability?username=john", "203.0.113.2"))
+}
+
+func TestSwaggerBasePath(t *testing.T) {
+	t.Parallel()
+
+	// every optional route group enabled
+	cfg := &config.Config{}
+	cfg.HTTP.AdminToken = "admin-s3cret"
+	cfg.Auth.JWTSecret = "jwt-s3cret"
+
+	e := echo.New()
+	require.NoError(t, NewRegister(
+		e, cfg, handlers.NewUserHandler(nil), handlers.NewDepartmentHandler(nil), handlers.NewAuthHandler(nil),
+		nil, handlers.NewHealthcheckHandler(nil), metrics.New(),
+	))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/swagger/doc.json", http.NoBody))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var spec struct {
+		BasePath string                    `json:"basePath"`
+		Paths    map[string]map[string]any `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &spec))
+	assert.Equal(t, APIBasePath, spec.BasePath, "Try it out in the Swagger UI calls the routes under the basePath")
+
+	// every documented operation is served under the basePath, "{id}" being ":id" for echo,
+	// the operational endpoints are documented as /../../<path> to step out of it
+	routes := map[string]bool{}
+	for _, route := range e.Routes() {
+		routes[route.Method+" "+route.Path] = true
+	}
+	params := strings.NewReplacer("{", ":", "}", "")
+	for documented, operations := range spec.Paths {
+		for method := range operations {
+			route := strings.ToUpper(method) + " " + path.Clean(spec.BasePath+params.Replace(documented))
+			assert.True(t, routes[route], "%s is documented but not registered", route)
+		}
+	}
+}
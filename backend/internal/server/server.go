@@ -2,13 +2,17 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"time"
 
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
 	slogecho "github.com/samber/slog-echo"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 
 	"user-management/internal/config"
@@ -16,30 +20,95 @@ import (
 )
 
 // NewServer returns a pointer to Server
-func NewServer(lc fx.Lifecycle, cfg *config.Config, h services.Healthcheck, v echo.Validator) *echo.Echo {
+func NewServer(lc fx.Lifecycle, cfg *config.Config, h services.Healthcheck, v echo.Validator, tp trace.TracerProvider) *echo.Echo {
 	e := echo.New()
 
+	// echo serves HTTPS with a server of its own
+	for _, s := range []*http.Server{e.Server, e.TLSServer} {
+		s.ReadTimeout = cfg.HTTP.ReadTimeout
+		s.ReadHeaderTimeout = cfg.HTTP.ReadHeaderTimeout
+		s.WriteTimeout = cfg.HTTP.WriteTimeout
+		s.IdleTimeout = cfg.HTTP.IdleTimeout
+	}
+
 	e.Validator = v
-	e.Use(slogecho.New(slog.Default()))
-	e.Use(middleware.Recover())
-	e.Use(middleware.CORS())
+	e.HTTPErrorHandler = HTTPErrorHandler
+	// the client IP (e.g. the rate limit key) is the peer address unless it's a trusted proxy
+	e.IPExtractor = echo.ExtractIPDirect()
+	if cfg.HTTP.TrustProxy {
+		e.IPExtractor = echo.ExtractIPFromXFFHeader()
+	}
+	// the request ID comes first, so every response (including the rejected ones) and log line carries it,
+	// only the response controller is taken before, from the writer of the connection
+	e.Pre(ResponseController(), RequestID(), RequestIDInErrors())
+	e.Pre(SecurityHeaders(cfg.HTTP.ContentTypeOptions, cfg.HTTP.FrameOptions, cfg.HTTP.ReferrerPolicy,
+		cfg.HTTP.HSTSMaxAge, cfg.HTTP.HSTSIncludeSubdomains))
+	drainer := NewDrainer(e.Server, e.TLSServer)
+	e.Pre(drainer.Middleware())
+	e.Pre(URLLengthLimit(cfg.HTTP.MaxURLLength, cfg.HTTP.MaxQueryParamLength))
+	// the request span is the parent of the service and repository spans
+	e.Use(otelecho.Middleware(config.AppName, otelecho.WithTracerProvider(tp)))
+	// the request logs carry the trace, continued from the traceparent header of the incoming request
+	e.Use(slogecho.NewWithConfig(slog.Default(), slogecho.Config{
+		DefaultLevel:     slog.LevelInfo,
+		ClientErrorLevel: slog.LevelWarn,
+		ServerErrorLevel: slog.LevelError,
+		WithRequestID:    true,
+		WithTraceID:      true,
+		WithSpanID:       true,
+	}))
+	e.Use(Recover())
+	e.Use(CORS(cfg.HTTP.CORSAllowedOrigins, cfg.HTTP.CORSAllowedMethods, cfg.HTTP.CORSAllowCredentials))
+	// the handlers' errors get the request ID before they're compressed, the first RequestIDInErrors passes them through
+	e.Use(Compress(cfg.HTTP.GzipLevel, cfg.HTTP.GzipMinLength), RequestIDInErrors())
+
+	redirect := NewHTTPSRedirect(cfg)
 
 	lc.Append(fx.Hook{
 		OnStart: func(context.Context) error {
+			tlsEnabled := cfg.HTTP.TLSCert != ""
+			if tlsEnabled {
+				// a broken certificate fails the start, rather than the listener in the background
+				if _, err := tls.LoadX509KeyPair(cfg.HTTP.TLSCert, cfg.HTTP.TLSKey); err != nil {
+					return fmt.Errorf("invalid TLS certificate or key: %w", err)
+				}
+			}
+
 			h.SetOnlineSince(time.Now())
 
 			go func() {
-				err := e.Start(fmt.Sprintf(":%d", cfg.HTTP.Port))
-				if err != nil {
+				var err error
+				address := fmt.Sprintf(":%d", cfg.HTTP.Port)
+				if tlsEnabled {
+					err = e.StartTLS(address, cfg.HTTP.TLSCert, cfg.HTTP.TLSKey)
+				} else {
+					err = e.Start(address)
+				}
+				if err != nil && !errors.Is(err, http.ErrServerClosed) {
 					slog.With("error", err).
 						Error("failed to start server")
 				}
 			}()
+
+			if redirect != nil {
+				go func() {
+					if err := redirect.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+						slog.With("error", err).
+							Error("failed to start the HTTPS redirect")
+					}
+				}()
+			}
 			return nil
 		},
 		OnStop: func(c context.Context) error {
-			slog.Info("Stopping server")
-			return e.Shutdown(c)
+			if redirect != nil {
+				// the redirects are answered at once, there's nothing to drain
+				_ = redirect.Close()
+			}
+
+			slog.With("timeout", cfg.HTTP.ShutdownTimeout).
+				Info("Stopping server, draining the in-flight requests")
+			return drainer.Shutdown(c, e, cfg.HTTP.ShutdownTimeout)
 		},
 	})
 
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/fx/fxtest"
+
+	"user-management/internal/config"
+	"user-management/internal/handlers"
+	"user-management/internal/metrics"
+	"user-management/internal/models"
+	"user-management/internal/services"
+)
+
+func TestServerTimeouts(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{}
+	cfg.HTTP.ReadTimeout = 15 * time.Second
+	cfg.HTTP.ReadHeaderTimeout = 50 * time.Millisecond
+	cfg.HTTP.WriteTimeout = 30 * time.Second
+	cfg.HTTP.IdleTimeout = 2 * time.Minute
+
+	e := NewServer(fxtest.NewLifecycle(t), cfg, nil, nil, noop.NewTracerProvider())
+	assert.Equal(t, 15*time.Second, e.Server.ReadTimeout)
+	assert.Equal(t, 50*time.Millisecond, e.Server.ReadHeaderTimeout)
+	assert.Equal(t, 30*time.Second, e.Server.WriteTimeout)
+	assert.Equal(t, 2*time.Minute, e.Server.IdleTimeout)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	e.Listener = ln
+	e.HideBanner, e.HidePort = true, true
+	go func() { _ = e.Start("") }()
+	t.Cleanup(func() { _ = e.Close() })
+
+	// a client trickling its headers in (slowloris) is disconnected past the header timeout
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	_, err = conn.Write([]byte("GET /healthz HTTP/1.1\r\nHost: localhost\r\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = bufio.NewReader(conn).ReadString('\n')
+	require.Error(t, err, "the connection is closed before the headers are complete")
+	var netErr net.Error
+	assert.False(t, errors.As(err, &netErr) && netErr.Timeout(), "closed by the server, not by the client deadline")
+}
+
+func TestServerStreamsNDJSON(t *testing.T) {
+	t.Parallel()
+
+	// more users than a flush, through the middleware chain of the server, pausing past the write timeout
+	// at every flush until the client got the users sent so far
+	const count = 250
+	writeTimeout := 100 * time.Millisecond
+	received := make(chan struct{})
+	svc := &services.UserServiceMock{
+		StreamUsersFunc: func(_ context.Context, _ models.ListParams, fn func(user *models.User) error) error {
+			for id := range int64(count) {
+				if id > 0 && id%100 == 0 {
+					select {
+					case <-received:
+					case <-time.After(5 * time.Second):
+						return errors.New("the flushed users didn't reach the client")
+					}
+					time.Sleep(2 * writeTimeout)
+				}
+				if err := fn(&models.User{UserID: id + 1}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	cfg := &config.Config{}
+	cfg.Auth.Disabled = true
+	e := NewServer(fxtest.NewLifecycle(t), cfg, nil, nil, noop.NewTracerProvider())
+	require.NoError(t, NewRegister(e, cfg, handlers.NewUserHandler(svc), handlers.NewDepartmentHandler(nil), nil, nil,
+		handlers.NewHealthcheckHandler(nil), metrics.New()))
+	srv := httptest.NewUnstartedServer(e)
+	srv.Config.WriteTimeout = writeTimeout
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	for _, encoding := range []string{"", "gzip"} {
+		t.Run("encoding "+encoding, func(t *testing.T) {
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+APIBasePath+"/users", http.NoBody)
+			require.NoError(t, err)
+			req.Header.Set(echo.HeaderAccept, handlers.MIMEApplicationNDJSON)
+			if encoding != "" {
+				req.Header.Set(echo.HeaderAcceptEncoding, encoding)
+			}
+
+			// the transport doesn't ask for gzip on its own, nor decompress
+			client := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = resp.Body.Close() })
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, encoding, resp.Header.Get(echo.HeaderContentEncoding))
+
+			var body io.Reader = resp.Body
+			if encoding != "" {
+				body, err = gzip.NewReader(resp.Body)
+				require.NoError(t, err)
+			}
+
+			decoder := json.NewDecoder(body)
+			lines := 0
+			for {
+				var user models.User
+				err := decoder.Decode(&user)
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				require.NoError(t, err)
+				lines++
+				assert.Equal(t, int64(lines), user.UserID)
+				if lines%100 == 0 {
+					select {
+					case received <- struct{}{}:
+					case <-time.After(5 * time.Second):
+						t.Fatal("the stream didn't wait for the client")
+					}
+				}
+			}
+			assert.Equal(t, count, lines)
+		})
+	}
+}
+
+func TestServerRequestIDInCompressedErrors(t *testing.T) {
+	t.Parallel()
+
+	e := NewServer(fxtest.NewLifecycle(t), &config.Config{}, nil, nil, noop.NewTracerProvider())
+	e.GET("/invalid", func(c echo.Context) error {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(http.StatusBadRequest, "invalid request"))
+	})
+	e.GET("/missing", func(echo.Context) error {
+		return echo.ErrNotFound
+	})
+
+	for _, target := range []string{"/invalid", "/missing"} {
+		for _, encoding := range []string{"", "gzip"} {
+			t.Run(target+" encoding "+encoding, func(t *testing.T) {
+				t.Parallel()
+
+				req := httptest.NewRequest(http.MethodGet, target, nil)
+				if encoding != "" {
+					req.Header.Set(echo.HeaderAcceptEncoding, encoding)
+				}
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, req)
+				require.GreaterOrEqual(t, rec.Code, http.StatusBadRequest)
+				assert.Equal(t, encoding, rec.Header().Get(echo.HeaderContentEncoding))
+
+				var body io.Reader = rec.Body
+				if encoding != "" {
+					var err error
+					body, err = gzip.NewReader(rec.Body)
+					require.NoError(t, err)
+				}
+				var fields map[string]any
+				require.NoError(t, json.NewDecoder(body).Decode(&fields))
+				assert.NotEmpty(t, rec.Header().Get(echo.HeaderXRequestID))
+				assert.Equal(t, rec.Header().Get(echo.HeaderXRequestID), fields["requestId"])
+			})
+		}
+	}
+}
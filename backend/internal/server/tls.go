@@ -0,0 +1,48 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"user-management/internal/config"
+)
+
+// httpsPort is the default port of HTTPS, left out of the redirect URLs
+const httpsPort = 443
+
+// NewHTTPSRedirect returns the plain HTTP server of the redirect port, redirecting every request to the same URL
+// over HTTPS on the server port. It's nil unless TLS and the redirect port are configured.
+func NewHTTPSRedirect(cfg *config.Config) *http.Server {
+	if cfg.HTTP.TLSCert == "" || cfg.HTTP.RedirectPort == 0 {
+		return nil
+	}
+	return &http.Server{
+		Addr:              ":" + strconv.Itoa(cfg.HTTP.RedirectPort),
+		Handler:           RedirectToHTTPS(cfg.HTTP.Port),
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+	}
+}
+
+// RedirectToHTTPS permanently redirects the requests to the same host, path and query over HTTPS on the port.
+// 308 keeps the method and the body, unlike 301.
+func RedirectToHTTPS(port int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		host = strings.Trim(host, "[]")
+
+		switch {
+		case port != httpsPort:
+			host = net.JoinHostPort(host, strconv.Itoa(port))
+		case strings.Contains(host, ":"):
+			// an IPv6 address, bracketed in URLs
+			host = "[" + host + "]"
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/fx/fxtest"
+
+	"user-management/internal/config"
+	"user-management/internal/services"
+)
+
+// writeSelfSignedCert writes a certificate for 127.0.0.1 and its key in dir and returns their paths
+func writeSelfSignedCert(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func TestServerTLS(t *testing.T) {
+	t.Parallel()
+
+	sqldb, err := sql.Open(sqliteshim.ShimName, "file::memory:")
+	require.NoError(t, err)
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	t.Cleanup(func() { _ = db.Close() })
+
+	cfg := &config.Config{}
+	cfg.HTTP.TLSCert, cfg.HTTP.TLSKey = writeSelfSignedCert(t, t.TempDir())
+	cfg.HTTP.ShutdownTimeout = 5 * time.Second
+
+	lc := fxtest.NewLifecycle(t)
+	e := NewServer(lc, cfg, services.NewHealthcheck(db, cfg), nil, noop.NewTracerProvider())
+	e.HideBanner, e.HidePort = true, true
+	started, release := make(chan struct{}), make(chan struct{})
+	e.GET("/slow", func(c echo.Context) error {
+		close(started)
+		<-release
+		return c.NoContent(http.StatusOK)
+	})
+
+	lc.RequireStart()
+	require.Eventually(t, func() bool { return e.TLSListenerAddr() != nil }, time.Second, time.Millisecond)
+	url := "https://" + e.TLSListenerAddr().String()
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}} //nolint:gosec // self-signed
+	// a request in flight completes during the graceful shutdown
+	done := make(chan int, 1)
+	go func() {
+		resp, err := client.Get(url + "/slow") //nolint:noctx
+		if err != nil {
+			done <- 0
+			return
+		}
+		_ = resp.Body.Close()
+		done <- resp.StatusCode
+	}()
+	<-started
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+	}()
+	require.NoError(t, lc.Stop(context.Background()))
+	assert.Equal(t, http.StatusOK, <-done)
+}
+
+func TestRedirectToHTTPS(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		port     int
+		host     string
+		expected string
+	}{
+		{"Other Port", 8443, "example.com:8081", "https://example.com:8443/api/v1/users?limit=10"},
+		{"Default Port", 443, "example.com", "https://example.com/api/v1/users?limit=10"},
+		{"IPv6", 443, "[::1]:8081", "https://[::1]/api/v1/users?limit=10"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/users?limit=10", http.NoBody)
+			req.Host = tc.host
+			resp := httptest.NewRecorder()
+			RedirectToHTTPS(tc.port).ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusPermanentRedirect, resp.Code)
+			assert.Equal(t, tc.expected, resp.Header().Get("Location"))
+		})
+	}
+}
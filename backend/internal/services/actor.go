@@ -0,0 +1,36 @@
+package services
+
+import "context"
+
+// SystemActor is recorded as the author of the changes made without an actor, e.g. by the CLI
+const SystemActor = "system"
+
+type (
+	actorKey              struct{}
+	authenticatedActorKey struct{}
+)
+
+// WithActor sets who makes the changes, recorded as the createdBy/updatedBy of the written users
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// WithAuthenticatedActor sets the subject of the request's credential as who makes the changes,
+// the actor named by the client then doesn't override it (see ActorAuthenticated)
+func WithAuthenticatedActor(ctx context.Context, subject string) context.Context {
+	return context.WithValue(WithActor(ctx, subject), authenticatedActorKey{}, true)
+}
+
+// ActorAuthenticated reports whether the actor was set from the request's credential by WithAuthenticatedActor
+func ActorAuthenticated(ctx context.Context) bool {
+	authenticated, _ := ctx.Value(authenticatedActorKey{}).(bool)
+	return authenticated
+}
+
+// ActorFrom returns the actor set by WithActor, SystemActor when there's none
+func ActorFrom(ctx context.Context) string {
+	if actor, _ := ctx.Value(actorKey{}).(string); actor != "" {
+		return actor
+	}
+	return SystemActor
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"user-management/internal/models"
+	"user-management/internal/repository"
+)
+
+// recordAudit records the change of a user in the audit log of repo, so in the transaction of the change.
+// before is nil for a create, after is nil for a delete.
+func recordAudit(
+	ctx context.Context, repo repository.UserRepository, action models.AuditAction, userID int64, before, after *models.User,
+) error {
+	return repo.Audit().Record(ctx, &models.AuditEntry{
+		UserID:    userID,
+		Action:    action,
+		Actor:     ActorFrom(ctx),
+		CreatedAt: time.Now(),
+		Diff:      diffUsers(before, after),
+	})
+}
+
+// diffUsers returns the user fields, by their JSON key, that differ between before and after
+func diffUsers(before, after *models.User) map[string]models.AuditChange {
+	oldFields, newFields := userFields(before), userFields(after)
+
+	diff := make(map[string]models.AuditChange)
+	for key, value := range oldFields {
+		if !reflect.DeepEqual(value, newFields[key]) {
+			diff[key] = models.AuditChange{Old: value, New: newFields[key]}
+		}
+	}
+	for key, value := range newFields {
+		if _, ok := oldFields[key]; !ok {
+			diff[key] = models.AuditChange{New: value}
+		}
+	}
+	return diff
+}
+
+// userFields returns the user's editable fields by their JSON key, nil for a nil user
+func userFields(user *models.User) map[string]any {
+	if user == nil {
+		return nil
+	}
+
+	// UserCommon only holds strings and an ID, so the round trip can't fail
+	data, _ := json.Marshal(user.UserCommon)
+	var fields map[string]any
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
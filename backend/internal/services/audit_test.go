@@ -0,0 +1,59 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"user-management/internal/models"
+)
+
+func TestDiffUsers(t *testing.T) {
+	t.Parallel()
+
+	before := &models.User{UserID: 1, UserCommon: models.UserCommon{
+		UserName: "johndoe", FirstName: "John", LastName: "Doe", Email: "john@example.com", UserStatus: models.UserStatusActive,
+	}}
+	after := *before
+	after.Email = "johnny@example.com"
+	after.Department = "Sales"
+	// not an editable field
+	after.Version = 2
+
+	testCases := []struct {
+		name     string
+		before   *models.User
+		after    *models.User
+		expected map[string]models.AuditChange
+	}{
+		{"Update", before, &after, map[string]models.AuditChange{
+			"email":      {Old: "john@example.com", New: "johnny@example.com"},
+			"department": {Old: "", New: "Sales"},
+		}},
+		{"No Change", before, before, map[string]models.AuditChange{}},
+		{"Create", nil, before, map[string]models.AuditChange{
+			"userName":   {New: "johndoe"},
+			"firstName":  {New: "John"},
+			"lastName":   {New: "Doe"},
+			"email":      {New: "john@example.com"},
+			"userStatus": {New: "A"},
+			"department": {New: ""},
+		}},
+		{"Delete", before, nil, map[string]models.AuditChange{
+			"userName":   {Old: "johndoe"},
+			"firstName":  {Old: "John"},
+			"lastName":   {Old: "Doe"},
+			"email":      {Old: "john@example.com"},
+			"userStatus": {Old: "A"},
+			"department": {Old: ""},
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, diffUsers(tc.before, tc.after))
+		})
+	}
+}
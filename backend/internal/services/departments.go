@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"user-management/internal/models"
+	"user-management/internal/repository"
+)
+
+// ErrDepartmentExists is returned when the department name is taken
+var ErrDepartmentExists = errors.New("department already exists")
+
+// DepartmentService manages the departments the users can belong to
+type DepartmentService interface {
+	ListDepartments(ctx context.Context) ([]models.Department, error)
+	CreateDepartment(ctx context.Context, req models.DepartmentCreateRequest) (*models.Department, error)
+}
+
+type departmentService struct {
+	repo repository.UserRepository
+}
+
+// NewDepartmentService creates a new department service.
+func NewDepartmentService(repo repository.UserRepository) DepartmentService {
+	return &departmentService{repo: repo}
+}
+
+// ListDepartments returns every department, ordered by name
+func (s *departmentService) ListDepartments(ctx context.Context) ([]models.Department, error) {
+	return s.repo.Departments().List(ctx)
+}
+
+// CreateDepartment returns ErrDepartmentExists when the name is taken
+func (s *departmentService) CreateDepartment(ctx context.Context, req models.DepartmentCreateRequest) (*models.Department, error) {
+	department := &models.Department{Name: req.Name}
+
+	var violation *repository.UniqueViolationError
+	if err := s.repo.Departments().Create(ctx, department); errors.As(err, &violation) {
+		return nil, ErrDepartmentExists
+	} else if err != nil {
+		return nil, err
+	}
+	return department, nil
+}
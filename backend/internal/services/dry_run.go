@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"user-management/internal/repository"
+)
+
+type dryRunKey struct{}
+
+// errDryRun rolls back the transaction of a dry run, it never leaves the service
+var errDryRun = errors.New("dry run")
+
+// WithDryRun marks the context so the write operations run every check and return
+// the would-be result, but roll back instead of persisting anything.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// IsDryRun reports whether the context was marked by WithDryRun
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// runInTx runs fn in a transaction, which is rolled back on a dry run even when fn succeeds
+func (s *userService) runInTx(ctx context.Context, fn func(ctx context.Context, repo repository.UserRepository) error) error {
+	err := s.repo.RunInTx(ctx, func(ctx context.Context, repo repository.UserRepository) error {
+		if err := fn(ctx, repo); err != nil {
+			return err
+		}
+		if IsDryRun(ctx) {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		return nil
+	}
+	return err
+}
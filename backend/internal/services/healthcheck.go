@@ -3,17 +3,44 @@ package services
 import (
 	"context"
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
 
 	"runtime"
 	"time"
+
+	"user-management/internal/config"
+	"user-management/internal/migrations"
 )
 
+// RuntimeStats are the Go runtime figures of the health report, enough for a quick check without a profiler
+type RuntimeStats struct {
+	NumGoroutine int
+	// NumGC is the number of completed GC cycles
+	NumGC uint32
+	// HeapInuse is the bytes of the in-use heap spans
+	HeapInuse uint64
+	// Sys is the bytes of memory obtained from the OS
+	Sys uint64
+}
+
 // Healthcheck interface define functions
 // that returns the database connection status
 // last time the sync was done and the system status
 type Healthcheck interface {
 	DatabaseReady() (bool, error)
+	// DatabaseReadyWithin is DatabaseReady giving up after timeout, for probes that have to fail fast
+	DatabaseReadyWithin(timeout time.Duration) (bool, error)
 	GetMemUsage() uint64
+	// GetRuntimeStats returns the goroutine count and the GC and heap stats of the Go runtime
+	GetRuntimeStats() RuntimeStats
+
+	// ClockSkew returns how far the database clock is ahead of the app clock (negative when behind),
+	// and whether it's within the configured threshold
+	ClockSkew() (time.Duration, bool, error)
+
+	// PendingMigrations returns the number of built-in migrations not applied to the database yet,
+	// giving up after timeout. It fails when the database was never migrated, without the migrations table.
+	PendingMigrations(timeout time.Duration) (int, error)
 
 	SetOnlineSince(time.Time)
 	OnlineSince() time.Duration
@@ -22,17 +49,34 @@ type Healthcheck interface {
 type hc struct {
 	onlineSince time.Time
 	db          *bun.DB
+
+	maxClockSkew time.Duration
+	// dbNow reads the database clock, swapped in tests
+	dbNow func(ctx context.Context) (time.Time, error)
+	now   func() time.Time
+	// migrationsWithStatus reads which migrations are applied, swapped in tests
+	migrationsWithStatus func(ctx context.Context) (migrate.MigrationSlice, error)
 }
 
 // NewHealthcheck returns an implementation of Healthcheck interface
-func NewHealthcheck(db *bun.DB) Healthcheck {
-	return &hc{
-		db: db,
+func NewHealthcheck(db *bun.DB, cfg *config.Config) Healthcheck {
+	h := &hc{
+		db:           db,
+		maxClockSkew: cfg.DB.MaxClockSkew,
+		now:          time.Now,
 	}
+	h.dbNow = h.queryDBNow
+	h.migrationsWithStatus = migrate.NewMigrator(db, migrations.Migrations).MigrationsWithStatus
+
+	return h
 }
 
 func (h *hc) DatabaseReady() (bool, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	return h.DatabaseReadyWithin(30 * time.Second)
+}
+
+func (h *hc) DatabaseReadyWithin(timeout time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	if err := h.db.PingContext(ctx); err != nil {
@@ -49,6 +93,53 @@ func (h *hc) GetMemUsage() uint64 {
 	return m.Alloc
 }
 
+func (h *hc) GetRuntimeStats() RuntimeStats {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	return RuntimeStats{
+		NumGoroutine: runtime.NumGoroutine(),
+		NumGC:        m.NumGC,
+		HeapInuse:    m.HeapInuse,
+		Sys:          m.Sys,
+	}
+}
+
+func (h *hc) ClockSkew() (time.Duration, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	before := h.now()
+	dbTime, err := h.dbNow(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	after := h.now()
+
+	// compare against the middle of the round trip, the query latency isn't skew
+	skew := dbTime.Sub(before.Add(after.Sub(before) / 2))
+
+	within := h.maxClockSkew <= 0 || (skew <= h.maxClockSkew && skew >= -h.maxClockSkew)
+	return skew, within, nil
+}
+
+func (h *hc) queryDBNow(ctx context.Context) (time.Time, error) {
+	var now time.Time
+	err := h.db.NewRaw("SELECT CURRENT_TIMESTAMP").Scan(ctx, &now)
+	return now, err
+}
+
+func (h *hc) PendingMigrations(timeout time.Duration) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ms, err := h.migrationsWithStatus(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return len(ms.Unapplied()), nil
+}
+
 func (h *hc) SetOnlineSince(t time.Time) {
 	h.onlineSince = t
 }
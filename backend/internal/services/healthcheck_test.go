@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun/migrate"
+)
+
+func TestClockSkew(t *testing.T) {
+	t.Parallel()
+
+	appTime := time.Date(2025, 4, 15, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		maxClockSkew time.Duration
+		dbOffset     time.Duration
+		dbErr        error
+		expectedSkew time.Duration
+		expectedOK   bool
+	}{
+		{name: "in sync", maxClockSkew: time.Second, expectedOK: true},
+		{name: "ahead within threshold", maxClockSkew: time.Second, dbOffset: 800 * time.Millisecond, expectedSkew: 800 * time.Millisecond, expectedOK: true},
+		{name: "ahead beyond threshold", maxClockSkew: time.Second, dbOffset: 3 * time.Second, expectedSkew: 3 * time.Second},
+		{name: "behind beyond threshold", maxClockSkew: time.Second, dbOffset: -90 * time.Second, expectedSkew: -90 * time.Second},
+		{name: "check disabled", dbOffset: time.Hour, expectedSkew: time.Hour, expectedOK: true},
+		{name: "database unavailable", maxClockSkew: time.Second, dbErr: errors.New("connection refused")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// the app clock advances 100ms per reading, so the query takes 100ms
+			clock := appTime
+			h := &hc{
+				maxClockSkew: tc.maxClockSkew,
+				now: func() time.Time {
+					clock = clock.Add(100 * time.Millisecond)
+					return clock
+				},
+				dbNow: func(context.Context) (time.Time, error) {
+					// the database answers in the middle of the round trip
+					return appTime.Add(150*time.Millisecond + tc.dbOffset), tc.dbErr
+				},
+			}
+
+			skew, ok, err := h.ClockSkew()
+			if tc.dbErr != nil {
+				require.ErrorIs(t, err, tc.dbErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSkew, skew)
+			assert.Equal(t, tc.expectedOK, ok)
+		})
+	}
+}
+
+func TestPendingMigrations(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		applied  []bool
+		err      error
+		expected int
+	}{
+		{name: "up to date", applied: []bool{true, true}},
+		{name: "pending", applied: []bool{true, false, false}, expected: 2},
+		{name: "no migrations table", err: errors.New(`relation "bun_migrations" does not exist`)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := &hc{
+				migrationsWithStatus: func(context.Context) (migrate.MigrationSlice, error) {
+					// an applied migration has the ID of its row in the migrations table
+					ms := make(migrate.MigrationSlice, len(tc.applied))
+					for i, applied := range tc.applied {
+						if applied {
+							ms[i].ID = int64(i + 1)
+						}
+					}
+					return ms, tc.err
+				},
+			}
+
+			pending, err := h.PendingMigrations(time.Second)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, pending)
+		})
+	}
+}
+
+func TestGetRuntimeStats(t *testing.T) {
+	t.Parallel()
+
+	stats := (&hc{}).GetRuntimeStats()
+	assert.Positive(t, stats.NumGoroutine)
+	assert.Positive(t, stats.HeapInuse)
+	assert.GreaterOrEqual(t, stats.Sys, stats.HeapInuse)
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"user-management/internal/models"
+)
+
+// UserMetrics counts the users changed by the write operations
+type UserMetrics interface {
+	UsersCreated(n int)
+	UsersUpdated(n int)
+	UsersDeleted(n int)
+}
+
+// instrumentedUserService counts the users each successful write changed, dry runs aren't counted
+type instrumentedUserService struct {
+	UserService
+
+	metrics UserMetrics
+}
+
+// NewInstrumentedUserService wraps the service so the changed users are counted by metrics
+func NewInstrumentedUserService(next UserService, metrics UserMetrics) UserService {
+	return &instrumentedUserService{UserService: next, metrics: metrics}
+}
+
+func (s *instrumentedUserService) CreateUser(ctx context.Context, req models.UserCreateRequest) (*models.User, error) {
+	user, err := s.UserService.CreateUser(ctx, req)
+	if err == nil && !IsDryRun(ctx) {
+		s.metrics.UsersCreated(1)
+	}
+	return user, err
+}
+
+func (s *instrumentedUserService) UpdateUser(ctx context.Context, id int64, req models.UserUpdateRequest) (*models.User, error) {
+	user, err := s.UserService.UpdateUser(ctx, id, req)
+	if err == nil && !IsDryRun(ctx) {
+		s.metrics.UsersUpdated(1)
+	}
+	return user, err
+}
+
+func (s *instrumentedUserService) DeleteUser(ctx context.Context, id int64, reassignTo int64) error {
+	err := s.UserService.DeleteUser(ctx, id, reassignTo)
+	if err == nil && !IsDryRun(ctx) {
+		s.metrics.UsersDeleted(1)
+	}
+	return err
+}
+
+func (s *instrumentedUserService) CreateUsers(
+	ctx context.Context, reqs []models.UserCreateRequest, mode models.ConflictMode,
+) (*models.UserBatchCreateResult, error) {
+	result, err := s.UserService.CreateUsers(ctx, reqs, mode)
+	if err == nil && !IsDryRun(ctx) {
+		s.metrics.UsersCreated(len(result.Created))
+	}
+	return result, err
+}
+
+func (s *instrumentedUserService) UpdateUsers(ctx context.Context, items []models.UserBatchUpdateItem) (*models.UserBatchUpdateResult, error) {
+	result, err := s.UserService.UpdateUsers(ctx, items)
+	if err == nil && !IsDryRun(ctx) {
+		s.metrics.UsersUpdated(len(result.Updated))
+	}
+	return result, err
+}
+
+func (s *instrumentedUserService) UpdateStatus(
+	ctx context.Context, ids []int64, status models.UserStatus,
+) (*models.UserStatusUpdateResult, error) {
+	result, err := s.UserService.UpdateStatus(ctx, ids, status)
+	if err == nil && !IsDryRun(ctx) {
+		s.metrics.UsersUpdated(result.Count)
+	}
+	return result, err
+}
+
+func (s *instrumentedUserService) DeactivateStaleUsers(
+	ctx context.Context, inactiveFor time.Duration, dryRun bool,
+) (*models.UserDeactivateStaleResult, error) {
+	result, err := s.UserService.DeactivateStaleUsers(ctx, inactiveFor, dryRun)
+	if err == nil && !dryRun {
+		s.metrics.UsersUpdated(result.Count)
+	}
+	return result, err
+}
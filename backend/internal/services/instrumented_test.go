@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"user-management/internal/models"
+)
+
+// countingMetrics records the counted users
+type countingMetrics struct {
+	created, updated, deleted int
+}
+
+func (m *countingMetrics) UsersCreated(n int) { m.created += n }
+func (m *countingMetrics) UsersUpdated(n int) { m.updated += n }
+func (m *countingMetrics) UsersDeleted(n int) { m.deleted += n }
+
+// writeUserService succeeds every write unless err is set
+type writeUserService struct {
+	UserService
+
+	err error
+}
+
+func (s *writeUserService) CreateUser(_ context.Context, req models.UserCreateRequest) (*models.User, error) {
+	return &models.User{UserCommon: req.UserCommon}, s.err
+}
+
+func (s *writeUserService) DeleteUser(_ context.Context, _ int64, _ int64) error {
+	return s.err
+}
+
+func (s *writeUserService) CreateUsers(
+	_ context.Context, reqs []models.UserCreateRequest, _ models.ConflictMode,
+) (*models.UserBatchCreateResult, error) {
+	// the last item is skipped as a duplicate
+	return &models.UserBatchCreateResult{Created: make([]models.User, len(reqs)-1)}, s.err
+}
+
+func TestInstrumentedUserService(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	req := models.UserCreateRequest{UserCommon: models.UserCommon{UserName: "johndoe"}}
+
+	t.Run("counts the changed users", func(t *testing.T) {
+		t.Parallel()
+
+		m := &countingMetrics{}
+		svc := NewInstrumentedUserService(&writeUserService{}, m)
+
+		_, _ = svc.CreateUser(ctx, req)
+		_, _ = svc.CreateUsers(ctx, []models.UserCreateRequest{req, req, req}, models.ConflictModeIgnore)
+		_ = svc.DeleteUser(ctx, 1, 0)
+
+		assert.Equal(t, &countingMetrics{created: 3, deleted: 1}, m, "skipped batch items aren't counted")
+	})
+
+	t.Run("ignores failures and dry runs", func(t *testing.T) {
+		t.Parallel()
+
+		m := &countingMetrics{}
+		_, _ = NewInstrumentedUserService(&writeUserService{err: errors.New("boom")}, m).CreateUser(ctx, req)
+		_, _ = NewInstrumentedUserService(&writeUserService{}, m).CreateUser(WithDryRun(ctx), req)
+
+		assert.Equal(t, &countingMetrics{}, m)
+	})
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"user-management/internal/models"
+)
+
+const (
+	// maxListCacheEntries bounds the number of cached list pages (one per distinct params)
+	maxListCacheEntries = 256
+	// listRefreshTimeout bounds a background revalidation, which outlives the request that triggered it
+	listRefreshTimeout = 10 * time.Second
+)
+
+// listCacheEntry is a cached list page
+type listCacheEntry struct {
+	users      []models.User
+	total      int
+	fetchedAt  time.Time
+	refreshing bool
+}
+
+// cachedUserService serves user lists with stale-while-revalidate semantics:
+// a page younger than ttl is served from the cache, a page up to maxStale older than that
+// is still served from the cache while it's refreshed in the background, anything older is fetched again.
+// Every write invalidates the whole cache, so writes must be overridden here rather than passed through.
+type cachedUserService struct {
+	UserService
+
+	ttl      time.Duration
+	maxStale time.Duration
+	now      func() time.Time
+
+	mu sync.Mutex
+	// generation is bumped on every invalidation, so refreshes started before a write are discarded
+	generation uint64
+	entries    map[models.ListParams]*listCacheEntry
+}
+
+// NewCachedUserService wraps the service with a stale-while-revalidate cache of the user lists.
+// The cached users are shared between callers and must not be modified.
+func NewCachedUserService(next UserService, ttl, maxStale time.Duration) UserService {
+	return &cachedUserService{
+		UserService: next,
+		ttl:         ttl,
+		maxStale:    maxStale,
+		now:         time.Now,
+		entries:     make(map[models.ListParams]*listCacheEntry),
+	}
+}
+
+func (s *cachedUserService) ListUsers(ctx context.Context, params models.ListParams) ([]models.User, int, error) {
+	s.mu.Lock()
+	if entry, ok := s.entries[params]; ok {
+		age := s.now().Sub(entry.fetchedAt)
+		if age <= s.ttl+s.maxStale {
+			if age > s.ttl && !entry.refreshing {
+				entry.refreshing = true
+				go s.refresh(context.WithoutCancel(ctx), params, s.generation)
+			}
+			s.mu.Unlock()
+			return entry.users, entry.total, nil
+		}
+	}
+	generation := s.generation
+	s.mu.Unlock()
+
+	users, total, err := s.UserService.ListUsers(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	s.store(params, generation, users, total)
+	return users, total, nil
+}
+
+// refresh revalidates a stale page in the background
+func (s *cachedUserService) refresh(ctx context.Context, params models.ListParams, generation uint64) {
+	ctx, cancel := context.WithTimeout(ctx, listRefreshTimeout)
+	defer cancel()
+
+	users, total, err := s.UserService.ListUsers(ctx, params)
+	if err != nil {
+		slog.With("error", err).Warn("failed to revalidate cached user list")
+
+		s.mu.Lock()
+		if entry, ok := s.entries[params]; ok && s.generation == generation {
+			entry.refreshing = false
+		}
+		s.mu.Unlock()
+		return
+	}
+
+	s.store(params, generation, users, total)
+}
+
+// store caches a page unless the cache was invalidated since it was read
+func (s *cachedUserService) store(params models.ListParams, generation uint64, users []models.User, total int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.generation != generation {
+		return
+	}
+
+	now := s.now()
+	if _, ok := s.entries[params]; !ok && len(s.entries) >= maxListCacheEntries {
+		for key, entry := range s.entries {
+			if now.Sub(entry.fetchedAt) > s.ttl+s.maxStale {
+				delete(s.entries, key)
+			}
+		}
+		if len(s.entries) >= maxListCacheEntries {
+			return
+		}
+	}
+
+	s.entries[params] = &listCacheEntry{users: users, total: total, fetchedAt: now}
+}
+
+// invalidate drops every cached page
+func (s *cachedUserService) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.generation++
+	s.entries = make(map[models.ListParams]*listCacheEntry)
+}
+
+func (s *cachedUserService) CreateUser(ctx context.Context, req models.UserCreateRequest) (*models.User, error) {
+	defer s.invalidate()
+	return s.UserService.CreateUser(ctx, req)
+}
+
+func (s *cachedUserService) UpdateUser(ctx context.Context, id int64, req models.UserUpdateRequest) (*models.User, error) {
+	defer s.invalidate()
+	return s.UserService.UpdateUser(ctx, id, req)
+}
+
+func (s *cachedUserService) DeleteUser(ctx context.Context, id int64, reassignTo int64) error {
+	defer s.invalidate()
+	return s.UserService.DeleteUser(ctx, id, reassignTo)
+}
+
+func (s *cachedUserService) SetPassword(ctx context.Context, id int64, password string) error {
+	// the password itself isn't listed, but the user's version is bumped
+	defer s.invalidate()
+	return s.UserService.SetPassword(ctx, id, password)
+}
+
+func (s *cachedUserService) CreateUsers(
+	ctx context.Context, reqs []models.UserCreateRequest, mode models.ConflictMode,
+) (*models.UserBatchCreateResult, error) {
+	defer s.invalidate()
+	return s.UserService.CreateUsers(ctx, reqs, mode)
+}
+
+func (s *cachedUserService) UpdateUsers(ctx context.Context, items []models.UserBatchUpdateItem) (*models.UserBatchUpdateResult, error) {
+	defer s.invalidate()
+	return s.UserService.UpdateUsers(ctx, items)
+}
+
+func (s *cachedUserService) UpdateStatus(
+	ctx context.Context, ids []int64, status models.UserStatus,
+) (*models.UserStatusUpdateResult, error) {
+	defer s.invalidate()
+	return s.UserService.UpdateStatus(ctx, ids, status)
+}
+
+func (s *cachedUserService) DeactivateStaleUsers(
+	ctx context.Context, inactiveFor time.Duration, dryRun bool,
+) (*models.UserDeactivateStaleResult, error) {
+	if !dryRun {
+		defer s.invalidate()
+	}
+	return s.UserService.DeactivateStaleUsers(ctx, inactiveFor, dryRun)
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-management/internal/models"
+)
+
+// stubUserService keeps the users in memory and counts the list calls
+type stubUserService struct {
+	UserService
+
+	mu    sync.Mutex
+	users []models.User
+	calls int
+}
+
+func (s *stubUserService) ListUsers(_ context.Context, _ models.ListParams) ([]models.User, int, error) {
+	s.mu.Lock()
+	s.calls++
+	users := append([]models.User(nil), s.users...)
+	s.mu.Unlock()
+
+	return users, len(users), nil
+}
+
+func (s *stubUserService) CreateUser(_ context.Context, req models.UserCreateRequest) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := models.User{UserID: int64(len(s.users) + 1), UserCommon: req.UserCommon}
+	s.users = append(s.users, user)
+	return &user, nil
+}
+
+func (s *stubUserService) listCalls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// addUnnoticed changes the data behind the cache's back, like another instance would
+func (s *stubUserService) addUnnoticed(userName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users = append(s.users, models.User{UserID: int64(len(s.users) + 1), UserCommon: models.UserCommon{UserName: userName}})
+}
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+const (
+	testTTL      = time.Minute
+	testMaxStale = time.Minute
+)
+
+func newTestCache(users ...string) (*cachedUserService, *stubUserService, *fakeClock) {
+	stub := &stubUserService{}
+	for _, userName := range users {
+		stub.addUnnoticed(userName)
+	}
+
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cache := NewCachedUserService(stub, testTTL, testMaxStale).(*cachedUserService)
+	cache.now = clock.Now
+
+	return cache, stub, clock
+}
+
+func listNames(t *testing.T, svc UserService) []string {
+	t.Helper()
+
+	users, total, err := svc.ListUsers(context.Background(), models.ListParams{Limit: 50})
+	require.NoError(t, err)
+	require.Len(t, users, total)
+
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.UserName)
+	}
+	return names
+}
+
+func TestCachedUserService(t *testing.T) {
+	t.Parallel()
+
+	t.Run("serves a fresh page from the cache", func(t *testing.T) {
+		t.Parallel()
+		cache, stub, clock := newTestCache("alice")
+
+		assert.Equal(t, []string{"alice"}, listNames(t, cache))
+		stub.addUnnoticed("bob")
+		clock.Advance(testTTL)
+
+		assert.Equal(t, []string{"alice"}, listNames(t, cache))
+		assert.Equal(t, 1, stub.listCalls())
+	})
+
+	t.Run("serves a stale page while revalidating it", func(t *testing.T) {
+		t.Parallel()
+		cache, stub, clock := newTestCache("alice")
+
+		listNames(t, cache)
+		stub.addUnnoticed("bob")
+		clock.Advance(testTTL + time.Second)
+
+		assert.Equal(t, []string{"alice"}, listNames(t, cache))
+		assert.Eventually(t, func() bool {
+			users, _, err := cache.ListUsers(context.Background(), models.ListParams{Limit: 50})
+			return err == nil && len(users) == 2
+		}, time.Second, 5*time.Millisecond)
+		assert.Equal(t, 2, stub.listCalls())
+	})
+
+	t.Run("fetches a page staler than the bound", func(t *testing.T) {
+		t.Parallel()
+		cache, stub, clock := newTestCache("alice")
+
+		listNames(t, cache)
+		stub.addUnnoticed("bob")
+		clock.Advance(testTTL + testMaxStale + time.Second)
+
+		assert.Equal(t, []string{"alice", "bob"}, listNames(t, cache))
+	})
+
+	t.Run("invalidates the cache on a write", func(t *testing.T) {
+		t.Parallel()
+		cache, _, _ := newTestCache("alice")
+
+		listNames(t, cache)
+		_, err := cache.CreateUser(context.Background(), models.UserCreateRequest{UserCommon: models.UserCommon{UserName: "bob"}})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"alice", "bob"}, listNames(t, cache))
+	})
+
+	t.Run("discards a page read before a write", func(t *testing.T) {
+		t.Parallel()
+		cache, _, _ := newTestCache()
+
+		// a revalidation reads the page, then a write lands before it's stored
+		cache.mu.Lock()
+		generation := cache.generation
+		cache.mu.Unlock()
+		cache.invalidate()
+
+		cache.store(models.ListParams{}, generation, []models.User{{UserID: 1}}, 1)
+
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		assert.Empty(t, cache.entries)
+	})
+}
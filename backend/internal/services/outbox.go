@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"user-management/internal/models"
+	"user-management/internal/repository"
+)
+
+// WithOutbox makes the service add an event per created, updated and deleted user to the outbox,
+// in the transaction of the change, for the poller to post them to the webhooks
+func WithOutbox(enabled bool) UserServiceOption {
+	return func(s *userService) {
+		s.outbox = enabled
+	}
+}
+
+// recordEvents adds an event per user to the outbox, unless it's disabled.
+// A dry run rolls them back with the change.
+func (s *userService) recordEvents(ctx context.Context, repo repository.UserRepository, eventType models.UserEventType, users ...models.User) error {
+	if !s.outbox || len(users) == 0 {
+		return nil
+	}
+
+	now, requestID := time.Now().UTC(), RequestIDFrom(ctx)
+	entries := make([]*models.OutboxEntry, len(users))
+	for i, user := range users {
+		entries[i] = &models.OutboxEntry{
+			Event:     models.UserEvent{Type: eventType, User: user, Timestamp: now, RequestID: requestID},
+			CreatedAt: now,
+		}
+	}
+	return repo.Outbox().Add(ctx, entries...)
+}
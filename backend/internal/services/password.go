@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"user-management/internal/models"
+	"user-management/internal/repository"
+)
+
+// SetPassword replaces the password in a transaction holding the user's row lock, bumping the user's version.
+// The hash isn't part of the user's representation, so no audit entry nor event is recorded.
+func (s *userService) SetPassword(ctx context.Context, id int64, password string) error {
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	if hash == "" {
+		return ErrInvalidPassword
+	}
+
+	return s.runInTx(ctx, func(ctx context.Context, repo repository.UserRepository) error {
+		user, err := repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		user.PasswordHash = hash
+		user.UpdatedBy = ActorFrom(ctx)
+		return repo.Update(ctx, user)
+	})
+}
+
+// hashPassword returns the bcrypt hash of the password, empty for an empty password
+func hashPassword(password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+	if len(password) < models.MinPasswordLength || len(password) > models.MaxPasswordLength {
+		return "", ErrInvalidPassword
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
@@ -0,0 +1,16 @@
+package services
+
+import "context"
+
+type requestIDKey struct{}
+
+// WithRequestID sets the ID of the request making the changes, passed on to the webhooks of their events
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFrom returns the request ID set by WithRequestID, empty when there's none
+func RequestIDFrom(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
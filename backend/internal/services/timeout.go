@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"user-management/internal/models"
+)
+
+// timeoutUserService bounds every call by a deadline, which bun passes on to the database driver
+// so a stuck query is cancelled instead of holding the request and its connection
+type timeoutUserService struct {
+	next    UserService
+	timeout time.Duration
+}
+
+// NewTimeoutUserService wraps the service so every call fails with context.DeadlineExceeded past the timeout,
+// a transaction is rolled back. A non-positive timeout leaves the service as is.
+func NewTimeoutUserService(next UserService, timeout time.Duration) UserService {
+	if timeout <= 0 {
+		return next
+	}
+	return &timeoutUserService{next: next, timeout: timeout}
+}
+
+func (s *timeoutUserService) ListUsers(ctx context.Context, params models.ListParams) ([]models.User, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.ListUsers(ctx, params)
+}
+
+// StreamUsers isn't bounded by the timeout, an export of a large table legitimately outlasts it.
+// The stream stops with the request instead.
+func (s *timeoutUserService) StreamUsers(ctx context.Context, params models.ListParams, fn func(user *models.User) error) error {
+	return s.next.StreamUsers(ctx, params, fn)
+}
+
+func (s *timeoutUserService) SearchUsers(ctx context.Context, query string) ([]models.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.SearchUsers(ctx, query)
+}
+
+func (s *timeoutUserService) CountUsers(ctx context.Context, params models.ListParams) (*models.UserCountResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.CountUsers(ctx, params)
+}
+
+func (s *timeoutUserService) GetUser(ctx context.Context, id int64) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.GetUser(ctx, id)
+}
+
+func (s *timeoutUserService) UserExists(ctx context.Context, id int64) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.UserExists(ctx, id)
+}
+
+func (s *timeoutUserService) CheckAvailability(ctx context.Context, email, userName string) (*models.UserAvailabilityResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.CheckAvailability(ctx, email, userName)
+}
+
+func (s *timeoutUserService) GetUserByUserName(ctx context.Context, userName string) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.GetUserByUserName(ctx, userName)
+}
+
+func (s *timeoutUserService) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.GetUserByEmail(ctx, email)
+}
+
+func (s *timeoutUserService) CreateUser(ctx context.Context, req models.UserCreateRequest) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.CreateUser(ctx, req)
+}
+
+func (s *timeoutUserService) UpdateUser(ctx context.Context, id int64, req models.UserUpdateRequest) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.UpdateUser(ctx, id, req)
+}
+
+func (s *timeoutUserService) DeleteUser(ctx context.Context, id int64, reassignTo int64) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.DeleteUser(ctx, id, reassignTo)
+}
+
+func (s *timeoutUserService) SetPassword(ctx context.Context, id int64, password string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.SetPassword(ctx, id, password)
+}
+
+func (s *timeoutUserService) CreateUsers(
+	ctx context.Context, reqs []models.UserCreateRequest, mode models.ConflictMode,
+) (*models.UserBatchCreateResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.CreateUsers(ctx, reqs, mode)
+}
+
+func (s *timeoutUserService) UpdateUsers(ctx context.Context, items []models.UserBatchUpdateItem) (*models.UserBatchUpdateResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.UpdateUsers(ctx, items)
+}
+
+func (s *timeoutUserService) UpdateStatus(
+	ctx context.Context, ids []int64, status models.UserStatus,
+) (*models.UserStatusUpdateResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.UpdateStatus(ctx, ids, status)
+}
+
+func (s *timeoutUserService) DeactivateStaleUsers(
+	ctx context.Context, inactiveFor time.Duration, dryRun bool,
+) (*models.UserDeactivateStaleResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.DeactivateStaleUsers(ctx, inactiveFor, dryRun)
+}
+
+func (s *timeoutUserService) GetUserHistory(ctx context.Context, id int64) ([]models.AuditEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.GetUserHistory(ctx, id)
+}
+
+func (s *timeoutUserService) GetUserReports(ctx context.Context, id int64) ([]models.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.GetUserReports(ctx, id)
+}
+
+// timeoutDepartmentService bounds every call by a deadline, as timeoutUserService
+type timeoutDepartmentService struct {
+	next    DepartmentService
+	timeout time.Duration
+}
+
+// NewTimeoutDepartmentService wraps the service as NewTimeoutUserService does
+func NewTimeoutDepartmentService(next DepartmentService, timeout time.Duration) DepartmentService {
+	if timeout <= 0 {
+		return next
+	}
+	return &timeoutDepartmentService{next: next, timeout: timeout}
+}
+
+func (s *timeoutDepartmentService) ListDepartments(ctx context.Context) ([]models.Department, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.ListDepartments(ctx)
+}
+
+func (s *timeoutDepartmentService) CreateDepartment(ctx context.Context, req models.DepartmentCreateRequest) (*models.Department, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.CreateDepartment(ctx, req)
+}
+
+// timeoutTokenService bounds every call by a deadline, as timeoutUserService
+type timeoutTokenService struct {
+	next    TokenService
+	timeout time.Duration
+}
+
+// NewTimeoutTokenService wraps the service as NewTimeoutUserService does
+func NewTimeoutTokenService(next TokenService, timeout time.Duration) TokenService {
+	if timeout <= 0 {
+		return next
+	}
+	return &timeoutTokenService{next: next, timeout: timeout}
+}
+
+func (s *timeoutTokenService) IssueRefreshToken(ctx context.Context, subject string, roles []string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.IssueRefreshToken(ctx, subject, roles)
+}
+
+func (s *timeoutTokenService) Refresh(ctx context.Context, refreshToken string) (*models.AccessTokenResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.Refresh(ctx, refreshToken)
+}
+
+func (s *timeoutTokenService) Revoke(ctx context.Context, refreshToken string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.Revoke(ctx, refreshToken)
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+
+	"user-management/internal/repository"
+)
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+
+	sqldb, err := sql.Open(sqliteshim.ShimName, "file::memory:")
+	require.NoError(t, err)
+	sqldb.SetMaxOpenConns(1)
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	t.Cleanup(func() { _ = db.Close() })
+
+	// an endless list of departments, reading them never ends
+	_, err = db.ExecContext(context.Background(), `CREATE VIEW departments AS
+		WITH RECURSIVE n(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM n)
+		SELECT x AS id, 'dept' || x AS name, CURRENT_TIMESTAMP AS created_at FROM n`)
+	require.NoError(t, err)
+
+	next := NewDepartmentService(repository.NewUserRepository(db))
+	assert.Same(t, next, NewTimeoutDepartmentService(next, 0), "a zero timeout leaves the service as is")
+	svc := NewTimeoutDepartmentService(next, 100*time.Millisecond)
+
+	start := time.Now()
+	_, err = svc.ListDepartments(context.Background())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 2*time.Second, "the query is interrupted at the deadline")
+
+	// the connection is usable again
+	var one int
+	require.NoError(t, db.NewSelect().ColumnExpr("1").Scan(context.Background(), &one))
+	assert.Equal(t, 1, one)
+}
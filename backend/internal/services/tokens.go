@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"user-management/internal/config"
+	"user-management/internal/models"
+	"user-management/internal/repository"
+)
+
+// ErrInvalidRefreshToken is returned for a refresh token that is unknown, expired or revoked
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+// TokenService exchanges the refresh tokens for short-lived access tokens
+type TokenService interface {
+	// IssueRefreshToken creates a refresh token for the subject and their roles. Only the token's digest is stored,
+	// the returned token can't be read back.
+	IssueRefreshToken(ctx context.Context, subject string, roles []string) (string, error)
+	// Refresh returns a new access token for the subject of a valid refresh token, ErrInvalidRefreshToken otherwise
+	Refresh(ctx context.Context, refreshToken string) (*models.AccessTokenResponse, error)
+	// Revoke revokes the refresh token, revoking it again is a no-op. An unknown token is ErrInvalidRefreshToken.
+	Revoke(ctx context.Context, refreshToken string) error
+}
+
+type tokenService struct {
+	repo       repository.UserRepository
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	// now returns the time the tokens are issued and checked at
+	now func() time.Time
+}
+
+// NewTokenService creates a new token service, signing the access tokens with HS256 by the JWT secret
+func NewTokenService(repo repository.UserRepository, cfg *config.Config) TokenService {
+	return &tokenService{
+		repo:       repo,
+		secret:     []byte(cfg.Auth.JWTSecret),
+		accessTTL:  cfg.Auth.AccessTokenTTL,
+		refreshTTL: cfg.Auth.RefreshTokenTTL,
+		now:        func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (s *tokenService) IssueRefreshToken(ctx context.Context, subject string, roles []string) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	now := s.now()
+	err := s.repo.RefreshTokens().Create(ctx, &models.RefreshToken{
+		TokenHash: hashToken(token),
+		Subject:   subject,
+		Roles:     strings.Join(roles, " "),
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.refreshTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *tokenService) Refresh(ctx context.Context, refreshToken string) (*models.AccessTokenResponse, error) {
+	if len(s.secret) == 0 {
+		return nil, errors.New("no JWT secret to sign the access tokens with")
+	}
+
+	stored, err := s.lookup(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if stored.RevokedAt != nil || !now.Before(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   stored.Subject,
+		"roles": strings.Fields(stored.Roles),
+		"iat":   now.Unix(),
+		"exp":   now.Add(s.accessTTL).Unix(),
+	}).SignedString(s.secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AccessTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(s.accessTTL.Seconds()),
+	}, nil
+}
+
+func (s *tokenService) Revoke(ctx context.Context, refreshToken string) error {
+	stored, err := s.lookup(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+	return s.repo.RefreshTokens().Revoke(ctx, stored.ID, s.now())
+}
+
+// lookup returns the stored refresh token, ErrInvalidRefreshToken when there's none
+func (s *tokenService) lookup(ctx context.Context, refreshToken string) (*models.RefreshToken, error) {
+	stored, err := s.repo.RefreshTokens().GetByHash(ctx, hashToken(refreshToken))
+	if errors.Is(err, repository.ErrRefreshTokenNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	return stored, err
+}
+
+// hashToken returns the hex SHA-256 digest of the token, the refresh tokens are random enough not to need a salt
+func hashToken(token string) string {
+	digest := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(digest[:])
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-management/internal/config"
+	"user-management/internal/repository"
+)
+
+func TestTokenService(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{}
+	cfg.Auth.JWTSecret = "jwt-s3cret"
+	cfg.Auth.AccessTokenTTL = 15 * time.Minute
+	cfg.Auth.RefreshTokenTTL = time.Hour
+
+	repo := repository.NewInMemoryUserRepository()
+	svc := NewTokenService(repo, cfg).(*tokenService)
+	now := time.Now().UTC()
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	refreshToken, err := svc.IssueRefreshToken(ctx, "jane", []string{"admin", "reader"})
+	require.NoError(t, err)
+	_, err = repo.RefreshTokens().GetByHash(ctx, refreshToken)
+	require.ErrorIs(t, err, repository.ErrRefreshTokenNotFound, "only the digest is stored")
+
+	resp, err := svc.Refresh(ctx, refreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, 900, resp.ExpiresIn)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.NewParser(jwt.WithTimeFunc(svc.now)).ParseWithClaims(resp.AccessToken, claims, func(*jwt.Token) (any, error) {
+		return []byte("jwt-s3cret"), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "jane", claims["sub"])
+	assert.Equal(t, []any{"admin", "reader"}, claims["roles"])
+	assert.InDelta(t, now.Add(15*time.Minute).Unix(), claims["exp"], 0)
+
+	_, err = svc.Refresh(ctx, "guess")
+	require.ErrorIs(t, err, ErrInvalidRefreshToken)
+	require.ErrorIs(t, svc.Revoke(ctx, "guess"), ErrInvalidRefreshToken)
+
+	// expired an hour after its issue
+	now = now.Add(time.Hour)
+	_, err = svc.Refresh(ctx, refreshToken)
+	require.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	now = now.Add(-time.Minute)
+	require.NoError(t, svc.Revoke(ctx, refreshToken))
+	_, err = svc.Refresh(ctx, refreshToken)
+	require.ErrorIs(t, err, ErrInvalidRefreshToken, "revoked")
+}
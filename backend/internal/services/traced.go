@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"user-management/internal/models"
+)
+
+// tracedUserService wraps every call in a service.<Method> span, the repository calls become its children
+type tracedUserService struct {
+	next   UserService
+	tracer trace.Tracer
+}
+
+// NewTracedUserService wraps the service so every call is traced as a child span of the context's span
+func NewTracedUserService(next UserService, tracer trace.Tracer) UserService {
+	return &tracedUserService{next: next, tracer: tracer}
+}
+
+func (s *tracedUserService) start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.Bool("dry_run", IsDryRun(ctx)))
+	return s.tracer.Start(ctx, "service."+name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records the error, if any, and ends the span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (s *tracedUserService) ListUsers(ctx context.Context, params models.ListParams) ([]models.User, int, error) {
+	ctx, span := s.start(ctx, "ListUsers")
+	users, total, err := s.next.ListUsers(ctx, params)
+	endSpan(span, err)
+	return users, total, err
+}
+
+func (s *tracedUserService) StreamUsers(ctx context.Context, params models.ListParams, fn func(user *models.User) error) error {
+	ctx, span := s.start(ctx, "StreamUsers")
+	err := s.next.StreamUsers(ctx, params, fn)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedUserService) SearchUsers(ctx context.Context, query string) ([]models.User, error) {
+	ctx, span := s.start(ctx, "SearchUsers")
+	users, err := s.next.SearchUsers(ctx, query)
+	endSpan(span, err)
+	return users, err
+}
+
+func (s *tracedUserService) CountUsers(ctx context.Context, params models.ListParams) (*models.UserCountResponse, error) {
+	ctx, span := s.start(ctx, "CountUsers")
+	counts, err := s.next.CountUsers(ctx, params)
+	endSpan(span, err)
+	return counts, err
+}
+
+func (s *tracedUserService) GetUser(ctx context.Context, id int64) (*models.User, error) {
+	ctx, span := s.start(ctx, "GetUser", attribute.Int64("user.id", id))
+	user, err := s.next.GetUser(ctx, id)
+	endSpan(span, err)
+	return user, err
+}
+
+func (s *tracedUserService) UserExists(ctx context.Context, id int64) (bool, error) {
+	ctx, span := s.start(ctx, "UserExists", attribute.Int64("user.id", id))
+	exists, err := s.next.UserExists(ctx, id)
+	endSpan(span, err)
+	return exists, err
+}
+
+func (s *tracedUserService) CheckAvailability(ctx context.Context, email, userName string) (*models.UserAvailabilityResponse, error) {
+	ctx, span := s.start(ctx, "CheckAvailability")
+	result, err := s.next.CheckAvailability(ctx, email, userName)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedUserService) GetUserByUserName(ctx context.Context, userName string) (*models.User, error) {
+	ctx, span := s.start(ctx, "GetUserByUserName")
+	user, err := s.next.GetUserByUserName(ctx, userName)
+	endSpan(span, err)
+	return user, err
+}
+
+func (s *tracedUserService) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	ctx, span := s.start(ctx, "GetUserByEmail")
+	user, err := s.next.GetUserByEmail(ctx, email)
+	endSpan(span, err)
+	return user, err
+}
+
+func (s *tracedUserService) CreateUser(ctx context.Context, req models.UserCreateRequest) (*models.User, error) {
+	ctx, span := s.start(ctx, "CreateUser")
+	user, err := s.next.CreateUser(ctx, req)
+	if user != nil {
+		span.SetAttributes(attribute.Int64("user.id", user.UserID))
+	}
+	endSpan(span, err)
+	return user, err
+}
+
+func (s *tracedUserService) UpdateUser(ctx context.Context, id int64, req models.UserUpdateRequest) (*models.User, error) {
+	ctx, span := s.start(ctx, "UpdateUser", attribute.Int64("user.id", id))
+	user, err := s.next.UpdateUser(ctx, id, req)
+	endSpan(span, err)
+	return user, err
+}
+
+func (s *tracedUserService) DeleteUser(ctx context.Context, id int64, reassignTo int64) error {
+	ctx, span := s.start(ctx, "DeleteUser", attribute.Int64("user.id", id))
+	err := s.next.DeleteUser(ctx, id, reassignTo)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedUserService) SetPassword(ctx context.Context, id int64, password string) error {
+	ctx, span := s.start(ctx, "SetPassword", attribute.Int64("user.id", id))
+	err := s.next.SetPassword(ctx, id, password)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedUserService) CreateUsers(
+	ctx context.Context, reqs []models.UserCreateRequest, mode models.ConflictMode,
+) (*models.UserBatchCreateResult, error) {
+	ctx, span := s.start(ctx, "CreateUsers", attribute.Int("batch.size", len(reqs)), attribute.String("batch.mode", string(mode)))
+	result, err := s.next.CreateUsers(ctx, reqs, mode)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedUserService) UpdateUsers(ctx context.Context, items []models.UserBatchUpdateItem) (*models.UserBatchUpdateResult, error) {
+	ctx, span := s.start(ctx, "UpdateUsers", attribute.Int("batch.size", len(items)))
+	result, err := s.next.UpdateUsers(ctx, items)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedUserService) UpdateStatus(
+	ctx context.Context, ids []int64, status models.UserStatus,
+) (*models.UserStatusUpdateResult, error) {
+	ctx, span := s.start(ctx, "UpdateStatus", attribute.Int("batch.size", len(ids)), attribute.String("user.status", string(status)))
+	result, err := s.next.UpdateStatus(ctx, ids, status)
+	if result != nil {
+		span.SetAttributes(attribute.Int("users.count", result.Count))
+	}
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedUserService) DeactivateStaleUsers(
+	ctx context.Context, inactiveFor time.Duration, dryRun bool,
+) (*models.UserDeactivateStaleResult, error) {
+	ctx, span := s.start(ctx, "DeactivateStaleUsers", attribute.String("inactive_for", inactiveFor.String()))
+	// the dry run is a parameter here rather than a context value
+	span.SetAttributes(attribute.Bool("dry_run", dryRun))
+	result, err := s.next.DeactivateStaleUsers(ctx, inactiveFor, dryRun)
+	if result != nil {
+		span.SetAttributes(attribute.Int("users.count", result.Count))
+	}
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedUserService) GetUserHistory(ctx context.Context, id int64) ([]models.AuditEntry, error) {
+	ctx, span := s.start(ctx, "GetUserHistory", attribute.Int64("user.id", id))
+	entries, err := s.next.GetUserHistory(ctx, id)
+	endSpan(span, err)
+	return entries, err
+}
+
+func (s *tracedUserService) GetUserReports(ctx context.Context, id int64) ([]models.User, error) {
+	ctx, span := s.start(ctx, "GetUserReports", attribute.Int64("user.id", id))
+	reports, err := s.next.GetUserReports(ctx, id)
+	endSpan(span, err)
+	return reports, err
+}
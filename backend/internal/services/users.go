@@ -3,121 +3,932 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
 	"time"
 
 	"user-management/internal/models"
 	"user-management/internal/repository"
 )
 
+// ErrInvalidSort is matched by the error ListUsers returns for an unknown sort field
+var ErrInvalidSort = repository.ErrInvalidSort
+
+// InvalidParamsError is returned by ListUsers, listing every rejected list parameter
+type InvalidParamsError = repository.InvalidParamsError
+
+// ValidateListParams checks the list parameters, see InvalidParamsError
+func ValidateListParams(params models.ListParams) error {
+	return repository.ValidateListParams(params)
+}
+
+// IsKeysetOrder reports whether the users are listed by ascending user_id, the order cursor pagination follows
+func IsKeysetOrder(params models.ListParams) bool {
+	return repository.IsKeysetOrder(params)
+}
+
+// Domain errors returned by the single-user operations, match them with errors.Is
+var (
+	// ErrUserNotFound is returned when the user doesn't exist
+	ErrUserNotFound = repository.ErrUserNotFound
+	// ErrUsernameExists is returned when the user name belongs to another user
+	ErrUsernameExists = errors.New("username already exists")
+	// ErrEmailExists is returned when the email belongs to another user
+	ErrEmailExists = errors.New("email already exists")
+	// ErrInvalidStatus is returned for a user status outside A, I, T
+	ErrInvalidStatus = errors.New("invalid user status")
+	// ErrInvalidPassword is returned for a password shorter than models.MinPasswordLength
+	// or longer than models.MaxPasswordLength bytes
+	ErrInvalidPassword = errors.New("invalid password: must be between 8 and 72 bytes long")
+	// ErrVersionConflict is returned when the user changed since the version the update is based on
+	ErrVersionConflict = repository.ErrVersionConflict
+	// ErrModifiedSince is returned when the user was updated after the UnmodifiedSince time of the update
+	ErrModifiedSince = errors.New("user was modified since the If-Unmodified-Since time")
+	// ErrUnknownDepartment is matched by the *UnknownDepartmentError returned for a department that doesn't exist
+	ErrUnknownDepartment = errors.New("unknown department")
+	// ErrUnknownManager is matched by the *InvalidManagerError returned for a manager who doesn't exist
+	ErrUnknownManager = errors.New("unknown manager")
+	// ErrManagerCycle is matched by the *InvalidManagerError returned when a user would end up managing themselves
+	ErrManagerCycle = errors.New("manager cycle")
+	// ErrHasReports is returned when deleting a user who still manages other users, without reassigning them
+	ErrHasReports = errors.New("user has direct reports")
+)
+
+//go:generate moq -rm -out users_mock.go . UserService
+
 // UserService provides user-related business logic operations.
 type UserService interface {
-	ListUsers(ctx context.Context) ([]models.User, error)
+	ListUsers(ctx context.Context, params models.ListParams) ([]models.User, int, error)
+	// StreamUsers calls fn with the users ListUsers would return, one at a time as they're read from the database,
+	// a zero limit streaming every matching user. An error of fn stops the stream and is returned.
+	StreamUsers(ctx context.Context, params models.ListParams, fn func(user *models.User) error) error
+	SearchUsers(ctx context.Context, query string) ([]models.User, error)
+	CountUsers(ctx context.Context, params models.ListParams) (*models.UserCountResponse, error)
 	GetUser(ctx context.Context, id int64) (*models.User, error)
+	UserExists(ctx context.Context, id int64) (bool, error)
+	GetUserByUserName(ctx context.Context, userName string) (*models.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	// CheckAvailability reports whether the email and the user name are free, an empty one isn't checked
+	CheckAvailability(ctx context.Context, email, userName string) (*models.UserAvailabilityResponse, error)
 	CreateUser(ctx context.Context, req models.UserCreateRequest) (*models.User, error)
 	UpdateUser(ctx context.Context, id int64, req models.UserUpdateRequest) (*models.User, error)
-	DeleteUser(ctx context.Context, id int64) error
+	// DeleteUser deletes the user, whose direct reports are first moved to the reassignTo manager, unless it's zero
+	DeleteUser(ctx context.Context, id int64, reassignTo int64) error
+	CreateUsers(ctx context.Context, reqs []models.UserCreateRequest, mode models.ConflictMode) (*models.UserBatchCreateResult, error)
+	UpdateUsers(ctx context.Context, items []models.UserBatchUpdateItem) (*models.UserBatchUpdateResult, error)
+	UpdateStatus(ctx context.Context, ids []int64, status models.UserStatus) (*models.UserStatusUpdateResult, error)
+	DeactivateStaleUsers(ctx context.Context, inactiveFor time.Duration, dryRun bool) (*models.UserDeactivateStaleResult, error)
+	GetUserHistory(ctx context.Context, id int64) ([]models.AuditEntry, error)
+	GetUserReports(ctx context.Context, id int64) ([]models.User, error)
+	// SetPassword replaces the user's password, e.g. for a reset, storing only its bcrypt hash
+	SetPassword(ctx context.Context, id int64, password string) error
+}
+
+// DuplicateUserError is returned by an atomic batch create or a batch update
+// when an item collides with an existing user or another item of the batch
+type DuplicateUserError struct {
+	models.UserBatchSkipped
+}
+
+func (e *DuplicateUserError) Error() string {
+	return fmt.Sprintf("item %d: %s %q already exists", e.Index, e.Field, e.Value)
+}
+
+// UnknownDepartmentError is returned when the department of a user isn't one of the departments,
+// unless they're auto-created (see WithAutoCreateDepartments)
+type UnknownDepartmentError struct {
+	// Index of the batch item, zero outside of the batch operations
+	Index      int
+	Department string
+}
+
+func (e *UnknownDepartmentError) Error() string {
+	return fmt.Sprintf("unknown department %q", e.Department)
+}
+
+// Is matches ErrUnknownDepartment
+func (e *UnknownDepartmentError) Is(target error) bool {
+	return target == ErrUnknownDepartment
+}
+
+// InvalidManagerError is returned when the manager of a user doesn't exist (ErrUnknownManager)
+// or would make the user their own manager, directly or through a chain of managers (ErrManagerCycle)
+type InvalidManagerError struct {
+	// Index of the batch item, zero outside of the batch operations
+	Index     int
+	ManagerID int64
+	Err       error
+}
+
+func (e *InvalidManagerError) Error() string {
+	if errors.Is(e.Err, ErrManagerCycle) {
+		return fmt.Sprintf("manager %d would create a management cycle", e.ManagerID)
+	}
+	return fmt.Sprintf("unknown manager %d", e.ManagerID)
+}
+
+func (e *InvalidManagerError) Unwrap() error {
+	return e.Err
+}
+
+// MissingUserError is returned by a batch update when an item refers to a user that doesn't exist
+type MissingUserError struct {
+	Index  int
+	UserID int64
+}
+
+func (e *MissingUserError) Error() string {
+	return fmt.Sprintf("item %d: user %d not found", e.Index, e.UserID)
 }
 
 type userService struct {
 	repo repository.UserRepository
+
+	autoCreateDepartments bool
+	outbox                bool
+}
+
+// UserServiceOption configures the user service
+type UserServiceOption func(*userService)
+
+// WithAutoCreateDepartments makes the service create the unknown departments of the users it creates and updates,
+// rather than rejecting them with ErrUnknownDepartment
+func WithAutoCreateDepartments(enabled bool) UserServiceOption {
+	return func(s *userService) {
+		s.autoCreateDepartments = enabled
+	}
 }
 
 // NewUserService creates a new user service.
-func NewUserService(repo repository.UserRepository) UserService {
-	return &userService{repo: repo}
+func NewUserService(repo repository.UserRepository, opts ...UserServiceOption) UserService {
+	s := &userService{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *userService) ListUsers(ctx context.Context, params models.ListParams) ([]models.User, int, error) {
+	return s.repo.List(ctx, params)
 }
 
-func (s *userService) ListUsers(ctx context.Context) ([]models.User, error) {
-	return s.repo.List(ctx)
+func (s *userService) StreamUsers(ctx context.Context, params models.ListParams, fn func(user *models.User) error) error {
+	return s.repo.Stream(ctx, params, fn)
 }
 
+func (s *userService) SearchUsers(ctx context.Context, query string) ([]models.User, error) {
+	return s.repo.SearchUsers(ctx, query)
+}
+
+// CountUsers counts the users matching the list filters, in total and per status
+func (s *userService) CountUsers(ctx context.Context, params models.ListParams) (*models.UserCountResponse, error) {
+	total, err := s.repo.Count(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repo.CountByStatus(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	byStatus := map[models.UserStatus]int{
+		models.UserStatusActive:     counts[models.UserStatusActive],
+		models.UserStatusInactive:   counts[models.UserStatusInactive],
+		models.UserStatusTerminated: counts[models.UserStatusTerminated],
+	}
+
+	return &models.UserCountResponse{Total: total, ByStatus: byStatus}, nil
+}
+
+// GetUser returns the user with their manager, ErrUserNotFound only when the user doesn't exist,
+// database failures are passed through
 func (s *userService) GetUser(ctx context.Context, id int64) (*models.User, error) {
-	return s.repo.GetByID(ctx, id)
+	user, err := s.repo.GetWithManager(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	return user, err
+}
+
+// UserExists reports whether the user exists, cheaper than GetUser when the user itself isn't needed
+func (s *userService) UserExists(ctx context.Context, id int64) (bool, error) {
+	return s.repo.ExistsByID(ctx, id)
+}
+
+// GetUserByUserName returns ErrUserNotFound when no user has the name
+func (s *userService) GetUserByUserName(ctx context.Context, userName string) (*models.User, error) {
+	return s.repo.GetByUserName(ctx, userName)
+}
+
+// GetUserByEmail returns ErrUserNotFound when no user has the email
+func (s *userService) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	return s.repo.GetByEmail(ctx, email)
+}
+
+func (s *userService) CheckAvailability(ctx context.Context, email, userName string) (*models.UserAvailabilityResponse, error) {
+	result := &models.UserAvailabilityResponse{}
+	if email != "" {
+		exists, err := s.repo.ExistsByEmail(ctx, email, 0)
+		if err != nil {
+			return nil, err
+		}
+		available := !exists
+		result.EmailAvailable = &available
+	}
+	if userName != "" {
+		exists, err := s.repo.ExistsByUserName(ctx, userName)
+		if err != nil {
+			return nil, err
+		}
+		available := !exists
+		result.UsernameAvailable = &available
+	}
+	return result, nil
 }
 
 func (s *userService) CreateUser(ctx context.Context, req models.UserCreateRequest) (*models.User, error) {
-	// Check if username already exists
-	exists, err := s.repo.ExistsByUserName(ctx, req.UserName)
+	if !req.UserStatus.IsValid() {
+		return nil, ErrInvalidStatus
+	}
+
+	user := newUser(req, ActorFrom(ctx))
+	// hashed before the transaction, bcrypt is deliberately slow
+	var err error
+	if user.PasswordHash, err = hashPassword(req.Password); err != nil {
+		return nil, err
+	}
+
+	err = s.runInTx(ctx, func(ctx context.Context, repo repository.UserRepository) error {
+		// Check if username already exists
+		exists, err := repo.ExistsByUserName(ctx, req.UserName)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrUsernameExists
+		}
+
+		// Check if email already exists
+		exists, err = repo.ExistsByEmail(ctx, req.Email, 0)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrEmailExists
+		}
+
+		if err := s.checkDepartment(ctx, repo, 0, req.Department); err != nil {
+			return err
+		}
+		if err := checkManager(ctx, repo, 0, 0, req.ManagerID); err != nil {
+			return err
+		}
+
+		// the checks above can race with a concurrent create, the unique constraint settles it
+		if err := uniqueViolation(repo.Create(ctx, user)); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, repo, models.AuditActionCreate, user.UserID, nil, user); err != nil {
+			return err
+		}
+		return s.recordEvents(ctx, repo, models.UserEventCreated, *user)
+	})
 	if err != nil {
 		return nil, err
 	}
-	if exists {
-		return nil, errors.New("username already exists")
+
+	return user, nil
+}
+
+// UpdateUser runs the read-modify-write cycle in a transaction holding the user's row lock,
+// so concurrent updates of the same user are applied one after the other.
+func (s *userService) UpdateUser(ctx context.Context, id int64, req models.UserUpdateRequest) (*models.User, error) {
+	if !req.UserStatus.IsValid() {
+		return nil, ErrInvalidStatus
+	}
+
+	var user *models.User
+
+	err := s.runInTx(ctx, func(ctx context.Context, repo repository.UserRepository) error {
+		var err error
+		user, err = repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+		if req.Version != 0 && req.Version != user.Version {
+			return ErrVersionConflict
+		}
+		// HTTP dates have no fraction of a second
+		if !req.UnmodifiedSince.IsZero() && user.UpdatedAt.Truncate(time.Second).After(req.UnmodifiedSince) {
+			return ErrModifiedSince
+		}
+
+		// Check if username already exists and belongs to another user
+		if user.UserName != req.UserName {
+			exists, err := repo.ExistsByUserName(ctx, req.UserName)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return ErrUsernameExists
+			}
+		}
+
+		// Check if email already exists and belongs to another user
+		if user.Email != req.Email {
+			exists, err := repo.ExistsByEmail(ctx, req.Email, id)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return ErrEmailExists
+			}
+		}
+
+		// the users of a department removed since keep it until it's changed
+		if user.Department != req.Department {
+			if err := s.checkDepartment(ctx, repo, 0, req.Department); err != nil {
+				return err
+			}
+		}
+		if !sameManager(user.ManagerID, req.ManagerID) {
+			if err := checkManager(ctx, repo, 0, id, req.ManagerID); err != nil {
+				return err
+			}
+		}
+
+		before := *user
+		stamped := applyUpdate(user, req.UserCommon, ActorFrom(ctx))
+
+		if err := uniqueViolation(repo.Update(ctx, user, stamped...)); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, repo, models.AuditActionUpdate, id, &before, user); err != nil {
+			return err
+		}
+		return s.recordEvents(ctx, repo, models.UserEventUpdated, *user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// DeleteUser returns ErrHasReports when the user still manages other users and reassignTo is zero.
+// Otherwise the reports are moved to the reassignTo manager in the same transaction, who can't be the user
+// or one of the users below them (*InvalidManagerError).
+func (s *userService) DeleteUser(ctx context.Context, id int64, reassignTo int64) error {
+	return s.runInTx(ctx, func(ctx context.Context, repo repository.UserRepository) error {
+		// read first, so the audit log and the event keep the deleted values
+		user, err := repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		reports, err := reassignReports(ctx, repo, id, reassignTo)
+		if err != nil {
+			return err
+		}
+		if err := s.recordEvents(ctx, repo, models.UserEventUpdated, reports...); err != nil {
+			return err
+		}
+
+		if err := repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, repo, models.AuditActionDelete, id, user, nil); err != nil {
+			return err
+		}
+		return s.recordEvents(ctx, repo, models.UserEventDeleted, *user)
+	})
+}
+
+// GetUserHistory returns the audit entries of the user, newest first. The history of a deleted user is kept,
+// ErrUserNotFound is only returned when the user has no history and doesn't exist.
+func (s *userService) GetUserHistory(ctx context.Context, id int64) ([]models.AuditEntry, error) {
+	entries, err := s.repo.Audit().ListByUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		// users created before the audit log have no history
+		exists, err := s.repo.ExistsByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, ErrUserNotFound
+		}
+		return []models.AuditEntry{}, nil
+	}
+
+	return entries, nil
+}
+
+// GetUserReports returns the users the user directly manages, ordered by ID.
+// ErrUserNotFound is only returned when the user has no reports and doesn't exist.
+func (s *userService) GetUserReports(ctx context.Context, id int64) ([]models.User, error) {
+	reports, err := s.repo.ListReports(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reports) == 0 {
+		exists, err := s.repo.ExistsByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, ErrUserNotFound
+		}
+	}
+
+	return reports, nil
+}
+
+// CreateUsers creates all users in a single transaction.
+// In atomic mode the first duplicate rolls back the batch with a *DuplicateUserError,
+// in ignore mode duplicates (including ones within the batch) are skipped and reported.
+func (s *userService) CreateUsers(ctx context.Context, reqs []models.UserCreateRequest, mode models.ConflictMode) (*models.UserBatchCreateResult, error) {
+	var result *models.UserBatchCreateResult
+
+	// hashed before the transaction, bcrypt is deliberately slow
+	hashes := make([]string, len(reqs))
+	for i, req := range reqs {
+		var err error
+		if hashes[i], err = hashPassword(req.Password); err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
 	}
 
-	// Check if email already exists
-	exists, err = s.repo.ExistsByEmail(ctx, req.Email, 0)
+	err := s.runInTx(ctx, func(ctx context.Context, repo repository.UserRepository) error {
+		// reset on every attempt, so a retried transaction doesn't accumulate results
+		result = &models.UserBatchCreateResult{
+			Created: make([]models.User, 0, len(reqs)),
+			Skipped: []models.UserBatchSkipped{},
+		}
+
+		for i, req := range reqs {
+			user, skipped, err := s.createBatchItem(ctx, repo, i, req, hashes[i])
+			if err != nil {
+				return err
+			}
+			if user != nil {
+				result.Created = append(result.Created, *user)
+				continue
+			}
+			if mode != models.ConflictModeIgnore {
+				return &DuplicateUserError{UserBatchSkipped: *skipped}
+			}
+			result.Skipped = append(result.Skipped, *skipped)
+		}
+		return s.recordEvents(ctx, repo, models.UserEventCreated, result.Created...)
+	})
 	if err != nil {
 		return nil, err
 	}
+
+	return result, nil
+}
+
+// createBatchItem inserts a single batch item,
+// returning either the created user or a skip record when it's a duplicate.
+func (s *userService) createBatchItem(
+	ctx context.Context, repo repository.UserRepository, index int, req models.UserCreateRequest, passwordHash string,
+) (*models.User, *models.UserBatchSkipped, error) {
+	// CreateIfNotExists doesn't tell which unique constraint conflicted, the user name is checked first so the
+	// skip record names the right field
+	exists, err := repo.ExistsByUserName(ctx, req.UserName)
+	if err != nil {
+		return nil, nil, err
+	}
 	if exists {
-		return nil, errors.New("email already exists")
+		return nil, &models.UserBatchSkipped{Index: index, Field: "userName", Value: req.UserName}, nil
 	}
 
-	user := &models.User{
-		UserCommon: models.UserCommon{
-			UserName:   req.UserName,
-			FirstName:  req.FirstName,
-			LastName:   req.LastName,
-			Email:      req.Email,
-			UserStatus: req.UserStatus,
-			Department: req.Department,
-		},
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	if err := s.checkDepartment(ctx, repo, index, req.Department); err != nil {
+		return nil, nil, err
+	}
+	if err := checkManager(ctx, repo, index, 0, req.ManagerID); err != nil {
+		return nil, nil, err
+	}
+
+	user := newUser(req, ActorFrom(ctx))
+	user.PasswordHash = passwordHash
+	inserted, err := repo.CreateIfNotExists(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !inserted {
+		return nil, &models.UserBatchSkipped{Index: index, Field: "email", Value: req.Email}, nil
 	}
 
-	if err := s.repo.Create(ctx, user); err != nil {
+	if err := recordAudit(ctx, repo, models.AuditActionCreate, user.UserID, nil, user); err != nil {
+		return nil, nil, err
+	}
+
+	return user, nil, nil
+}
+
+// UpdateUsers applies all updates in a single transaction, any failing item rolls back the whole batch.
+// Items are applied in order, so an item may take over a user name or email
+// released by an earlier item, but two items can never claim the same user, user name or email.
+func (s *userService) UpdateUsers(ctx context.Context, items []models.UserBatchUpdateItem) (*models.UserBatchUpdateResult, error) {
+	if err := checkBatchUpdateConflicts(items); err != nil {
 		return nil, err
 	}
 
-	return user, nil
+	var result *models.UserBatchUpdateResult
+
+	err := s.runInTx(ctx, func(ctx context.Context, repo repository.UserRepository) error {
+		// reset on every attempt, so a retried transaction doesn't accumulate results
+		result = &models.UserBatchUpdateResult{Updated: make([]models.User, 0, len(items))}
+
+		for i, item := range items {
+			user, err := s.updateBatchItem(ctx, repo, i, item)
+			if err != nil {
+				return err
+			}
+			result.Updated = append(result.Updated, *user)
+		}
+		return s.recordEvents(ctx, repo, models.UserEventUpdated, result.Updated...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
 
-func (s *userService) UpdateUser(ctx context.Context, id int64, req models.UserUpdateRequest) (*models.User, error) {
-	user, err := s.repo.GetByID(ctx, id)
+// checkBatchUpdateConflicts rejects batches where several items claim the same user, user name or email
+func checkBatchUpdateConflicts(items []models.UserBatchUpdateItem) error {
+	ids := make(map[int64]struct{}, len(items))
+	userNames := make(map[string]struct{}, len(items))
+	emails := make(map[string]struct{}, len(items))
+
+	for i, item := range items {
+		if _, ok := ids[item.UserID]; ok {
+			return &DuplicateUserError{UserBatchSkipped: models.UserBatchSkipped{Index: i, Field: "id", Value: fmt.Sprint(item.UserID)}}
+		}
+		if _, ok := userNames[item.UserName]; ok {
+			return &DuplicateUserError{UserBatchSkipped: models.UserBatchSkipped{Index: i, Field: "userName", Value: item.UserName}}
+		}
+		if _, ok := emails[item.Email]; ok {
+			return &DuplicateUserError{UserBatchSkipped: models.UserBatchSkipped{Index: i, Field: "email", Value: item.Email}}
+		}
+
+		ids[item.UserID] = struct{}{}
+		userNames[item.UserName] = struct{}{}
+		emails[item.Email] = struct{}{}
+	}
+
+	return nil
+}
+
+// updateBatchItem updates a single batch item against the state left by the previous items
+func (s *userService) updateBatchItem(
+	ctx context.Context, repo repository.UserRepository, index int, item models.UserBatchUpdateItem,
+) (*models.User, error) {
+	user, err := repo.GetByIDForUpdate(ctx, item.UserID)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &MissingUserError{Index: index, UserID: item.UserID}
+		}
 		return nil, err
 	}
 
-	// Check if username already exists and belongs to another user
-	if user.UserName != req.UserName {
-		exists, err := s.repo.ExistsByUserName(ctx, req.UserName)
+	if user.UserName != item.UserName {
+		exists, err := repo.ExistsByUserName(ctx, item.UserName)
 		if err != nil {
 			return nil, err
 		}
 		if exists {
-			return nil, errors.New("username already exists")
+			return nil, &DuplicateUserError{UserBatchSkipped: models.UserBatchSkipped{Index: index, Field: "userName", Value: item.UserName}}
 		}
 	}
 
-	// Check if email already exists and belongs to another user
-	if user.Email != req.Email {
-		exists, err := s.repo.ExistsByEmail(ctx, req.Email, id)
+	if user.Email != item.Email {
+		exists, err := repo.ExistsByEmail(ctx, item.Email, item.UserID)
 		if err != nil {
 			return nil, err
 		}
 		if exists {
-			return nil, errors.New("email already exists")
+			return nil, &DuplicateUserError{UserBatchSkipped: models.UserBatchSkipped{Index: index, Field: "email", Value: item.Email}}
 		}
 	}
 
-	user.UserName = req.UserName
-	user.FirstName = req.FirstName
-	user.LastName = req.LastName
-	user.Email = req.Email
-	user.UserStatus = req.UserStatus
-	user.Department = req.Department
-	user.UpdatedAt = time.Now()
+	if user.Department != item.Department {
+		if err := s.checkDepartment(ctx, repo, index, item.Department); err != nil {
+			return nil, err
+		}
+	}
+	if !sameManager(user.ManagerID, item.ManagerID) {
+		if err := checkManager(ctx, repo, index, item.UserID, item.ManagerID); err != nil {
+			return nil, err
+		}
+	}
 
-	if err := s.repo.Update(ctx, user); err != nil {
+	before := *user
+	stamped := applyUpdate(user, item.UserCommon, ActorFrom(ctx))
+
+	if err := repo.Update(ctx, user, stamped...); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, repo, models.AuditActionUpdate, user.UserID, &before, user); err != nil {
 		return nil, err
 	}
 
 	return user, nil
 }
 
-func (s *userService) DeleteUser(ctx context.Context, id int64) error {
-	return s.repo.Delete(ctx, id)
+// UpdateStatus sets the status of the users in a single transaction, leaving the users already in it untouched.
+// The result counts the changed users and lists the IDs no user has.
+func (s *userService) UpdateStatus(ctx context.Context, ids []int64, status models.UserStatus) (*models.UserStatusUpdateResult, error) {
+	if !status.IsValid() {
+		return nil, ErrInvalidStatus
+	}
+
+	var result *models.UserStatusUpdateResult
+
+	err := s.runInTx(ctx, func(ctx context.Context, repo repository.UserRepository) error {
+		users, err := repo.ListByIDsForUpdate(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		// reset on every attempt, so a retried transaction doesn't accumulate results
+		result = &models.UserStatusUpdateResult{NotFound: []int64{}}
+		found := make(map[int64]struct{}, len(users))
+		changed := make([]models.User, 0, len(users))
+		for _, user := range users {
+			found[user.UserID] = struct{}{}
+			if user.UserStatus != status {
+				changed = append(changed, user)
+			}
+		}
+		for _, id := range ids {
+			if _, ok := found[id]; !ok && !slices.Contains(result.NotFound, id) {
+				result.NotFound = append(result.NotFound, id)
+			}
+		}
+		result.Count = len(changed)
+
+		if len(changed) == 0 {
+			return nil
+		}
+		if err := setStatus(ctx, repo, changed, status); err != nil {
+			return err
+		}
+		return s.recordEvents(ctx, repo, models.UserEventUpdated, changed...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeactivateStaleUsers marks the active users who haven't logged in for inactiveFor as inactive
+// in a single transaction. A dry run only reports the users who would be deactivated.
+func (s *userService) DeactivateStaleUsers(ctx context.Context, inactiveFor time.Duration, dryRun bool) (*models.UserDeactivateStaleResult, error) {
+	cutoff := time.Now().Add(-inactiveFor)
+	result := &models.UserDeactivateStaleResult{DryRun: dryRun}
+
+	var err error
+	if dryRun {
+		// only reported, the users are read without locking them
+		result.Users, err = s.repo.ListStale(ctx, cutoff)
+	} else {
+		err = s.repo.RunInTx(ctx, func(ctx context.Context, repo repository.UserRepository) error {
+			users, err := repo.ListStaleForUpdate(ctx, cutoff)
+			if err != nil {
+				return err
+			}
+			result.Users = users
+			if err := setStatus(ctx, repo, users, models.UserStatusInactive); err != nil {
+				return err
+			}
+			return s.recordEvents(ctx, repo, models.UserEventUpdated, users...)
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Users == nil {
+		result.Users = []models.User{}
+	}
+	result.Count = len(result.Users)
+
+	if !dryRun {
+		slog.With("cutoff", cutoff).
+			With("count", result.Count).
+			Info("Deactivated stale users")
+	}
+
+	return result, nil
+}
+
+// setStatus sets the status of the users, in the database and in place, with an audit entry per user
+func setStatus(ctx context.Context, repo repository.UserRepository, users []models.User, status models.UserStatus) error {
+	actor := ActorFrom(ctx)
+	ids := make([]int64, len(users))
+	for i := range users {
+		ids[i] = users[i].UserID
+	}
+
+	// the database clock stamps the change
+	at, err := repo.UpdateStatus(ctx, ids, status, actor)
+	if err != nil {
+		return err
+	}
+
+	// the audit log is stamped by the app clock, as by recordAudit
+	now := time.Now()
+	entries := make([]*models.AuditEntry, len(users))
+	for i := range users {
+		entries[i] = &models.AuditEntry{
+			UserID:    users[i].UserID,
+			Action:    models.AuditActionUpdate,
+			Actor:     actor,
+			CreatedAt: now,
+			Diff:      map[string]models.AuditChange{"userStatus": {Old: users[i].UserStatus, New: status}},
+		}
+		statusUpdatedAt := at
+		users[i].UserStatus = status
+		users[i].StatusUpdatedAt = &statusUpdatedAt
+		users[i].UpdatedAt = at
+		users[i].UpdatedBy = actor
+	}
+	return repo.Audit().Record(ctx, entries...)
+}
+
+// uniqueViolation translates the repository's unique constraint violations into
+// ErrUsernameExists or ErrEmailExists based on the constraint name, other errors are passed through
+func uniqueViolation(err error) error {
+	var violation *repository.UniqueViolationError
+	if !errors.As(err, &violation) {
+		return err
+	}
+
+	switch {
+	case strings.Contains(violation.Constraint, "user_name"):
+		return ErrUsernameExists
+	case strings.Contains(violation.Constraint, "email"):
+		return ErrEmailExists
+	default:
+		return err
+	}
+}
+
+// checkDepartment returns an *UnknownDepartmentError unless the department exists, or is empty.
+// With the auto-created departments, an unknown department is created instead.
+func (s *userService) checkDepartment(ctx context.Context, repo repository.UserRepository, index int, name string) error {
+	if name == "" {
+		return nil
+	}
+
+	exists, err := repo.Departments().ExistsByName(ctx, name)
+	if err != nil || exists {
+		return err
+	}
+	if !s.autoCreateDepartments {
+		return &UnknownDepartmentError{Index: index, Department: name}
+	}
+
+	_, err = repo.Departments().CreateIfNotExists(ctx, &models.Department{Name: name})
+	return err
+}
+
+// checkManager returns an *InvalidManagerError unless the manager exists and the user, zero for a new one,
+// isn't found among the manager and the managers above them. A nil manager is always valid.
+func checkManager(ctx context.Context, repo repository.UserRepository, index int, userID int64, managerID *int64) error {
+	if managerID == nil {
+		return nil
+	}
+	invalid := func(err error) error {
+		return &InvalidManagerError{Index: index, ManagerID: *managerID, Err: err}
+	}
+	if *managerID == userID {
+		return invalid(ErrManagerCycle)
+	}
+
+	// seen stops the walk on a cycle left by a concurrent change, the user isn't part of it
+	seen := map[int64]struct{}{}
+	for id := *managerID; ; {
+		manager, err := repo.GetByID(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) && id == *managerID {
+			return invalid(ErrUnknownManager)
+		}
+		if err != nil {
+			return err
+		}
+		if _, ok := seen[id]; ok || manager.ManagerID == nil {
+			return nil
+		}
+		if *manager.ManagerID == userID {
+			return invalid(ErrManagerCycle)
+		}
+		seen[id] = struct{}{}
+		id = *manager.ManagerID
+	}
+}
+
+// reassignReports moves the direct reports of the manager to reassignTo, each as an audited update,
+// and returns the moved reports. Without reports there's nothing to move,
+// with reports and a zero reassignTo it returns ErrHasReports.
+func reassignReports(ctx context.Context, repo repository.UserRepository, managerID int64, reassignTo int64) ([]models.User, error) {
+	reports, err := repo.ListReports(ctx, managerID)
+	if err != nil || len(reports) == 0 {
+		return nil, err
+	}
+	if reassignTo == 0 {
+		return nil, ErrHasReports
+	}
+
+	// the new manager is checked as if they managed the leaving manager, whose reports they take over
+	if err := checkManager(ctx, repo, 0, managerID, &reassignTo); err != nil {
+		return nil, err
+	}
+
+	actor := ActorFrom(ctx)
+	for i := range reports {
+		report := &reports[i]
+		before := *report
+		report.ManagerID = &reassignTo
+		report.UpdatedBy = actor
+
+		if err := repo.Update(ctx, report); err != nil {
+			return nil, err
+		}
+		if err := recordAudit(ctx, repo, models.AuditActionUpdate, report.UserID, &before, report); err != nil {
+			return nil, err
+		}
+	}
+	return reports, nil
+}
+
+// sameManager reports whether both manager IDs are nil or equal
+func sameManager(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// applyUpdate copies the updatable fields onto the user and records actor as the author of the change.
+// It returns the columns of the per-field timestamps to bump, only when the email or status actually change,
+// for the repository to stamp them with the database clock as the update time.
+func applyUpdate(user *models.User, common models.UserCommon, actor string) []string {
+	var stamped []string
+	if user.Email != common.Email {
+		stamped = append(stamped, repository.EmailUpdatedAtColumn)
+	}
+	if user.UserStatus != common.UserStatus {
+		stamped = append(stamped, repository.StatusUpdatedAtColumn)
+	}
+
+	user.UserName = common.UserName
+	user.FirstName = common.FirstName
+	user.LastName = common.LastName
+	user.Email = common.Email
+	user.UserStatus = common.UserStatus
+	user.Department = common.Department
+	user.Phone = common.Phone
+	user.ManagerID = common.ManagerID
+	user.UpdatedBy = actor
+	return stamped
+}
+
+// newUser builds a new user model from the create request, created by actor.
+// The timestamps are left to the database.
+func newUser(req models.UserCreateRequest, actor string) *models.User {
+	return &models.User{
+		UserCommon: models.UserCommon{
+			UserName:   req.UserName,
+			FirstName:  req.FirstName,
+			LastName:   req.LastName,
+			Email:      req.Email,
+			UserStatus: req.UserStatus,
+			Department: req.Department,
+			Phone:      req.Phone,
+			ManagerID:  req.ManagerID,
+		},
+		CreatedBy: actor,
+		UpdatedBy: actor,
+		Version:   1,
+	}
 }
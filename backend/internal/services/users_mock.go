@@ -0,0 +1,1031 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+	"user-management/internal/models"
+)
+
+// Ensure, that UserServiceMock does implement UserService.
+// If this is not the case, regenerate this file with moq.
+var _ UserService = &UserServiceMock{}
+
+// UserServiceMock is a mock implementation of UserService.
+//
+//	func TestSomethingThatUsesUserService(t *testing.T) {
+//
+//		// make and configure a mocked UserService
+//		mockedUserService := &UserServiceMock{
+//			CheckAvailabilityFunc: func(ctx context.Context, email string, userName string) (*models.UserAvailabilityResponse, error) {
+//				panic("mock out the CheckAvailability method")
+//			},
+//			CountUsersFunc: func(ctx context.Context, params models.ListParams) (*models.UserCountResponse, error) {
+//				panic("mock out the CountUsers method")
+//			},
+//			CreateUserFunc: func(ctx context.Context, req models.UserCreateRequest) (*models.User, error) {
+//				panic("mock out the CreateUser method")
+//			},
+//			CreateUsersFunc: func(ctx context.Context, reqs []models.UserCreateRequest, mode models.ConflictMode) (*models.UserBatchCreateResult, error) {
+//				panic("mock out the CreateUsers method")
+//			},
+//			DeactivateStaleUsersFunc: func(ctx context.Context, inactiveFor time.Duration, dryRun bool) (*models.UserDeactivateStaleResult, error) {
+//				panic("mock out the DeactivateStaleUsers method")
+//			},
+//			DeleteUserFunc: func(ctx context.Context, id int64, reassignTo int64) error {
+//				panic("mock out the DeleteUser method")
+//			},
+//			GetUserFunc: func(ctx context.Context, id int64) (*models.User, error) {
+//				panic("mock out the GetUser method")
+//			},
+//			GetUserByEmailFunc: func(ctx context.Context, email string) (*models.User, error) {
+//				panic("mock out the GetUserByEmail method")
+//			},
+//			GetUserByUserNameFunc: func(ctx context.Context, userName string) (*models.User, error) {
+//				panic("mock out the GetUserByUserName method")
+//			},
+//			GetUserHistoryFunc: func(ctx context.Context, id int64) ([]models.AuditEntry, error) {
+//				panic("mock out the GetUserHistory method")
+//			},
+//			GetUserReportsFunc: func(ctx context.Context, id int64) ([]models.User, error) {
+//				panic("mock out the GetUserReports method")
+//			},
+//			ListUsersFunc: func(ctx context.Context, params models.ListParams) ([]models.User, int, error) {
+//				panic("mock out the ListUsers method")
+//			},
+//			SearchUsersFunc: func(ctx context.Context, query string) ([]models.User, error) {
+//				panic("mock out the SearchUsers method")
+//			},
+//			SetPasswordFunc: func(ctx context.Context, id int64, password string) error {
+//				panic("mock out the SetPassword method")
+//			},
+//			StreamUsersFunc: func(ctx context.Context, params models.ListParams, fn func(user *models.User) error) error {
+//				panic("mock out the StreamUsers method")
+//			},
+//			UpdateStatusFunc: func(ctx context.Context, ids []int64, status models.UserStatus) (*models.UserStatusUpdateResult, error) {
+//				panic("mock out the UpdateStatus method")
+//			},
+//			UpdateUserFunc: func(ctx context.Context, id int64, req models.UserUpdateRequest) (*models.User, error) {
+//				panic("mock out the UpdateUser method")
+//			},
+//			UpdateUsersFunc: func(ctx context.Context, items []models.UserBatchUpdateItem) (*models.UserBatchUpdateResult, error) {
+//				panic("mock out the UpdateUsers method")
+//			},
+//			UserExistsFunc: func(ctx context.Context, id int64) (bool, error) {
+//				panic("mock out the UserExists method")
+//			},
+//		}
+//
+//		// use mockedUserService in code that requires UserService
+//		// and then make assertions.
+//
+//	}
+type UserServiceMock struct {
+	// CheckAvailabilityFunc mocks the CheckAvailability method.
+	CheckAvailabilityFunc func(ctx context.Context, email string, userName string) (*models.UserAvailabilityResponse, error)
+
+	// CountUsersFunc mocks the CountUsers method.
+	CountUsersFunc func(ctx context.Context, params models.ListParams) (*models.UserCountResponse, error)
+
+	// CreateUserFunc mocks the CreateUser method.
+	CreateUserFunc func(ctx context.Context, req models.UserCreateRequest) (*models.User, error)
+
+	// CreateUsersFunc mocks the CreateUsers method.
+	CreateUsersFunc func(ctx context.Context, reqs []models.UserCreateRequest, mode models.ConflictMode) (*models.UserBatchCreateResult, error)
+
+	// DeactivateStaleUsersFunc mocks the DeactivateStaleUsers method.
+	DeactivateStaleUsersFunc func(ctx context.Context, inactiveFor time.Duration, dryRun bool) (*models.UserDeactivateStaleResult, error)
+
+	// DeleteUserFunc mocks the DeleteUser method.
+	DeleteUserFunc func(ctx context.Context, id int64, reassignTo int64) error
+
+	// GetUserFunc mocks the GetUser method.
+	GetUserFunc func(ctx context.Context, id int64) (*models.User, error)
+
+	// GetUserByEmailFunc mocks the GetUserByEmail method.
+	GetUserByEmailFunc func(ctx context.Context, email string) (*models.User, error)
+
+	// GetUserByUserNameFunc mocks the GetUserByUserName method.
+	GetUserByUserNameFunc func(ctx context.Context, userName string) (*models.User, error)
+
+	// GetUserHistoryFunc mocks the GetUserHistory method.
+	GetUserHistoryFunc func(ctx context.Context, id int64) ([]models.AuditEntry, error)
+
+	// GetUserReportsFunc mocks the GetUserReports method.
+	GetUserReportsFunc func(ctx context.Context, id int64) ([]models.User, error)
+
+	// ListUsersFunc mocks the ListUsers method.
+	ListUsersFunc func(ctx context.Context, params models.ListParams) ([]models.User, int, error)
+
+	// SearchUsersFunc mocks the SearchUsers method.
+	SearchUsersFunc func(ctx context.Context, query string) ([]models.User, error)
+
+	// SetPasswordFunc mocks the SetPassword method.
+	SetPasswordFunc func(ctx context.Context, id int64, password string) error
+
+	// StreamUsersFunc mocks the StreamUsers method.
+	StreamUsersFunc func(ctx context.Context, params models.ListParams, fn func(user *models.User) error) error
+
+	// UpdateStatusFunc mocks the UpdateStatus method.
+	UpdateStatusFunc func(ctx context.Context, ids []int64, status models.UserStatus) (*models.UserStatusUpdateResult, error)
+
+	// UpdateUserFunc mocks the UpdateUser method.
+	UpdateUserFunc func(ctx context.Context, id int64, req models.UserUpdateRequest) (*models.User, error)
+
+	// UpdateUsersFunc mocks the UpdateUsers method.
+	UpdateUsersFunc func(ctx context.Context, items []models.UserBatchUpdateItem) (*models.UserBatchUpdateResult, error)
+
+	// UserExistsFunc mocks the UserExists method.
+	UserExistsFunc func(ctx context.Context, id int64) (bool, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// CheckAvailability holds details about calls to the CheckAvailability method.
+		CheckAvailability []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Email is the email argument value.
+			Email string
+			// UserName is the userName argument value.
+			UserName string
+		}
+		// CountUsers holds details about calls to the CountUsers method.
+		CountUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Params is the params argument value.
+			Params models.ListParams
+		}
+		// CreateUser holds details about calls to the CreateUser method.
+		CreateUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Req is the req argument value.
+			Req models.UserCreateRequest
+		}
+		// CreateUsers holds details about calls to the CreateUsers method.
+		CreateUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Reqs is the reqs argument value.
+			Reqs []models.UserCreateRequest
+			// Mode is the mode argument value.
+			Mode models.ConflictMode
+		}
+		// DeactivateStaleUsers holds details about calls to the DeactivateStaleUsers method.
+		DeactivateStaleUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// InactiveFor is the inactiveFor argument value.
+			InactiveFor time.Duration
+			// DryRun is the dryRun argument value.
+			DryRun bool
+		}
+		// DeleteUser holds details about calls to the DeleteUser method.
+		DeleteUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+			// ReassignTo is the reassignTo argument value.
+			ReassignTo int64
+		}
+		// GetUser holds details about calls to the GetUser method.
+		GetUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
+		// GetUserByEmail holds details about calls to the GetUserByEmail method.
+		GetUserByEmail []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Email is the email argument value.
+			Email string
+		}
+		// GetUserByUserName holds details about calls to the GetUserByUserName method.
+		GetUserByUserName []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserName is the userName argument value.
+			UserName string
+		}
+		// GetUserHistory holds details about calls to the GetUserHistory method.
+		GetUserHistory []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
+		// GetUserReports holds details about calls to the GetUserReports method.
+		GetUserReports []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
+		// ListUsers holds details about calls to the ListUsers method.
+		ListUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Params is the params argument value.
+			Params models.ListParams
+		}
+		// SearchUsers holds details about calls to the SearchUsers method.
+		SearchUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query string
+		}
+		// SetPassword holds details about calls to the SetPassword method.
+		SetPassword []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+			// Password is the password argument value.
+			Password string
+		}
+		// StreamUsers holds details about calls to the StreamUsers method.
+		StreamUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Params is the params argument value.
+			Params models.ListParams
+			// Fn is the fn argument value.
+			Fn func(user *models.User) error
+		}
+		// UpdateStatus holds details about calls to the UpdateStatus method.
+		UpdateStatus []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Ids is the ids argument value.
+			Ids []int64
+			// Status is the status argument value.
+			Status models.UserStatus
+		}
+		// UpdateUser holds details about calls to the UpdateUser method.
+		UpdateUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+			// Req is the req argument value.
+			Req models.UserUpdateRequest
+		}
+		// UpdateUsers holds details about calls to the UpdateUsers method.
+		UpdateUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Items is the items argument value.
+			Items []models.UserBatchUpdateItem
+		}
+		// UserExists holds details about calls to the UserExists method.
+		UserExists []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
+	}
+	lockCheckAvailability    sync.RWMutex
+	lockCountUsers           sync.RWMutex
+	lockCreateUser           sync.RWMutex
+	lockCreateUsers          sync.RWMutex
+	lockDeactivateStaleUsers sync.RWMutex
+	lockDeleteUser           sync.RWMutex
+	lockGetUser              sync.RWMutex
+	lockGetUserByEmail       sync.RWMutex
+	lockGetUserByUserName    sync.RWMutex
+	lockGetUserHistory       sync.RWMutex
+	lockGetUserReports       sync.RWMutex
+	lockListUsers            sync.RWMutex
+	lockSearchUsers          sync.RWMutex
+	lockSetPassword          sync.RWMutex
+	lockStreamUsers          sync.RWMutex
+	lockUpdateStatus         sync.RWMutex
+	lockUpdateUser           sync.RWMutex
+	lockUpdateUsers          sync.RWMutex
+	lockUserExists           sync.RWMutex
+}
+
+// CheckAvailability calls CheckAvailabilityFunc.
+func (mock *UserServiceMock) CheckAvailability(ctx context.Context, email string, userName string) (*models.UserAvailabilityResponse, error) {
+	if mock.CheckAvailabilityFunc == nil {
+		panic("UserServiceMock.CheckAvailabilityFunc: method is nil but UserService.CheckAvailability was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Email    string
+		UserName string
+	}{
+		Ctx:      ctx,
+		Email:    email,
+		UserName: userName,
+	}
+	mock.lockCheckAvailability.Lock()
+	mock.calls.CheckAvailability = append(mock.calls.CheckAvailability, callInfo)
+	mock.lockCheckAvailability.Unlock()
+	return mock.CheckAvailabilityFunc(ctx, email, userName)
+}
+
+// CheckAvailabilityCalls gets all the calls that were made to CheckAvailability.
+// Check the length with:
+//
+//	len(mockedUserService.CheckAvailabilityCalls())
+func (mock *UserServiceMock) CheckAvailabilityCalls() []struct {
+	Ctx      context.Context
+	Email    string
+	UserName string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Email    string
+		UserName string
+	}
+	mock.lockCheckAvailability.RLock()
+	calls = mock.calls.CheckAvailability
+	mock.lockCheckAvailability.RUnlock()
+	return calls
+}
+
+// CountUsers calls CountUsersFunc.
+func (mock *UserServiceMock) CountUsers(ctx context.Context, params models.ListParams) (*models.UserCountResponse, error) {
+	if mock.CountUsersFunc == nil {
+		panic("UserServiceMock.CountUsersFunc: method is nil but UserService.CountUsers was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Params models.ListParams
+	}{
+		Ctx:    ctx,
+		Params: params,
+	}
+	mock.lockCountUsers.Lock()
+	mock.calls.CountUsers = append(mock.calls.CountUsers, callInfo)
+	mock.lockCountUsers.Unlock()
+	return mock.CountUsersFunc(ctx, params)
+}
+
+// CountUsersCalls gets all the calls that were made to CountUsers.
+// Check the length with:
+//
+//	len(mockedUserService.CountUsersCalls())
+func (mock *UserServiceMock) CountUsersCalls() []struct {
+	Ctx    context.Context
+	Params models.ListParams
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Params models.ListParams
+	}
+	mock.lockCountUsers.RLock()
+	calls = mock.calls.CountUsers
+	mock.lockCountUsers.RUnlock()
+	return calls
+}
+
+// CreateUser calls CreateUserFunc.
+func (mock *UserServiceMock) CreateUser(ctx context.Context, req models.UserCreateRequest) (*models.User, error) {
+	if mock.CreateUserFunc == nil {
+		panic("UserServiceMock.CreateUserFunc: method is nil but UserService.CreateUser was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Req models.UserCreateRequest
+	}{
+		Ctx: ctx,
+		Req: req,
+	}
+	mock.lockCreateUser.Lock()
+	mock.calls.CreateUser = append(mock.calls.CreateUser, callInfo)
+	mock.lockCreateUser.Unlock()
+	return mock.CreateUserFunc(ctx, req)
+}
+
+// CreateUserCalls gets all the calls that were made to CreateUser.
+// Check the length with:
+//
+//	len(mockedUserService.CreateUserCalls())
+func (mock *UserServiceMock) CreateUserCalls() []struct {
+	Ctx context.Context
+	Req models.UserCreateRequest
+} {
+	var calls []struct {
+		Ctx context.Context
+		Req models.UserCreateRequest
+	}
+	mock.lockCreateUser.RLock()
+	calls = mock.calls.CreateUser
+	mock.lockCreateUser.RUnlock()
+	return calls
+}
+
+// CreateUsers calls CreateUsersFunc.
+func (mock *UserServiceMock) CreateUsers(ctx context.Context, reqs []models.UserCreateRequest, mode models.ConflictMode) (*models.UserBatchCreateResult, error) {
+	if mock.CreateUsersFunc == nil {
+		panic("UserServiceMock.CreateUsersFunc: method is nil but UserService.CreateUsers was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Reqs []models.UserCreateRequest
+		Mode models.ConflictMode
+	}{
+		Ctx:  ctx,
+		Reqs: reqs,
+		Mode: mode,
+	}
+	mock.lockCreateUsers.Lock()
+	mock.calls.CreateUsers = append(mock.calls.CreateUsers, callInfo)
+	mock.lockCreateUsers.Unlock()
+	return mock.CreateUsersFunc(ctx, reqs, mode)
+}
+
+// CreateUsersCalls gets all the calls that were made to CreateUsers.
+// Check the length with:
+//
+//	len(mockedUserService.CreateUsersCalls())
+func (mock *UserServiceMock) CreateUsersCalls() []struct {
+	Ctx  context.Context
+	Reqs []models.UserCreateRequest
+	Mode models.ConflictMode
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Reqs []models.UserCreateRequest
+		Mode models.ConflictMode
+	}
+	mock.lockCreateUsers.RLock()
+	calls = mock.calls.CreateUsers
+	mock.lockCreateUsers.RUnlock()
+	return calls
+}
+
+// DeactivateStaleUsers calls DeactivateStaleUsersFunc.
+func (mock *UserServiceMock) DeactivateStaleUsers(ctx context.Context, inactiveFor time.Duration, dryRun bool) (*models.UserDeactivateStaleResult, error) {
+	if mock.DeactivateStaleUsersFunc == nil {
+		panic("UserServiceMock.DeactivateStaleUsersFunc: method is nil but UserService.DeactivateStaleUsers was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		InactiveFor time.Duration
+		DryRun      bool
+	}{
+		Ctx:         ctx,
+		InactiveFor: inactiveFor,
+		DryRun:      dryRun,
+	}
+	mock.lockDeactivateStaleUsers.Lock()
+	mock.calls.DeactivateStaleUsers = append(mock.calls.DeactivateStaleUsers, callInfo)
+	mock.lockDeactivateStaleUsers.Unlock()
+	return mock.DeactivateStaleUsersFunc(ctx, inactiveFor, dryRun)
+}
+
+// DeactivateStaleUsersCalls gets all the calls that were made to DeactivateStaleUsers.
+// Check the length with:
+//
+//	len(mockedUserService.DeactivateStaleUsersCalls())
+func (mock *UserServiceMock) DeactivateStaleUsersCalls() []struct {
+	Ctx         context.Context
+	InactiveFor time.Duration
+	DryRun      bool
+} {
+	var calls []struct {
+		Ctx         context.Context
+		InactiveFor time.Duration
+		DryRun      bool
+	}
+	mock.lockDeactivateStaleUsers.RLock()
+	calls = mock.calls.DeactivateStaleUsers
+	mock.lockDeactivateStaleUsers.RUnlock()
+	return calls
+}
+
+// DeleteUser calls DeleteUserFunc.
+func (mock *UserServiceMock) DeleteUser(ctx context.Context, id int64, reassignTo int64) error {
+	if mock.DeleteUserFunc == nil {
+		panic("UserServiceMock.DeleteUserFunc: method is nil but UserService.DeleteUser was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		ID         int64
+		ReassignTo int64
+	}{
+		Ctx:        ctx,
+		ID:         id,
+		ReassignTo: reassignTo,
+	}
+	mock.lockDeleteUser.Lock()
+	mock.calls.DeleteUser = append(mock.calls.DeleteUser, callInfo)
+	mock.lockDeleteUser.Unlock()
+	return mock.DeleteUserFunc(ctx, id, reassignTo)
+}
+
+// DeleteUserCalls gets all the calls that were made to DeleteUser.
+// Check the length with:
+//
+//	len(mockedUserService.DeleteUserCalls())
+func (mock *UserServiceMock) DeleteUserCalls() []struct {
+	Ctx        context.Context
+	ID         int64
+	ReassignTo int64
+} {
+	var calls []struct {
+		Ctx        context.Context
+		ID         int64
+		ReassignTo int64
+	}
+	mock.lockDeleteUser.RLock()
+	calls = mock.calls.DeleteUser
+	mock.lockDeleteUser.RUnlock()
+	return calls
+}
+
+// GetUser calls GetUserFunc.
+func (mock *UserServiceMock) GetUser(ctx context.Context, id int64) (*models.User, error) {
+	if mock.GetUserFunc == nil {
+		panic("UserServiceMock.GetUserFunc: method is nil but UserService.GetUser was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetUser.Lock()
+	mock.calls.GetUser = append(mock.calls.GetUser, callInfo)
+	mock.lockGetUser.Unlock()
+	return mock.GetUserFunc(ctx, id)
+}
+
+// GetUserCalls gets all the calls that were made to GetUser.
+// Check the length with:
+//
+//	len(mockedUserService.GetUserCalls())
+func (mock *UserServiceMock) GetUserCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockGetUser.RLock()
+	calls = mock.calls.GetUser
+	mock.lockGetUser.RUnlock()
+	return calls
+}
+
+// GetUserByEmail calls GetUserByEmailFunc.
+func (mock *UserServiceMock) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	if mock.GetUserByEmailFunc == nil {
+		panic("UserServiceMock.GetUserByEmailFunc: method is nil but UserService.GetUserByEmail was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Email string
+	}{
+		Ctx:   ctx,
+		Email: email,
+	}
+	mock.lockGetUserByEmail.Lock()
+	mock.calls.GetUserByEmail = append(mock.calls.GetUserByEmail, callInfo)
+	mock.lockGetUserByEmail.Unlock()
+	return mock.GetUserByEmailFunc(ctx, email)
+}
+
+// GetUserByEmailCalls gets all the calls that were made to GetUserByEmail.
+// Check the length with:
+//
+//	len(mockedUserService.GetUserByEmailCalls())
+func (mock *UserServiceMock) GetUserByEmailCalls() []struct {
+	Ctx   context.Context
+	Email string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Email string
+	}
+	mock.lockGetUserByEmail.RLock()
+	calls = mock.calls.GetUserByEmail
+	mock.lockGetUserByEmail.RUnlock()
+	return calls
+}
+
+// GetUserByUserName calls GetUserByUserNameFunc.
+func (mock *UserServiceMock) GetUserByUserName(ctx context.Context, userName string) (*models.User, error) {
+	if mock.GetUserByUserNameFunc == nil {
+		panic("UserServiceMock.GetUserByUserNameFunc: method is nil but UserService.GetUserByUserName was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		UserName string
+	}{
+		Ctx:      ctx,
+		UserName: userName,
+	}
+	mock.lockGetUserByUserName.Lock()
+	mock.calls.GetUserByUserName = append(mock.calls.GetUserByUserName, callInfo)
+	mock.lockGetUserByUserName.Unlock()
+	return mock.GetUserByUserNameFunc(ctx, userName)
+}
+
+// GetUserByUserNameCalls gets all the calls that were made to GetUserByUserName.
+// Check the length with:
+//
+//	len(mockedUserService.GetUserByUserNameCalls())
+func (mock *UserServiceMock) GetUserByUserNameCalls() []struct {
+	Ctx      context.Context
+	UserName string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		UserName string
+	}
+	mock.lockGetUserByUserName.RLock()
+	calls = mock.calls.GetUserByUserName
+	mock.lockGetUserByUserName.RUnlock()
+	return calls
+}
+
+// GetUserHistory calls GetUserHistoryFunc.
+func (mock *UserServiceMock) GetUserHistory(ctx context.Context, id int64) ([]models.AuditEntry, error) {
+	if mock.GetUserHistoryFunc == nil {
+		panic("UserServiceMock.GetUserHistoryFunc: method is nil but UserService.GetUserHistory was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetUserHistory.Lock()
+	mock.calls.GetUserHistory = append(mock.calls.GetUserHistory, callInfo)
+	mock.lockGetUserHistory.Unlock()
+	return mock.GetUserHistoryFunc(ctx, id)
+}
+
+// GetUserHistoryCalls gets all the calls that were made to GetUserHistory.
+// Check the length with:
+//
+//	len(mockedUserService.GetUserHistoryCalls())
+func (mock *UserServiceMock) GetUserHistoryCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockGetUserHistory.RLock()
+	calls = mock.calls.GetUserHistory
+	mock.lockGetUserHistory.RUnlock()
+	return calls
+}
+
+// GetUserReports calls GetUserReportsFunc.
+func (mock *UserServiceMock) GetUserReports(ctx context.Context, id int64) ([]models.User, error) {
+	if mock.GetUserReportsFunc == nil {
+		panic("UserServiceMock.GetUserReportsFunc: method is nil but UserService.GetUserReports was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetUserReports.Lock()
+	mock.calls.GetUserReports = append(mock.calls.GetUserReports, callInfo)
+	mock.lockGetUserReports.Unlock()
+	return mock.GetUserReportsFunc(ctx, id)
+}
+
+// GetUserReportsCalls gets all the calls that were made to GetUserReports.
+// Check the length with:
+//
+//	len(mockedUserService.GetUserReportsCalls())
+func (mock *UserServiceMock) GetUserReportsCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockGetUserReports.RLock()
+	calls = mock.calls.GetUserReports
+	mock.lockGetUserReports.RUnlock()
+	return calls
+}
+
+// ListUsers calls ListUsersFunc.
+func (mock *UserServiceMock) ListUsers(ctx context.Context, params models.ListParams) ([]models.User, int, error) {
+	if mock.ListUsersFunc == nil {
+		panic("UserServiceMock.ListUsersFunc: method is nil but UserService.ListUsers was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Params models.ListParams
+	}{
+		Ctx:    ctx,
+		Params: params,
+	}
+	mock.lockListUsers.Lock()
+	mock.calls.ListUsers = append(mock.calls.ListUsers, callInfo)
+	mock.lockListUsers.Unlock()
+	return mock.ListUsersFunc(ctx, params)
+}
+
+// ListUsersCalls gets all the calls that were made to ListUsers.
+// Check the length with:
+//
+//	len(mockedUserService.ListUsersCalls())
+func (mock *UserServiceMock) ListUsersCalls() []struct {
+	Ctx    context.Context
+	Params models.ListParams
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Params models.ListParams
+	}
+	mock.lockListUsers.RLock()
+	calls = mock.calls.ListUsers
+	mock.lockListUsers.RUnlock()
+	return calls
+}
+
+// SearchUsers calls SearchUsersFunc.
+func (mock *UserServiceMock) SearchUsers(ctx context.Context, query string) ([]models.User, error) {
+	if mock.SearchUsersFunc == nil {
+		panic("UserServiceMock.SearchUsersFunc: method is nil but UserService.SearchUsers was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query string
+	}{
+		Ctx:   ctx,
+		Query: query,
+	}
+	mock.lockSearchUsers.Lock()
+	mock.calls.SearchUsers = append(mock.calls.SearchUsers, callInfo)
+	mock.lockSearchUsers.Unlock()
+	return mock.SearchUsersFunc(ctx, query)
+}
+
+// SearchUsersCalls gets all the calls that were made to SearchUsers.
+// Check the length with:
+//
+//	len(mockedUserService.SearchUsersCalls())
+func (mock *UserServiceMock) SearchUsersCalls() []struct {
+	Ctx   context.Context
+	Query string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query string
+	}
+	mock.lockSearchUsers.RLock()
+	calls = mock.calls.SearchUsers
+	mock.lockSearchUsers.RUnlock()
+	return calls
+}
+
+// SetPassword calls SetPasswordFunc.
+func (mock *UserServiceMock) SetPassword(ctx context.Context, id int64, password string) error {
+	if mock.SetPasswordFunc == nil {
+		panic("UserServiceMock.SetPasswordFunc: method is nil but UserService.SetPassword was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		ID       int64
+		Password string
+	}{
+		Ctx:      ctx,
+		ID:       id,
+		Password: password,
+	}
+	mock.lockSetPassword.Lock()
+	mock.calls.SetPassword = append(mock.calls.SetPassword, callInfo)
+	mock.lockSetPassword.Unlock()
+	return mock.SetPasswordFunc(ctx, id, password)
+}
+
+// SetPasswordCalls gets all the calls that were made to SetPassword.
+// Check the length with:
+//
+//	len(mockedUserService.SetPasswordCalls())
+func (mock *UserServiceMock) SetPasswordCalls() []struct {
+	Ctx      context.Context
+	ID       int64
+	Password string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		ID       int64
+		Password string
+	}
+	mock.lockSetPassword.RLock()
+	calls = mock.calls.SetPassword
+	mock.lockSetPassword.RUnlock()
+	return calls
+}
+
+// StreamUsers calls StreamUsersFunc.
+func (mock *UserServiceMock) StreamUsers(ctx context.Context, params models.ListParams, fn func(user *models.User) error) error {
+	if mock.StreamUsersFunc == nil {
+		panic("UserServiceMock.StreamUsersFunc: method is nil but UserService.StreamUsers was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Params models.ListParams
+		Fn     func(user *models.User) error
+	}{
+		Ctx:    ctx,
+		Params: params,
+		Fn:     fn,
+	}
+	mock.lockStreamUsers.Lock()
+	mock.calls.StreamUsers = append(mock.calls.StreamUsers, callInfo)
+	mock.lockStreamUsers.Unlock()
+	return mock.StreamUsersFunc(ctx, params, fn)
+}
+
+// StreamUsersCalls gets all the calls that were made to StreamUsers.
+// Check the length with:
+//
+//	len(mockedUserService.StreamUsersCalls())
+func (mock *UserServiceMock) StreamUsersCalls() []struct {
+	Ctx    context.Context
+	Params models.ListParams
+	Fn     func(user *models.User) error
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Params models.ListParams
+		Fn     func(user *models.User) error
+	}
+	mock.lockStreamUsers.RLock()
+	calls = mock.calls.StreamUsers
+	mock.lockStreamUsers.RUnlock()
+	return calls
+}
+
+// UpdateStatus calls UpdateStatusFunc.
+func (mock *UserServiceMock) UpdateStatus(ctx context.Context, ids []int64, status models.UserStatus) (*models.UserStatusUpdateResult, error) {
+	if mock.UpdateStatusFunc == nil {
+		panic("UserServiceMock.UpdateStatusFunc: method is nil but UserService.UpdateStatus was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Ids    []int64
+		Status models.UserStatus
+	}{
+		Ctx:    ctx,
+		Ids:    ids,
+		Status: status,
+	}
+	mock.lockUpdateStatus.Lock()
+	mock.calls.UpdateStatus = append(mock.calls.UpdateStatus, callInfo)
+	mock.lockUpdateStatus.Unlock()
+	return mock.UpdateStatusFunc(ctx, ids, status)
+}
+
+// UpdateStatusCalls gets all the calls that were made to UpdateStatus.
+// Check the length with:
+//
+//	len(mockedUserService.UpdateStatusCalls())
+func (mock *UserServiceMock) UpdateStatusCalls() []struct {
+	Ctx    context.Context
+	Ids    []int64
+	Status models.UserStatus
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Ids    []int64
+		Status models.UserStatus
+	}
+	mock.lockUpdateStatus.RLock()
+	calls = mock.calls.UpdateStatus
+	mock.lockUpdateStatus.RUnlock()
+	return calls
+}
+
+// UpdateUser calls UpdateUserFunc.
+func (mock *UserServiceMock) UpdateUser(ctx context.Context, id int64, req models.UserUpdateRequest) (*models.User, error) {
+	if mock.UpdateUserFunc == nil {
+		panic("UserServiceMock.UpdateUserFunc: method is nil but UserService.UpdateUser was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+		Req models.UserUpdateRequest
+	}{
+		Ctx: ctx,
+		ID:  id,
+		Req: req,
+	}
+	mock.lockUpdateUser.Lock()
+	mock.calls.UpdateUser = append(mock.calls.UpdateUser, callInfo)
+	mock.lockUpdateUser.Unlock()
+	return mock.UpdateUserFunc(ctx, id, req)
+}
+
+// UpdateUserCalls gets all the calls that were made to UpdateUser.
+// Check the length with:
+//
+//	len(mockedUserService.UpdateUserCalls())
+func (mock *UserServiceMock) UpdateUserCalls() []struct {
+	Ctx context.Context
+	ID  int64
+	Req models.UserUpdateRequest
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+		Req models.UserUpdateRequest
+	}
+	mock.lockUpdateUser.RLock()
+	calls = mock.calls.UpdateUser
+	mock.lockUpdateUser.RUnlock()
+	return calls
+}
+
+// UpdateUsers calls UpdateUsersFunc.
+func (mock *UserServiceMock) UpdateUsers(ctx context.Context, items []models.UserBatchUpdateItem) (*models.UserBatchUpdateResult, error) {
+	if mock.UpdateUsersFunc == nil {
+		panic("UserServiceMock.UpdateUsersFunc: method is nil but UserService.UpdateUsers was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Items []models.UserBatchUpdateItem
+	}{
+		Ctx:   ctx,
+		Items: items,
+	}
+	mock.lockUpdateUsers.Lock()
+	mock.calls.UpdateUsers = append(mock.calls.UpdateUsers, callInfo)
+	mock.lockUpdateUsers.Unlock()
+	return mock.UpdateUsersFunc(ctx, items)
+}
+
+// UpdateUsersCalls gets all the calls that were made to UpdateUsers.
+// Check the length with:
+//
+//	len(mockedUserService.UpdateUsersCalls())
+func (mock *UserServiceMock) UpdateUsersCalls() []struct {
+	Ctx   context.Context
+	Items []models.UserBatchUpdateItem
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Items []models.UserBatchUpdateItem
+	}
+	mock.lockUpdateUsers.RLock()
+	calls = mock.calls.UpdateUsers
+	mock.lockUpdateUsers.RUnlock()
+	return calls
+}
+
+// UserExists calls UserExistsFunc.
+func (mock *UserServiceMock) UserExists(ctx context.Context, id int64) (bool, error) {
+	if mock.UserExistsFunc == nil {
+		panic("UserServiceMock.UserExistsFunc: method is nil but UserService.UserExists was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockUserExists.Lock()
+	mock.calls.UserExists = append(mock.calls.UserExists, callInfo)
+	mock.lockUserExists.Unlock()
+	return mock.UserExistsFunc(ctx, id)
+}
+
+// UserExistsCalls gets all the calls that were made to UserExists.
+// Check the length with:
+//
+//	len(mockedUserService.UserExistsCalls())
+func (mock *UserServiceMock) UserExistsCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockUserExists.RLock()
+	calls = mock.calls.UserExists
+	mock.lockUserExists.RUnlock()
+	return calls
+}
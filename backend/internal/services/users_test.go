@@ -0,0 +1,468 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"user-management/internal/models"
+	"user-management/internal/repository"
+)
+
+func TestInvalidStatus(t *testing.T) {
+	t.Parallel()
+
+	// rejected before reaching the repository
+	svc := NewUserService(nil)
+	common := models.UserCommon{UserName: "johndoe", Email: "john@example.com", UserStatus: "X"}
+
+	_, err := svc.CreateUser(context.Background(), models.UserCreateRequest{UserCommon: common})
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+
+	_, err = svc.UpdateUser(context.Background(), 1, models.UserUpdateRequest{UserCommon: common})
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+}
+
+// racingUserRepository passes the uniqueness checks but fails the insert,
+// as if a concurrent transaction created the same user in between
+type racingUserRepository struct {
+	repository.UserRepository
+
+	constraint string
+}
+
+func (r *racingUserRepository) RunInTx(ctx context.Context, fn func(ctx context.Context, repo repository.UserRepository) error) error {
+	return fn(ctx, r)
+}
+
+func (r *racingUserRepository) ExistsByUserName(_ context.Context, _ string) (bool, error) {
+	return false, nil
+}
+
+func (r *racingUserRepository) ExistsByEmail(_ context.Context, _ string, _ int64) (bool, error) {
+	return false, nil
+}
+
+func (r *racingUserRepository) Create(_ context.Context, _ *models.User) error {
+	return &repository.UniqueViolationError{Constraint: r.constraint, Err: errors.New("duplicate key value")}
+}
+
+func TestCreateUserUniqueViolation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		constraint string
+		expected   error
+	}{
+		{constraint: "users_email_key", expected: ErrEmailExists},
+		{constraint: "users_user_name_key", expected: ErrUsernameExists},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.constraint, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewUserService(&racingUserRepository{constraint: tc.constraint})
+			_, err := svc.CreateUser(context.Background(), models.UserCreateRequest{UserCommon: models.UserCommon{
+				UserName:   "johndoe",
+				Email:      "john@example.com",
+				UserStatus: models.UserStatusActive,
+			}})
+			require.ErrorIs(t, err, tc.expected)
+		})
+	}
+
+	t.Run("unknown constraint is passed through", func(t *testing.T) {
+		t.Parallel()
+
+		svc := NewUserService(&racingUserRepository{constraint: "users_pkey"})
+		_, err := svc.CreateUser(context.Background(), models.UserCreateRequest{UserCommon: models.UserCommon{
+			UserName:   "johndoe",
+			Email:      "john@example.com",
+			UserStatus: models.UserStatusActive,
+		}})
+
+		var violation *repository.UniqueViolationError
+		assert.ErrorAs(t, err, &violation)
+	})
+}
+
+func TestActorFrom(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, SystemActor, ActorFrom(context.Background()))
+	assert.Equal(t, SystemActor, ActorFrom(WithActor(context.Background(), "")))
+	assert.Equal(t, "alice", ActorFrom(WithActor(context.Background(), "alice")))
+}
+
+func TestDryRunInMemory(t *testing.T) {
+	t.Parallel()
+
+	svc := NewUserService(repository.NewInMemoryUserRepository())
+	ctx := context.Background()
+	req := models.UserCreateRequest{UserCommon: models.UserCommon{
+		UserName:   "johndoe",
+		FirstName:  "John",
+		LastName:   "Doe",
+		Email:      "john@example.com",
+		UserStatus: models.UserStatusActive,
+	}}
+
+	user, err := svc.CreateUser(WithDryRun(ctx), req)
+	require.NoError(t, err)
+	assert.Equal(t, "johndoe", user.UserName)
+
+	_, total, err := svc.ListUsers(ctx, models.ListParams{})
+	require.NoError(t, err)
+	assert.Zero(t, total, "the dry run was rolled back")
+
+	_, err = svc.CreateUser(ctx, req)
+	require.NoError(t, err)
+	_, err = svc.CreateUser(ctx, req)
+	require.ErrorIs(t, err, ErrUsernameExists)
+}
+
+func TestCreateUserEmailExistsMock(t *testing.T) {
+	t.Parallel()
+
+	repo := &repository.UserRepositoryMock{
+		ExistsByUserNameFunc: func(_ context.Context, _ string) (bool, error) { return false, nil },
+		ExistsByEmailFunc:    func(_ context.Context, _ string, _ int64) (bool, error) { return true, nil },
+	}
+	repo.RunInTxFunc = func(ctx context.Context, fn func(ctx context.Context, repo repository.UserRepository) error) error {
+		return fn(ctx, repo)
+	}
+
+	_, err := NewUserService(repo).CreateUser(context.Background(), models.UserCreateRequest{UserCommon: models.UserCommon{
+		UserName:   "johndoe",
+		Email:      "john@example.com",
+		UserStatus: models.UserStatusActive,
+	}})
+	require.ErrorIs(t, err, ErrEmailExists)
+
+	require.Len(t, repo.ExistsByEmailCalls(), 1)
+	assert.Equal(t, "john@example.com", repo.ExistsByEmailCalls()[0].Email)
+	assert.Empty(t, repo.CreateCalls(), "nothing is inserted")
+}
+
+func TestDeactivateStaleUsersDryRunMock(t *testing.T) {
+	t.Parallel()
+
+	stale := []models.User{{UserID: 3}, {UserID: 5}}
+	repo := &repository.UserRepositoryMock{
+		ListStaleFunc: func(_ context.Context, _ time.Time) ([]models.User, error) { return stale, nil },
+	}
+
+	result, err := NewUserService(repo).DeactivateStaleUsers(context.Background(), 90*24*time.Hour, true)
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 2, result.Count)
+	assert.Equal(t, stale, result.Users)
+
+	// read without a transaction nor row locks, nothing is changed
+	assert.Empty(t, repo.RunInTxCalls())
+	assert.Empty(t, repo.ListStaleForUpdateCalls())
+	assert.Empty(t, repo.UpdateStatusCalls())
+}
+
+func TestUserExists(t *testing.T) {
+	t.Parallel()
+
+	repo := repository.NewInMemoryUserRepository()
+	svc := NewUserService(repo)
+	ctx := context.Background()
+
+	user, err := svc.CreateUser(ctx, models.UserCreateRequest{UserCommon: models.UserCommon{
+		UserName:   "johndoe",
+		Email:      "john@example.com",
+		UserStatus: models.UserStatusActive,
+	}})
+	require.NoError(t, err)
+
+	exists, err := svc.UserExists(ctx, user.UserID)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = svc.UserExists(ctx, user.UserID+1)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestUnknownDepartment(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	req := models.UserCreateRequest{UserCommon: models.UserCommon{
+		UserName:   "johndoe",
+		FirstName:  "John",
+		LastName:   "Doe",
+		Email:      "john@example.com",
+		UserStatus: models.UserStatusActive,
+		Department: "Research",
+	}}
+
+	_, err := NewUserService(repository.NewInMemoryUserRepository()).CreateUser(ctx, req)
+	require.ErrorIs(t, err, ErrUnknownDepartment)
+	var unknown *UnknownDepartmentError
+	require.ErrorAs(t, err, &unknown)
+	assert.Equal(t, "Research", unknown.Department)
+
+	repo := repository.NewInMemoryUserRepository()
+	_, err = NewUserService(repo, WithAutoCreateDepartments(true)).CreateUser(ctx, req)
+	require.NoError(t, err)
+	exists, err := repo.Departments().ExistsByName(ctx, "Research")
+	require.NoError(t, err)
+	assert.True(t, exists, "the department was created with the user")
+}
+
+func TestManagerCycle(t *testing.T) {
+	t.Parallel()
+
+	svc := NewUserService(repository.NewInMemoryUserRepository())
+	ctx := context.Background()
+
+	// each user manages the next one: alice > bob > carol
+	request := func(userName string, managerID *int64) models.UserCommon {
+		return models.UserCommon{
+			UserName:   userName,
+			Email:      userName + "@example.com",
+			UserStatus: models.UserStatusActive,
+			ManagerID:  managerID,
+		}
+	}
+	var users []*models.User
+	var managerID *int64
+	for _, userName := range []string{"alice", "bob", "carol"} {
+		user, err := svc.CreateUser(ctx, models.UserCreateRequest{UserCommon: request(userName, managerID)})
+		require.NoError(t, err)
+		users = append(users, user)
+		managerID = &user.UserID
+	}
+	alice, carol := users[0], users[2]
+
+	testCases := []struct {
+		name      string
+		managerID int64
+		expected  error
+	}{
+		{"self", alice.UserID, ErrManagerCycle},
+		{"chain", carol.UserID, ErrManagerCycle},
+		{"unknown", 999, ErrUnknownManager},
+	}
+	for _, tc := range testCases {
+		_, err := svc.UpdateUser(ctx, alice.UserID, models.UserUpdateRequest{UserCommon: request("alice", &tc.managerID)})
+		require.ErrorIs(t, err, tc.expected, tc.name)
+		var managerErr *InvalidManagerError
+		require.ErrorAs(t, err, &managerErr, tc.name)
+		assert.Equal(t, tc.managerID, managerErr.ManagerID, tc.name)
+	}
+
+	// carol can move under alice, who's above her already
+	_, err := svc.UpdateUser(ctx, carol.UserID, models.UserUpdateRequest{UserCommon: request("carol", &alice.UserID)})
+	require.NoError(t, err)
+
+	reports, err := svc.GetUserReports(ctx, alice.UserID)
+	require.NoError(t, err)
+	assert.Len(t, reports, 2)
+
+	user, err := svc.GetUser(ctx, carol.UserID)
+	require.NoError(t, err)
+	require.NotNil(t, user.Manager)
+	assert.Equal(t, "alice", user.Manager.UserName)
+
+	_, err = svc.GetUserReports(ctx, 999)
+	require.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestDeleteManager(t *testing.T) {
+	t.Parallel()
+
+	repo := repository.NewInMemoryUserRepository()
+	svc := NewUserService(repo)
+	ctx := context.Background()
+
+	// alice manages bob, who manages carol
+	var managerID *int64
+	for _, userName := range []string{"alice", "bobby", "carol"} {
+		user, err := svc.CreateUser(ctx, models.UserCreateRequest{UserCommon: models.UserCommon{
+			UserName:   userName,
+			Email:      userName + "@example.com",
+			UserStatus: models.UserStatusActive,
+			ManagerID:  managerID,
+		}})
+		require.NoError(t, err)
+		managerID = &user.UserID
+	}
+	const alice, bob, carol = 1, 2, 3
+
+	testCases := []struct {
+		name       string
+		id         int64
+		reassignTo int64
+		expected   error
+	}{
+		{"no reassignment", bob, 0, ErrHasReports},
+		{"self-reference", bob, bob, ErrManagerCycle},
+		{"direct report", alice, bob, ErrManagerCycle},
+		{"chain", alice, carol, ErrManagerCycle},
+		{"unknown", bob, 999, ErrUnknownManager},
+	}
+	for _, tc := range testCases {
+		require.ErrorIs(t, svc.DeleteUser(ctx, tc.id, tc.reassignTo), tc.expected, tc.name)
+		exists, err := svc.UserExists(ctx, tc.id)
+		require.NoError(t, err)
+		assert.True(t, exists, "%s: the deletion was rolled back", tc.name)
+	}
+
+	require.NoError(t, svc.DeleteUser(ctx, bob, alice))
+	user, err := svc.GetUser(ctx, carol)
+	require.NoError(t, err)
+	assert.Equal(t, int64(alice), *user.ManagerID)
+	assert.Equal(t, int64(2), user.Version, "the reassignment is an update")
+
+	history, err := svc.GetUserHistory(ctx, carol)
+	require.NoError(t, err)
+	assert.Equal(t, models.AuditActionUpdate, history[0].Action)
+	assert.Contains(t, history[0].Diff, "managerId")
+
+	// the reassignment is ignored without reports
+	require.NoError(t, svc.DeleteUser(ctx, carol, 999))
+}
+
+// outboxEvents returns the type and user ID of every unsent outbox event, in order
+func outboxEvents(t *testing.T, repo repository.UserRepository) []string {
+	t.Helper()
+
+	entries, err := repo.Outbox().ListUnsent(context.Background(), 100)
+	require.NoError(t, err)
+	events := make([]string, len(entries))
+	for i, entry := range entries {
+		events[i] = fmt.Sprintf("%s %d", entry.Event.Type, entry.Event.User.UserID)
+	}
+	return events
+}
+
+func TestOutbox(t *testing.T) {
+	t.Parallel()
+
+	repo := repository.NewInMemoryUserRepository()
+	svc := NewUserService(repo, WithOutbox(true))
+	ctx := context.Background()
+	newReq := func(userName string, managerID *int64) models.UserCreateRequest {
+		return models.UserCreateRequest{UserCommon: models.UserCommon{
+			UserName:   userName,
+			Email:      userName + "@example.com",
+			UserStatus: models.UserStatusActive,
+			ManagerID:  managerID,
+		}}
+	}
+
+	alice, err := svc.CreateUser(ctx, newReq("alice", nil))
+	require.NoError(t, err)
+	_, err = svc.CreateUsers(ctx, []models.UserCreateRequest{newReq("bobby", &alice.UserID), newReq("carol", nil)}, models.ConflictModeAtomic)
+	require.NoError(t, err)
+
+	// the events of the failures and the dry runs are rolled back with them
+	_, err = svc.CreateUser(ctx, newReq("alice", nil))
+	require.ErrorIs(t, err, ErrUsernameExists)
+	_, err = svc.CreateUser(WithDryRun(ctx), newReq("david", nil))
+	require.NoError(t, err)
+	require.ErrorIs(t, svc.DeleteUser(ctx, alice.UserID, 0), ErrHasReports)
+
+	_, err = svc.UpdateStatus(ctx, []int64{1, 2, 3}, models.UserStatusActive)
+	require.NoError(t, err)
+	_, err = svc.UpdateStatus(ctx, []int64{1, 3}, models.UserStatusInactive)
+	require.NoError(t, err)
+
+	// bobby is moved to carol
+	require.NoError(t, svc.DeleteUser(WithRequestID(ctx, "req-1"), alice.UserID, 3))
+
+	assert.Equal(t, []string{
+		"user.created 1",
+		"user.created 2",
+		"user.created 3",
+		"user.updated 1",
+		"user.updated 3",
+		"user.updated 2",
+		"user.deleted 1",
+	}, outboxEvents(t, repo))
+
+	entries, err := repo.Outbox().ListUnsent(ctx, 100)
+	require.NoError(t, err)
+	deleted := entries[len(entries)-1].Event
+	assert.Equal(t, "alice", deleted.User.UserName, "the deleted user as it was")
+	assert.False(t, deleted.Timestamp.IsZero())
+	assert.Equal(t, "req-1", deleted.RequestID)
+	assert.Empty(t, entries[0].Event.RequestID)
+	assert.Equal(t, models.UserStatusInactive, entries[3].Event.User.UserStatus)
+	assert.Equal(t, int64(3), *entries[5].Event.User.ManagerID)
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		repo := repository.NewInMemoryUserRepository()
+		_, err := NewUserService(repo).CreateUser(ctx, newReq("alice", nil))
+		require.NoError(t, err)
+		assert.Empty(t, outboxEvents(t, repo))
+	})
+}
+
+func TestPassword(t *testing.T) {
+	t.Parallel()
+
+	repo := repository.NewInMemoryUserRepository()
+	svc := NewUserService(repo)
+	ctx := context.Background()
+	newReq := func(userName, password string) models.UserCreateRequest {
+		return models.UserCreateRequest{
+			UserCommon: models.UserCommon{
+				UserName:   userName,
+				Email:      userName + "@example.com",
+				UserStatus: models.UserStatusActive,
+			},
+			Password: password,
+		}
+	}
+
+	alice, err := svc.CreateUser(ctx, newReq("alice", "correct-horse"))
+	require.NoError(t, err)
+	require.NoError(t, bcrypt.CompareHashAndPassword([]byte(alice.PasswordHash), []byte("correct-horse")))
+
+	// the hash is never serialized, including in the audit log
+	body, err := json.Marshal(alice)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), alice.PasswordHash)
+	history, err := svc.GetUserHistory(ctx, alice.UserID)
+	require.NoError(t, err)
+	body, err = json.Marshal(history)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), alice.PasswordHash)
+
+	result, err := svc.CreateUsers(ctx, []models.UserCreateRequest{newReq("bobby", ""), newReq("carol", "battery-staple")}, models.ConflictModeAtomic)
+	require.NoError(t, err)
+	assert.Empty(t, result.Created[0].PasswordHash, "the password is optional")
+	require.NoError(t, bcrypt.CompareHashAndPassword([]byte(result.Created[1].PasswordHash), []byte("battery-staple")))
+
+	_, err = svc.CreateUser(ctx, newReq("david", "short"))
+	require.ErrorIs(t, err, ErrInvalidPassword)
+	_, err = svc.CreateUsers(ctx, []models.UserCreateRequest{newReq("david", strings.Repeat("é", 37))}, models.ConflictModeAtomic)
+	require.ErrorIs(t, err, ErrInvalidPassword, "74 bytes, past the bcrypt limit")
+
+	require.NoError(t, svc.SetPassword(WithActor(ctx, "jane.admin"), alice.UserID, "staple-battery"))
+	reset, err := repo.GetByID(ctx, alice.UserID)
+	require.NoError(t, err)
+	require.NoError(t, bcrypt.CompareHashAndPassword([]byte(reset.PasswordHash), []byte("staple-battery")))
+	assert.Equal(t, alice.Version+1, reset.Version)
+	assert.Equal(t, "jane.admin", reset.UpdatedBy)
+
+	require.ErrorIs(t, svc.SetPassword(ctx, alice.UserID, ""), ErrInvalidPassword)
+	require.ErrorIs(t, svc.SetPassword(ctx, 42, "staple-battery"), ErrUserNotFound)
+}
@@ -0,0 +1,73 @@
+// Package tracing sets up the OpenTelemetry tracer provider.
+package tracing
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/fx"
+
+	"user-management/internal/config"
+	"user-management/internal/version"
+)
+
+// TracerName names the tracer of the application's own spans
+const TracerName = "user-management"
+
+// TracesURL returns the URL the spans are posted to: like OTEL_EXPORTER_OTLP_ENDPOINT in the OpenTelemetry spec,
+// the endpoint is the collector's base URL, the traces path is appended to it
+func TracesURL(endpoint string) string {
+	return strings.TrimSuffix(endpoint, "/") + "/v1/traces"
+}
+
+// NewTracerProvider exports the spans over OTLP/HTTP to the configured endpoint,
+// without an endpoint tracing is a no-op. The provider is also installed as the global one.
+// The W3C trace context propagator is installed either way, so incoming traceparent headers are honored:
+// a no-op span still carries the incoming trace, whose ID the request logs then show.
+func NewTracerProvider(lc fx.Lifecycle, cfg *config.Config) (trace.TracerProvider, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if cfg.Tracing.OTLPEndpoint == "" {
+		return noop.NewTracerProvider(), nil
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(TracesURL(cfg.Tracing.OTLPEndpoint)))
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(config.AppName),
+			semconv.ServiceVersion(version.Get().Version),
+		)),
+	)
+
+	otel.SetTracerProvider(tp)
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			// flushes the spans still buffered by the batcher
+			if err := tp.Shutdown(ctx); err != nil {
+				slog.With("error", err).Error("failed to shut down the tracer provider")
+				return err
+			}
+			return nil
+		},
+	})
+
+	slog.With("endpoint", cfg.Tracing.OTLPEndpoint).Info("Tracing enabled")
+
+	return tp, nil
+}
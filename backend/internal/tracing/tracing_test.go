@@ -0,0 +1,15 @@
+package tracing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracesURL(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "http://localhost:4318/v1/traces", TracesURL("http://localhost:4318"))
+	assert.Equal(t, "http://localhost:4318/v1/traces", TracesURL("http://localhost:4318/"))
+	assert.Equal(t, "https://collector.example.com/otlp/v1/traces", TracesURL("https://collector.example.com/otlp"))
+}
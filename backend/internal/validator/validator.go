@@ -1,12 +1,17 @@
 package validator
 
 import (
+	"errors"
 	"log/slog"
 	"os"
+	"reflect"
 	"regexp"
 	"strings"
 
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
 	"github.com/labstack/echo/v4"
 )
 
@@ -15,6 +20,31 @@ const alphaUnicodeNumericRegexString = `^[\p{L}\p{N},.:;&# ]+$`
 // wrapper implementation.
 type wrapper struct {
 	validator *validator.Validate
+	trans     ut.Translator
+}
+
+// Error is returned by the echo validator for rejected fields, its message is made of the English sentences
+// describing every rejected field, e.g. "userName must be at least 4 characters in length"
+type Error struct {
+	Fields validator.ValidationErrors
+	trans  ut.Translator
+}
+
+func (e *Error) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, fe := range e.Fields {
+		messages = append(messages, e.Translate(fe))
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *Error) Unwrap() error {
+	return e.Fields
+}
+
+// Translate returns the sentence describing a rejected field
+func (e *Error) Translate(fe validator.FieldError) string {
+	return fe.Translate(e.trans)
 }
 
 // NewValidator creates a new validator with custom validation
@@ -37,14 +67,77 @@ func NewEchoValidator() echo.Validator {
 		os.Exit(1)
 	}
 
+	// report the fields by their JSON keys, the names the API clients know
+	v.RegisterTagNameFunc(jsonFieldName)
+
+	trans, err := NewTranslator(v)
+	if err != nil {
+		slog.With("error", err).
+			Error("failed to register validation translations")
+		os.Exit(1)
+	}
+
 	return &wrapper{
 		validator: v,
+		trans:     trans,
 	}
 }
 
-// Validate data
+// customTranslations are the messages of the tags the default English translations don't cover
+var customTranslations = map[string]string{
+	"alphanumunicode":           "{0} can only contain letters and digits",
+	"alphaNumUnicodeWithSpaces": "{0} can only contain letters, digits, spaces and , . : ; & #",
+}
+
+// NewTranslator registers the English messages of the built-in tags and the custom ones on the validator
+func NewTranslator(v *validator.Validate) (ut.Translator, error) {
+	english := en.New()
+	trans, _ := ut.New(english, english).GetTranslator("en")
+
+	if err := entranslations.RegisterDefaultTranslations(v, trans); err != nil {
+		return nil, err
+	}
+
+	for tag, text := range customTranslations {
+		err := v.RegisterTranslation(tag, trans,
+			func(trans ut.Translator) error {
+				return trans.Add(tag, text, false)
+			},
+			func(trans ut.Translator, fe validator.FieldError) string {
+				message, _ := trans.T(fe.Tag(), fe.Field())
+				return message
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return trans, nil
+}
+
+// jsonFieldName returns the JSON key of a struct field, or the Go name when it has none
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	default:
+		return name
+	}
+}
+
+// Validate data, the rejected fields are reported in an *Error
 func (v *wrapper) Validate(i any) error {
-	return v.validator.Struct(i)
+	err := v.validator.Struct(i)
+
+	var fieldErrs validator.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		return &Error{Fields: fieldErrs, trans: v.trans}
+	}
+	return err
 }
 
 // alphaUnicodeNumericRegex returns a compiled regex
@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestIsAlphanumUnicodeWithSpaces performs a matrix test for the validation function
@@ -63,6 +64,52 @@ func TestIsAlphanumUnicodeWithSpaces(t *testing.T) {
 	}
 }
 
+// TestEchoValidatorTranslations checks every tag used by the user models comes back as an English sentence
+func TestEchoValidatorTranslations(t *testing.T) {
+	t.Parallel()
+
+	type TestStruct struct {
+		UserName   string `json:"userName" validate:"required,min=4,alphanum"`
+		FirstName  string `json:"firstName" validate:"alphanumunicode,max=5"`
+		Email      string `json:"email" validate:"email"`
+		UserStatus string `json:"userStatus" validate:"oneof=A I T"`
+		Department string `json:"department" validate:"alphaNumUnicodeWithSpaces"`
+		Version    int64  `json:"version" validate:"gt=0"`
+	}
+
+	testCases := []struct {
+		name     string
+		input    TestStruct
+		expected string
+	}{
+		{"required", TestStruct{}, "userName is a required field"},
+		{"min", TestStruct{UserName: "abc"}, "userName must be at least 4 characters in length"},
+		{"alphanum", TestStruct{UserName: "john-doe"}, "userName can only contain alphanumeric characters"},
+		{"alphanumunicode", TestStruct{FirstName: "J@"}, "firstName can only contain letters and digits"},
+		{"max", TestStruct{FirstName: "Johnathan"}, "firstName must be a maximum of 5 characters in length"},
+		{"email", TestStruct{Email: "john"}, "email must be a valid email address"},
+		{"oneof", TestStruct{UserStatus: "X"}, "userStatus must be one of [A I T]"},
+		{"custom", TestStruct{Department: "R&D!"}, "department can only contain letters, digits, spaces and , . : ; & #"},
+		{"gt", TestStruct{Version: -1}, "version must be greater than 0"},
+	}
+
+	v := NewEchoValidator()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var validationErr *Error
+			require.ErrorAs(t, v.Validate(tc.input), &validationErr)
+
+			messages := make([]string, 0, len(validationErr.Fields))
+			for _, fe := range validationErr.Fields {
+				messages = append(messages, validationErr.Translate(fe))
+			}
+			assert.Contains(t, messages, tc.expected)
+		})
+	}
+}
+
 // Benchmark the validation function
 func BenchmarkIsAlphanumUnicodeWithSpaces(b *testing.B) {
 	v := validator.New()
@@ -0,0 +1,62 @@
+// Package version reports the build information of the running binary.
+package version
+
+import (
+	"runtime/debug"
+)
+
+// Set at build time, e.g.
+//
+//	go build -ldflags="-X user-management/internal/version.Version=v1.2.0 -X user-management/internal/version.Revision=$(git rev-parse HEAD)"
+//
+// When left empty they're filled from the build info Go embeds in the binary where available.
+var (
+	Version   string
+	Revision  string
+	BuildTime string
+)
+
+// Info describes the running binary
+type Info struct {
+	Version   string `json:"version" example:"v1.2.0"`
+	Revision  string `json:"revision" example:"8f3c2a1"`
+	BuildTime string `json:"buildTime,omitempty" example:"2025-04-15T12:00:00Z"`
+	GoVersion string `json:"goVersion" example:"go1.24.1"`
+} // @name VersionInfo
+
+// Get returns the build information, the ldflags-injected values take precedence over the embedded build info
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Revision:  Revision,
+		BuildTime: BuildTime,
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.GoVersion = bi.GoVersion
+		if info.Version == "" && bi.Main.Version != "(devel)" {
+			info.Version = bi.Main.Version
+		}
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Revision == "" {
+					info.Revision = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = setting.Value
+				}
+			}
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+	if info.Revision == "" {
+		info.Revision = "unknown"
+	}
+
+	return info
+}
@@ -0,0 +1,25 @@
+package version
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	info := Get()
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+	assert.NotEmpty(t, info.Version)
+	assert.NotEmpty(t, info.Revision)
+
+	Version, Revision, BuildTime = "v1.2.0", "8f3c2a1", "2025-04-15T12:00:00Z"
+	t.Cleanup(func() { Version, Revision, BuildTime = "", "", "" })
+
+	assert.Equal(t, Info{
+		Version:   "v1.2.0",
+		Revision:  "8f3c2a1",
+		BuildTime: "2025-04-15T12:00:00Z",
+		GoVersion: runtime.Version(),
+	}, Get())
+}
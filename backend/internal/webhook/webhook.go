@@ -0,0 +1,110 @@
+// Package webhook posts the user change events to the configured URLs.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"user-management/internal/config"
+	"user-management/internal/models"
+)
+
+// Dispatcher posts the events to every URL, retrying the failed deliveries with an exponential backoff.
+// A delivery succeeds on any 2xx response.
+type Dispatcher struct {
+	urls       []string
+	client     *http.Client
+	maxRetries int
+	backoff    time.Duration
+}
+
+// New creates a dispatcher posting to the urls, each attempt timing out after timeout.
+// A failed delivery is retried maxRetries times, first after backoff which then doubles on every retry.
+func New(urls []string, timeout time.Duration, maxRetries int, backoff time.Duration) *Dispatcher {
+	return &Dispatcher{
+		urls:       urls,
+		client:     &http.Client{Timeout: timeout},
+		maxRetries: maxRetries,
+		backoff:    backoff,
+	}
+}
+
+// Send posts the event to every URL, it fails when any of them kept failing.
+// The URLs that succeeded get the event again when it's sent once more.
+func (d *Dispatcher) Send(ctx context.Context, event models.UserEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	var failed error
+	for _, target := range d.urls {
+		if err := d.deliver(ctx, target, body, event.RequestID); err != nil {
+			slog.With("error", err).
+				With("url", config.RedactURL(target)).
+				With("type", event.Type).
+				With("user_id", event.User.UserID).
+				Error("webhook delivery failed")
+			failed = fmt.Errorf("webhook %s: %w", config.RedactURL(target), err)
+		}
+	}
+	return failed
+}
+
+// deliver posts the body to the URL, retrying on failure
+func (d *Dispatcher) deliver(ctx context.Context, target string, body []byte, requestID string) error {
+	wait := d.backoff
+	for attempt := 0; ; attempt++ {
+		err := d.post(ctx, target, body, requestID)
+		if err == nil || attempt == d.maxRetries {
+			return err
+		}
+
+		slog.With("error", err).
+			With("url", config.RedactURL(target)).
+			With("attempt", attempt+1).
+			Debug("webhook delivery failed, retrying")
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+		wait *= 2
+	}
+}
+
+// post posts the body to the URL, with the ID of the request that made the change when known.
+// The URL in the client errors is redacted, they end up in the logs.
+func (d *Dispatcher) post(ctx context.Context, target string, body []byte, requestID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if requestID != "" {
+		req.Header.Set("X-Request-ID", requestID)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = config.RedactURL(urlErr.URL)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user-management/internal/models"
+)
+
+func testEvent(id int64) models.UserEvent {
+	return models.UserEvent{
+		Type:      models.UserEventCreated,
+		User:      models.User{UserID: id, UserCommon: models.UserCommon{UserName: "john.doe"}},
+		Timestamp: time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestDispatcherSends(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan models.UserEvent, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Empty(t, r.Header.Get("X-Request-ID"), "only sent when known")
+
+		var event models.UserEvent
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		received <- event
+	}))
+	defer srv.Close()
+
+	d := New([]string{srv.URL, srv.URL + "/other"}, time.Second, 0, time.Millisecond)
+	require.NoError(t, d.Send(context.Background(), testEvent(7)))
+
+	require.Len(t, received, 2)
+	event := <-received
+	assert.Equal(t, models.UserEventCreated, event.Type)
+	assert.Equal(t, int64(7), event.User.UserID)
+	assert.Equal(t, "john.doe", event.User.UserName)
+	assert.True(t, testEvent(7).Timestamp.Equal(event.Timestamp))
+}
+
+func TestDispatcherSendsRequestID(t *testing.T) {
+	t.Parallel()
+
+	requestIDs := make(chan string, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestIDs <- r.Header.Get("X-Request-ID")
+		// the retry carries it too
+		if len(requestIDs) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	event := testEvent(7)
+	event.RequestID = "req-1"
+	require.NoError(t, New([]string{srv.URL}, time.Second, 1, time.Millisecond).Send(context.Background(), event))
+
+	require.Len(t, requestIDs, 2)
+	assert.Equal(t, "req-1", <-requestIDs)
+	assert.Equal(t, "req-1", <-requestIDs)
+}
+
+func TestDispatcherRetries(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		failures   int32
+		maxRetries int
+		attempts   int32
+		fails      bool
+	}{
+		{"Succeeds After Retries", 2, 3, 3, false},
+		{"Gives Up", 10, 2, 3, true},
+		{"No Retry", 10, 0, 1, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if attempts.Add(1) <= tc.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer srv.Close()
+
+			err := New([]string{srv.URL}, time.Second, tc.maxRetries, time.Millisecond).Send(context.Background(), testEvent(1))
+			if tc.fails {
+				require.ErrorContains(t, err, "unexpected status 503")
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.attempts, attempts.Load())
+		})
+	}
+}
+
+func TestDispatcherTimeout(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first attempt outlasts the timeout
+		if attempts.Add(1) == 1 {
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+			}
+		}
+	}))
+	defer srv.Close()
+
+	require.NoError(t, New([]string{srv.URL}, 50*time.Millisecond, 1, time.Millisecond).Send(context.Background(), testEvent(1)))
+	assert.Equal(t, int32(2), attempts.Load())
+}
+
+func TestDispatcherCanceled(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	// the backoff outlasts the context
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	require.Error(t, New([]string{srv.URL}, time.Second, 5, time.Minute).Send(ctx, testEvent(1)))
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestDispatcherRedactsURL(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	target := strings.Replace(srv.URL, "http://", "http://hook:s3cret@", 1)
+	// the connection is refused, the client error carries the URL
+	srv.Close()
+
+	err := New([]string{target}, time.Second, 0, time.Millisecond).Send(context.Background(), testEvent(1))
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "s3cret")
+	assert.Contains(t, err.Error(), "hook:xxxxx@")
+}
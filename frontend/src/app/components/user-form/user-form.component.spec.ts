@@ -38,6 +38,9 @@ describe("UserFormComponent", () => {
     department: "IT",
     createdAt: "2023-01-01T00:00:00Z",
     updatedAt: "2023-01-01T00:00:00Z",
+    createdBy: "system",
+    updatedBy: "system",
+    version: 1,
   };
 
   const setupTestWithMode = async (mode: "create" | "edit", user?: User) => {
@@ -137,6 +140,9 @@ describe("UserFormComponent", () => {
           department: "Finance",
           createdAt: "2023-01-01T00:00:00Z",
           updatedAt: "2023-01-01T00:00:00Z",
+          createdBy: "system",
+          updatedBy: "system",
+          version: 1,
         }),
       );
 
@@ -171,7 +177,7 @@ describe("UserFormComponent", () => {
       });
 
       // Setup service mock to throw error
-      const errorResponse = { error: { error: "Username already exists" } };
+      const errorResponse = { error: { message: "Username already exists" } };
       userServiceSpy.createUser.and.returnValue(
         throwError(() => errorResponse),
       );
@@ -230,6 +236,9 @@ describe("UserFormComponent", () => {
           department: "Marketing",
           createdAt: "2023-01-01T00:00:00Z",
           updatedAt: "2023-01-02T00:00:00Z",
+          createdBy: "system",
+          updatedBy: "system",
+          version: 1,
         }),
       );
 
@@ -261,7 +270,7 @@ describe("UserFormComponent", () => {
 
       const errorMessage = "email already exists";
       // Setup service mock to throw error
-      const errorResponse = { error: { error: errorMessage } };
+      const errorResponse = { error: { message: errorMessage } };
       userServiceSpy.updateUser.and.returnValue(
         throwError(() => errorResponse),
       );
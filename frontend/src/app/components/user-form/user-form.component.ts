@@ -27,6 +27,7 @@ import {
   UserCreateRequest,
   UserUpdateRequest,
   UserStatus,
+  ErrorResponse,
 } from "../../models/user.model";
 
 import { UserService } from "../../services/user.service";
@@ -298,8 +299,12 @@ export class UserFormComponent implements OnInit, OnDestroy {
       error !== null &&
       "error" in error
     ) {
-      const errorObj = error as { error: { error?: string } };
-      this.errorMessage = errorObj.error?.error || defaultErrorMessage;
+      const errorObj = error as { error: Partial<ErrorResponse> };
+      const fieldErrors = errorObj.error?.errors
+        ?.map((fieldError) => fieldError.message)
+        .join(", ");
+      this.errorMessage =
+        fieldErrors || errorObj.error?.message || defaultErrorMessage;
     } else {
       this.errorMessage = defaultErrorMessage;
     }
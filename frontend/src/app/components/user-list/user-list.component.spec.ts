@@ -44,6 +44,9 @@ describe("UserListComponent", () => {
       department: "IT",
       createdAt: "2023-01-01T00:00:00Z",
       updatedAt: "2023-01-01T00:00:00Z",
+      createdBy: "system",
+      updatedBy: "system",
+      version: 1,
     },
     {
       id: 2,
@@ -55,6 +58,9 @@ describe("UserListComponent", () => {
       department: "HR",
       createdAt: "2023-01-02T00:00:00Z",
       updatedAt: "2023-01-02T00:00:00Z",
+      createdBy: "system",
+      updatedBy: "system",
+      version: 1,
     },
     {
       id: 3,
@@ -66,6 +72,9 @@ describe("UserListComponent", () => {
       department: "Finance",
       createdAt: "2023-01-03T00:00:00Z",
       updatedAt: "2023-01-03T00:00:00Z",
+      createdBy: "system",
+      updatedBy: "system",
+      version: 1,
     },
   ];
 
@@ -236,6 +245,9 @@ describe("UserListComponent", () => {
         department: "Test",
         createdAt: "2023-01-03T00:00:00Z",
         updatedAt: "2023-01-03T00:00:00Z",
+        createdBy: "system",
+        updatedBy: "system",
+        version: 1,
       });
     }
 
@@ -272,6 +284,9 @@ describe("UserListComponent", () => {
         department: "Marketing",
         createdAt: "2023-01-03T00:00:00Z",
         updatedAt: "2023-01-03T00:00:00Z",
+        createdBy: "system",
+        updatedBy: "system",
+        version: 1,
       },
       {
         id: 1,
@@ -283,6 +298,9 @@ describe("UserListComponent", () => {
         department: "IT",
         createdAt: "2023-01-01T00:00:00Z",
         updatedAt: "2023-01-01T00:00:00Z",
+        createdBy: "system",
+        updatedBy: "system",
+        version: 1,
       },
       {
         id: 2,
@@ -294,6 +312,9 @@ describe("UserListComponent", () => {
         department: "HR",
         createdAt: "2023-01-02T00:00:00Z",
         updatedAt: "2023-01-02T00:00:00Z",
+        createdBy: "system",
+        updatedBy: "system",
+        version: 1,
       },
     ];
 
@@ -480,6 +501,9 @@ describe("UserListComponent", () => {
         department: undefined,
         createdAt: "2023-01-01T00:00:00Z",
         updatedAt: "2023-01-01T00:00:00Z",
+        createdBy: "system",
+        updatedBy: "system",
+        version: 1,
       } as unknown as User,
     ];
 
@@ -507,6 +531,9 @@ describe("UserListComponent", () => {
         department: "Test",
         createdAt: "2023-01-04T00:00:00Z",
         updatedAt: "2023-01-04T00:00:00Z",
+        createdBy: "system",
+        updatedBy: "system",
+        version: 1,
       },
       {
         id: 5,
@@ -518,6 +545,9 @@ describe("UserListComponent", () => {
         department: "Test",
         createdAt: "2023-01-05T00:00:00Z",
         updatedAt: "2023-01-05T00:00:00Z",
+        createdBy: "system",
+        updatedBy: "system",
+        version: 1,
       },
       {
         id: 6,
@@ -529,6 +559,9 @@ describe("UserListComponent", () => {
         department: "Test",
         createdAt: "2023-01-06T00:00:00Z",
         updatedAt: "2023-01-06T00:00:00Z",
+        createdBy: "system",
+        updatedBy: "system",
+        version: 1,
       },
     );
 
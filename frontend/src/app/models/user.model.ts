@@ -1,5 +1,282 @@
 // Code generated by tygo. DO NOT EDIT.
 
+//////////
+// source: audit.go
+
+/**
+ * AuditAction is the kind of change recorded by an audit entry
+ */
+export type AuditAction = string;
+/**
+ * AuditActionCreate records a created user
+ */
+export const AuditActionCreate: AuditAction = "create";
+/**
+ * AuditActionUpdate records an updated user, including a status change by the stale users deactivation
+ */
+export const AuditActionUpdate: AuditAction = "update";
+/**
+ * AuditActionDelete records a deleted user
+ */
+export const AuditActionDelete: AuditAction = "delete";
+/**
+ * AuditChange holds the values of a field before and after a change,
+ * Old is omitted for a create and New for a delete
+ */
+export interface AuditChange {
+  old?: any;
+  new?: any;
+} // @name AuditChange
+/**
+ * AuditEntry records a change of a user, written in the same transaction as the change
+ */
+export interface AuditEntry {
+  id: number /* int64 */;
+  /**
+   * ID of the changed user, kept once the user is deleted
+   */
+  userId: number /* int64 */;
+  action: AuditAction;
+  /**
+   * Who made the change
+   */
+  actor: string;
+  createdAt: string /* RFC3339 */;
+  /**
+   * Changed fields by their JSON key
+   */
+  diff: { [key: string]: AuditChange};
+} // @name AuditEntry
+
+//////////
+// source: department.go
+
+/**
+ * Department is a department the users can belong to, they refer to it by its name
+ */
+export interface Department {
+  id: number /* int64 */;
+  /**
+   * Name, unique among the departments
+   */
+  name: string;
+  createdAt: string /* RFC3339 */;
+} // @name Department
+/**
+ * DepartmentCreateRequest is the request body for creating a department
+ * swagger:model DepartmentCreateRequest
+ * 	@required	["name"]
+ */
+export interface DepartmentCreateRequest {
+  /**
+   * Name of the department, the value of the users' department field
+   * 	@maxLength	255
+   * 	@example	Engineering
+   */
+  name: string;
+} // @name DepartmentCreateRequest
+
+//////////
+// source: error.go
+
+/**
+ * ErrorResponse is the body of every error response
+ */
+export interface ErrorResponse {
+  /**
+   * Machine readable code of the error, the snake_case text of the HTTP status
+   */
+  code: string;
+  /**
+   * English sentence describing the error
+   */
+  message: string;
+  /**
+   * Every rejected field at once, set on the validation errors and the errors of a batch item
+   */
+  errors?: FieldError[];
+  /**
+   * ID of the request, as in the X-Request-ID header, added to every error body by the server
+   */
+  requestId?: string;
+} // @name ErrorResponse
+
+//////////
+// source: event.go
+
+/**
+ * UserEventType is the kind of change a user event notifies
+ */
+export type UserEventType = string;
+/**
+ * UserEventCreated notifies a created user
+ */
+export const UserEventCreated: UserEventType = "user.created";
+/**
+ * UserEventUpdated notifies an updated user, including a status change or a reassigned manager
+ */
+export const UserEventUpdated: UserEventType = "user.updated";
+/**
+ * UserEventDeleted notifies a deleted user, holding the user as it was before the delete
+ */
+export const UserEventDeleted: UserEventType = "user.deleted";
+/**
+ * UserEvent is the body posted to the webhooks after a committed change of a user
+ */
+export interface UserEvent {
+  type: UserEventType;
+  user: User;
+  timestamp: string /* RFC3339 */;
+  /**
+   * ID of the request that made the change, also sent as the X-Request-ID header of the webhook
+   */
+  requestId?: string;
+} // @name UserEvent
+
+//////////
+// source: health.go
+
+/**
+ * HealthStatus is the report of GET /status, meant for humans and monitoring
+ */
+export interface HealthStatus {
+  /**
+   * Memory allocated by the process
+   */
+  mem_usage: string;
+  /**
+   * Number of live goroutines
+   */
+  num_goroutine: number /* int */;
+  /**
+   * Number of completed GC cycles
+   */
+  num_gc: number /* uint32 */;
+  /**
+   * Memory of the heap spans in use
+   */
+  heap_inuse: string;
+  /**
+   * Memory obtained from the OS
+   */
+  sys: string;
+  /**
+   * Uptime of the process
+   */
+  online_t: string;
+  /**
+   * Whether the database can be reached
+   */
+  db_status: string;
+  /**
+   * Drift of the database clock from the app clock
+   */
+  clock_skew: string;
+  /**
+   * DEGRADED once the clock skew exceeds the configured threshold
+   */
+  clock_status: string;
+  /**
+   * Whether every migration is applied to the database, unknown when it can't be told, e.g. on a database never migrated
+   */
+  migrations: string;
+  /**
+   * Number of migrations not applied yet
+   */
+  pending_migrations?: number /* int */;
+} // @name HealthStatus
+/**
+ * The migration statuses of HealthStatus
+ */
+export const MigrationsUpToDate = "up_to_date";
+export const MigrationsPending = "pending";
+export const MigrationsUnknown = "unknown";
+/**
+ * ProbeStatus is the body of the Kubernetes probes, GET /healthz and GET /readyz
+ */
+export interface ProbeStatus {
+  status: string;
+} // @name ProbeStatus
+
+//////////
+// source: outbox.go
+
+/**
+ * OutboxEntry is a user event waiting to be posted to the webhooks,
+ * written in the same transaction as the change so a committed change is never left out
+ */
+export interface OutboxEntry {
+  id: number /* int64 */;
+  event: UserEvent;
+  createdAt: string /* RFC3339 */;
+  sentAt?: string /* RFC3339 */;
+  /**
+   * Failed delivery attempts
+   */
+  attempts: number /* int */;
+} // @name OutboxEntry
+
+//////////
+// source: token.go
+
+/**
+ * RefreshToken is a long-lived token exchanged for short-lived access tokens, stored by the SHA-256 digest
+ * of the token so a leaked table can't be replayed
+ */
+export interface RefreshToken {
+  id: number /* int64 */;
+  /**
+   * Subject of the access tokens
+   */
+  subject: string;
+  /**
+   * Space-separated roles of the access tokens
+   */
+  roles: string;
+  createdAt: string /* RFC3339 */;
+  expiresAt: string /* RFC3339 */;
+  revokedAt?: string /* RFC3339 */;
+} // @name RefreshToken
+/**
+ * RefreshTokenRequest is the request body for refreshing an access token or revoking a refresh token
+ */
+export interface RefreshTokenRequest {
+  refreshToken: string;
+} // @name RefreshTokenRequest
+/**
+ * RefreshTokenIssueRequest is the request body for issuing a refresh token
+ */
+export interface RefreshTokenIssueRequest {
+  /**
+   * Subject of the access tokens, e.g. the user name
+   */
+  subject: string;
+  /**
+   * Roles of the access tokens
+   */
+  roles: string[];
+} // @name RefreshTokenIssueRequest
+/**
+ * RefreshTokenIssueResponse is the response body for an issued refresh token, which can't be read back
+ */
+export interface RefreshTokenIssueResponse {
+  refreshToken: string;
+} // @name RefreshTokenIssueResponse
+/**
+ * AccessTokenResponse is the response body for a refreshed access token
+ */
+export interface AccessTokenResponse {
+  /**
+   * JWT signed with HS256, sent as "Authorization: Bearer <accessToken>"
+   */
+  accessToken: string;
+  tokenType: string;
+  /**
+   * Lifetime of the access token in seconds
+   */
+  expiresIn: number /* int */;
+} // @name AccessTokenResponse
+
 //////////
 // source: user.go
 
@@ -51,6 +328,19 @@ export interface UserCommon {
    * 	@example	Engineering
    */
   department: string;
+  /**
+   * Contact phone number in E.164 format
+   * 	@maxLength	16
+   * 	@pattern	^\+[1-9]?[0-9]{7,14}$
+   * 	@example	+14155552671
+   */
+  phone?: string;
+  /**
+   * ID of the user's manager, omitted when they have none
+   * 	@minimum	1
+   * 	@example	2
+   */
+  managerId?: number /* int64 */;
 } // @name UserCommon
 /**
  * User represents a user in the system
@@ -59,16 +349,147 @@ export interface User extends UserCommon {
   id: number /* int64 */;
   createdAt: string /* RFC3339 */;
   updatedAt: string /* RFC3339 */;
+  /**
+   * Who created the user, "system" for the changes made without an actor (e.g. by the CLI)
+   */
+  createdBy: string;
+  /**
+   * Who last changed the user
+   */
+  updatedBy: string;
+  /**
+   * When the email last changed, omitted if it never changed since the creation
+   */
+  emailUpdatedAt?: string /* RFC3339 */;
+  /**
+   * When the status last changed, omitted if it never changed since the creation
+   */
+  statusUpdatedAt?: string /* RFC3339 */;
+  /**
+   * Incremented by every update, send it back (as If-Match or in the body) to detect concurrent changes
+   */
+  version: number /* int64 */;
+  /**
+   * When the user last logged in, omitted if they never did
+   */
+  lastLoginAt?: string /* RFC3339 */;
+  /**
+   * The user's manager, only loaded by GET /users/{id}, without their own manager
+   */
+  manager?: User;
 } // @name User
 /**
  * UserCreateRequest is the request body for creating a user
  * swagger:model UserCreateRequest
  * 	@required	["userName", "firstName", "lastName", "email", "userStatus"]
  */
-export interface UserCreateRequest extends UserCommon {} // @name UserCreateRequest
+export interface UserCreateRequest extends UserCommon {
+  /**
+   * Password, write-only: only its bcrypt hash is stored and it's never returned
+   * 	@minLength	8
+   * 	@maxLength	72
+   * 	@format		password
+   */
+  password?: string;
+} // @name UserCreateRequest
 /**
  * UserUpdateRequest is the request body for updating a user
  * swagger:model UserUpdateRequest
  * 	@required	["userName", "firstName", "lastName", "email", "userStatus"]
  */
-export interface UserUpdateRequest extends UserCommon {} // @name UserUpdateRequest
+export interface UserUpdateRequest extends UserCommon {
+  /**
+   * Version the update is based on, a stale version is rejected with 409. Omit it to overwrite unconditionally
+   */
+  version?: number /* int64 */;
+} // @name UserUpdateRequest
+/**
+ * MinPasswordLength and MaxPasswordLength bound the length of a password, bcrypt ignores the bytes past 72
+ */
+export const MinPasswordLength = 8;
+export const MaxPasswordLength = 72;
+/**
+ * UserDeleteResponse is the response body for a deleted user
+ */
+export interface UserDeleteResponse {
+  deleted: boolean;
+  id: number /* int64 */;
+} // @name UserDeleteResponse
+/**
+ * UserListResponse is the response body for a page of users
+ */
+export interface UserListResponse {
+  users: User[];
+  /**
+   * Total number of users matching the criteria, regardless of the page
+   */
+  total: number /* int */;
+  /**
+   * Applied page size
+   */
+  limit: number /* int */;
+  /**
+   * Applied number of skipped users
+   */
+  offset: number /* int */;
+  /**
+   * Cursor of the next page (the cursor query parameter), set when the page is full and ordered by user_id ascending
+   */
+  nextCursor?: string;
+} // @name UserListResponse
+/**
+ * UserCountResponse is the response body for counting users
+ */
+export interface UserCountResponse {
+  /**
+   * Number of users matching the criteria
+   */
+  total: number /* int */;
+  /**
+   * Number of matching users per status, every status is listed
+   */
+  byStatus: { [key: UserStatus]: number /* int */};
+} // @name UserCountResponse
+/**
+ * UserAvailabilityResponse is the response body for checking whether an email and a user name are still free,
+ * only the checked ones are set
+ */
+export interface UserAvailabilityResponse {
+  emailAvailable?: boolean;
+  usernameAvailable?: boolean;
+} // @name UserAvailabilityResponse
+/**
+ * UserDeactivateStaleResult is the response body for deactivating the users who haven't logged in for a while
+ */
+export interface UserDeactivateStaleResult {
+  /**
+   * Whether the users were only reported, not deactivated
+   */
+  dryRun: boolean;
+  /**
+   * Number of affected users
+   */
+  count: number /* int */;
+  users: User[];
+} // @name UserDeactivateStaleResult
+
+//////////
+// source: validation.go
+
+/**
+ * FieldError describes a request body field rejected by the validation rules
+ */
+export interface FieldError {
+  /**
+   * JSON key of the field, prefixed by the item position for the batch endpoints
+   */
+  field: string;
+  /**
+   * Validation rule the field failed
+   */
+  tag: string;
+  /**
+   * English sentence describing the failure
+   */
+  message: string;
+} // @name FieldError
@@ -29,6 +29,9 @@ describe("UserService", () => {
       department: "IT",
       createdAt: "2023-01-01T00:00:00Z",
       updatedAt: "2023-01-01T00:00:00Z",
+      createdBy: "system",
+      updatedBy: "system",
+      version: 1,
     },
     {
       id: 2,
@@ -40,6 +43,9 @@ describe("UserService", () => {
       department: "HR",
       createdAt: "2023-01-02T00:00:00Z",
       updatedAt: "2023-01-02T00:00:00Z",
+      createdBy: "system",
+      updatedBy: "system",
+      version: 1,
     },
   ];
 
@@ -85,9 +91,9 @@ describe("UserService", () => {
         expect(users).toEqual(mockUsers);
       });
 
-      const req = httpMock.expectOne(apiUrl);
+      const req = httpMock.expectOne(`${apiUrl}?limit=500`);
       expect(req.request.method).toBe("GET");
-      req.flush(mockUsers);
+      req.flush({ users: mockUsers, total: 2, limit: 500, offset: 0 });
     });
   });
 
@@ -111,6 +117,9 @@ describe("UserService", () => {
         id: 3,
         createdAt: "2023-01-03T00:00:00Z",
         updatedAt: "2023-01-03T00:00:00Z",
+        createdBy: "system",
+        updatedBy: "system",
+        version: 1,
       };
 
       service.createUser(mockUserCreateRequest).subscribe((user) => {
@@ -132,6 +141,9 @@ describe("UserService", () => {
         id: userId,
         createdAt: "2023-01-01T00:00:00Z",
         updatedAt: "2023-01-04T00:00:00Z",
+        createdBy: "system",
+        updatedBy: "system",
+        version: 1,
       };
 
       service.updateUser(userId, mockUserUpdateRequest).subscribe((user) => {
@@ -1,13 +1,19 @@
 import { Injectable } from "@angular/core";
 import { HttpClient } from "@angular/common/http";
-import { Observable } from "rxjs";
+import { Observable, map } from "rxjs";
 import { environment } from "../../environments/environment";
 import {
   User,
   UserCreateRequest,
+  UserListResponse,
   UserUpdateRequest,
 } from "../models/user.model";
 
+/**
+ * Largest page size served by the API, the list is sorted and filtered client-side
+ */
+const MAX_PAGE_SIZE = 500;
+
 @Injectable({
   providedIn: "root",
 })
@@ -20,7 +26,11 @@ export class UserService {
    * Get all users
    */
   getUsers(): Observable<User[]> {
-    return this.http.get<User[]>(this.apiUrl);
+    return this.http
+      .get<UserListResponse>(this.apiUrl, {
+        params: { limit: MAX_PAGE_SIZE },
+      })
+      .pipe(map((response) => response.users));
   }
 
   /**